@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// writeGitHubActions reports a pull request to a GitHub Actions workflow: annotations on out,
+// step outputs in $GITHUB_OUTPUT, and a markdown summary in $GITHUB_STEP_SUMMARY. Outside
+// Actions, outputs are written to out instead and the summary is skipped. It reports whether
+// the pull request is ready to merge.
+func writeGitHubActions(out io.Writer, data *prx.PullRequestData, owner, repo string, prNumber int) (bool, error) {
+	pr := &data.PullRequest
+	blocker := pr.MergeBlocker()
+	ready := blocker == ""
+	ref := fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)
+
+	if pr.CheckSummary != nil {
+		for _, name := range slices.Sorted(maps.Keys(pr.CheckSummary.Failing)) {
+			workflowCommand(out, "error", "Check failed: "+name, pr.CheckSummary.Failing[name])
+		}
+	}
+	if a := pr.ApprovalSummary; a != nil && a.ChangesRequested > 0 {
+		workflowCommand(out, "warning", "Changes requested", fmt.Sprintf("%s has %d outstanding change requests", ref, a.ChangesRequested))
+	}
+	if t := pr.ThreadSummary; t != nil && t.Unresolved > 0 {
+		workflowCommand(out, "warning", "Unresolved threads", fmt.Sprintf("%s has %d unresolved review threads", ref, t.Unresolved))
+	}
+	for _, w := range data.FetchReport.Warnings {
+		workflowCommand(out, "warning", "Incomplete data: "+w.Section, w.Error)
+	}
+	if ready {
+		workflowCommand(out, "notice", "Ready to merge", ref+" is ready to merge")
+	} else {
+		workflowCommand(out, "error", "Not ready to merge", fmt.Sprintf("%s is not ready to merge: %s", ref, blocker))
+	}
+
+	outputs := [][2]string{
+		{"mergeable", strconv.FormatBool(ready)},
+		{"mergeable_state", pr.MergeableState},
+		{"state", pr.State},
+		{"draft", strconv.FormatBool(pr.Draft)},
+		{"test_state", pr.TestState},
+		{"head_sha", pr.HeadSHA},
+	}
+	if a := pr.ApprovalSummary; a != nil {
+		outputs = append(outputs,
+			[2]string{"approvals", strconv.Itoa(a.ApprovalsWithWriteAccess)},
+			[2]string{"changes_requested", strconv.Itoa(a.ChangesRequested)})
+	}
+	if t := pr.ThreadSummary; t != nil {
+		outputs = append(outputs, [2]string{"unresolved_threads", strconv.Itoa(t.Unresolved)})
+	}
+	if pr.CheckSummary != nil {
+		outputs = append(outputs, [2]string{"failing_checks", strconv.Itoa(len(pr.CheckSummary.Failing))})
+	}
+	var b strings.Builder
+	for _, o := range outputs {
+		fmt.Fprintf(&b, "%s=%s\n", o[0], o[1])
+	}
+	if err := appendToEnvFile("GITHUB_OUTPUT", out, b.String()); err != nil {
+		return ready, fmt.Errorf("writing outputs: %w", err)
+	}
+
+	var summary strings.Builder
+	if err := writeReport(&summary, pr, ref, "markdown"); err != nil {
+		return ready, err
+	}
+	if err := appendToEnvFile("GITHUB_STEP_SUMMARY", io.Discard, summary.String()); err != nil {
+		return ready, fmt.Errorf("writing step summary: %w", err)
+	}
+	return ready, nil
+}
+
+// appendToEnvFile appends content to the file named by the environment variable, or writes it to fallback if unset.
+func appendToEnvFile(env string, fallback io.Writer, content string) error {
+	path := os.Getenv(env)
+	if path == "" {
+		_, err := io.WriteString(fallback, content)
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close() //nolint:errcheck,gosec // The write error is more useful
+		return err
+	}
+	return f.Close()
+}
+
+// workflowCommand writes a ::notice, ::warning, or ::error annotation.
+func workflowCommand(out io.Writer, command, title, message string) {
+	props := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	data := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	fmt.Fprintf(out, "::%s title=%s::%s\n", command, props.Replace(title), data.Replace(message))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// batchOptions configures fetching several pull requests in one invocation.
+type batchOptions struct {
+	refTime     time.Time
+	repo        string // OWNER/NAME or HOST/OWNER/NAME whose pull requests to fetch, instead of urls
+	state       string // Of the repository's pull requests: open, closed, or all
+	format      string // json or ndjson
+	urls        []string
+	failOn      []string // --fail-on criteria, checked for each pull request
+	concurrency int
+	debug       bool
+	noCache     bool
+}
+
+// batchResult is a pull request in batch output: its data, plus where it's from.
+type batchResult struct {
+	*prx.PullRequestData
+
+	URL string `json:"url"`
+}
+
+// runBatch fetches several pull requests, or those of a repository, and writes them as a JSON
+// array or as NDJSON, one per line. A repository's open pull requests that change the same files
+// are flagged with OverlappingPRs. It returns the exit code.
+func runBatch(opts batchOptions) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	urls := opts.urls
+	if opts.repo != "" {
+		var err error
+		if urls, err = repositoryPullRequestURLs(ctx, opts.repo, opts.state, opts.debug, opts.noCache); err != nil {
+			log.Printf("Failed to list pull requests: %v", err)
+			return 1
+		}
+	}
+
+	prs, ok := fetchPullRequests(ctx, urls, opts.refTime, opts.concurrency, opts.debug, opts.noCache)
+	if opts.repo != "" {
+		flagOverlappingPRs(prs)
+	}
+	if err := writeBatch(os.Stdout, prs, opts.format); err != nil {
+		log.Printf("Failed to write pull requests: %v", err)
+		return 1
+	}
+	for i := range prs {
+		if reasons := failures(&prs[i].Data.PullRequest, opts.failOn); len(reasons) > 0 {
+			writeFailures(os.Stderr, prs[i].URL, reasons)
+			ok = false
+		}
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// flagOverlappingPRs flags the open pull requests of a repository that change the same files.
+func flagOverlappingPRs(prs []prx.DigestPullRequest) {
+	if len(prs) == 0 {
+		return
+	}
+	data := make([]*prx.PullRequestData, len(prs))
+	for i := range prs {
+		data[i] = prs[i].Data
+	}
+	prx.FlagOverlappingPRs(prs[0].Owner, prs[0].Repo, data)
+}
+
+// repositoryPullRequestURLs returns the URLs of the pull requests in state of repo, given as
+// OWNER/NAME on github.com or HOST/OWNER/NAME.
+func repositoryPullRequestURLs(ctx context.Context, repo, state string, debug, noCache bool) ([]string, error) {
+	parts := strings.Split(repo, "/")
+	switch len(parts) {
+	case 2:
+		parts = append([]string{githubHost}, parts...)
+	case 3:
+	default:
+		return nil, fmt.Errorf("invalid repository %q: want OWNER/NAME or HOST/OWNER/NAME", repo)
+	}
+	host, owner, name := strings.ToLower(parts[0]), parts[1], parts[2]
+
+	token, err := githubToken(host)
+	if err != nil {
+		return nil, err
+	}
+	client := prx.NewClient(token, clientOptions(host, debug, noCache)...)
+	defer client.Close() //nolint:errcheck // Nothing to do about cache close errors
+
+	numbers, err := client.PullRequestNumbers(ctx, owner, name, state)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(numbers))
+	for i, n := range numbers {
+		urls[i] = fmt.Sprintf("https://%s/%s/%s/pull/%d", host, owner, name, n)
+	}
+	return urls, nil
+}
+
+// writeBatch writes pull requests to w as a JSON array, or as NDJSON.
+func writeBatch(w io.Writer, prs []prx.DigestPullRequest, format string) error {
+	results := make([]batchResult, len(prs))
+	for i := range prs {
+		results[i] = batchResult{PullRequestData: prs[i].Data, URL: prs[i].URL}
+	}
+	encoder := json.NewEncoder(w)
+	if format != "ndjson" {
+		return encoder.Encode(results)
+	}
+	for i := range results {
+		if err := encoder.Encode(&results[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+func TestFlagOverlappingPRs(t *testing.T) {
+	pr := func(number int, files ...string) prx.DigestPullRequest {
+		return prx.DigestPullRequest{
+			Data:  &prx.PullRequestData{PullRequest: prx.PullRequest{Number: number, State: "open", Files: files}},
+			Owner: "owner",
+			Repo:  "repo",
+		}
+	}
+	prs := []prx.DigestPullRequest{pr(1, "a.go", "b.go"), pr(2, "b.go"), pr(3, "c.go")}
+	flagOverlappingPRs(prs)
+
+	var out bytes.Buffer
+	if err := writeBatch(&out, prs, "json"); err != nil {
+		t.Fatalf("writeBatch() error: %v", err)
+	}
+	var results []struct {
+		PullRequest struct {
+			OverlappingPRs []prx.PRRef `json:"overlapping_prs"`
+			Number         int         `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("decoding batch output: %v", err)
+	}
+	if len(results) != len(prs) {
+		t.Fatalf("got %d results, want %d", len(results), len(prs))
+	}
+	want := map[int]int{1: 2, 2: 1, 3: 0}
+	for i := range results {
+		r := &results[i].PullRequest
+		switch {
+		case want[r.Number] == 0 && len(r.OverlappingPRs) != 0:
+			t.Errorf("PR %d OverlappingPRs = %+v, want none", r.Number, r.OverlappingPRs)
+		case want[r.Number] != 0 && (len(r.OverlappingPRs) != 1 || r.OverlappingPRs[0].Number != want[r.Number]):
+			t.Errorf("PR %d OverlappingPRs = %+v, want PR %d", r.Number, r.OverlappingPRs, want[r.Number])
+		}
+	}
+
+	flagOverlappingPRs(nil) // A sweep of a repository without pull requests
+}
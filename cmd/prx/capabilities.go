@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// runCapabilities implements "prx capabilities": it prints what the server and the token for it
+// support as JSON, and returns the exit code.
+func runCapabilities(args []string) int {
+	fs := flag.NewFlagSet("capabilities", flag.ExitOnError)
+	host := fs.String("host", githubHost, "GitHub or GitHub Enterprise Server host")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	tokens.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s capabilities [--host=HOST] [--debug]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		fs.Usage()
+		return 1
+	}
+	if *debug {
+		enableDebugLogging()
+	}
+
+	token, err := githubToken(*host)
+	if err != nil {
+		log.Printf("Failed to get GitHub token: %v", err)
+		return 1
+	}
+	client := prx.NewClient(token, clientOptions(*host, *debug, true)...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(client.TokenCapabilities(ctx)); err != nil {
+		log.Printf("Failed to write capabilities: %v", err)
+		return 1
+	}
+	return 0
+}
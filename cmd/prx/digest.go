@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// runDigest implements "prx digest": it fetches the given pull requests and writes what needs a
+// person's or team's attention, and returns the exit code. Pull requests that can't be fetched are
+// reported and left out, and make the exit code nonzero.
+func runDigest(args []string) int {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	users := fs.String("user", "", "Comma-separated logins: a person, or a team's members")
+	teams := fs.String("team", "", "Comma-separated teams (org/slug) whose review requests and mentions count")
+	name := fs.String("name", "", "Name for the heading; defaults to the first user or team")
+	format := fs.String("format", string(prx.DigestFormatMarkdown), "Output format: markdown or html")
+	localeName := fs.String("locale", "", "Language of ages, such as de or fr; defaults to LANG")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	noCache := fs.Bool("no-cache", false, "Disable caching")
+	tokens.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s digest --user=LOGIN [--team=ORG/SLUG] [--name=NAME] [--format=markdown|html] [--locale=LANG] <pull-request-url>...\n",
+			os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 || *users == "" && *teams == "" {
+		fs.Usage()
+		return 1
+	}
+	locale, err := resolveLocale(*localeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *debug {
+		enableDebugLogging()
+	}
+	id := prx.DigestIdentity{Name: *name, Logins: splitList(*users), Teams: splitList(*teams)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	prs, ok := fetchPullRequests(ctx, fs.Args(), time.Now(), defaultConcurrency, *debug, *noCache)
+
+	digest := prx.BuildDigest(id, prs, time.Now())
+	digest.Locale = locale
+	if err := digest.Write(os.Stdout, prx.DigestFormat(*format)); err != nil {
+		log.Printf("Failed to write digest: %v", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var values []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
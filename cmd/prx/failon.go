@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// failOnCriteria are the criteria accepted by --fail-on. Each returns why the pull request fails it,
+// or "" if it doesn't.
+var failOnCriteria = map[string]func(pr *prx.PullRequest) string{
+	"failing-checks": func(pr *prx.PullRequest) string {
+		if pr.CheckSummary == nil || len(pr.CheckSummary.Failing) == 0 {
+			return ""
+		}
+		return "failing checks: " + strings.Join(slices.Sorted(maps.Keys(pr.CheckSummary.Failing)), ", ")
+	},
+	"pending-checks": func(pr *prx.PullRequest) string {
+		if pr.CheckSummary == nil || len(pr.CheckSummary.Pending) == 0 {
+			return ""
+		}
+		return "pending checks: " + strings.Join(slices.Sorted(maps.Keys(pr.CheckSummary.Pending)), ", ")
+	},
+	"changes-requested": func(pr *prx.PullRequest) string {
+		if pr.ApprovalSummary == nil || pr.ApprovalSummary.ChangesRequested == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d outstanding change requests", pr.ApprovalSummary.ChangesRequested)
+	},
+	// Fewer approvals than the base branch requires, or none if it requires none
+	"unapproved": func(pr *prx.PullRequest) string {
+		approvals := 0
+		if pr.ApprovalSummary != nil {
+			approvals = pr.ApprovalSummary.ApprovalsWithWriteAccess
+		}
+		switch {
+		case !pr.ApprovalsSatisfied && pr.RequiredApprovals > 0:
+			return fmt.Sprintf("%d of %d required approvals from reviewers with write access", approvals, pr.RequiredApprovals)
+		case approvals == 0:
+			return "no approvals from reviewers with write access"
+		default:
+			return ""
+		}
+	},
+	"unresolved-threads": func(pr *prx.PullRequest) string {
+		if pr.ThreadSummary == nil || pr.ThreadSummary.Unresolved == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d unresolved review threads", pr.ThreadSummary.Unresolved)
+	},
+	"conflicts": func(pr *prx.PullRequest) string {
+		if pr.MergeableState != "dirty" {
+			return ""
+		}
+		return "merge conflicts with the base branch"
+	},
+	"draft": func(pr *prx.PullRequest) string {
+		if !pr.Draft {
+			return ""
+		}
+		return "draft"
+	},
+	"not-ready": func(pr *prx.PullRequest) string {
+		if blocker := pr.MergeBlocker(); blocker != "" {
+			return "not ready to merge: " + blocker
+		}
+		return ""
+	},
+}
+
+// parseFailOn splits a --fail-on value, rejecting unknown criteria.
+func parseFailOn(s string) ([]string, error) {
+	criteria := splitList(s)
+	for _, c := range criteria {
+		if _, ok := failOnCriteria[c]; !ok {
+			return nil, fmt.Errorf("unknown --fail-on criterion %q: want %s", c, strings.Join(slices.Sorted(maps.Keys(failOnCriteria)), ", "))
+		}
+	}
+	return criteria, nil
+}
+
+// failures returns why the pull request fails the criteria, in their order.
+func failures(pr *prx.PullRequest, criteria []string) []string {
+	var reasons []string
+	for _, c := range criteria {
+		if reason := failOnCriteria[c](pr); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+// writeFailures explains why a pull request fails the --fail-on criteria.
+func writeFailures(w io.Writer, ref string, reasons []string) {
+	fmt.Fprintf(w, "✗ %s fails --fail-on:\n", ref)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "  - %s\n", reason)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"unapproved", []string{"unapproved"}, false},
+		{" failing-checks, ,draft ", []string{"failing-checks", "draft"}, false},
+		{"failing-checks,unknown", nil, true},
+		{"Draft", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseFailOn(tt.value)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("parseFailOn(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFailures(t *testing.T) {
+	approved := func(approvals, stale, required int) *prx.PullRequest {
+		return &prx.PullRequest{
+			State:              "open",
+			MergeableState:     "clean",
+			ApprovalSummary:    &prx.ApprovalSummary{ApprovalsWithWriteAccess: approvals, StaleApprovals: stale},
+			RequiredApprovals:  required,
+			ApprovalsSatisfied: approvals >= required,
+		}
+	}
+	all := slices.Sorted(maps.Keys(failOnCriteria))
+	tests := []struct {
+		name     string
+		pr       *prx.PullRequest
+		criteria []string
+		want     []string
+	}{
+		{"no criteria", &prx.PullRequest{Draft: true}, nil, nil},
+		{"clean", approved(1, 0, 1), all, nil},
+		{"one of two required approvals", approved(1, 0, 2), []string{"unapproved"},
+			[]string{"1 of 2 required approvals from reviewers with write access"}},
+		{"stale approvals still count", approved(2, 2, 2), []string{"unapproved"}, nil},
+		{"none required and none given", approved(0, 0, 0), []string{"unapproved"},
+			[]string{"no approvals from reviewers with write access"}},
+		{"no approval summary", &prx.PullRequest{}, []string{"unapproved"},
+			[]string{"no approvals from reviewers with write access"}},
+		{
+			"in criteria order",
+			&prx.PullRequest{
+				State:           "open",
+				Draft:           true,
+				MergeableState:  "dirty",
+				CheckSummary:    &prx.CheckSummary{Failing: map[string]string{"test": "", "lint": ""}, Pending: map[string]string{"e2e": ""}},
+				ApprovalSummary: &prx.ApprovalSummary{ChangesRequested: 2},
+				ThreadSummary:   &prx.ThreadSummary{Unresolved: 3},
+			},
+			[]string{"draft", "failing-checks", "pending-checks", "changes-requested", "unresolved-threads", "conflicts", "not-ready"},
+			[]string{
+				"draft",
+				"failing checks: lint, test",
+				"pending checks: e2e",
+				"2 outstanding change requests",
+				"3 unresolved review threads",
+				"merge conflicts with the base branch",
+				"not ready to merge: pull request is a draft",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failures(tt.pr, tt.criteria); !slices.Equal(got, tt.want) {
+				t.Errorf("failures() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
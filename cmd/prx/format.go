@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// defaultCSVFields are the event columns written by --format=csv without --fields.
+var defaultCSVFields = []string{"timestamp", "kind", "actor", "bot", "target", "outcome", "body"}
+
+// eventFields maps the JSON names of event fields, as accepted by --fields, to their kinds.
+var eventFields = func() map[string]reflect.Kind {
+	fields := make(map[string]reflect.Kind)
+	t := reflect.TypeFor[prx.Event]()
+	for i := range t.NumField() {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			fields[name] = t.Field(i).Type.Kind()
+		}
+	}
+	return fields
+}()
+
+// parseFields splits a --fields value, rejecting names that aren't event fields.
+func parseFields(s string) ([]string, error) {
+	fields := splitList(s)
+	for _, f := range fields {
+		if _, ok := eventFields[f]; !ok {
+			return nil, fmt.Errorf("unknown event field %q", f)
+		}
+	}
+	return fields, nil
+}
+
+// writeEvents writes events to w as NDJSON, one JSON object per line, or as CSV with a header row.
+// Only the named fields are written, or for NDJSON all of them if there are none.
+func writeEvents(w io.Writer, events []prx.Event, format string, fields []string) error {
+	switch format {
+	case "ndjson":
+		encoder := json.NewEncoder(w)
+		for i := range events {
+			if len(fields) == 0 {
+				if err := encoder.Encode(&events[i]); err != nil {
+					return err
+				}
+				continue
+			}
+			selected, err := selectFields(&events[i], fields)
+			if err != nil {
+				return err
+			}
+			// Written by hand to keep the order of fields
+			var line bytes.Buffer
+			line.WriteByte('{')
+			for _, f := range fields {
+				if v, ok := selected[f]; ok {
+					if line.Len() > 1 {
+						line.WriteByte(',')
+					}
+					fmt.Fprintf(&line, "%q:%s", f, v)
+				}
+			}
+			line.WriteString("}\n")
+			if _, err := line.WriteTo(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		if len(fields) == 0 {
+			fields = defaultCSVFields
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+		row := make([]string, len(fields))
+		for i := range events {
+			selected, err := selectFields(&events[i], fields)
+			if err != nil {
+				return err
+			}
+			for j, f := range fields {
+				row[j] = csvValue(f, selected[f])
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// selectFields returns the named fields of e as JSON, leaving out empty ones.
+func selectFields(e *prx.Event, fields []string) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected, nil
+}
+
+// csvValue formats a field's JSON value for a CSV cell: strings unquoted, empty booleans and
+// numbers as false and 0, and lists and objects as JSON.
+func csvValue(field string, v json.RawMessage) string {
+	if v == nil {
+		switch eventFields[field] {
+		case reflect.Bool:
+			return "false"
+		case reflect.Int, reflect.Int64:
+			return "0"
+		default:
+			return ""
+		}
+	}
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return s
+	}
+	return string(v)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// Exit codes of the gate command.
+const (
+	gateExitPassed = 0
+	gateExitFailed = 1 // The policy isn't met
+	gateExitError  = 2 // The policy couldn't be evaluated
+)
+
+// runGate implements "prx gate": it evaluates a policy file against a pull request, explains any
+// unmet requirements, and returns the exit code, so it can run in CI as a required check.
+func runGate(args []string) int {
+	fs := flag.NewFlagSet("gate", flag.ExitOnError)
+	policyPath := fs.String("policy", "", "Policy file (YAML or JSON) listing the pull request's merge requirements")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	noCache := fs.Bool("no-cache", false, "Disable caching")
+	tokens.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s gate --policy=FILE [--debug] [--no-cache] <pull-request-url>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return gateExitError
+	}
+	if *policyPath == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return gateExitError
+	}
+	if *debug {
+		enableDebugLogging()
+	}
+
+	policy, err := prx.LoadPolicy(*policyPath)
+	if err != nil {
+		log.Printf("Invalid policy: %v", err)
+		return gateExitError
+	}
+	host, owner, repo, prNumber, err := parsePRURL(fs.Arg(0))
+	if err != nil {
+		log.Printf("Invalid PR URL: %v", err)
+		return gateExitError
+	}
+	token, err := githubToken(host)
+	if err != nil {
+		log.Printf("Failed to get GitHub token: %v", err)
+		return gateExitError
+	}
+
+	opts := clientOptions(host, *debug, *noCache)
+	if policy.RequireCompleteDescription {
+		opts = append(opts, prx.WithDescriptionTemplates())
+	}
+	client := prx.NewClient(token, opts...)
+	defer client.Close() //nolint:errcheck // Nothing to do about cache close errors on exit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	data, err := client.PullRequest(ctx, owner, repo, prNumber)
+	if err != nil {
+		log.Printf("Failed to fetch PR data: %v", err)
+		return gateExitError
+	}
+	result := policy.Evaluate(data)
+	if err := client.RecordPolicyVerdict(ctx, owner, repo, result.Verdict(data.PullRequest.HeadSHA)); err != nil {
+		log.Printf("Failed to record policy verdict: %v", err)
+	}
+
+	writeGateResult(os.Stdout, &result, fmt.Sprintf("%s/%s#%d", owner, repo, prNumber), data.FetchReport)
+	if !result.Passed {
+		return gateExitFailed
+	}
+	return gateExitPassed
+}
+
+// writeGateResult explains a policy result, noting data that couldn't be fetched and may have skewed it.
+func writeGateResult(w io.Writer, result *prx.PolicyResult, ref string, report prx.FetchReport) {
+	if result.Passed {
+		fmt.Fprintf(w, "✓ %s meets policy %q\n", ref, result.Policy)
+	} else {
+		fmt.Fprintf(w, "✗ %s does not meet policy %q:\n", ref, result.Policy)
+		for _, reason := range result.Unmet {
+			fmt.Fprintf(w, "  - %s\n", reason)
+		}
+	}
+	if !report.Complete() {
+		fmt.Fprintln(w, "\nSome data couldn't be fetched and may affect this result:")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(w, "  - %s %s: %s\n", warning.Section, warning.Target, warning.Error)
+		}
+	}
+}
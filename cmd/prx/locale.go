@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// resolveLocale returns the locale to format human-facing output with: the one named by --locale,
+// or else the environment's (LC_ALL, LC_MESSAGES, or LANG), or English if it isn't supported.
+func resolveLocale(name string) (*prx.Locale, error) {
+	if name != "" {
+		locale, ok := prx.LookupLocale(name)
+		if !ok {
+			return nil, fmt.Errorf("unsupported locale %q: want en, de, es, fr, ja, or pt", name)
+		}
+		return locale, nil
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			if locale, ok := prx.LookupLocale(v); ok {
+				return locale, nil
+			}
+			break
+		}
+	}
+	locale, _ := prx.LookupLocale("en")
+	return locale, nil
+}
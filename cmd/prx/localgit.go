@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// localBranch is the checked-out branch of a local git repository and the GitHub repository that
+// pull requests from it target.
+type localBranch struct {
+	host      string
+	owner     string // Owner of the base repository
+	repo      string
+	headOwner string // Owner of the repository the branch is pushed to
+	branch    string // Branch name on the remote it's pushed to
+}
+
+// currentBranch inspects the git repository in the working directory. Pull requests are assumed to
+// target the "upstream" remote if there is one, as in a fork, and "origin" otherwise; the branch is
+// assumed to be pushed to its tracking branch, or to a same-named branch on origin.
+func currentBranch(ctx context.Context) (*localBranch, error) {
+	branch, err := git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if branch == "HEAD" {
+		return nil, errors.New("HEAD is detached; check out a branch")
+	}
+
+	headRemote := "origin"
+	if upstream, err := git(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		if remote, name, ok := strings.Cut(upstream, "/"); ok {
+			headRemote, branch = remote, name
+		}
+	}
+	headURL, err := git(ctx, "remote", "get-url", headRemote)
+	if err != nil {
+		return nil, err
+	}
+	host, headOwner, repo, err := parseRemoteURL(headURL)
+	if err != nil {
+		return nil, fmt.Errorf("remote %s: %w", headRemote, err)
+	}
+
+	lb := &localBranch{host: host, owner: headOwner, repo: repo, headOwner: headOwner, branch: branch}
+	if baseURL, err := git(ctx, "remote", "get-url", "upstream"); err == nil {
+		if lb.host, lb.owner, lb.repo, err = parseRemoteURL(baseURL); err != nil {
+			return nil, fmt.Errorf("remote upstream: %w", err)
+		}
+	}
+	return lb, nil
+}
+
+// git runs a git command and returns its trimmed output.
+func git(ctx context.Context, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseRemoteURL parses a git remote URL in HTTPS ("https://github.com/owner/repo.git"),
+// SSH ("ssh://git@github.com/owner/repo.git"), or scp-like ("git@github.com:owner/repo.git") form.
+func parseRemoteURL(remote string) (host, owner, repo string, err error) {
+	var path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", "", err
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		var ok bool
+		host, path, ok = strings.Cut(remote, ":")
+		if !ok {
+			return "", "", "", fmt.Errorf("unrecognized remote URL %q", remote)
+		}
+		if _, h, found := strings.Cut(host, "@"); found {
+			host = h
+		}
+	}
+
+	host = strings.ToLower(host)
+	if host == "www."+githubHost {
+		host = githubHost
+	}
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("unrecognized remote URL %q", remote)
+	}
+	return host, parts[0], parts[1], nil
+}
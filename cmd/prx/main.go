@@ -2,6 +2,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,38 +10,122 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/codeGROOVE-dev/fido/pkg/store/null"
 	"github.com/codeGROOVE-dev/prx/pkg/prx"
+	"github.com/codeGROOVE-dev/prx/pkg/prx/replay"
 )
 
 const (
+	githubHost       = "github.com"
 	expectedURLParts = 4
 	pullPathIndex    = 2
 	pullPathValue    = "pull"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "gate":
+			os.Exit(runGate(os.Args[2:]))
+		case "capabilities":
+			os.Exit(runCapabilities(os.Args[2:]))
+		case "sample":
+			os.Exit(runSample(os.Args[2:]))
+		case "digest":
+			os.Exit(runDigest(os.Args[2:]))
+		case "whatif":
+			os.Exit(runWhatIf(os.Args[2:]))
+		default:
+		}
+	}
+
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	noCache := flag.Bool("no-cache", false, "Disable caching")
 	referenceTimeStr := flag.String("reference-time", "", "Reference time for cache validation (RFC3339 format, e.g., 2025-03-16T06:18:08Z)")
+	audit := flag.String("audit", "", "Output the governance audit trail instead, as \"cef\" or \"ocsf\"")
+	actions := flag.Bool("github-actions", false, "Emit GitHub Actions annotations, step outputs, and a job summary, failing unless the PR is ready to merge")
+	gist := flag.Bool("gist", false, "Upload the report (markdown unless --format is html or timeline) as a secret gist and print its URL (token needs the gist scope)")
+	diagnostics := flag.Bool("diagnostics", false, "Output unresolved review threads and check annotations as LSP-style diagnostics by file")
+	format := flag.String("format", "json", "Output format: \"json\" for the pull request, \"ndjson\" or \"csv\" for its events, \"timeline\" to read, or a \"markdown\" or \"html\" report")
+	fieldList := flag.String("fields", "", "Comma-separated event fields for --format=ndjson or csv, such as timestamp,kind,actor")
+	repoSpec := flag.String("repo", "", "Fetch the pull requests of a repository, given as OWNER/NAME or HOST/OWNER/NAME")
+	state := flag.String("state", "open", "State of the pull requests fetched with --repo: open, closed, or all")
+	concurrency := flag.Int("concurrency", defaultConcurrency, "Number of pull requests to fetch at once with --repo or several URLs")
+	watch := flag.Bool("watch", false, "Keep fetching the pull request, printing new events and state changes as they occur")
+	interval := flag.Duration("interval", defaultWatchInterval, "How often to fetch the pull request with --watch")
+	localeName := flag.String("locale", "", "Language of times and numbers in --format=timeline, such as de or fr; defaults to LANG")
+	failOnList := flag.String("fail-on", "", "Comma-separated criteria that make the command exit 1, for CI: failing-checks, pending-checks, changes-requested, unapproved, unresolved-threads, conflicts, draft, or not-ready")
+	record := flag.String("record", "", "Save the GitHub API traffic to a replay fixture file, for offline tests (implies --no-cache)")
+	tokens.register(flag.CommandLine)
 	flag.Parse()
 
 	if *debug {
-		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		})))
+		enableDebugLogging()
+	}
+	formatSet := false
+	flag.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+	if *gist && !formatSet {
+		*format = "markdown"
 	}
 
-	if flag.NArg() != 1 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [--debug] [--no-cache] [--reference-time=TIME] <pull-request-url>\n", os.Args[0])
+	fields, err := parseFields(*fieldList)
+	var failOn []string
+	if err == nil {
+		failOn, err = parseFailOn(*failOnList)
+	}
+	var locale *prx.Locale
+	if err == nil {
+		locale, err = resolveLocale(*localeName)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	// --fields only applies to event formats, and --audit has formats of its own
+	eventFormat := *format == "ndjson" || *format == "csv"
+	var badFormat bool
+	switch *format {
+	case "json":
+		badFormat = len(fields) > 0
+	case "ndjson", "csv":
+		badFormat = *audit != ""
+	case "timeline", "markdown", "html":
+		badFormat = *audit != "" || len(fields) > 0
+	default:
+		badFormat = true
+	}
+	// Several pull requests are written as a JSON array, or one per line with --format=ndjson
+	batch := *repoSpec != "" || flag.NArg() > 1
+	badBatch := batch && (*format != "json" && *format != "ndjson" || len(fields) > 0 ||
+		*audit != "" || *actions || *gist || *diagnostics || *repoSpec != "" && flag.NArg() > 0)
+	// Watching writes lines as things happen: JSON for json or ndjson, or timeline lines
+	badWatch := *watch && (batch || *format != "json" && *format != "ndjson" && *format != "timeline" ||
+		*audit != "" || *actions || *gist || *diagnostics || *interval <= 0)
+	// Gists share a rendered report rather than data
+	badGist := *gist && *format != "markdown" && *format != "html" && *format != "timeline"
+	// --github-actions has its own pass/fail rule, and diagnostics aren't about a single state
+	badFailOn := len(failOn) > 0 && (*watch || *actions || *diagnostics)
+	badRecord := *record != "" && (batch || *watch || *diagnostics)
+	if !batch && flag.NArg() != 1 || err != nil || badFormat || badBatch || badWatch || badGist || badFailOn || badRecord {
+		fmt.Fprintf(os.Stderr, "Usage: %s [--debug] [--no-cache] [--reference-time=TIME] [--audit=cef|ocsf] [--format=json|ndjson|csv|timeline|markdown|html] [--fields=NAME,...] [--locale=LANG] [--github-actions] [--gist] [--diagnostics] [--fail-on=CRITERION,...] [--record=FILE] <pull-request-url | .>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [--format=json|ndjson] [--concurrency=N] [--fail-on=CRITERION,...] (--repo=OWNER/NAME [--state=open|closed|all] | <pull-request-url>...)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s --watch [--interval=30s] [--format=json|ndjson|timeline] [--fields=NAME,...] [--locale=LANG] <pull-request-url | .>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s gate --policy=FILE <pull-request-url>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s capabilities [--host=HOST]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s sample [--count=N] [--seed=N]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s digest --user=LOGIN [--team=ORG/SLUG] [--format=markdown|html] [--locale=LANG] <pull-request-url>...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s whatif [--checks=NAME,...] [--ruleset=FILE] <pull-request-url>...\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Example: %s https://github.com/golang/go/pull/12345\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Use \".\" for the pull request of the branch checked out in the current directory\n")
+		fmt.Fprintf(os.Stderr, "The GitHub token is read from --token, --token-file, GH_TOKEN or GITHUB_TOKEN, or 'gh auth token'\n")
 		os.Exit(1)
 	}
 
@@ -55,86 +140,283 @@ func main() {
 		}
 	}
 
+	if batch {
+		os.Exit(runBatch(batchOptions{
+			refTime: referenceTime, repo: *repoSpec, state: *state, format: *format, urls: flag.Args(),
+			concurrency: *concurrency, debug: *debug, noCache: *noCache, failOn: failOn,
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
 	prURL := flag.Arg(0)
 
-	owner, repo, prNumber, err := parsePRURL(prURL)
-	if err != nil {
+	var local *localBranch
+	var host, owner, repo string
+	var prNumber int
+	if prURL == "." {
+		local, err = currentBranch(ctx)
+		if err != nil {
+			log.Printf("Failed to inspect local git repository: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		host, owner, repo = local.host, local.owner, local.repo
+	} else if host, owner, repo, prNumber, err = parsePRURL(prURL); err != nil {
 		log.Printf("Invalid PR URL: %v", err)
+		cancel()
 		os.Exit(1)
 	}
 
-	token, err := githubToken()
+	token, err := githubToken(host)
 	if err != nil {
 		log.Printf("Failed to get GitHub token: %v", err)
+		cancel()
 		os.Exit(1)
 	}
 
-	var opts []prx.Option
-	if *debug {
-		opts = append(opts, prx.WithLogger(slog.Default()))
+	opts := clientOptions(host, *debug, *noCache || *record != "")
+	var recorder *replay.Recorder
+	if *record != "" {
+		recorder = &replay.Recorder{}
+		opts = append(opts, prx.WithHTTPClient(&http.Client{Transport: recorder, Timeout: 30 * time.Second}))
+	}
+	client := prx.NewClient(token, opts...)
+	if local != nil {
+		prNumber, err = client.PullRequestForBranch(ctx, owner, repo, local.headOwner, local.branch)
+		if err != nil {
+			log.Printf("Failed to find PR for branch %s: %v", local.branch, err)
+			cancel()
+			os.Exit(1)
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	if *watch {
+		cancel()
+		os.Exit(runWatch(watchOptions{
+			client: client, ref: fmt.Sprintf("%s/%s#%d", owner, repo, prNumber), owner: owner, repo: repo,
+			format: *format, fields: fields, number: prNumber, locale: locale, interval: *interval, color: useColor(os.Stdout),
+		}))
+	}
 
-	// Configure client options
-	if *noCache {
-		opts = append(opts, prx.WithCacheStore(null.New[string, prx.PullRequestData]()))
+	if *diagnostics {
+		files, err := client.Diagnostics(ctx, owner, repo, prNumber)
+		if err == nil {
+			err = json.NewEncoder(os.Stdout).Encode(files)
+		}
+		if err != nil {
+			log.Printf("Failed to output diagnostics: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		cancel()
+		return
 	}
 
-	client := prx.NewClient(token, opts...)
 	data, err := client.PullRequestWithReferenceTime(ctx, owner, repo, prNumber, referenceTime)
 	if err != nil {
 		log.Printf("Failed to fetch PR data: %v", err)
 		cancel()
 		os.Exit(1) //nolint:gocritic // False positive: cancel() is called immediately before os.Exit()
 	}
+	if recorder != nil {
+		if err := recorder.Save(*record); err != nil {
+			log.Printf("Failed to save replay fixture: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+	}
+
+	if *actions {
+		ready, err := writeGitHubActions(os.Stdout, data, owner, repo, prNumber)
+		if err != nil {
+			log.Printf("Failed to write GitHub Actions output: %v", err)
+		}
+		cancel()
+		if err != nil || !ready {
+			os.Exit(1)
+		}
+		return
+	}
 
-	encoder := json.NewEncoder(os.Stdout)
-	if err := encoder.Encode(data); err != nil {
+	var out bytes.Buffer
+	name := fmt.Sprintf("%s-%s-%d.json", owner, repo, prNumber)
+	if *audit != "" {
+		if err := data.WriteAuditTrail(&out, prx.AuditFormat(*audit), owner, repo); err != nil {
+			log.Printf("Failed to write audit trail: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		name = fmt.Sprintf("%s-%s-%d-audit.%s", owner, repo, prNumber, *audit)
+	} else if eventFormat {
+		if err := writeEvents(&out, data.Events, *format, fields); err != nil {
+			log.Printf("Failed to write events: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		name = fmt.Sprintf("%s-%s-%d-events.%s", owner, repo, prNumber, *format)
+	} else if *format == "timeline" {
+		ref := fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)
+		if err := writeTimeline(&out, data, ref, time.Now(), !*gist && useColor(os.Stdout), locale); err != nil {
+			log.Printf("Failed to write timeline: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		name = fmt.Sprintf("%s-%s-%d-timeline.txt", owner, repo, prNumber)
+	} else if *format == "markdown" || *format == "html" {
+		ext := "md"
+		if *format == "html" {
+			ext = "html"
+		}
+		if err := writeReport(&out, &data.PullRequest, fmt.Sprintf("%s/%s#%d", owner, repo, prNumber), *format); err != nil {
+			log.Printf("Failed to write report: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		name = fmt.Sprintf("%s-%s-%d.%s", owner, repo, prNumber, ext)
+	} else if err := json.NewEncoder(&out).Encode(data); err != nil {
 		log.Printf("Failed to encode pull request: %v", err)
 		cancel()
 		os.Exit(1)
 	}
 
+	if *gist {
+		description := fmt.Sprintf("prx report for %s/%s#%d", owner, repo, prNumber)
+		gistURL, err := prx.NewGistSink(client, description).Upload(ctx, name, out.String())
+		if err != nil {
+			log.Printf("Failed to upload gist: %v", err)
+			cancel()
+			os.Exit(1)
+		}
+		fmt.Println(gistURL)
+	} else if _, err := out.WriteTo(os.Stdout); err != nil {
+		log.Printf("Failed to write output: %v", err)
+		cancel()
+		os.Exit(1)
+	}
+
 	cancel() // Ensure context is cancelled before exit
+	if reasons := failures(&data.PullRequest, failOn); len(reasons) > 0 {
+		writeFailures(os.Stderr, fmt.Sprintf("%s/%s#%d", owner, repo, prNumber), reasons)
+		os.Exit(1)
+	}
 }
 
-func githubToken() (string, error) {
-	cmd := exec.CommandContext(context.Background(), "gh", "auth", "token")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to run 'gh auth token': %w", err)
+// enableDebugLogging sends debug logs to stderr.
+func enableDebugLogging() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})))
+}
+
+// clientOptions returns the client options shared by all commands.
+func clientOptions(host string, debug, noCache bool) []prx.Option {
+	var opts []prx.Option
+	if debug {
+		opts = append(opts, prx.WithLogger(slog.Default()))
+	}
+	if host != githubHost {
+		// GitHub Enterprise Server serves its API under /api/v3 and /api/graphql
+		opts = append(opts, prx.WithBaseURL("https://"+host+"/api/v3", ""))
 	}
+	if noCache {
+		opts = append(opts, prx.WithCacheStore(null.New[string, prx.PullRequestData]()))
+	}
+	return opts
+}
 
-	token := strings.TrimSpace(string(output))
-	if token == "" {
-		return "", errors.New("no token returned by 'gh auth token'")
+// defaultConcurrency is how many pull requests commands fetch at once.
+const defaultConcurrency = 4
+
+// fetchPullRequests fetches the pull requests at urls as of refTime, up to concurrency at a time with
+// a client per host, logging and leaving out any that can't be fetched. The rest are returned in the
+// order of urls. It reports whether all of them were fetched.
+func fetchPullRequests(
+	ctx context.Context, urls []string, refTime time.Time, concurrency int, debug, noCache bool,
+) ([]prx.DigestPullRequest, bool) {
+	clients := make(map[string]*prx.Client) // By host; nil if there's no token for it
+	defer func() {
+		for _, client := range clients {
+			if client != nil {
+				client.Close() //nolint:errcheck,gosec // Nothing to do about cache close errors on exit
+			}
+		}
+	}()
+
+	results := make([]*prx.DigestPullRequest, len(urls))
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(concurrency, 1))
+	for i, prURL := range urls {
+		host, owner, repo, prNumber, err := parsePRURL(prURL)
+		if err != nil {
+			log.Printf("Invalid PR URL %s: %v", prURL, err)
+			failed.Store(true)
+			continue
+		}
+		client, known := clients[host]
+		if !known {
+			if token, err := githubToken(host); err != nil {
+				log.Printf("Failed to get GitHub token for %s: %v", host, err)
+			} else {
+				client = prx.NewClient(token, clientOptions(host, debug, noCache)...)
+			}
+			clients[host] = client
+		}
+		if client == nil {
+			failed.Store(true)
+			continue
+		}
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			data, err := client.PullRequestWithReferenceTime(ctx, owner, repo, prNumber, refTime)
+			if err != nil {
+				log.Printf("Failed to fetch %s: %v", prURL, err)
+				failed.Store(true)
+				return
+			}
+			results[i] = &prx.DigestPullRequest{Data: data, Owner: owner, Repo: repo, URL: prURL}
+		})
 	}
+	wg.Wait()
 
-	return token, nil
+	var prs []prx.DigestPullRequest
+	for _, pr := range results {
+		if pr != nil {
+			prs = append(prs, *pr)
+		}
+	}
+	return prs, !failed.Load()
 }
 
-//nolint:revive // function-result-limit: function needs all 4 return values
-func parsePRURL(prURL string) (owner, repo string, prNumber int, err error) {
+// parsePRURL parses a pull request URL on github.com or a GitHub Enterprise Server host.
+//
+//nolint:revive // function-result-limit: function needs all 5 return values
+func parsePRURL(prURL string) (host, owner, repo string, prNumber int, err error) {
 	u, err := url.Parse(prURL)
 	if err != nil {
-		return "", "", 0, err
+		return "", "", "", 0, err
 	}
 
-	if u.Host != "github.com" {
-		return "", "", 0, errors.New("not a GitHub URL")
+	if u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", "", "", 0, errors.New("not a GitHub URL")
+	}
+	host = strings.ToLower(u.Host)
+	if host == "www."+githubHost {
+		host = githubHost
 	}
 
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
 	if len(parts) != expectedURLParts || parts[pullPathIndex] != pullPathValue {
-		return "", "", 0, errors.New("invalid PR URL format")
+		return "", "", "", 0, errors.New("invalid PR URL format")
 	}
 
 	prNumber, err = strconv.Atoi(parts[3])
 	if err != nil {
-		return "", "", 0, fmt.Errorf("invalid PR number: %w", err)
+		return "", "", "", 0, fmt.Errorf("invalid PR number: %w", err)
 	}
 
-	return parts[0], parts[1], prNumber, nil
+	return host, parts[0], parts[1], prNumber, nil
 }
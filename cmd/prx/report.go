@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// writeReport renders a pull request's health report as markdown or as an HTML fragment: whether
+// it's ready to merge, its reviews and threads, and its failing checks. The markdown report is
+// also the GitHub Actions job summary.
+func writeReport(w io.Writer, pr *prx.PullRequest, ref, format string) error {
+	status := "Ready to merge"
+	if blocker := pr.MergeBlocker(); blocker != "" {
+		status = "Not ready to merge: " + blocker
+	}
+	rows := [][2]string{{"State", pr.State}, {"Merge state", pr.MergeableState}}
+	if pr.TestState != "" {
+		rows = append(rows, [2]string{"Tests", pr.TestState})
+	}
+	if a := pr.ApprovalSummary; a != nil {
+		rows = append(rows,
+			[2]string{"Approvals", strconv.Itoa(a.ApprovalsWithWriteAccess)},
+			[2]string{"Changes requested", strconv.Itoa(a.ChangesRequested)})
+	}
+	if t := pr.ThreadSummary; t != nil {
+		rows = append(rows, [2]string{"Unresolved threads", fmt.Sprintf("%d of %d", t.Unresolved, t.Total)})
+	}
+	var failing []string
+	if pr.CheckSummary != nil {
+		failing = slices.Sorted(maps.Keys(pr.CheckSummary.Failing))
+	}
+
+	var b strings.Builder
+	switch format {
+	case "markdown":
+		fmt.Fprintf(&b, "### %s: %s\n\n**%s**\n\n", ref, pr.Title, status)
+		b.WriteString("| | |\n|---|---|\n")
+		for _, row := range rows {
+			fmt.Fprintf(&b, "| %s | %s |\n", row[0], row[1])
+		}
+		if len(failing) > 0 {
+			b.WriteString("\n**Failing checks**\n\n")
+			for _, name := range failing {
+				fmt.Fprintf(&b, "- %s: %s\n", name, pr.CheckSummary.Failing[name])
+			}
+		}
+	case "html":
+		fmt.Fprintf(&b, "<h3>%s: %s</h3>\n<p><strong>%s</strong></p>\n<table>\n",
+			html.EscapeString(ref), html.EscapeString(pr.Title), html.EscapeString(status))
+		for _, row := range rows {
+			fmt.Fprintf(&b, "<tr><th>%s</th><td>%s</td></tr>\n", row[0], html.EscapeString(row[1]))
+		}
+		b.WriteString("</table>\n")
+		if len(failing) > 0 {
+			b.WriteString("<p><strong>Failing checks</strong></p>\n<ul>\n")
+			for _, name := range failing {
+				fmt.Fprintf(&b, "<li>%s: %s</li>\n", html.EscapeString(name), html.EscapeString(pr.CheckSummary.Failing[name]))
+			}
+			b.WriteString("</ul>\n")
+		}
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
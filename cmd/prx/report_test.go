@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+func TestWriteReport(t *testing.T) {
+	pr := &prx.PullRequest{
+		Title:           "Fix <script> handling",
+		State:           "open",
+		MergeableState:  "dirty",
+		ApprovalSummary: &prx.ApprovalSummary{ApprovalsWithWriteAccess: 1},
+		ThreadSummary:   &prx.ThreadSummary{Total: 3, Unresolved: 2},
+		CheckSummary:    &prx.CheckSummary{Failing: map[string]string{"lint": "2 errors"}},
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"markdown", []string{
+			"### owner/repo#1: Fix <script> handling\n",
+			"**Not ready to merge: merge conflicts with the base branch**\n",
+			"| Approvals | 1 |\n",
+			"| Unresolved threads | 2 of 3 |\n",
+			"- lint: 2 errors\n",
+		}},
+		{"html", []string{
+			"<h3>owner/repo#1: Fix &lt;script&gt; handling</h3>\n",
+			"<p><strong>Not ready to merge: merge conflicts with the base branch</strong></p>\n",
+			"<tr><th>Approvals</th><td>1</td></tr>\n",
+			"<tr><th>Unresolved threads</th><td>2 of 3</td></tr>\n",
+			"<li>lint: 2 errors</li>\n",
+		}},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := writeReport(&b, pr, "owner/repo#1", tt.format); err != nil {
+			t.Fatalf("writeReport(%s) error: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("writeReport(%s) missing %q in:\n%s", tt.format, want, b.String())
+			}
+		}
+	}
+	if err := writeReport(&strings.Builder{}, pr, "owner/repo#1", "json"); err == nil {
+		t.Error("writeReport(json) succeeded, want an error")
+	}
+}
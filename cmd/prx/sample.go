@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// runSample implements "prx sample": it writes synthetic pull requests as JSON lines, for demos and
+// benchmarks that shouldn't call the GitHub API, and returns the exit code.
+func runSample(args []string) int {
+	fs := flag.NewFlagSet("sample", flag.ExitOnError)
+	count := fs.Int("count", 100, "Number of pull requests")
+	seed := fs.Uint64("seed", 1, "Random seed; the same seed and flags always produce the same output")
+	end := fs.String("end", "", "Latest event time (RFC3339); defaults to 2025-01-01T00:00:00Z")
+	window := fs.Duration("window", 90*24*time.Hour, "Pull requests are created within this long before --end")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s sample [--count=N] [--seed=N] [--end=TIME] [--window=DURATION]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		fs.Usage()
+		return 1
+	}
+
+	opts := prx.SampleOptions{PullRequests: *count, Seed: *seed, Window: *window}
+	if *end != "" {
+		t, err := time.Parse(time.RFC3339, *end)
+		if err != nil {
+			log.Printf("Invalid end time (use RFC3339, e.g., 2025-03-16T06:18:08Z): %v", err)
+			return 1
+		}
+		opts.End = t
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, data := range prx.GenerateSample(opts) {
+		if err := encoder.Encode(data); err != nil {
+			log.Printf("Failed to write sample: %v", err)
+			return 1
+		}
+	}
+	return 0
+}
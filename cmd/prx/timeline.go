@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// ANSI styles for --format=timeline.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// maxTimelineSummary limits the body excerpt shown for each event.
+const maxTimelineSummary = 72
+
+// timelineStyle applies ANSI styles, or nothing if color is off.
+type timelineStyle bool
+
+func (color timelineStyle) apply(style, s string) string {
+	if !color || style == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// useColor reports whether to color output to f: only for terminals, and not if NO_COLOR is set.
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writeTimeline writes a chronological summary of the pull request's events, with times relative
+// to now, followed by a merge readiness panel. Times and counts are formatted for locale.
+func writeTimeline(w io.Writer, data *prx.PullRequestData, ref string, now time.Time, color bool, locale *prx.Locale) error {
+	style := timelineStyle(color)
+	pr := &data.PullRequest
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", style.apply(ansiBold, ref+" "+pr.Title))
+	fmt.Fprintf(&b, "%s opened %s\n\n", pr.Author, locale.Ago(now.Sub(pr.CreatedAt)))
+
+	for i := range data.Events {
+		b.WriteString(timelineLine(style, &data.Events[i], now, locale))
+	}
+
+	b.WriteString("\n" + style.apply(ansiBold, "Merge readiness") + "\n")
+	state := pr.State
+	if pr.Draft {
+		state += ", draft"
+	}
+	fmt.Fprintf(&b, "  %-12s %s\n", "State", state)
+	if pr.WaitingOn != "" {
+		fmt.Fprintf(&b, "  %-12s %s: %s\n", "Waiting on", pr.WaitingOn, pr.WaitingOnReason)
+	}
+	if cs := pr.CheckSummary; cs != nil {
+		checks := fmt.Sprintf("%s passing, %s failing, %s pending",
+			locale.Number(len(cs.Success)), locale.Number(len(cs.Failing)), locale.Number(len(cs.Pending)))
+		if len(cs.Failing) > 0 {
+			checks += " (" + strings.Join(slices.Sorted(maps.Keys(cs.Failing)), ", ") + ")"
+		}
+		fmt.Fprintf(&b, "  %-12s %s\n", "Checks", checks)
+	}
+	if a := pr.ApprovalSummary; a != nil {
+		fmt.Fprintf(&b, "  %-12s %s with write access, %s changes requested\n", "Approvals",
+			locale.Number(a.ApprovalsWithWriteAccess), locale.Number(a.ChangesRequested))
+	}
+	if t := pr.ThreadSummary; t != nil && t.Total > 0 {
+		fmt.Fprintf(&b, "  %-12s %s unresolved of %s\n", "Threads", locale.Number(t.Unresolved), locale.Number(t.Total))
+	}
+	switch blocker := pr.MergeBlocker(); {
+	case pr.Merged && pr.MergedAt != nil:
+		fmt.Fprintf(&b, "  %s\n", style.apply(ansiGreen, fmt.Sprintf("✓ Merged by %s %s", pr.MergedBy, locale.Ago(now.Sub(*pr.MergedAt)))))
+	case blocker == "":
+		fmt.Fprintf(&b, "  %s\n", style.apply(ansiGreen, "✓ Ready to merge"))
+	default:
+		fmt.Fprintf(&b, "  %s\n", style.apply(ansiRed, "✗ Not ready to merge: "+blocker))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// timelineLine formats an event as a line of the timeline.
+func timelineLine(style timelineStyle, e *prx.Event, now time.Time, locale *prx.Locale) string {
+	actor := e.Actor
+	if actor == "" {
+		actor = "-"
+	}
+	actorStyle := ""
+	if e.Bot {
+		actorStyle = ansiDim
+	}
+	detail := e.Outcome
+	if detail == "" {
+		detail = e.Target
+	}
+	line := fmt.Sprintf("%16s  %s %s %s %s",
+		locale.Ago(now.Sub(e.Timestamp)),
+		style.apply(actorStyle, pad(actor, 20)),
+		style.apply(ansiCyan, pad(string(e.Kind), 22)),
+		style.apply(outcomeStyle(e.Kind, e.Outcome), pad(detail, 18)),
+		timelineSummary(e))
+	return strings.TrimRight(line, " ") + "\n"
+}
+
+// outcomeStyle colors an event's outcome by whether it's good, bad, or still in progress.
+func outcomeStyle(kind prx.EventKind, outcome string) string {
+	switch {
+	case kind == prx.EventKindPRMerged, kind == prx.EventKindMerged:
+		return ansiGreen
+	case kind == prx.EventKindPRClosed, kind == prx.EventKindClosed:
+		return ansiRed
+	default:
+	}
+	switch outcome {
+	case "success", "approved":
+		return ansiGreen
+	case "failure", "error", "timed_out", "action_required", "cancelled", "changes_requested":
+		return ansiRed
+	case "pending", "queued", "in_progress", "waiting":
+		return ansiYellow
+	default:
+		return ""
+	}
+}
+
+// timelineSummary returns the first line of an event's body, or of its description, shortened.
+func timelineSummary(e *prx.Event) string {
+	s := e.Body
+	if e.Kind == prx.EventKindCommit || s == "" {
+		s = e.Description // Commit bodies are SHAs
+	}
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if utf8.RuneCountInString(s) > maxTimelineSummary {
+		s = string([]rune(s)[:maxTimelineSummary-1]) + "…"
+	}
+	return s
+}
+
+// pad pads or shortens s to width runes.
+func pad(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
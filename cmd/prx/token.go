@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tokenSources are the --token and --token-file flags, shared by all commands.
+type tokenSources struct {
+	token string
+	file  string
+}
+
+// tokens are where githubToken looks for a token before the environment and the gh CLI.
+var tokens tokenSources
+
+// register adds the --token and --token-file flags to fs.
+func (s *tokenSources) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.token, "token", "", "GitHub token, for every host; prefer --token-file or GH_TOKEN, which don't show up in ps")
+	fs.StringVar(&s.file, "token-file", "", "File containing the GitHub token, for every host")
+}
+
+// githubToken returns the token for host, from the first of: --token, --token-file, GH_TOKEN or
+// GITHUB_TOKEN (GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN for other hosts, as in the gh CLI),
+// and 'gh auth token'.
+func githubToken(host string) (string, error) {
+	if tokens.token != "" {
+		return tokens.token, nil
+	}
+	if tokens.file != "" {
+		b, err := os.ReadFile(tokens.file)
+		if err != nil {
+			return "", fmt.Errorf("reading token file: %w", err)
+		}
+		token := strings.TrimSpace(string(b))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", tokens.file)
+		}
+		return token, nil
+	}
+
+	vars := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if host != githubHost {
+		vars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, v := range vars {
+		if token := strings.TrimSpace(os.Getenv(v)); token != "" {
+			return token, nil
+		}
+	}
+
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", fmt.Errorf("no token for %s: use --token or --token-file, set %s, or install the gh CLI and run 'gh auth login'",
+			host, strings.Join(vars, " or "))
+	}
+	cmd := exec.CommandContext(context.Background(), "gh", "auth", "token", "--hostname", host)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run 'gh auth token': %w", err)
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", errors.New("no token returned by 'gh auth token'")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// defaultWatchInterval is how often --watch fetches the pull request.
+const defaultWatchInterval = 30 * time.Second
+
+// watchOptions configures following a pull request with --watch.
+type watchOptions struct {
+	client   *prx.Client
+	ref      string // OWNER/REPO#NUMBER, for timeline headers
+	owner    string
+	repo     string
+	format   string // json or ndjson for JSON lines, or timeline
+	fields   []string
+	number   int
+	locale   *prx.Locale
+	interval time.Duration
+	color    bool
+}
+
+// watchChange is a change to the pull request's state, as a JSON line.
+type watchChange struct {
+	Timestamp time.Time `json:"timestamp"`
+	Change    string    `json:"change"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+}
+
+// watchField is a part of the pull request's state that watch mode reports changes to.
+type watchField struct {
+	name  string
+	value string
+}
+
+// runWatch fetches the pull request every interval until interrupted. It writes what the pull request
+// looks like at first, then only new events and state changes. Fetch errors are logged and retried at
+// the next interval. It returns the exit code.
+func runWatch(opts watchOptions) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var seen map[string]bool // Keys of events already written; nil until the first fetch
+	var state []watchField
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		// A reference time of now bypasses cached data, but keeps its event sequence numbers
+		data, err := opts.client.PullRequestWithReferenceTime(fetchCtx, opts.owner, opts.repo, opts.number, time.Now())
+		cancel()
+		switch {
+		case ctx.Err() != nil:
+			return 0
+		case err != nil:
+			log.Printf("Failed to fetch PR data: %v", err)
+		case seen == nil:
+			seen = make(map[string]bool, len(data.Events))
+			for i := range data.Events {
+				seen[watchEventKey(&data.Events[i])] = true
+			}
+			state = watchFields(&data.PullRequest)
+			if err := writeWatchStart(os.Stdout, data, opts); err != nil {
+				log.Printf("Failed to write output: %v", err)
+				return 1
+			}
+		default:
+			var events []prx.Event
+			for i := range data.Events {
+				if k := watchEventKey(&data.Events[i]); !seen[k] {
+					seen[k] = true
+					events = append(events, data.Events[i])
+				}
+			}
+			current := watchFields(&data.PullRequest)
+			if err := writeWatchUpdate(os.Stdout, events, diffWatchFields(state, current, time.Now()), opts); err != nil {
+				log.Printf("Failed to write output: %v", err)
+				return 1
+			}
+			state = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchEventKey identifies an event across fetches. A check run that finishes has a new key, so
+// that its outcome is reported.
+func watchEventKey(e *prx.Event) string {
+	return strings.Join([]string{string(e.Kind), e.Timestamp.UTC().Format(time.RFC3339Nano), e.Actor, e.Target, e.Body, e.Outcome}, "\x00")
+}
+
+// watchFields returns the parts of the pull request's state that watch mode reports changes to.
+func watchFields(pr *prx.PullRequest) []watchField {
+	state := pr.State
+	if pr.Draft {
+		state += ", draft"
+	}
+	var checks string
+	if cs := pr.CheckSummary; cs != nil {
+		checks = fmt.Sprintf("%d passing, %d failing, %d pending", len(cs.Success), len(cs.Failing), len(cs.Pending))
+	}
+	return []watchField{
+		{"state", state},
+		{"title", pr.Title},
+		{"head_sha", pr.HeadSHA},
+		{"mergeable_state", pr.MergeableState},
+		{"checks", checks},
+		{"waiting_on", string(pr.WaitingOn)},
+		{"merge_blocker", pr.MergeBlocker()},
+	}
+}
+
+// diffWatchFields returns the changes from one state to the next.
+func diffWatchFields(from, to []watchField, now time.Time) []watchChange {
+	var changes []watchChange
+	for i := range to {
+		if i < len(from) && from[i].value != to[i].value {
+			changes = append(changes, watchChange{Timestamp: now, Change: to[i].name, From: from[i].value, To: to[i].value})
+		}
+	}
+	return changes
+}
+
+// writeWatchStart writes the pull request as first fetched: its timeline, or its events as JSON lines.
+func writeWatchStart(w io.Writer, data *prx.PullRequestData, opts watchOptions) error {
+	if opts.format == "timeline" {
+		return writeTimeline(w, data, opts.ref, time.Now(), opts.color, opts.locale)
+	}
+	return writeEvents(w, data.Events, "ndjson", opts.fields)
+}
+
+// writeWatchUpdate writes new events and state changes, as timeline lines or as JSON lines.
+func writeWatchUpdate(w io.Writer, events []prx.Event, changes []watchChange, opts watchOptions) error {
+	if opts.format != "timeline" {
+		if err := writeEvents(w, events, "ndjson", opts.fields); err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		for i := range changes {
+			if err := encoder.Encode(&changes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	style := timelineStyle(opts.color)
+	now := time.Now()
+	var b strings.Builder
+	for i := range events {
+		b.WriteString(timelineLine(style, &events[i], now, opts.locale))
+	}
+	for _, c := range changes {
+		from, to := c.From, c.To
+		if from == "" {
+			from = "-"
+		}
+		if to == "" {
+			to = "-"
+		}
+		fmt.Fprintf(&b, "%16s  %s %s → %s\n", opts.locale.Ago(now.Sub(c.Timestamp)), style.apply(ansiBold, c.Change+":"), from, to)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
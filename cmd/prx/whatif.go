@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// runWhatIf implements "prx whatif": it re-evaluates merged pull requests as if a proposed set of
+// checks had been required, writes how many would have been blocked and for how long as JSON, and
+// returns the exit code.
+func runWhatIf(args []string) int {
+	fs := flag.NewFlagSet("whatif", flag.ExitOnError)
+	checks := fs.String("checks", "", "Comma-separated names of the checks to require")
+	rulesetPath := fs.String("ruleset", "", "Ruleset JSON, as exported from GitHub, whose required status checks to add")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	noCache := fs.Bool("no-cache", false, "Disable caching")
+	tokens.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s whatif [--checks=NAME,...] [--ruleset=FILE] <pull-request-url>...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 || *checks == "" && *rulesetPath == "" {
+		fs.Usage()
+		return 1
+	}
+	if *debug {
+		enableDebugLogging()
+	}
+
+	required := splitList(*checks)
+	if *rulesetPath != "" {
+		data, err := os.ReadFile(*rulesetPath)
+		if err != nil {
+			log.Printf("Failed to read ruleset: %v", err)
+			return 1
+		}
+		fromRuleset, err := prx.RulesetRequiredChecks(data)
+		if err != nil {
+			log.Printf("Invalid ruleset: %v", err)
+			return 1
+		}
+		required = append(required, fromRuleset...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	fetched, ok := fetchPullRequests(ctx, fs.Args(), time.Now(), defaultConcurrency, *debug, *noCache)
+	prs := make([]*prx.PullRequestData, len(fetched))
+	for i := range fetched {
+		prs[i] = fetched[i].Data
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(prx.SimulateRequiredChecks(prs, required)); err != nil {
+		log.Printf("Failed to write simulation: %v", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
@@ -296,7 +296,7 @@ func compareEvents(restEvents, graphqlEvents []prx.Event) {
 func countEventsByType(events []prx.Event) map[string]int {
 	counts := make(map[string]int)
 	for i := range events {
-		counts[events[i].Kind]++
+		counts[string(events[i].Kind)]++
 	}
 	return counts
 }
@@ -304,7 +304,8 @@ func countEventsByType(events []prx.Event) map[string]int {
 func groupEventsByType(events []prx.Event) map[string][]prx.Event {
 	grouped := make(map[string][]prx.Event)
 	for i := range events {
-		grouped[events[i].Kind] = append(grouped[events[i].Kind], events[i])
+		kind := string(events[i].Kind)
+		grouped[kind] = append(grouped[kind], events[i])
 	}
 	return grouped
 }
@@ -0,0 +1,403 @@
+package prx
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Analytics derived from a pull request's events. These are computed on demand
+// from PullRequestData rather than stored, so they work equally on cached data.
+
+// ReviewerChurn counts review request changes for a single reviewer or team.
+type ReviewerChurn struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
+// ReviewRequestChurn summarizes how often review requests were added and removed.
+// High churn is a signal of review routing problems.
+type ReviewRequestChurn struct {
+	ByReviewer  map[string]ReviewerChurn `json:"by_reviewer,omitempty"`
+	Added       int                      `json:"added"`
+	Removed     int                      `json:"removed"`
+	Rerequested int                      `json:"rerequested"` // Requests for a reviewer whose earlier request was removed
+}
+
+// ReviewRequestChurn computes review request churn from the pull request's events.
+func (d *PullRequestData) ReviewRequestChurn() ReviewRequestChurn {
+	churn := ReviewRequestChurn{ByReviewer: make(map[string]ReviewerChurn)}
+	removed := make(map[string]bool)
+
+	for i := range d.Events {
+		e := &d.Events[i]
+		if e.Target == "" {
+			continue
+		}
+		rc := churn.ByReviewer[e.Target]
+		switch e.Kind {
+		case EventKindReviewRequested:
+			rc.Added++
+			churn.Added++
+			if removed[e.Target] {
+				churn.Rerequested++
+				removed[e.Target] = false
+			}
+		case EventKindReviewRequestRemoved:
+			rc.Removed++
+			churn.Removed++
+			removed[e.Target] = true
+		default:
+			continue
+		}
+		churn.ByReviewer[e.Target] = rc
+	}
+
+	return churn
+}
+
+// personalEmailDomains lists well-known consumer email providers.
+var personalEmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"outlook.com":    true,
+	"hotmail.com":    true,
+	"live.com":       true,
+	"yahoo.com":      true,
+	"icloud.com":     true,
+	"me.com":         true,
+	"proton.me":      true,
+	"protonmail.com": true,
+	"aol.com":        true,
+	"gmx.com":        true,
+	"qq.com":         true,
+	"163.com":        true,
+}
+
+// PersonalEmailDomains returns the commit email domains belonging to consumer email providers,
+// with their commit counts. It requires the client to be configured with WithCommitEmailDomains.
+func (d *PullRequestData) PersonalEmailDomains() map[string]int {
+	personal := make(map[string]int)
+	for domain, n := range d.PullRequest.CommitEmailDomains {
+		if personalEmailDomains[domain] {
+			personal[domain] = n
+		}
+	}
+	return personal
+}
+
+// SuggestionStats counts suggested changes in review comments and how they were taken up.
+type SuggestionStats struct {
+	Suggested int `json:"suggested"`
+	Applied   int `json:"applied"`  // Committed with GitHub's "Apply suggestion" button
+	Outdated  int `json:"outdated"` // Not applied directly, but the suggested lines changed later
+}
+
+// AdoptionRate returns the fraction of suggestions that were applied, or 0 without suggestions.
+func (s SuggestionStats) AdoptionRate() float64 {
+	if s.Suggested == 0 {
+		return 0
+	}
+	return float64(s.Applied) / float64(s.Suggested)
+}
+
+// SuggestionAdoption summarizes suggested changes by reviewer.
+type SuggestionAdoption struct {
+	ByReviewer map[string]SuggestionStats `json:"by_reviewer,omitempty"`
+	Total      SuggestionStats            `json:"total"`
+}
+
+// Add merges other into a, e.g. to report adoption across pull requests.
+func (a *SuggestionAdoption) Add(other SuggestionAdoption) {
+	if a.ByReviewer == nil {
+		a.ByReviewer = make(map[string]SuggestionStats)
+	}
+	for reviewer, o := range other.ByReviewer {
+		s := a.ByReviewer[reviewer]
+		s.Suggested += o.Suggested
+		s.Applied += o.Applied
+		s.Outdated += o.Outdated
+		a.ByReviewer[reviewer] = s
+	}
+	a.Total.Suggested += other.Total.Suggested
+	a.Total.Applied += other.Total.Applied
+	a.Total.Outdated += other.Total.Outdated
+}
+
+// SuggestionAdoption computes how often each reviewer's suggested changes were applied.
+// GitHub doesn't expose whether a suggestion was applied, so a suggestion counts as applied when
+// its lines have since changed (the comment is outdated) and a later commit has GitHub's default
+// "Apply suggestion(s) ..." message naming the reviewer, or the batch message that names no one.
+func (d *PullRequestData) SuggestionAdoption() SuggestionAdoption {
+	adoption := SuggestionAdoption{ByReviewer: make(map[string]SuggestionStats)}
+
+	var applyCommits []*Event
+	for i := range d.Events {
+		e := &d.Events[i]
+		if e.Kind == EventKindCommit && strings.HasPrefix(strings.ToLower(e.Description), "apply suggestion") {
+			applyCommits = append(applyCommits, e)
+		}
+	}
+
+	for i := range d.Events {
+		e := &d.Events[i]
+		if e.Kind != EventKindReviewComment || !e.Suggestion || e.Actor == "" {
+			continue
+		}
+		s := adoption.ByReviewer[e.Actor]
+		s.Suggested++
+		adoption.Total.Suggested++
+		switch {
+		case e.Outdated && slices.ContainsFunc(applyCommits, func(c *Event) bool { return appliesSuggestionBy(c, e) }):
+			s.Applied++
+			adoption.Total.Applied++
+		case e.Outdated:
+			s.Outdated++
+			adoption.Total.Outdated++
+		default:
+			// Suggested lines unchanged
+		}
+		adoption.ByReviewer[e.Actor] = s
+	}
+
+	return adoption
+}
+
+// appliesSuggestionBy reports whether an "Apply suggestion" commit could have applied comment:
+// it follows the comment and either names its author or names no one.
+func appliesSuggestionBy(commit, comment *Event) bool {
+	if commit.Timestamp.Before(comment.Timestamp) {
+		return false
+	}
+	subject, _, _ := strings.Cut(strings.ToLower(commit.Description), "\n")
+	return !strings.Contains(subject, "@") || slices.Contains(strings.Fields(subject), "@"+strings.ToLower(comment.Actor))
+}
+
+// ActivityBucket counts activity within one hour or day, starting at Start (UTC).
+type ActivityBucket struct {
+	Start    time.Time `json:"start"`
+	Comments int       `json:"comments,omitempty"` // Comments, reviews, and review comments
+	Commits  int       `json:"commits,omitempty"`
+	Checks   int       `json:"checks,omitempty"` // Check runs and status checks
+}
+
+// ActivityHistogram buckets pull request activity for rendering sparklines.
+// Only buckets with activity are included, in chronological order.
+type ActivityHistogram struct {
+	Hourly []ActivityBucket `json:"hourly,omitempty"`
+	Daily  []ActivityBucket `json:"daily,omitempty"`
+}
+
+// calculateActivityHistogram buckets comment, commit, and check events by hour and by day.
+func calculateActivityHistogram(events []Event) *ActivityHistogram {
+	return &ActivityHistogram{
+		Hourly: activityBuckets(events, time.Hour),
+		Daily:  activityBuckets(events, 24*time.Hour),
+	}
+}
+
+// activityBuckets groups events into buckets of the given size.
+func activityBuckets(events []Event, size time.Duration) []ActivityBucket {
+	byStart := make(map[time.Time]*ActivityBucket)
+	for i := range events {
+		e := &events[i]
+		if e.Timestamp.IsZero() {
+			continue
+		}
+		start := e.Timestamp.UTC().Truncate(size)
+		b, ok := byStart[start]
+		if !ok {
+			b = &ActivityBucket{Start: start}
+		}
+		switch e.Kind {
+		case EventKindComment, EventKindReview, EventKindReviewComment:
+			b.Comments++
+		case EventKindCommit:
+			b.Commits++
+		case EventKindCheckRun, EventKindStatusCheck:
+			b.Checks++
+		default:
+			continue
+		}
+		byStart[start] = b
+	}
+
+	buckets := make([]ActivityBucket, 0, len(byStart))
+	for _, b := range byStart {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+	return buckets
+}
+
+// PRRef identifies another pull request in the same repository, along with the
+// files it changes in common with the pull request it is attached to.
+type PRRef struct {
+	Owner  string   `json:"owner"`
+	Repo   string   `json:"repo"`
+	Files  []string `json:"files"` // Files changed by both pull requests, sorted
+	Number int      `json:"number"`
+}
+
+// FlagOverlappingPRs sets OverlappingPRs on each open pull request in prs that changes
+// files also changed by another open pull request in prs, flagging potential merge
+// conflicts or duplicated effort. All prs must belong to owner/repo, typically the
+// result of a repository sweep. Closed and merged pull requests are ignored.
+func FlagOverlappingPRs(owner, repo string, prs []*PullRequestData) {
+	byFile := make(map[string][]int) // path to indexes into prs
+	for i, d := range prs {
+		if d == nil {
+			continue
+		}
+		d.PullRequest.OverlappingPRs = nil
+		if d.PullRequest.State != "open" {
+			continue
+		}
+		for _, path := range d.PullRequest.Files {
+			byFile[path] = append(byFile[path], i)
+		}
+	}
+
+	shared := make(map[[2]int][]string) // pair of indexes to files changed by both
+	for path, idx := range byFile {
+		for a := range idx {
+			for b := a + 1; b < len(idx); b++ {
+				pair := [2]int{idx[a], idx[b]}
+				shared[pair] = append(shared[pair], path)
+			}
+		}
+	}
+
+	for pair, files := range shared {
+		sort.Strings(files)
+		for side := range 2 {
+			pr, other := prs[pair[side]], prs[pair[1-side]]
+			pr.PullRequest.OverlappingPRs = append(pr.PullRequest.OverlappingPRs, PRRef{
+				Owner:  owner,
+				Repo:   repo,
+				Number: other.PullRequest.Number,
+				Files:  slices.Clone(files),
+			})
+		}
+	}
+
+	for _, d := range prs {
+		if d == nil {
+			continue
+		}
+		sort.Slice(d.PullRequest.OverlappingPRs, func(i, j int) bool {
+			return d.PullRequest.OverlappingPRs[i].Number < d.PullRequest.OverlappingPRs[j].Number
+		})
+	}
+}
+
+// TimingSummary holds review latency metrics. Durations are measured from when the
+// pull request became ready for review, and are nil if the milestone hasn't happened.
+type TimingSummary struct {
+	TimeToFirstReview   *time.Duration `json:"time_to_first_review,omitempty"`
+	TimeToFirstApproval *time.Duration `json:"time_to_first_approval,omitempty"`
+	TimeInDraft         time.Duration  `json:"time_in_draft,omitempty"`     // Total time spent as a draft, up to the fetch time
+	ReviewIterations    int            `json:"review_iterations,omitempty"` // Changes requested followed by new commits
+}
+
+// calculateTimingSummary derives review latency metrics from chronologically sorted events.
+// Draft time for a pull request that is still a draft is counted up to now.
+func calculateTimingSummary(pr *PullRequest, events []Event, now time.Time) *TimingSummary {
+	summary := &TimingSummary{}
+
+	// A pull request opened as a draft has a ready_for_review before any convert_to_draft
+	draft := pr.Draft
+	for i := range events {
+		if events[i].Kind == EventKindReadyForReview || events[i].Kind == EventKindConvertToDraft {
+			draft = events[i].Kind == EventKindReadyForReview
+			break
+		}
+	}
+	draftSince := pr.CreatedAt
+	ready := pr.CreatedAt
+	if draft {
+		ready = time.Time{}
+	}
+
+	changesRequested := false
+	for i := range events {
+		e := &events[i]
+		switch e.Kind {
+		case EventKindConvertToDraft:
+			if !draft {
+				draft = true
+				draftSince = e.Timestamp
+			}
+		case EventKindReadyForReview:
+			if draft {
+				draft = false
+				summary.TimeInDraft += e.Timestamp.Sub(draftSince)
+				if ready.IsZero() {
+					ready = e.Timestamp
+				}
+			}
+		case EventKindReview:
+			if e.Actor == pr.Author || e.Bot || ready.IsZero() {
+				continue
+			}
+			if summary.TimeToFirstReview == nil {
+				d := e.Timestamp.Sub(ready)
+				summary.TimeToFirstReview = &d
+			}
+			if e.Outcome == string(ReviewStateApproved) && summary.TimeToFirstApproval == nil {
+				d := e.Timestamp.Sub(ready)
+				summary.TimeToFirstApproval = &d
+			}
+			if e.Outcome == string(ReviewStateChangesRequested) {
+				changesRequested = true
+			}
+		case EventKindCommit:
+			if changesRequested {
+				summary.ReviewIterations++
+				changesRequested = false
+			}
+		default:
+		}
+	}
+
+	if draft {
+		end := now
+		if pr.ClosedAt != nil {
+			end = *pr.ClosedAt
+		}
+		if end.After(draftSince) {
+			summary.TimeInDraft += end.Sub(draftSince)
+		}
+	}
+
+	return summary
+}
+
+// setLastActivity sets the pull request's latest human, author, and reviewer activity. Events by bots,
+// and check runs and status checks, which are reported by CI rather than people, are ignored.
+func setLastActivity(pr *PullRequest, events []Event) {
+	pr.LastHumanActivity, pr.LastAuthorActivity, pr.LastReviewerActivity = time.Time{}, time.Time{}, time.Time{}
+	for i := range events {
+		e := &events[i]
+		if e.Bot || e.Actor == "" || e.Kind == EventKindCheckRun || e.Kind == EventKindStatusCheck {
+			continue
+		}
+		if e.Timestamp.After(pr.LastHumanActivity) {
+			pr.LastHumanActivity = e.Timestamp
+		}
+		switch {
+		case e.Actor == pr.Author:
+			if e.Timestamp.After(pr.LastAuthorActivity) {
+				pr.LastAuthorActivity = e.Timestamp
+			}
+		case e.Kind == EventKindReview, e.Kind == EventKindReviewComment, e.Kind == EventKindComment:
+			if e.Timestamp.After(pr.LastReviewerActivity) {
+				pr.LastReviewerActivity = e.Timestamp
+			}
+		default:
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestReviewRequestChurn(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return t0.Add(time.Duration(h) * time.Hour) }
+
+	data := &PullRequestData{Events: []Event{
+		{Kind: EventKindReviewRequested, Timestamp: at(0), Target: "alice"},
+		{Kind: EventKindReviewRequested, Timestamp: at(0), Target: "platform-team"},
+		{Kind: EventKindReviewRequestRemoved, Timestamp: at(1), Target: "alice"},
+		{Kind: EventKindReviewRequested, Timestamp: at(2), Target: "bob"},
+		{Kind: EventKindReviewRequested, Timestamp: at(3), Target: "alice"},
+		{Kind: EventKindComment, Timestamp: at(4), Actor: "alice"},
+		{Kind: EventKindReviewRequested, Timestamp: at(5)}, // missing target is ignored
+	}}
+
+	churn := data.ReviewRequestChurn()
+	if churn.Added != 4 {
+		t.Errorf("Added = %d, want 4", churn.Added)
+	}
+	if churn.Removed != 1 {
+		t.Errorf("Removed = %d, want 1", churn.Removed)
+	}
+	if churn.Rerequested != 1 {
+		t.Errorf("Rerequested = %d, want 1", churn.Rerequested)
+	}
+	if got := churn.ByReviewer["alice"]; got != (ReviewerChurn{Added: 2, Removed: 1}) {
+		t.Errorf("alice churn = %+v", got)
+	}
+	if len(churn.ByReviewer) != 3 {
+		t.Errorf("expected 3 reviewers, got %v", churn.ByReviewer)
+	}
+}
+
+func TestReviewRequestChurn_NoEvents(t *testing.T) {
+	churn := (&PullRequestData{}).ReviewRequestChurn()
+	if churn.Added != 0 || churn.Removed != 0 || len(churn.ByReviewer) != 0 {
+		t.Errorf("expected empty churn, got %+v", churn)
+	}
+}
+
+// commitsFixture builds GraphQL PR data with one commit per author email.
+func commitsFixture(t *testing.T, emails ...string) *graphQLPullRequestComplete {
+	t.Helper()
+	var nodes []map[string]any
+	for _, email := range emails {
+		nodes = append(nodes, map[string]any{"commit": map[string]any{"author": map[string]any{"email": email}}})
+	}
+	raw, err := json.Marshal(map[string]any{"commits": map[string]any{"nodes": nodes}})
+	if err != nil {
+		t.Fatalf("marshaling fixture: %v", err)
+	}
+	var data graphQLPullRequestComplete
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("unmarshaling fixture: %v", err)
+	}
+	return &data
+}
+
+func TestSuggestionAdoption(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &PullRequestData{Events: []Event{
+		{Kind: EventKindReviewComment, Timestamp: t0, Actor: "alice", Suggestion: true, Outdated: true},
+		{Kind: EventKindReviewComment, Timestamp: t0, Actor: "alice", Suggestion: true},
+		{Kind: EventKindReviewComment, Timestamp: t0, Actor: "carol", Body: "nit: rename"},
+		{Kind: EventKindCommit, Timestamp: t0.Add(time.Hour), Description: "Apply suggestion from @alice\n\nCo-authored-by: alice"},
+		{Kind: EventKindReviewComment, Timestamp: t0.Add(2 * time.Hour), Actor: "carol", Suggestion: true, Outdated: true},
+		{Kind: EventKindCommit, Timestamp: t0.Add(3 * time.Hour), Description: "Apply suggestions from code review"},
+		{Kind: EventKindReviewComment, Timestamp: t0.Add(4 * time.Hour), Actor: "bob", Suggestion: true, Outdated: true},
+		{Kind: EventKindCommit, Timestamp: t0.Add(5 * time.Hour), Description: "Rework the parser"},
+	}}
+
+	got := data.SuggestionAdoption()
+	want := map[string]SuggestionStats{
+		"alice": {Suggested: 2, Applied: 1},
+		"carol": {Suggested: 1, Applied: 1},
+		"bob":   {Suggested: 1, Outdated: 1}, // Changed by a regular commit
+	}
+	if !maps.Equal(got.ByReviewer, want) {
+		t.Errorf("ByReviewer = %+v, want %+v", got.ByReviewer, want)
+	}
+	if got.Total != (SuggestionStats{Suggested: 4, Applied: 2, Outdated: 1}) {
+		t.Errorf("Total = %+v", got.Total)
+	}
+	if rate := got.ByReviewer["alice"].AdoptionRate(); rate != 0.5 {
+		t.Errorf("AdoptionRate() = %v, want 0.5", rate)
+	}
+
+	var across SuggestionAdoption
+	across.Add(got)
+	across.Add(got)
+	if across.ByReviewer["alice"].Applied != 2 || across.Total.Suggested != 8 {
+		t.Errorf("Add() = %+v", across)
+	}
+}
+
+func TestContainsSuggestion(t *testing.T) {
+	if !containsSuggestion("Maybe:\n```suggestion\nreturn nil\n```") {
+		t.Error("expected suggestion block to be detected")
+	}
+	if containsSuggestion("```go\nreturn nil\n```") || containsSuggestion("a suggestion") {
+		t.Error("expected plain code and prose not to be suggestions")
+	}
+}
+
+func TestCommitEmailDomains(t *testing.T) {
+	data := commitsFixture(t, "dev@Example.com", "dev@example.com", "me@gmail.com", "no-at-sign", "trailing@")
+
+	domains := commitEmailDomains(data)
+	want := map[string]int{"example.com": 2, "gmail.com": 1}
+	if !maps.Equal(domains, want) {
+		t.Errorf("commitEmailDomains() = %v, want %v", domains, want)
+	}
+
+	pr := &PullRequestData{PullRequest: PullRequest{CommitEmailDomains: domains}}
+	if got := pr.PersonalEmailDomains(); !maps.Equal(got, map[string]int{"gmail.com": 1}) {
+		t.Errorf("PersonalEmailDomains() = %v", got)
+	}
+}
+
+func TestWithCommitEmailDomains(t *testing.T) {
+	data := commitsFixture(t, "dev@corp.example")
+
+	off := NewClient("t", WithCacheStore(null.New[string, PullRequestData]()))
+	if pr := off.convertGraphQLToPullRequest(context.Background(), data, "o", "r"); pr.CommitEmailDomains != nil {
+		t.Errorf("expected no domains without opt-in, got %v", pr.CommitEmailDomains)
+	}
+
+	on := NewClient("t", WithCacheStore(null.New[string, PullRequestData]()), WithCommitEmailDomains())
+	if pr := on.convertGraphQLToPullRequest(context.Background(), data, "o", "r"); pr.CommitEmailDomains["corp.example"] != 1 {
+		t.Errorf("expected corp.example domain, got %v", pr.CommitEmailDomains)
+	}
+}
+
+func TestCalculateActivityHistogram(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC)
+	events := []Event{
+		{Kind: EventKindCommit, Timestamp: t0},
+		{Kind: EventKindComment, Timestamp: t0.Add(10 * time.Minute)},
+		{Kind: EventKindCheckRun, Timestamp: t0.Add(20 * time.Minute)},
+		{Kind: EventKindReview, Timestamp: t0.Add(2 * time.Hour)},
+		{Kind: EventKindLabeled, Timestamp: t0.Add(3 * time.Hour)}, // not counted
+		{Kind: EventKindStatusCheck, Timestamp: t0.Add(26 * time.Hour)},
+		{Kind: EventKindComment}, // zero timestamp ignored
+	}
+
+	h := calculateActivityHistogram(events)
+
+	wantHourly := []ActivityBucket{
+		{Start: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Commits: 1, Comments: 1, Checks: 1},
+		{Start: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Comments: 1},
+		{Start: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), Checks: 1},
+	}
+	if !slices.Equal(h.Hourly, wantHourly) {
+		t.Errorf("Hourly = %+v, want %+v", h.Hourly, wantHourly)
+	}
+
+	wantDaily := []ActivityBucket{
+		{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Commits: 1, Comments: 2, Checks: 1},
+		{Start: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Checks: 1},
+	}
+	if !slices.Equal(h.Daily, wantDaily) {
+		t.Errorf("Daily = %+v, want %+v", h.Daily, wantDaily)
+	}
+}
+
+func TestFlagOverlappingPRs(t *testing.T) {
+	pr := func(number int, state string, files ...string) *PullRequestData {
+		return &PullRequestData{PullRequest: PullRequest{Number: number, State: state, Files: files}}
+	}
+	prs := []*PullRequestData{
+		pr(1, "open", "a.go", "b.go", "README.md"),
+		pr(2, "open", "b.go", "a.go"),
+		pr(3, "open", "README.md", "c.go"),
+		pr(4, "open", "d.go"),
+		pr(5, "closed", "a.go"),
+	}
+
+	FlagOverlappingPRs("owner", "repo", prs)
+
+	want := map[int][]PRRef{
+		1: {
+			{Owner: "owner", Repo: "repo", Number: 2, Files: []string{"a.go", "b.go"}},
+			{Owner: "owner", Repo: "repo", Number: 3, Files: []string{"README.md"}},
+		},
+		2: {{Owner: "owner", Repo: "repo", Number: 1, Files: []string{"a.go", "b.go"}}},
+		3: {{Owner: "owner", Repo: "repo", Number: 1, Files: []string{"README.md"}}},
+	}
+	for _, d := range prs {
+		got := d.PullRequest.OverlappingPRs
+		w := want[d.PullRequest.Number]
+		if !slices.EqualFunc(got, w, func(a, b PRRef) bool {
+			return a.Owner == b.Owner && a.Repo == b.Repo && a.Number == b.Number && slices.Equal(a.Files, b.Files)
+		}) {
+			t.Errorf("PR #%d OverlappingPRs = %+v, want %+v", d.PullRequest.Number, got, w)
+		}
+	}
+}
+
+func TestCalculateTimingSummary(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	pr := &PullRequest{Author: "author", CreatedAt: t0}
+	events := []Event{
+		{Kind: EventKindPROpened, Timestamp: t0, Actor: "author"},
+		{Kind: EventKindReadyForReview, Timestamp: t0.Add(2 * time.Hour), Actor: "author"},
+		{Kind: EventKindReview, Timestamp: t0.Add(3 * time.Hour), Actor: "author", Outcome: "commented"}, // self-review ignored
+		{Kind: EventKindReview, Timestamp: t0.Add(4 * time.Hour), Actor: "bot[bot]", Bot: true, Outcome: "commented"},
+		{Kind: EventKindReview, Timestamp: t0.Add(5 * time.Hour), Actor: "alice", Outcome: "changes_requested"},
+		{Kind: EventKindCommit, Timestamp: t0.Add(6 * time.Hour), Actor: "author"},
+		{Kind: EventKindConvertToDraft, Timestamp: t0.Add(7 * time.Hour), Actor: "author"},
+		{Kind: EventKindReadyForReview, Timestamp: t0.Add(8 * time.Hour), Actor: "author"},
+		{Kind: EventKindReview, Timestamp: t0.Add(9 * time.Hour), Actor: "bob", Outcome: "changes_requested"},
+		{Kind: EventKindCommit, Timestamp: t0.Add(10 * time.Hour), Actor: "author"},
+		{Kind: EventKindCommit, Timestamp: t0.Add(11 * time.Hour), Actor: "author"},
+		{Kind: EventKindReview, Timestamp: t0.Add(12 * time.Hour), Actor: "alice", Outcome: "approved"},
+	}
+
+	got := calculateTimingSummary(pr, events, t0.Add(24*time.Hour))
+
+	if got.TimeToFirstReview == nil || *got.TimeToFirstReview != 3*time.Hour {
+		t.Errorf("TimeToFirstReview = %v, want 3h", got.TimeToFirstReview)
+	}
+	if got.TimeToFirstApproval == nil || *got.TimeToFirstApproval != 10*time.Hour {
+		t.Errorf("TimeToFirstApproval = %v, want 10h", got.TimeToFirstApproval)
+	}
+	if got.TimeInDraft != 3*time.Hour {
+		t.Errorf("TimeInDraft = %v, want 3h", got.TimeInDraft)
+	}
+	if got.ReviewIterations != 2 {
+		t.Errorf("ReviewIterations = %d, want 2", got.ReviewIterations)
+	}
+}
+
+func TestCalculateTimingSummary_StillDraft(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	pr := &PullRequest{Author: "author", CreatedAt: t0, Draft: true}
+	events := []Event{
+		{Kind: EventKindReview, Timestamp: t0.Add(time.Hour), Actor: "alice", Outcome: "commented"},
+	}
+
+	got := calculateTimingSummary(pr, events, t0.Add(5*time.Hour))
+
+	if got.TimeToFirstReview != nil {
+		t.Errorf("TimeToFirstReview = %v for a PR never ready for review, want nil", *got.TimeToFirstReview)
+	}
+	if got.TimeInDraft != 5*time.Hour {
+		t.Errorf("TimeInDraft = %v, want 5h", got.TimeInDraft)
+	}
+}
+
+func TestSetLastActivity(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	pr := &PullRequest{Author: "author"}
+	events := []Event{
+		{Kind: EventKindPROpened, Timestamp: t0, Actor: "author"},
+		{Kind: EventKindReview, Timestamp: t0.Add(1 * time.Hour), Actor: "alice", Outcome: "commented"},
+		{Kind: EventKindCommit, Timestamp: t0.Add(2 * time.Hour), Actor: "author"},
+		{Kind: EventKindLabeled, Timestamp: t0.Add(3 * time.Hour), Actor: "maintainer"},
+		{Kind: EventKindComment, Timestamp: t0.Add(4 * time.Hour), Actor: "dependabot[bot]", Bot: true},
+		{Kind: EventKindCheckRun, Timestamp: t0.Add(5 * time.Hour), Actor: "github-actions"},
+	}
+
+	setLastActivity(pr, events)
+
+	if !pr.LastHumanActivity.Equal(t0.Add(3 * time.Hour)) {
+		t.Errorf("LastHumanActivity = %v, want the label at +3h", pr.LastHumanActivity)
+	}
+	if !pr.LastAuthorActivity.Equal(t0.Add(2 * time.Hour)) {
+		t.Errorf("LastAuthorActivity = %v, want the commit at +2h", pr.LastAuthorActivity)
+	}
+	if !pr.LastReviewerActivity.Equal(t0.Add(1 * time.Hour)) {
+		t.Errorf("LastReviewerActivity = %v, want the review at +1h", pr.LastReviewerActivity)
+	}
+
+	setLastActivity(pr, events[4:])
+	if !pr.LastHumanActivity.IsZero() || !pr.LastAuthorActivity.IsZero() || !pr.LastReviewerActivity.IsZero() {
+		t.Errorf("activity from bots and checks = %v, %v, %v; want zero", pr.LastHumanActivity, pr.LastAuthorActivity, pr.LastReviewerActivity)
+	}
+}
@@ -0,0 +1,249 @@
+package prx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditFormat selects the encoding of an exported audit trail.
+type AuditFormat string
+
+// Audit trail formats understood by SIEMs.
+const (
+	AuditFormatCEF  AuditFormat = "cef"  // ArcSight Common Event Format, one event per line
+	AuditFormatOCSF AuditFormat = "ocsf" // OCSF 1.1 API Activity events, one JSON object per line
+)
+
+// AuditActionMergeBypass marks a merge without an approval or without passing required checks.
+const AuditActionMergeBypass = "merge_bypass"
+
+// auditVersion is reported as the product version in exported events.
+const auditVersion = "1.0"
+
+// AuditRecord is a code review governance event: an approval, change request or dismissal,
+// a force push or base change, a merge, or a merge that bypassed review or required checks.
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"` // Event kind, or AuditActionMergeBypass
+	Actor    string    `json:"actor"`
+	Target   string    `json:"target,omitempty"`
+	Outcome  string    `json:"outcome,omitempty"`
+	Message  string    `json:"message"`
+	Severity int       `json:"severity"` // 0 (lowest) to 10, as in CEF
+	Bot      bool      `json:"bot,omitempty"`
+}
+
+// auditKinds maps governance event kinds to their severity and description.
+var auditKinds = map[EventKind]struct {
+	message  string
+	severity int
+}{
+	EventKindReview:             {"Pull request reviewed", 3},
+	EventKindReviewDismissed:    {"Review dismissed", 6},
+	EventKindHeadRefForcePushed: {"Head branch force-pushed", 5},
+	EventKindBaseRefForcePushed: {"Base branch force-pushed", 7},
+	EventKindBaseRefChanged:     {"Base branch changed", 5},
+	EventKindAutoMergeEnabled:   {"Auto-merge enabled", 3},
+	EventKindAutoMergeDisabled:  {"Auto-merge disabled", 3},
+	EventKindPRMerged:           {"Pull request merged", 3},
+}
+
+// AuditTrail returns the governance events of the pull request in chronological order.
+// Reviews are included only when they approve or request changes. A merge without any prior
+// approval from someone other than the author, or while a required check wasn't passing,
+// is followed by an AuditActionMergeBypass record.
+func (d *PullRequestData) AuditTrail() []AuditRecord {
+	var records []AuditRecord
+	for i := range d.Events {
+		e := &d.Events[i]
+		kind, ok := auditKinds[e.Kind]
+		if !ok {
+			continue
+		}
+		if e.Kind == EventKindReview && e.Outcome != "approved" && e.Outcome != "changes_requested" {
+			continue
+		}
+		msg := kind.message
+		if e.Outcome != "" {
+			msg += ": " + e.Outcome
+		}
+		records = append(records, AuditRecord{
+			Time:     e.Timestamp,
+			Action:   string(e.Kind),
+			Actor:    e.Actor,
+			Target:   e.Target,
+			Outcome:  e.Outcome,
+			Message:  msg,
+			Severity: kind.severity,
+			Bot:      e.Bot,
+		})
+		if e.Kind == EventKindPRMerged {
+			if reason := d.mergeBypass(e.Timestamp); reason != "" {
+				records = append(records, AuditRecord{
+					Time:     e.Timestamp,
+					Action:   AuditActionMergeBypass,
+					Actor:    e.Actor,
+					Message:  reason,
+					Severity: 8,
+					Bot:      e.Bot,
+				})
+			}
+		}
+	}
+	slices.SortStableFunc(records, func(a, b AuditRecord) int { return a.Time.Compare(b.Time) })
+	return records
+}
+
+// mergeBypass describes how a merge at t bypassed review or required checks, or returns "".
+func (d *PullRequestData) mergeBypass(t time.Time) string {
+	approved := false
+	latest := make(map[string]*Event)
+	for i := range d.Events {
+		e := &d.Events[i]
+		if e.Timestamp.After(t) {
+			continue
+		}
+		switch e.Kind {
+		case EventKindReview:
+			if e.Outcome == "approved" && e.Actor != d.PullRequest.Author {
+				approved = true
+			}
+		case EventKindCheckRun, EventKindStatusCheck:
+			if e.Required && (latest[e.Body] == nil || e.Timestamp.After(latest[e.Body].Timestamp)) {
+				latest[e.Body] = e
+			}
+		default:
+		}
+	}
+
+	var reasons []string
+	if !approved {
+		reasons = append(reasons, "no approval")
+	}
+	var failing []string
+	for name, e := range latest {
+		switch e.Outcome {
+		case "success", "neutral", "skipped":
+		default:
+			failing = append(failing, name)
+		}
+	}
+	if len(failing) > 0 {
+		slices.Sort(failing)
+		reasons = append(reasons, "required checks not passing: "+strings.Join(failing, ", "))
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return "Merged with " + strings.Join(reasons, "; ")
+}
+
+// WriteAuditTrail writes the pull request's audit trail to w in format, one event per line.
+// Owner and repo identify the pull request in each event.
+func (d *PullRequestData) WriteAuditTrail(w io.Writer, format AuditFormat, owner, repo string) error {
+	resource := fmt.Sprintf("%s/%s#%d", owner, repo, d.PullRequest.Number)
+	for _, r := range d.AuditTrail() {
+		var line []byte
+		switch format {
+		case AuditFormatCEF:
+			line = []byte(cefLine(&r, owner, repo, d.PullRequest.Number))
+		case AuditFormatOCSF:
+			var err error
+			if line, err = json.Marshal(ocsfEvent(&r, resource)); err != nil {
+				return fmt.Errorf("encoding OCSF event: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown audit format %q", format)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("writing audit trail: %w", err)
+		}
+	}
+	return nil
+}
+
+// cefLine formats r as a CEF:0 event.
+func cefLine(r *AuditRecord, owner, repo string, number int) string {
+	ext := []string{
+		"rt=" + strconv.FormatInt(r.Time.UnixMilli(), 10),
+		"suser=" + cefValue(r.Actor),
+		"act=" + cefValue(r.Action),
+		"cs1Label=repository",
+		"cs1=" + cefValue(owner+"/"+repo),
+		"cn1Label=pullRequest",
+		"cn1=" + strconv.Itoa(number),
+		"msg=" + cefValue(r.Message),
+	}
+	if r.Target != "" {
+		ext = append(ext, "duser="+cefValue(r.Target))
+	}
+	if r.Outcome != "" {
+		ext = append(ext, "outcome="+cefValue(r.Outcome))
+	}
+	return strings.Join([]string{
+		"CEF:0", "codeGROOVE", "prx", auditVersion,
+		cefHeader(r.Action), cefHeader(r.Message), strconv.Itoa(r.Severity),
+		strings.Join(ext, " "),
+	}, "|")
+}
+
+// cefHeader escapes a CEF header field.
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r", " ", "\n", " ").Replace(s)
+}
+
+// cefValue escapes a CEF extension value.
+func cefValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// ocsfEvent maps r to an OCSF API Activity event, keeping prx-specific fields in unmapped.
+func ocsfEvent(r *AuditRecord, resource string) map[string]any {
+	const classUID, activityOther = 6003, 99
+	severityID := 1 // Informational
+	switch {
+	case r.Severity >= 9:
+		severityID = 5 // Critical
+	case r.Severity >= 7:
+		severityID = 4 // High
+	case r.Severity >= 5:
+		severityID = 3 // Medium
+	case r.Severity >= 4:
+		severityID = 2 // Low
+	default:
+	}
+	actorType := "User"
+	if r.Bot {
+		actorType = "Bot"
+	}
+	unmapped := map[string]any{"action": r.Action}
+	if r.Target != "" {
+		unmapped["target"] = r.Target
+	}
+	if r.Outcome != "" {
+		unmapped["outcome"] = r.Outcome
+	}
+	return map[string]any{
+		"class_uid":    classUID,
+		"category_uid": 6,
+		"activity_id":  activityOther,
+		"type_uid":     classUID*100 + activityOther,
+		"time":         r.Time.UnixMilli(),
+		"severity_id":  severityID,
+		"message":      r.Message,
+		"status_id":    1, // Success
+		"actor":        map[string]any{"user": map[string]any{"name": r.Actor, "type": actorType}},
+		"api":          map[string]any{"operation": r.Action},
+		"resources":    []map[string]any{{"type": "pull_request", "name": resource}},
+		"metadata": map[string]any{
+			"version": "1.1.0",
+			"product": map[string]any{"name": "prx", "vendor_name": "codeGROOVE", "version": auditVersion},
+		},
+		"unmapped": unmapped,
+	}
+}
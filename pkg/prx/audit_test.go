@@ -0,0 +1,82 @@
+package prx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPullRequestData_AuditTrail(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	data := &PullRequestData{
+		PullRequest: PullRequest{Number: 7, Author: "alice"},
+		Events: []Event{
+			{Kind: EventKindCommit, Timestamp: t0, Actor: "alice"},
+			{Kind: EventKindReview, Timestamp: t0.Add(time.Minute), Actor: "bob", Outcome: "commented"},
+			{Kind: EventKindHeadRefForcePushed, Timestamp: t0.Add(2 * time.Minute), Actor: "alice"},
+			{Kind: EventKindCheckRun, Timestamp: t0.Add(3 * time.Minute), Body: "ci/test", Outcome: "failure", Required: true},
+			{Kind: EventKindPRMerged, Timestamp: t0.Add(4 * time.Minute), Actor: "carol|admin"},
+			{Kind: EventKindReview, Timestamp: t0.Add(5 * time.Minute), Actor: "bob", Outcome: "approved"},
+		},
+	}
+
+	records := data.AuditTrail()
+	var actions []string
+	for _, r := range records {
+		actions = append(actions, r.Action)
+	}
+	want := []string{string(EventKindHeadRefForcePushed), string(EventKindPRMerged), AuditActionMergeBypass, string(EventKindReview)}
+	if strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Fatalf("AuditTrail() actions = %v, want %v", actions, want)
+	}
+	if msg := records[2].Message; msg != "Merged with no approval; required checks not passing: ci/test" {
+		t.Errorf("bypass message = %q", msg)
+	}
+
+	var cef bytes.Buffer
+	if err := data.WriteAuditTrail(&cef, AuditFormatCEF, "owner", "repo"); err != nil {
+		t.Fatalf("WriteAuditTrail(CEF) error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(cef.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d CEF lines, want 4", len(lines))
+	}
+	if !strings.HasPrefix(lines[2], "CEF:0|codeGROOVE|prx|1.0|merge_bypass|Merged with no approval") ||
+		!strings.Contains(lines[2], "|8|") || !strings.Contains(lines[2], `suser=carol|admin`) ||
+		!strings.Contains(lines[2], "cs1=owner/repo") {
+		t.Errorf("CEF bypass line = %q", lines[2])
+	}
+
+	var ocsf bytes.Buffer
+	if err := data.WriteAuditTrail(&ocsf, AuditFormatOCSF, "owner", "repo"); err != nil {
+		t.Fatalf("WriteAuditTrail(OCSF) error: %v", err)
+	}
+	var event struct {
+		Resources []struct {
+			Name string `json:"name"`
+		} `json:"resources"`
+		ClassUID   int `json:"class_uid"`
+		SeverityID int `json:"severity_id"`
+	}
+	if err := json.Unmarshal([]byte(strings.Split(ocsf.String(), "\n")[2]), &event); err != nil {
+		t.Fatalf("decoding OCSF event: %v", err)
+	}
+	if event.ClassUID != 6003 || event.SeverityID != 4 || len(event.Resources) != 1 || event.Resources[0].Name != "owner/repo#7" {
+		t.Errorf("OCSF bypass event = %+v", event)
+	}
+
+	if err := data.WriteAuditTrail(&ocsf, "xml", "owner", "repo"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestCEFEscaping(t *testing.T) {
+	if got := cefHeader(`a|b\c`); got != `a\|b\\c` {
+		t.Errorf("cefHeader() = %q", got)
+	}
+	if got := cefValue("k=v\nnext"); got != `k\=v\nnext` {
+		t.Errorf("cefValue() = %q", got)
+	}
+}
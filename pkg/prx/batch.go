@@ -0,0 +1,118 @@
+package prx
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BatchRequest is a pull request to fetch with PullRequests.
+type BatchRequest struct {
+	// Deadline, if set, bounds the fetch: it is canceled if still running then, and not started if
+	// the deadline passes first. It is also passed on through the fetch's context.
+	Deadline time.Time
+	Owner    string
+	Repo     string
+	Number   int
+	// Priority orders fetches, highest first, e.g. to fetch the pull requests a dashboard shows
+	// above the fold before the rest.
+	Priority int
+}
+
+// BatchResult is a pull request fetched by PullRequests, or why it couldn't be.
+type BatchResult struct {
+	Data  *PullRequestData
+	Err   error
+	Index int // Of the request
+}
+
+// PullRequests fetches pull requests as of refTime, up to concurrency at a time, yielding each as
+// it completes. Fetches start in order of priority, then of deadline, earliest first, and then in
+// the order given. Every request yields one result, including those canceled by ctx or their
+// deadline, unless the caller stops early, which cancels the fetches in flight.
+func (c *Client) PullRequests(ctx context.Context, requests []BatchRequest, refTime time.Time, concurrency int) iter.Seq[BatchResult] {
+	return func(yield func(BatchResult) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		order := make([]int, len(requests))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return compareBatchRequests(&requests[a], &requests[b])
+		})
+
+		results := make(chan BatchResult)
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		send := func(r BatchResult) {
+			select {
+			case results <- r:
+			case <-stop:
+			}
+		}
+		go func() {
+			defer close(results)
+			defer wg.Wait()
+			sem := make(chan struct{}, max(concurrency, 1))
+			for _, i := range order {
+				select {
+				case sem <- struct{}{}:
+				case <-stop:
+					return
+				}
+				r := &requests[i]
+				if !r.Deadline.IsZero() && !time.Now().Before(r.Deadline) {
+					<-sem
+					send(BatchResult{Index: i, Err: fmt.Errorf("%s/%s#%d: deadline passed before the fetch started: %w",
+						r.Owner, r.Repo, r.Number, context.DeadlineExceeded)})
+					continue
+				}
+				wg.Go(func() {
+					defer func() { <-sem }()
+					fetchCtx := ctx
+					if !r.Deadline.IsZero() {
+						var cancel context.CancelFunc
+						fetchCtx, cancel = context.WithDeadline(ctx, r.Deadline)
+						defer cancel()
+					}
+					data, err := c.PullRequestWithReferenceTime(fetchCtx, r.Owner, r.Repo, r.Number, refTime)
+					send(BatchResult{Index: i, Data: data, Err: err})
+				})
+			}
+		}()
+
+		for r := range results {
+			if !yield(r) {
+				close(stop)
+				cancel()
+				for range results { //nolint:revive // Wait for fetches in flight to wind down
+				}
+				return
+			}
+		}
+	}
+}
+
+// compareBatchRequests orders requests by priority, highest first, then by deadline, earliest
+// first, with requests without a deadline last.
+func compareBatchRequests(a, b *BatchRequest) int {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c
+	}
+	switch {
+	case a.Deadline.IsZero() && b.Deadline.IsZero():
+		return 0
+	case a.Deadline.IsZero():
+		return 1
+	case b.Deadline.IsZero():
+		return -1
+	default:
+		return a.Deadline.Compare(b.Deadline)
+	}
+}
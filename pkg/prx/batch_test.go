@@ -0,0 +1,99 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequests(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+			return
+		}
+		var req struct {
+			Variables struct {
+				Number int `json:"number"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		mu.Lock()
+		fetched = append(fetched, req.Variables.Number)
+		mu.Unlock()
+		if req.Variables.Number == 3 {
+			<-r.Context().Done() // Slower than its deadline
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"repository": {"pullRequest": {
+			"number": %d, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+			"headRef": {"target": {"oid": "abc123"}}}},
+			"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`, req.Variables.Number)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	now := time.Now()
+	requests := []BatchRequest{
+		{Owner: "o", Repo: "r", Number: 1},
+		{Owner: "o", Repo: "r", Number: 2, Priority: 10},
+		{Owner: "o", Repo: "r", Number: 3, Deadline: now.Add(200 * time.Millisecond)},
+		{Owner: "o", Repo: "r", Number: 4, Priority: 5},
+		{Owner: "o", Repo: "r", Number: 5, Deadline: now.Add(-time.Second)},
+	}
+	results := make(map[int]BatchResult)
+	for r := range client.PullRequests(context.Background(), requests, now, 1) {
+		if _, dup := results[r.Index]; dup {
+			t.Errorf("request %d yielded twice", r.Index)
+		}
+		results[r.Index] = r
+	}
+
+	if want := []int{2, 4, 3, 1}; !slices.Equal(fetched, want) {
+		t.Errorf("fetched %v, want %v: by priority, then deadline", fetched, want)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("got %d results, want %d", len(results), len(requests))
+	}
+	for _, i := range []int{0, 1, 3} {
+		if r := results[i]; r.Err != nil || r.Data == nil || r.Data.PullRequest.Number != requests[i].Number {
+			t.Errorf("result for #%d = %+v, want its pull request", requests[i].Number, r)
+		}
+	}
+	for _, i := range []int{2, 4} {
+		if err := results[i].Err; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("result for #%d: error = %v, want deadline exceeded", requests[i].Number, err)
+		}
+	}
+
+	// Stopping early stops fetching. A later reference time bypasses the cached results.
+	mu.Lock()
+	fetched = nil
+	mu.Unlock()
+	unbounded := []BatchRequest{requests[0], requests[1], requests[3]}
+	for range client.PullRequests(context.Background(), unbounded, now.Add(time.Hour), 1) {
+		break
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// The next fetch may have started before the first result was taken
+	if len(fetched) > 2 {
+		t.Errorf("fetched %v after stopping at the first result, want at most two fetches", fetched)
+	}
+}
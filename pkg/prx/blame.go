@@ -0,0 +1,202 @@
+package prx
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// blameBatchSize is how many files are blamed per GraphQL query; blame is expensive for GitHub to
+// compute, so batches are kept small.
+const blameBatchSize = 20
+
+// WithChangedLineBlame populates PullRequest.ChangedLineAuthors with who last changed each base line
+// the pull request modifies or removes, found with git blame at the merge base. Lines added to the
+// base aren't attributed to anyone. This costs two REST requests, one more per 100 changed files,
+// and a GraphQL query per 20 changed files, so it is off by default.
+func WithChangedLineBlame() Option {
+	return func(c *Client) {
+		c.changedLineBlame = true
+	}
+}
+
+// hunkHeader matches a unified diff hunk header, capturing the first line number on the old side.
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// removedLines returns the line numbers, on the old side of a unified diff, of the lines it removes.
+// A modified line appears in a diff as removed and added again, so these are the changed base lines.
+func removedLines(patch string) []int {
+	var lines []int
+	old := 0
+	for line := range strings.SplitSeq(patch, "\n") {
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			old, _ = strconv.Atoi(m[1]) // The pattern only matches digits
+			continue
+		}
+		switch {
+		case old == 0, strings.HasPrefix(line, "+"), strings.HasPrefix(line, `\`):
+			// Before the first hunk, an added line, or "\ No newline at end of file"
+		case strings.HasPrefix(line, "-"):
+			lines = append(lines, old)
+			old++
+		default:
+			old++
+		}
+	}
+	return lines
+}
+
+// blamedFile is a changed file whose removed base lines are to be blamed.
+type blamedFile struct {
+	path     string // Path in the pull request
+	basePath string // Path at the merge base, which differs for renamed files
+	lines    []int
+}
+
+// blameRange is a range of lines last changed by the same commit.
+type blameRange struct {
+	Commit struct {
+		Author struct {
+			User *struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		} `json:"author"`
+	} `json:"commit"`
+	StartingLine int `json:"startingLine"`
+	EndingLine   int `json:"endingLine"`
+}
+
+// fetchChangedLineAuthors blames the base lines each changed file modifies or removes, returning line
+// counts by author login for each file. Lines last changed by bots, or by commit authors without a
+// GitHub account, are left out.
+func (c *Client) fetchChangedLineAuthors(ctx context.Context, owner, repo string, prNumber int) (map[string]map[string]int, error) {
+	changed, err := c.github.PullRequestFiles(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var files []blamedFile
+	for _, f := range changed {
+		if f == nil || f.Status == "added" {
+			continue
+		}
+		if lines := removedLines(f.Patch); len(lines) > 0 {
+			files = append(files, blamedFile{path: f.Filename, basePath: cmp.Or(f.PreviousFilename, f.Filename), lines: lines})
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	base, err := c.github.MergeBase(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[string]map[string]int)
+	for start := 0; start < len(files); start += blameBatchSize {
+		batch := files[start:min(start+blameBatchSize, len(files))]
+		ranges, err := c.blame(ctx, owner, repo, base, batch)
+		if err != nil {
+			return authors, err
+		}
+		for i, f := range batch {
+			for _, line := range f.lines {
+				login := blameAuthor(ranges[i], line)
+				if login == "" || c.classifyBot(ctx, graphQLActor{Login: login}) {
+					continue
+				}
+				if authors[f.path] == nil {
+					authors[f.path] = make(map[string]int)
+				}
+				authors[f.path][login]++
+			}
+		}
+	}
+	return authors, nil
+}
+
+// blame fetches the blame of each file at commit base, returning the ranges of each in order.
+func (c *Client) blame(ctx context.Context, owner, repo, base string, files []blamedFile) ([][]blameRange, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Object map[string]struct {
+					Ranges []blameRange `json:"ranges"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []graphQLResponseError `json:"errors"`
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.basePath
+	}
+	query, variables := blameQuery(paths)
+	variables["owner"], variables["repo"], variables["oid"] = owner, repo, base
+	if err := c.github.GraphQL(ctx, query, variables, &resp); err != nil {
+		return nil, fmt.Errorf("blaming %s/%s@%s: %w", owner, repo, truncateSHA(base), err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("blaming %s/%s@%s: %w", owner, repo, truncateSHA(base), classifyGraphQLErrors(resp.Errors))
+	}
+	ranges := make([][]blameRange, len(files))
+	for i := range files {
+		ranges[i] = resp.Data.Repository.Object["f"+strconv.Itoa(i)].Ranges
+	}
+	return ranges, nil
+}
+
+// blameQuery builds a query blaming each of paths at a commit, aliased f0, f1, and so on.
+func blameQuery(paths []string) (string, map[string]any) {
+	variables := make(map[string]any, len(paths)+3)
+	var params, fields strings.Builder
+	for i, p := range paths {
+		fmt.Fprintf(&params, ", $p%d: String!", i)
+		fmt.Fprintf(&fields, `
+				f%d: blame(path: $p%d) {
+					...BlameFields
+				}`, i, i)
+		variables["p"+strconv.Itoa(i)] = p
+	}
+	query := fmt.Sprintf(`
+query($owner: String!, $repo: String!, $oid: GitObjectID!%s) {
+	repository(owner: $owner, name: $repo) {
+		object(oid: $oid) {
+			... on Commit {%s
+			}
+		}
+	}
+}
+
+fragment BlameFields on Blame {
+	ranges {
+		startingLine
+		endingLine
+		commit {
+			author {
+				user {
+					login
+				}
+			}
+		}
+	}
+}
+`, params.String(), fields.String())
+	return query, variables
+}
+
+// blameAuthor returns the login of who last changed line, or "" if it isn't known.
+func blameAuthor(ranges []blameRange, line int) string {
+	for i := range ranges {
+		r := &ranges[i]
+		if line >= r.StartingLine && line <= r.EndingLine {
+			if u := r.Commit.Author.User; u != nil {
+				return u.Login
+			}
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestRemovedLines(t *testing.T) {
+	patch := "@@ -3,4 +3,4 @@ func main() {\n" +
+		" a\n" +
+		"-b\n" +
+		"+B\n" +
+		" c\n" +
+		"-d\n" +
+		"\\ No newline at end of file\n" +
+		"@@ -20,2 +20,3 @@\n" +
+		" x\n" +
+		"+y\n" +
+		"-z"
+	if got, want := removedLines(patch), []int{4, 6, 21}; !slices.Equal(got, want) {
+		t.Errorf("removedLines() = %v, want %v", got, want)
+	}
+	if got := removedLines("@@ -0,0 +1,2 @@\n+new\n+file"); len(got) != 0 {
+		t.Errorf("removedLines() of an added file = %v, want none", got)
+	}
+}
+
+func TestClient_ChangedLineAuthors(t *testing.T) {
+	var blamed []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1/files":
+			w.Write([]byte(`[
+				{"filename": "main.go", "status": "modified", "patch": "@@ -1,3 +1,3 @@\n-a\n-b\n+c\n d\n-e"},
+				{"filename": "new.go", "status": "renamed", "previous_filename": "old.go", "patch": "@@ -10 +10 @@\n-x\n+y"},
+				{"filename": "added.go", "status": "added", "patch": "@@ -0,0 +1 @@\n+z"},
+				{"filename": "logo.png", "status": "modified"}
+			]`))
+		case "/repos/owner/repo/pulls/1":
+			w.Write([]byte(`{"base": {"sha": "base1"}, "head": {"sha": "head1"}}`))
+		case "/repos/owner/repo/compare/base1...head1":
+			w.Write([]byte(`{"merge_base_commit": {"sha": "fork1"}}`))
+		case "/graphql":
+			var req struct {
+				Variables map[string]any `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Variables["oid"] != "fork1" {
+				t.Errorf("blamed at %v, want the merge base", req.Variables["oid"])
+			}
+			blamed = append(blamed, req.Variables["p0"], req.Variables["p1"])
+			w.Write([]byte(`{"data": {"repository": {"object": {
+				"f0": {"ranges": [
+					{"startingLine": 1, "endingLine": 1, "commit": {"author": {"user": {"login": "alice"}}}},
+					{"startingLine": 2, "endingLine": 2, "commit": {"author": {"user": {"login": "renovate[bot]"}}}},
+					{"startingLine": 3, "endingLine": 5, "commit": {"author": {"user": null}}}
+				]},
+				"f1": {"ranges": [{"startingLine": 1, "endingLine": 12, "commit": {"author": {"user": {"login": "bob"}}}}]}
+			}}}}`))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithChangedLineBlame())
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	authors, err := client.fetchChangedLineAuthors(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("fetchChangedLineAuthors() error: %v", err)
+	}
+	if want := []any{"main.go", "old.go"}; !slices.Equal(blamed, want) {
+		t.Errorf("blamed %v, want %v", blamed, want)
+	}
+	// Line 2 was last changed by a bot, and line 3 by a commit author without an account
+	want := map[string]map[string]int{"main.go": {"alice": 1}, "new.go": {"bob": 1}}
+	if !maps.EqualFunc(authors, want, maps.Equal) {
+		t.Errorf("fetchChangedLineAuthors() = %v, want %v", authors, want)
+	}
+}
@@ -0,0 +1,199 @@
+package prx
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido"
+	"github.com/codeGROOVE-dev/fido/pkg/store/localfs"
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+// botDecisionsCacheTTL is how long bot classifications are reused; logins rarely change type.
+const botDecisionsCacheTTL = 30 * 24 * time.Hour
+
+// Sources of a bot classification, in increasing order of confidence.
+const (
+	BotSourceLogin    = "login"    // Heuristics on the login and node ID
+	BotSourceTypename = "typename" // GraphQL __typename or webhook sender type
+	BotSourceOverride = "override" // Set with WithBotOverrides
+)
+
+// ActorType is the kind of account behind an event.
+type ActorType string
+
+// Actor types.
+const (
+	ActorTypeHuman       ActorType = "human"
+	ActorTypeGitHubApp   ActorType = "github_app"   // A GitHub App's bot account, such as dependabot[bot]
+	ActorTypeMachineUser ActorType = "machine_user" // A user account run by automation; see WithMachineUsers
+	ActorTypeDeployKey   ActorType = "deploy_key"   // A push made without an account, as with a deploy key
+	// An account that no longer exists, shown by GitHub as "ghost"
+	ActorTypeGhost ActorType = "ghost"
+	// A GitHub App whose installation was suspended or removed, so that GitHub no longer reports its account
+	ActorTypeSuspendedApp ActorType = "suspended_app"
+)
+
+// ghostLogin is the placeholder login GitHub shows for accounts that no longer exist.
+const ghostLogin = "ghost"
+
+// BotDecision records how a login was classified as a bot or a human.
+type BotDecision struct {
+	DecidedAt time.Time `json:"decided_at"`
+	Source    string    `json:"source"`
+	Bot       bool      `json:"bot"`
+}
+
+// BotStore is the interface for bot classification cache storage backends.
+// This is an alias for fido.Store with the appropriate type parameters.
+type BotStore = fido.Store[string, BotDecision]
+
+// WithBotCacheStore sets the store that persists bot classifications across pull requests and runs.
+// By default they are persisted next to the default PR cache, or kept in memory when WithCacheStore is used.
+func WithBotCacheStore(store BotStore) Option {
+	return func(c *Client) {
+		cache, err := fido.NewTiered(store, fido.TTL(botDecisionsCacheTTL))
+		if err != nil {
+			c.logger.Warn("failed to create bot cache from store, using default", "error", err)
+			return
+		}
+		c.botCache = cache
+	}
+}
+
+// WithBotOverrides forces the classification of the given logins, e.g. for a human whose login
+// ends in "bot", or a service account that doesn't look like one. Logins are case-insensitive.
+func WithBotOverrides(overrides map[string]bool) Option {
+	return func(c *Client) {
+		c.botOverrides = make(map[string]bool, len(overrides))
+		for login, bot := range overrides {
+			c.botOverrides[strings.ToLower(login)] = bot
+		}
+	}
+}
+
+// WithMachineUsers lists service accounts: user accounts run by automation whose logins don't look like
+// bots, such as an organization's release or CI account. Their events have ActorTypeMachineUser and are
+// classified as bots, and their approvals don't count toward ApprovalSummary. Logins are case-insensitive;
+// the option can be repeated to combine lists from several organizations.
+func WithMachineUsers(logins ...string) Option {
+	return func(c *Client) {
+		if c.machineUsers == nil {
+			c.machineUsers = make(map[string]bool, len(logins))
+		}
+		for _, login := range logins {
+			c.machineUsers[strings.ToLower(login)] = true
+		}
+	}
+}
+
+// BotDecision returns how login was classified, if it has been seen or is overridden.
+func (c *Client) BotDecision(ctx context.Context, login string) (BotDecision, bool) {
+	key := strings.ToLower(login)
+	if bot, ok := c.botOverrides[key]; ok {
+		return BotDecision{Bot: bot, Source: BotSourceOverride}, true
+	}
+	if c.machineUsers[key] {
+		return BotDecision{Bot: true, Source: BotSourceOverride}, true
+	}
+	if c.botCache == nil {
+		return BotDecision{}, false
+	}
+	decision, found, err := c.botCache.Get(ctx, key)
+	if err != nil {
+		c.logger.WarnContext(ctx, "bot cache get error", "error", err)
+		return BotDecision{}, false
+	}
+	return decision, found
+}
+
+// classifyBot reports whether actor is a bot, reading through the bot classification cache.
+// A cached heuristic decision is replaced once the actor's type is known.
+func (c *Client) classifyBot(ctx context.Context, actor graphQLActor) bool {
+	if actor.Login == "" {
+		return false
+	}
+	cached, found := c.BotDecision(ctx, actor.Login)
+	if found && (cached.Source != BotSourceLogin || actor.Type == "") {
+		return cached.Bot
+	}
+
+	decision := BotDecision{Bot: isBot(actor), Source: BotSourceLogin, DecidedAt: time.Now()}
+	if actor.Type != "" {
+		decision.Source = BotSourceTypename
+	}
+	if found && cached.Bot == decision.Bot && cached.Source == decision.Source {
+		return decision.Bot
+	}
+	if c.botCache != nil {
+		if err := c.botCache.Set(ctx, strings.ToLower(actor.Login), decision); err != nil {
+			c.logger.WarnContext(ctx, "bot cache set error", "error", err)
+		}
+	}
+	return decision.Bot
+}
+
+// classifyActor determines the kind of account behind actor. Bots are GitHub Apps when GitHub says so,
+// or their login has the "[bot]" suffix apps get in REST responses, and machine users otherwise.
+func (c *Client) classifyActor(ctx context.Context, actor graphQLActor) ActorType {
+	switch {
+	case actor.Login == "":
+		return ""
+	case actor.Login == ghostLogin:
+		return ActorTypeGhost
+	case c.machineUsers[strings.ToLower(actor.Login)]:
+		return ActorTypeMachineUser
+	case !c.classifyBot(ctx, actor):
+		return ActorTypeHuman
+	case actor.Type == "Bot", strings.HasSuffix(actor.Login, "[bot]"):
+		return ActorTypeGitHubApp
+	default:
+		return ActorTypeMachineUser
+	}
+}
+
+// isGhost reports whether actor is missing, or is GitHub's placeholder for an account that no longer exists.
+func isGhost(actor graphQLActor) bool {
+	return actor.Login == "" || actor.Login == ghostLogin
+}
+
+// attributeGhost sets the actor of an event whose account no longer exists. GitHub still names the app
+// behind events by GitHub Apps whose installation was suspended or removed; given its slug, the event is
+// attributed to the app's bot account. Otherwise it's attributed to the "ghost" placeholder.
+func attributeGhost(e *Event, appSlug string) {
+	if appSlug != "" {
+		e.Actor, e.ActorType, e.Bot = appSlug+"[bot]", ActorTypeSuspendedApp, true
+		return
+	}
+	e.Actor, e.ActorType, e.Bot = ghostLogin, ActorTypeGhost, false
+}
+
+// createDefaultTieredCache creates a cache for data shared across pull requests, persisted to disk
+// under prefix unless inMemory is set. The name describes the cached data in log messages.
+func createDefaultTieredCache[V any](log *slog.Logger, name, prefix string, ttl time.Duration, inMemory bool) *fido.TieredCache[string, V] {
+	var store fido.Store[string, V] = null.New[string, V]()
+	if !inMemory {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		dir = filepath.Join(dir, "prx")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			log.Warn("failed to create cache directory, keeping "+name+" in memory", "error", err)
+		} else if s, err := localfs.New[string, V](prefix, dir); err != nil {
+			log.Warn("failed to create "+name+" cache store, keeping "+name+" in memory", "error", err)
+		} else {
+			store = s
+		}
+	}
+	cache, err := fido.NewTiered(store, fido.TTL(ttl))
+	if err != nil {
+		log.Warn("failed to create "+name+" cache, "+name+" won't be cached", "error", err)
+		return nil
+	}
+	return cache
+}
@@ -0,0 +1,189 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/localfs"
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_ClassifyBot(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithBotOverrides(map[string]bool{"Abbot": false, "deploy-svc": true}),
+	)
+
+	// Overrides win over heuristics and type evidence.
+	if client.classifyBot(ctx, graphQLActor{Login: "abbot"}) {
+		t.Error("expected overridden human not to be a bot")
+	}
+	if !client.classifyBot(ctx, graphQLActor{Login: "deploy-svc", Type: "User"}) {
+		t.Error("expected overridden service account to be a bot")
+	}
+	if d, ok := client.BotDecision(ctx, "ABBOT"); !ok || d.Source != BotSourceOverride || d.Bot {
+		t.Errorf("BotDecision(ABBOT) = %+v, %v; want human override", d, ok)
+	}
+
+	// Heuristic decisions are cached...
+	if client.classifyBot(ctx, graphQLActor{Login: "release-app"}) {
+		t.Error("expected release-app to be classified as human by login")
+	}
+	if d, ok := client.BotDecision(ctx, "release-app"); !ok || d.Source != BotSourceLogin || d.Bot || d.DecidedAt.IsZero() {
+		t.Errorf("BotDecision(release-app) = %+v, %v; want login heuristic", d, ok)
+	}
+	// ...until type evidence replaces them...
+	if !client.classifyBot(ctx, graphQLActor{Login: "release-app", Type: "Bot"}) {
+		t.Error("expected __typename Bot to override the login heuristic")
+	}
+	if d, _ := client.BotDecision(ctx, "release-app"); d.Source != BotSourceTypename || !d.Bot {
+		t.Errorf("BotDecision(release-app) = %+v, want typename bot", d)
+	}
+	// ...which then sticks for appearances without type evidence.
+	if !client.classifyBot(ctx, graphQLActor{Login: "release-app"}) {
+		t.Error("expected cached typename decision to be reused")
+	}
+
+	if _, ok := client.BotDecision(ctx, "never-seen"); ok {
+		t.Error("expected no decision for unseen login")
+	}
+}
+
+func TestClient_ClassifyActor(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithMachineUsers("Release-Svc"),
+		WithMachineUsers("deployer"),
+	)
+
+	tests := []struct {
+		actor graphQLActor
+		want  ActorType
+	}{
+		{graphQLActor{Login: "octocat", Type: "User"}, ActorTypeHuman},
+		{graphQLActor{Login: "dependabot", Type: "Bot"}, ActorTypeGitHubApp},
+		{graphQLActor{Login: "renovate[bot]"}, ActorTypeGitHubApp},
+		{graphQLActor{Login: "k8s-ci-robot", Type: "User"}, ActorTypeMachineUser}, // Looks like a bot but isn't an app
+		{graphQLActor{Login: "release-svc", Type: "User"}, ActorTypeMachineUser},
+		{graphQLActor{Login: "deployer"}, ActorTypeMachineUser},
+		{graphQLActor{}, ""},
+	}
+	for _, tt := range tests {
+		if got := client.classifyActor(ctx, tt.actor); got != tt.want {
+			t.Errorf("classifyActor(%+v) = %q, want %q", tt.actor, got, tt.want)
+		}
+	}
+	if !client.classifyBot(ctx, graphQLActor{Login: "release-svc", Type: "User"}) {
+		t.Error("expected machine user to be a bot")
+	}
+
+	summary := calculateApprovalSummary([]Event{
+		{Kind: EventKindReview, Actor: "octocat", ActorType: ActorTypeHuman, Outcome: "approved", WriteAccess: WriteAccessDefinitely},
+		{Kind: EventKindReview, Actor: "release-svc", ActorType: ActorTypeMachineUser, Outcome: "approved", WriteAccess: WriteAccessDefinitely},
+		{Kind: EventKindReview, Actor: "dependabot", ActorType: ActorTypeGitHubApp, Outcome: "approved", WriteAccess: WriteAccessDefinitely},
+	}, "")
+	if summary.ApprovalsWithWriteAccess != 1 {
+		t.Errorf("ApprovalsWithWriteAccess = %d, want automated approvals ignored", summary.ApprovalsWithWriteAccess)
+	}
+
+	event := client.parseGraphQLTimelineEvent(ctx, map[string]any{
+		"__typename": "HeadRefForcePushedEvent",
+		"createdAt":  "2025-01-01T00:00:00Z",
+		"actor":      nil,
+	}, "owner", "repo")
+	if event == nil || event.ActorType != ActorTypeDeployKey {
+		t.Errorf("force push without an actor = %+v, want deploy key", event)
+	}
+}
+
+func TestWithBotCacheStore_Persists(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	newClient := func() *Client {
+		store, err := localfs.New[string, BotDecision]("prx-bot", dir)
+		if err != nil {
+			t.Fatalf("creating store: %v", err)
+		}
+		return NewClient("token", WithCacheStore(null.New[string, PullRequestData]()), WithBotCacheStore(store))
+	}
+
+	first := newClient()
+	if !first.classifyBot(ctx, graphQLActor{Login: "helper", Type: "Bot"}) {
+		t.Fatal("expected helper to be classified as a bot by type")
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	second := newClient()
+	if d, ok := second.BotDecision(ctx, "helper"); !ok || !d.Bot || d.Source != BotSourceTypename {
+		t.Errorf("BotDecision(helper) = %+v, %v; want persisted typename bot", d, ok)
+	}
+}
+
+func TestClient_GhostActors(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("token", WithCacheStore(null.New[string, PullRequestData]()))
+
+	if got := client.classifyActor(ctx, graphQLActor{Login: "ghost", Type: "User"}); got != ActorTypeGhost {
+		t.Errorf("classifyActor(ghost) = %q, want %q", got, ActorTypeGhost)
+	}
+
+	event := client.parseGraphQLTimelineEvent(ctx, map[string]any{
+		"__typename": "LabeledEvent",
+		"createdAt":  "2025-01-01T00:00:00Z",
+		"actor":      nil,
+		"label":      map[string]any{"name": "bug"},
+	}, "owner", "repo")
+	if event == nil || event.Actor != "ghost" || event.ActorType != ActorTypeGhost || event.Bot {
+		t.Errorf("label without an actor = %+v, want ghost", event)
+	}
+
+	tests := []struct {
+		name      string
+		comment   string
+		wantActor string
+		wantType  ActorType
+		wantBot   bool
+	}{
+		{
+			name:      "suspended app",
+			comment:   `{"created_at":"2025-01-01T11:00:00Z","user":null,"body":"Coverage: 81%","performed_via_github_app":{"slug":"codecov","name":"Codecov"}}`,
+			wantActor: "codecov[bot]",
+			wantType:  ActorTypeSuspendedApp,
+			wantBot:   true,
+		},
+		{
+			name:      "deleted user",
+			comment:   `{"created_at":"2025-01-01T11:00:00Z","user":{"login":"ghost","type":"User"},"body":"LGTM"}`,
+			wantActor: "ghost",
+			wantType:  ActorTypeGhost,
+		},
+		{
+			name:      "app with an account",
+			comment:   `{"created_at":"2025-01-01T11:00:00Z","user":{"login":"codecov[bot]","type":"Bot"},"body":"Coverage: 81%","performed_via_github_app":{"slug":"codecov"}}`,
+			wantActor: "codecov[bot]",
+			wantType:  ActorTypeGitHubApp,
+			wantBot:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hook webhookPayload
+			payload := `{"action":"created","repository":{"name":"repo","owner":{"login":"owner"}},"issue":{"number":7,"pull_request":{}},"comment":` + tt.comment + `}`
+			if err := json.Unmarshal([]byte(payload), &hook); err != nil {
+				t.Fatal(err)
+			}
+			events := client.webhookEvents(ctx, "issue_comment", &hook, prRef{owner: "owner", repo: "repo", number: 7})
+			if len(events) != 1 {
+				t.Fatalf("webhookEvents() = %+v, want one comment", events)
+			}
+			if e := events[0]; e.Actor != tt.wantActor || e.ActorType != tt.wantType || e.Bot != tt.wantBot {
+				t.Errorf("comment actor = %q, %q, bot %v; want %q, %q, bot %v", e.Actor, e.ActorType, e.Bot, tt.wantActor, tt.wantType, tt.wantBot)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoPullRequest is returned by PullRequestForBranch when no pull request has the branch as its head.
+var ErrNoPullRequest = errors.New("no pull request for branch")
+
+// ticketRefPattern matches issue tracker keys such as Jira's "PROJ-123". Only upper-case keys match,
+// so words in branch names such as "patch-1" or "release-2024" aren't taken for keys.
+var ticketRefPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-[1-9][0-9]{0,6}\b`)
+
+// WithTicketPrefixes limits the issue tracker keys found in head branch names to those of the given
+// projects, such as "PROJ" or "ENG", matched in any case. Use it for trackers whose branch names have
+// lower-case keys, such as Linear's "eng-42-fix-cache".
+func WithTicketPrefixes(prefixes ...string) Option {
+	return func(c *Client) {
+		if len(prefixes) == 0 {
+			return
+		}
+		quoted := make([]string, len(prefixes))
+		for i, p := range prefixes {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		c.ticketPattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)-[1-9][0-9]{0,6}\b`)
+	}
+}
+
+// WithBranchPatterns enables validation of head branch names against the given glob patterns
+// (e.g. "feature/*", "fix/*"), as matched by path.Match. PullRequest.BranchNameValid reports
+// whether the branch matched any of them.
+func WithBranchPatterns(patterns ...string) Option {
+	return func(c *Client) {
+		c.branchPatterns = patterns
+	}
+}
+
+// branchNameValid reports whether branch matches any of patterns.
+func branchNameValid(branch string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, branch); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ticketRefs extracts issue tracker keys matching pattern, or ticketRefPattern if it's nil, from a
+// branch name, upper-cased and in order of appearance.
+func ticketRefs(branch string, pattern *regexp.Regexp) []string {
+	if pattern == nil {
+		pattern = ticketRefPattern
+	}
+	var refs []string
+	for _, m := range pattern.FindAllString(branch, -1) {
+		ref := strings.ToUpper(m)
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// PullRequestForBranch returns the number of the pull request in owner/repo whose head is branch in
+// headOwner's repository, which is owner for same-repository pull requests and the fork's owner otherwise.
+// An open pull request is preferred; otherwise the most recently updated closed one is returned.
+func (c *Client) PullRequestForBranch(ctx context.Context, owner, repo, headOwner, branch string) (int, error) {
+	params := url.Values{
+		"head":      {headOwner + ":" + branch},
+		"state":     {"all"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {"30"},
+	}
+	var prs []struct {
+		State  string `json:"state"`
+		Number int    `json:"number"`
+	}
+	if _, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s/pulls?%s", owner, repo, params.Encode()), &prs); err != nil {
+		return 0, fmt.Errorf("finding pull request for %s:%s in %s/%s: %w", headOwner, branch, owner, repo, err)
+	}
+	if len(prs) == 0 {
+		return 0, fmt.Errorf("%s:%s in %s/%s: %w", headOwner, branch, owner, repo, ErrNoPullRequest)
+	}
+	for _, pr := range prs {
+		if pr.State == "open" {
+			return pr.Number, nil
+		}
+	}
+	return prs[0].Number, nil
+}
+
+// maxPullRequestListPages caps PullRequestNumbers at 10,000 pull requests.
+const maxPullRequestListPages = 100
+
+// PullRequestNumbers lists the numbers of the pull requests in owner/repo with state "open",
+// "closed", or "all", most recently updated first, for fetching many pull requests at once.
+// At most 10,000 are listed.
+func (c *Client) PullRequestNumbers(ctx context.Context, owner, repo, state string) ([]int, error) {
+	params := url.Values{
+		"state":     {state},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {"100"},
+	}
+	var numbers []int
+	page := 1
+	for range maxPullRequestListPages {
+		params.Set("page", strconv.Itoa(page))
+		var prs []struct {
+			Number int `json:"number"`
+		}
+		resp, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s/pulls?%s", owner, repo, params.Encode()), &prs)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s pull requests in %s/%s: %w", state, owner, repo, err)
+		}
+		for _, pr := range prs {
+			numbers = append(numbers, pr.Number)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return numbers, nil
+}
@@ -0,0 +1,148 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestTicketRefs(t *testing.T) {
+	prefixed := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithTicketPrefixes("ENG", "proj"))
+	tests := []struct {
+		branch   string
+		want     []string
+		prefixed []string // With WithTicketPrefixes("ENG", "proj")
+	}{
+		{branch: "feature/PROJ-123-add-login", want: []string{"PROJ-123"}, prefixed: []string{"PROJ-123"}},
+		{branch: "alice/eng-42-fix-cache", want: nil, prefixed: []string{"ENG-42"}},
+		{branch: "ABC-1_DEF-22", want: nil}, // underscore joins words, so no boundary
+		{branch: "ABC-1/DEF-22/abc-1", want: []string{"ABC-1", "DEF-22"}},
+		{branch: "main", want: nil},
+		{branch: "release-v2", want: nil},
+		{branch: "proj-0123", want: nil}, // keys don't start with zero
+		{branch: "", want: nil},
+		// Ordinary words followed by numbers aren't keys
+		{branch: "patch-1", want: nil},
+		{branch: "release-2024", want: nil},
+		{branch: "fix-2-bugs", want: nil},
+		{branch: "renovate/actions-checkout-4.x", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if got := ticketRefs(tt.branch, nil); !slices.Equal(got, tt.want) {
+				t.Errorf("ticketRefs(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+			if got := ticketRefs(tt.branch, prefixed.ticketPattern); !slices.Equal(got, tt.prefixed) {
+				t.Errorf("ticketRefs(%q) with prefixes = %v, want %v", tt.branch, got, tt.prefixed)
+			}
+		})
+	}
+}
+
+func TestBranchNameValid(t *testing.T) {
+	patterns := []string{"feature/*", "fix/*", "[bad"}
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "feature/PROJ-1-login", want: true},
+		{branch: "fix/typo", want: true},
+		{branch: "feature/nested/branch", want: false},
+		{branch: "patch-1", want: false},
+	}
+	for _, tt := range tests {
+		if got := branchNameValid(tt.branch, patterns); got != tt.want {
+			t.Errorf("branchNameValid(%q) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestWithBranchPatterns(t *testing.T) {
+	data := &graphQLPullRequestComplete{}
+	data.HeadRef.Name = "patch-1"
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	if pr := client.convertGraphQLToPullRequest(context.Background(), data, "owner", "repo"); pr.BranchNameValid != nil {
+		t.Errorf("BranchNameValid = %v without patterns, want nil", *pr.BranchNameValid)
+	}
+
+	client = NewClient("test-token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithBranchPatterns("feature/*"),
+	)
+	pr := client.convertGraphQLToPullRequest(context.Background(), data, "owner", "repo")
+	if pr.BranchNameValid == nil || *pr.BranchNameValid {
+		t.Errorf("BranchNameValid = %v, want false", pr.BranchNameValid)
+	}
+	if pr.HeadRef != "patch-1" {
+		t.Errorf("HeadRef = %q, want patch-1", pr.HeadRef)
+	}
+}
+
+func TestClient_PullRequestForBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Query().Get("head") {
+		case "fork:feature":
+			body = `[{"number": 9, "state": "closed"}, {"number": 7, "state": "open"}]`
+		case "owner:old":
+			body = `[{"number": 3, "state": "closed"}, {"number": 2, "state": "closed"}]`
+		default:
+			body = `[]`
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	if n, err := client.PullRequestForBranch(ctx, "owner", "repo", "fork", "feature"); err != nil || n != 7 {
+		t.Errorf("PullRequestForBranch(fork:feature) = %d, %v; want the open PR 7", n, err)
+	}
+	if n, err := client.PullRequestForBranch(ctx, "owner", "repo", "owner", "old"); err != nil || n != 3 {
+		t.Errorf("PullRequestForBranch(owner:old) = %d, %v; want the latest PR 3", n, err)
+	}
+	if _, err := client.PullRequestForBranch(ctx, "owner", "repo", "owner", "none"); !errors.Is(err, ErrNoPullRequest) {
+		t.Errorf("PullRequestForBranch(owner:none) error = %v, want ErrNoPullRequest", err)
+	}
+}
+
+func TestClient_PullRequestNumbers(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+		}
+		body := `[{"number": 12}, {"number": 10}]`
+		if r.URL.Query().Get("page") == "2" {
+			body = `[{"number": 4}]`
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls?page=2>; rel="next"`, server.URL))
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	got, err := client.PullRequestNumbers(context.Background(), "owner", "repo", "open")
+	if err != nil {
+		t.Fatalf("PullRequestNumbers() error: %v", err)
+	}
+	if want := []int{12, 10, 4}; !slices.Equal(got, want) {
+		t.Errorf("PullRequestNumbers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+// Package bucket implements prx.ObjectStorage for S3 and Google Cloud Storage buckets over their
+// HTTP APIs, so ephemeral runners can share a cache without linking a cloud SDK:
+//
+//	storage := bucket.NewS3("my-bucket", "us-east-1", bucket.S3CredentialsFromEnv())
+//	client := prx.NewClient(token, prx.WithObjectCache(prx.NewObjectCacheStore(storage, "prx-cache/")))
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures an S3 or GCS bucket.
+type Option func(*config)
+
+type config struct {
+	client   *http.Client
+	endpoint string
+}
+
+// WithEndpoint sets the URL requests are sent to, such as an S3-compatible service like MinIO or
+// Cloudflare R2. Objects are addressed path-style, as endpoint/bucket/key.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+func newConfig(defaultEndpoint string, opts []Option) config {
+	c := config{client: &http.Client{Timeout: time.Minute}, endpoint: defaultEndpoint}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// response is a buffered HTTP response.
+type response struct {
+	header http.Header
+	body   []byte
+	status int
+}
+
+// send sends req and reads its response.
+func send(client *http.Client, req *http.Request) (*response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // best-effort close
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	return &response{header: resp.Header, body: body, status: resp.StatusCode}, nil
+}
+
+// statusError returns the error for an unexpected response, including the start of its body,
+// which holds the service's error code.
+func statusError(resp *response) error {
+	body := string(resp.body)
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("unexpected status %d: %s", resp.status, strings.TrimSpace(body))
+}
+
+// userMetadata returns the user-defined metadata in header, whose names start with prefix,
+// keyed by their lower-case names without it.
+func userMetadata(header http.Header, prefix string) map[string]string {
+	metadata := map[string]string{}
+	for name, values := range header {
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) && len(values) > 0 {
+			metadata[strings.ToLower(name[len(prefix):])] = values[0]
+		}
+	}
+	return metadata
+}
+
+// objectPath returns the escaped path of key in bucket, or of the bucket itself if key is empty.
+func objectPath(bucket, key string) string {
+	if key == "" {
+		return "/" + escape(bucket, false)
+	}
+	return "/" + escape(bucket, false) + "/" + escape(key, true)
+}
+
+// escape percent-encodes everything in s but unreserved characters, and slashes if keepSlash is set,
+// as SigV4 canonical requests require; GCS accepts the same encoding.
+func escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || keepSlash && c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// TokenFunc returns an OAuth 2.0 access token. It's called for each request, so it can refresh
+// expired tokens.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenFunc that always returns token, such as one from
+// google-github-actions/auth with token_format: access_token.
+func StaticToken(token string) TokenFunc {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
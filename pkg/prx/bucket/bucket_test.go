@@ -0,0 +1,119 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package bucket
+
+import (
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// fakeBucket serves the object requests of one bucket, storing metadata from headers starting
+// with metaPrefix. Listing is left to the test's handler.
+type fakeBucket struct {
+	data       map[string][]byte
+	metadata   map[string]http.Header
+	metaPrefix string
+	mu         sync.Mutex
+}
+
+func newFakeBucket(metaPrefix string) *fakeBucket {
+	return &fakeBucket{data: map[string][]byte{}, metadata: map[string]http.Header{}, metaPrefix: metaPrefix}
+}
+
+// serveObject handles a request for key.
+func (b *fakeBucket) serveObject(w http.ResponseWriter, r *http.Request, key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := b.data[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		maps.Copy(w.Header(), b.metadata[key])
+		w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		b.data[key] = data
+		b.metadata[key] = http.Header{}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, b.metaPrefix) {
+				b.metadata[key][name] = values
+			}
+		}
+	case http.MethodDelete:
+		if _, ok := b.data[key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(b.data, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// keys returns the sorted keys starting with prefix.
+func (b *fakeBucket) keys(prefix string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var keys []string
+	for key := range b.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// testStorage exercises an ObjectStorage backed by an empty bucket.
+func testStorage(t *testing.T, storage prx.ObjectStorage) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, _, found, err := storage.Get(ctx, "cache/missing.json"); err != nil || found {
+		t.Fatalf("Get(missing) = found %v, %v; want not found", found, err)
+	}
+	keys := []string{"cache/a.json", "cache/owner/repo#1 b.json", "other/c.json"}
+	for _, key := range keys {
+		if err := storage.Put(ctx, key, []byte(`{"key":"`+key+`"}`), map[string]string{prx.ObjectMetadataExpiry: "123"}); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+	}
+	data, metadata, found, err := storage.Get(ctx, "cache/owner/repo#1 b.json")
+	if err != nil || !found {
+		t.Fatalf("Get = found %v, %v", found, err)
+	}
+	if string(data) != `{"key":"cache/owner/repo#1 b.json"}` {
+		t.Errorf("Get data = %s", data)
+	}
+	if want := map[string]string{prx.ObjectMetadataExpiry: "123"}; !reflect.DeepEqual(metadata, want) {
+		t.Errorf("Get metadata = %v, want %v", metadata, want)
+	}
+
+	got, err := storage.List(ctx, "cache/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	slices.Sort(got)
+	if want := keys[:2]; !slices.Equal(got, want) {
+		t.Errorf("List = %q, want %q", got, want)
+	}
+
+	for range 2 {
+		if err := storage.Delete(ctx, "cache/a.json"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	if _, _, found, err := storage.Get(ctx, "cache/a.json"); err != nil || found {
+		t.Errorf("Get after Delete = found %v, %v; want not found", found, err)
+	}
+}
@@ -0,0 +1,122 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+var _ prx.ObjectStorage = (*GCS)(nil)
+
+// GCS stores objects in a Google Cloud Storage bucket, authenticating with OAuth 2.0 access tokens.
+// Metadata is stored as custom metadata.
+type GCS struct {
+	client   *http.Client
+	token    TokenFunc
+	endpoint string
+	bucket   string
+}
+
+// NewGCS returns the Cloud Storage bucket named bucket. The tokens need the devstorage.read_write scope.
+func NewGCS(bucket string, token TokenFunc, opts ...Option) *GCS {
+	cfg := newConfig("https://storage.googleapis.com", opts)
+	return &GCS{client: cfg.client, token: token, endpoint: cfg.endpoint, bucket: bucket}
+}
+
+// Get returns an object and its metadata, with found false if it doesn't exist.
+func (g *GCS) Get(ctx context.Context, key string) (data []byte, metadata map[string]string, found bool, err error) {
+	resp, err := g.do(ctx, http.MethodGet, g.endpoint+objectPath(g.bucket, key), nil, nil)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("getting %s: %w", key, err)
+	}
+	switch resp.status {
+	case http.StatusOK:
+		return resp.body, userMetadata(resp.header, "X-Goog-Meta-"), true, nil
+	case http.StatusNotFound:
+		return nil, nil, false, nil
+	default:
+		return nil, nil, false, fmt.Errorf("getting %s: %w", key, statusError(resp))
+	}
+}
+
+// Put creates or replaces an object.
+func (g *GCS) Put(ctx context.Context, key string, data []byte, metadata map[string]string) error {
+	header := http.Header{}
+	for name, value := range metadata {
+		header.Set("X-Goog-Meta-"+name, value)
+	}
+	resp, err := g.do(ctx, http.MethodPut, g.endpoint+objectPath(g.bucket, key), header, data)
+	if err != nil {
+		return fmt.Errorf("putting %s: %w", key, err)
+	}
+	if resp.status != http.StatusOK {
+		return fmt.Errorf("putting %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// Delete removes an object; deleting a missing object is not an error.
+func (g *GCS) Delete(ctx context.Context, key string) error {
+	resp, err := g.do(ctx, http.MethodDelete, g.endpoint+objectPath(g.bucket, key), nil, nil)
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", key, err)
+	}
+	if resp.status != http.StatusNoContent && resp.status != http.StatusOK && resp.status != http.StatusNotFound {
+		return fmt.Errorf("deleting %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// List returns the keys of all objects whose key starts with prefix, using the JSON API.
+func (g *GCS) List(ctx context.Context, prefix string) ([]string, error) {
+	query := url.Values{"prefix": {prefix}, "fields": {"items(name),nextPageToken"}}
+	var keys []string
+	for {
+		u := g.endpoint + "/storage/v1/b/" + url.PathEscape(g.bucket) + "/o?" + query.Encode()
+		resp, err := g.do(ctx, http.MethodGet, u, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", prefix, err)
+		}
+		if resp.status != http.StatusOK {
+			return nil, fmt.Errorf("listing %s: %w", prefix, statusError(resp))
+		}
+		var page struct {
+			NextPageToken string `json:"nextPageToken"`
+			Items         []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(resp.body, &page); err != nil {
+			return nil, fmt.Errorf("listing %s: decoding response: %w", prefix, err)
+		}
+		for _, obj := range page.Items {
+			keys = append(keys, obj.Name)
+		}
+		if page.NextPageToken == "" {
+			return keys, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// do sends an authenticated request to u.
+func (g *GCS) do(ctx context.Context, method, u string, header http.Header, body []byte) (*response, error) {
+	token, err := g.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting access token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return send(g.client, req)
+}
@@ -0,0 +1,63 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package bucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGCS(t *testing.T) {
+	b := newFakeBucket("X-Goog-Meta-")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if key, ok := strings.CutPrefix(r.URL.Path, "/my-bucket/"); ok {
+			b.serveObject(w, r, key)
+			return
+		}
+		if r.URL.Path != "/storage/v1/b/my-bucket/o" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// One key per page, so listing follows page tokens.
+		type item struct {
+			Name string `json:"name"`
+		}
+		var page struct {
+			NextPageToken string `json:"nextPageToken,omitempty"`
+			Items         []item `json:"items,omitempty"`
+		}
+		keys := b.keys(r.URL.Query().Get("prefix"))
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			for len(keys) > 0 && keys[0] <= token {
+				keys = keys[1:]
+			}
+		}
+		if len(keys) > 0 {
+			page.Items = []item{{Name: keys[0]}}
+		}
+		if len(keys) > 1 {
+			page.NextPageToken = keys[0]
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	testStorage(t, NewGCS("my-bucket", StaticToken("token"), WithEndpoint(server.URL)))
+}
+
+func TestGCS_TokenError(t *testing.T) {
+	g := NewGCS("my-bucket", func(context.Context) (string, error) {
+		return "", errors.New("expired")
+	}, WithEndpoint("http://127.0.0.1:0"))
+	if _, _, _, err := g.Get(context.Background(), "key"); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Get = %v, want token error", err)
+	}
+}
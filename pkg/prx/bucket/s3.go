@@ -0,0 +1,216 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+var _ prx.ObjectStorage = (*S3)(nil)
+
+// S3 stores objects in an S3 bucket, or a bucket of an S3-compatible service (see WithEndpoint),
+// signing requests with AWS Signature Version 4. Metadata is stored as user-defined metadata.
+type S3 struct {
+	client   *http.Client
+	now      func() time.Time
+	creds    S3Credentials
+	endpoint string
+	bucket   string
+	region   string
+}
+
+// S3Credentials are the AWS credentials requests are signed with.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // For temporary credentials, such as from aws-actions/configure-aws-credentials
+}
+
+// S3CredentialsFromEnv returns the credentials in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN.
+func S3CredentialsFromEnv() S3Credentials {
+	return S3Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// NewS3 returns the S3 bucket named bucket in region, e.g. "us-east-1".
+func NewS3(bucket, region string, creds S3Credentials, opts ...Option) *S3 {
+	cfg := newConfig("https://s3."+region+".amazonaws.com", opts)
+	return &S3{client: cfg.client, now: time.Now, creds: creds, endpoint: cfg.endpoint, bucket: bucket, region: region}
+}
+
+// Get returns an object and its metadata, with found false if it doesn't exist. Without the
+// s3:ListBucket permission, S3 answers 403 rather than 404 for missing objects, which is an error.
+func (s *S3) Get(ctx context.Context, key string) (data []byte, metadata map[string]string, found bool, err error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("getting %s: %w", key, err)
+	}
+	switch resp.status {
+	case http.StatusOK:
+		return resp.body, userMetadata(resp.header, "X-Amz-Meta-"), true, nil
+	case http.StatusNotFound:
+		return nil, nil, false, nil
+	default:
+		return nil, nil, false, fmt.Errorf("getting %s: %w", key, statusError(resp))
+	}
+}
+
+// Put creates or replaces an object.
+func (s *S3) Put(ctx context.Context, key string, data []byte, metadata map[string]string) error {
+	header := http.Header{}
+	for name, value := range metadata {
+		header.Set("X-Amz-Meta-"+name, value)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, nil, header, data)
+	if err != nil {
+		return fmt.Errorf("putting %s: %w", key, err)
+	}
+	if resp.status != http.StatusOK {
+		return fmt.Errorf("putting %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// Delete removes an object; deleting a missing object is not an error.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", key, err)
+	}
+	if resp.status != http.StatusNoContent && resp.status != http.StatusOK && resp.status != http.StatusNotFound {
+		return fmt.Errorf("deleting %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// List returns the keys of all objects whose key starts with prefix.
+func (s *S3) List(ctx context.Context, prefix string) ([]string, error) {
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	var keys []string
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", prefix, err)
+		}
+		if resp.status != http.StatusOK {
+			return nil, fmt.Errorf("listing %s: %w", prefix, statusError(resp))
+		}
+		var page struct {
+			NextContinuationToken string
+			Contents              []struct {
+				Key string
+			}
+			IsTruncated bool
+		}
+		if err := xml.Unmarshal(resp.body, &page); err != nil {
+			return nil, fmt.Errorf("listing %s: decoding response: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, obj.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// do sends a signed request for key, or for the bucket itself if key is empty.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*response, error) {
+	path := objectPath(s.bucket, key)
+	rawQuery := canonicalQuery(query)
+	u := s.endpoint + path
+	if rawQuery != "" {
+		u += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, path, rawQuery, body)
+	return send(s.client, req)
+}
+
+// sign adds the headers and Authorization of AWS Signature Version 4 to req, signing its host and
+// every header already set. path and rawQuery are the request's canonical URI and query string.
+func (s *S3) sign(req *http.Request, path, rawQuery string, body []byte) {
+	amzDate := s.now().UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.creds.SessionToken)
+	}
+
+	values := map[string]string{"host": req.URL.Host}
+	for name, v := range req.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, path, rawQuery, headers.String(), signedHeaders, payloadHash}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.creds.SecretAccessKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.creds.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+// canonicalQuery returns query encoded with its keys sorted, as SigV4 requires.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, escape(k, false)+"="+escape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return h.Sum(nil)
+}
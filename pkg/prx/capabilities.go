@@ -0,0 +1,340 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// Minimum GitHub Enterprise Server versions for optional features.
+var (
+	minRulesetsVersion   = serverVersion{major: 3, minor: 11}
+	minMergeQueueVersion = serverVersion{major: 3, minor: 12}
+)
+
+// Capabilities describes which optional API features the target server supports.
+// Features the server lacks are disabled, and the reason recorded in Adjustments,
+// rather than failing requests with raw 404 or GraphQL schema errors.
+//
+// Capabilities also reports what the client's token can do, so tools can show it upfront instead of
+// failing deep inside a fetch. Token fields are only set by Client.TokenCapabilities.
+type Capabilities struct {
+	ServerVersion string   `json:"server_version,omitempty"` // GitHub Enterprise Server version; empty for github.com
+	Adjustments   []string `json:"adjustments,omitempty"`    // Human-readable notes on disabled or adjusted features
+	Rulesets      bool     `json:"rulesets"`                 // Repository rulesets REST API
+	MergeQueue    bool     `json:"merge_queue"`              // Merge queue GraphQL timeline event types
+
+	RateLimit *RateLimitState `json:"rate_limit,omitempty"` // Current quotas; nil if the server has rate limiting disabled
+	// Writes reports whether the token's scopes allow writes, such as Merge, and listing collaborators,
+	// which needs push access. It is nil for fine-grained and GitHub App tokens, whose permissions are
+	// granted per repository.
+	Writes        *bool    `json:"writes,omitempty"`
+	Login         string   `json:"login,omitempty"`          // Authenticated user; empty for GitHub App installations
+	TokenType     string   `json:"token_type,omitempty"`     // One of the TokenType constants; empty if unknown
+	Profile       Profile  `json:"profile,omitempty"`        // Set with WithProfile
+	Scopes        []string `json:"scopes,omitempty"`         // Scopes of classic and OAuth app tokens
+	TokenProblems []string `json:"token_problems,omitempty"` // Probes of the token that failed
+	GraphQL       bool     `json:"graphql"`                  // The GraphQL API accepts the token
+}
+
+// Token types reported in Capabilities.TokenType, recognized by their prefixes.
+const (
+	TokenTypeClassic     = "classic"      // Personal access token (classic), "ghp_"
+	TokenTypeFineGrained = "fine-grained" // Fine-grained personal access token, "github_pat_"
+	TokenTypeOAuth       = "oauth"        // OAuth app token, "gho_"
+	TokenTypeAppUser     = "app-user"     // GitHub App user-to-server token, "ghu_"
+	TokenTypeApp         = "app"          // GitHub App installation token, "ghs_", or WithAppAuth
+)
+
+// serverVersion is a parsed GitHub Enterprise Server major.minor version.
+type serverVersion struct {
+	major int
+	minor int
+}
+
+func (v serverVersion) less(o serverVersion) bool {
+	return v.major < o.major || (v.major == o.major && v.minor < o.minor)
+}
+
+func (v serverVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// parseServerVersion parses versions such as "3.10.4".
+func parseServerVersion(s string) (serverVersion, bool) {
+	parts := strings.SplitN(s, ".", 3)
+	if len(parts) < 2 {
+		return serverVersion{}, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return serverVersion{}, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return serverVersion{}, false
+	}
+	return serverVersion{major: major, minor: minor}, true
+}
+
+// fullCapabilities returns capabilities for github.com or an unidentified server.
+func fullCapabilities() Capabilities {
+	return Capabilities{Rulesets: true, MergeQueue: true}
+}
+
+// capabilitiesForVersion determines capabilities from a GitHub Enterprise Server version.
+func capabilitiesForVersion(version string) Capabilities {
+	caps := fullCapabilities()
+	caps.ServerVersion = version
+
+	v, ok := parseServerVersion(version)
+	if !ok {
+		return caps
+	}
+	if v.less(minRulesetsVersion) {
+		caps.Rulesets = false
+		caps.Adjustments = append(caps.Adjustments,
+			fmt.Sprintf("rulesets API disabled: requires GHES %s, server is %s", minRulesetsVersion, v))
+	}
+	if v.less(minMergeQueueVersion) {
+		caps.MergeQueue = false
+		caps.Adjustments = append(caps.Adjustments,
+			fmt.Sprintf("merge queue timeline events disabled: requires GHES %s, server is %s", minMergeQueueVersion, v))
+	}
+	return caps
+}
+
+// Capabilities reports the optional features supported by the target server. The server is probed
+// via the meta endpoint on first use and the result kept, so this is cheap to call for every fetch;
+// github.com is assumed to support everything.
+func (c *Client) Capabilities(ctx context.Context) Capabilities {
+	caps := c.serverCapabilities(ctx)
+	caps.Profile = c.profile
+	return caps
+}
+
+// TokenCapabilities reports what Capabilities does, plus what the client's token can do. The token
+// is probed on every call with up to three requests, of which only fetching the authenticated user
+// counts against the rate limit, so it's meant for showing upfront rather than for every fetch.
+func (c *Client) TokenCapabilities(ctx context.Context) Capabilities {
+	caps := c.Capabilities(ctx)
+	c.probeToken(ctx, &caps)
+	return caps
+}
+
+// probeToken fills in the token fields of caps.
+func (c *Client) probeToken(ctx context.Context, caps *Capabilities) {
+	problem := func(what string, err error) {
+		caps.TokenProblems = append(caps.TokenProblems, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	caps.TokenType = tokenType(c.token)
+	if c.appTokens != nil {
+		caps.TokenType = TokenTypeApp
+	}
+	if caps.TokenType != TokenTypeApp {
+		var user struct {
+			Login string `json:"login"`
+		}
+		resp, err := c.github.Get(ctx, "/user", &user)
+		if err != nil {
+			problem("fetching the authenticated user", err)
+		} else {
+			caps.Login = user.Login
+			caps.Scopes = resp.OAuthScopes
+			if resp.OAuthScopes != nil {
+				writes := slices.Contains(resp.OAuthScopes, "repo") || slices.Contains(resp.OAuthScopes, "public_repo")
+				caps.Writes = &writes
+			}
+		}
+	}
+
+	var probe struct {
+		Data struct {
+			RateLimit struct {
+				Limit int `json:"limit"`
+			} `json:"rateLimit"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	switch err := c.github.GraphQL(ctx, `query { rateLimit { limit } }`, nil, &probe); {
+	case err != nil:
+		problem("querying the GraphQL API", err)
+	case len(probe.Errors) > 0:
+		problem("querying the GraphQL API", errors.New(probe.Errors[0].Message))
+	default:
+		caps.GraphQL = true
+	}
+
+	limits, err := c.github.RateLimits(ctx)
+	var apiErr *github.Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		// Rate limiting is disabled on the server
+	case err != nil:
+		problem("fetching rate limits", err)
+	default:
+		for _, rl := range limits {
+			c.recordRateLimit(rl)
+		}
+		state := c.RateLimitState()
+		caps.RateLimit = &state
+	}
+}
+
+// tokenType recognizes the type of a GitHub token by its prefix.
+func tokenType(token string) string {
+	switch {
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenTypeClassic
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenTypeFineGrained
+	case strings.HasPrefix(token, "gho_"):
+		return TokenTypeOAuth
+	case strings.HasPrefix(token, "ghu_"):
+		return TokenTypeAppUser
+	case strings.HasPrefix(token, "ghs_"):
+		return TokenTypeApp
+	default:
+		return ""
+	}
+}
+
+// capsProbeRetry is how long full support is assumed after a failed capabilities probe before the
+// server is probed again.
+const capsProbeRetry = time.Minute
+
+// serverCapabilities returns the optional features supported by the target server, probing it on first use.
+// A successful probe is kept for the client's lifetime; a failed one is retried after capsProbeRetry.
+// The probe runs without holding capsMu, so a slow server doesn't block concurrent fetches.
+func (c *Client) serverCapabilities(ctx context.Context) Capabilities {
+	c.capsMu.Lock()
+	cached, retryAt := c.caps, c.capsRetryAt
+	c.capsMu.Unlock()
+
+	var caps Capabilities
+	switch {
+	case cached != nil:
+		caps = *cached
+	case time.Now().Before(retryAt):
+		caps = fullCapabilities()
+	default:
+		probed, ok := c.probeCapabilities(ctx)
+		c.capsMu.Lock()
+		if ok {
+			c.caps = &probed
+		} else {
+			c.capsRetryAt = time.Now().Add(capsProbeRetry)
+		}
+		c.capsMu.Unlock()
+		caps = probed
+	}
+	caps.Adjustments = slices.Clone(caps.Adjustments)
+
+	c.capsMu.Lock()
+	rulesetsNotFound := c.rulesetsNotFound
+	c.capsMu.Unlock()
+	if rulesetsNotFound && caps.Rulesets {
+		caps.Rulesets = false
+		caps.Adjustments = append(caps.Adjustments, "rulesets API disabled: server returned 404")
+	}
+	return caps
+}
+
+// probeCapabilities queries the meta endpoint of a non-github.com server for its version. It reports
+// false if the server couldn't be probed, in which case full support is assumed.
+func (c *Client) probeCapabilities(ctx context.Context) (Capabilities, bool) {
+	if c.github.BaseURL == "" || c.github.BaseURL == github.API {
+		return fullCapabilities(), true
+	}
+
+	meta, err := c.github.Meta(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to probe server capabilities, assuming full support", "error", err, "retry_in", capsProbeRetry)
+		return fullCapabilities(), false
+	}
+	if meta.InstalledVersion == "" {
+		return fullCapabilities(), true
+	}
+
+	caps := capabilitiesForVersion(meta.InstalledVersion)
+	c.logger.InfoContext(ctx, "detected GitHub Enterprise Server",
+		"version", caps.ServerVersion, "adjustments", caps.Adjustments)
+	return caps, true
+}
+
+// disableRulesets records that the rulesets API is unavailable when a non-github.com server
+// answers the rulesets endpoint with 404. It reports whether err was handled this way.
+func (c *Client) disableRulesets(ctx context.Context, err error) bool {
+	var apiErr *github.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return false
+	}
+	if c.github.BaseURL == "" || c.github.BaseURL == github.API {
+		return false
+	}
+
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+	if !c.rulesetsNotFound {
+		c.rulesetsNotFound = true
+		c.logger.InfoContext(ctx, "rulesets API not available on server, disabling")
+	}
+	return true
+}
+
+// adaptGraphQLQuery returns query with fragments for unsupported timeline
+// event types and selections of unsupported fields removed, so older servers
+// don't reject the whole query.
+func adaptGraphQLQuery(query string, caps Capabilities) string {
+	if !caps.MergeQueue {
+		query = removeGraphQLFragment(query, "AddedToMergeQueueEvent")
+		query = removeGraphQLFragment(query, "RemovedFromMergeQueueEvent")
+		query = removeGraphQLSelection(query, "mergeQueueEntry {")
+		query = removeGraphQLSelection(query, "mergeQueue {")
+	}
+	return query
+}
+
+// removeGraphQLFragment removes an inline fragment ("... on TypeName { ... }") from query.
+func removeGraphQLFragment(query, typeName string) string {
+	return removeGraphQLSelection(query, "... on "+typeName+" {")
+}
+
+// removeGraphQLSelection removes the first selection starting with marker, which ends in the
+// opening brace of its selection set, from query.
+func removeGraphQLSelection(query, marker string) string {
+	start := strings.Index(query, marker)
+	if start < 0 {
+		return query
+	}
+	depth := 0
+	for i := start + len(marker) - 1; i < len(query); i++ {
+		switch query[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				// Also drop the indentation preceding the fragment and the trailing newline
+				lineStart := strings.LastIndexByte(query[:start], '\n') + 1
+				end := i + 1
+				if end < len(query) && query[end] == '\n' {
+					end++
+				}
+				return query[:lineStart] + query[end:]
+			}
+		default:
+		}
+	}
+	return query
+}
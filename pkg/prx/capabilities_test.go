@@ -0,0 +1,223 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestCapabilitiesForVersion(t *testing.T) {
+	tests := []struct {
+		version         string
+		wantRulesets    bool
+		wantMergeQueue  bool
+		wantAdjustments int
+	}{
+		{version: "3.9.2", wantRulesets: false, wantMergeQueue: false, wantAdjustments: 2},
+		{version: "3.11.0", wantRulesets: true, wantMergeQueue: false, wantAdjustments: 1},
+		{version: "3.12.1", wantRulesets: true, wantMergeQueue: true, wantAdjustments: 0},
+		{version: "4.0.0", wantRulesets: true, wantMergeQueue: true, wantAdjustments: 0},
+		{version: "garbage", wantRulesets: true, wantMergeQueue: true, wantAdjustments: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			caps := capabilitiesForVersion(tt.version)
+			if caps.Rulesets != tt.wantRulesets {
+				t.Errorf("Rulesets = %v, want %v", caps.Rulesets, tt.wantRulesets)
+			}
+			if caps.MergeQueue != tt.wantMergeQueue {
+				t.Errorf("MergeQueue = %v, want %v", caps.MergeQueue, tt.wantMergeQueue)
+			}
+			if len(caps.Adjustments) != tt.wantAdjustments {
+				t.Errorf("Adjustments = %v, want %d entries", caps.Adjustments, tt.wantAdjustments)
+			}
+			if caps.ServerVersion != tt.version {
+				t.Errorf("ServerVersion = %q, want %q", caps.ServerVersion, tt.version)
+			}
+		})
+	}
+}
+
+func TestAdaptGraphQLQuery(t *testing.T) {
+	full := adaptGraphQLQuery(completeGraphQLQuery, fullCapabilities())
+	if full != completeGraphQLQuery {
+		t.Error("expected full capabilities to use the complete query unchanged")
+	}
+
+	reduced := adaptGraphQLQuery(completeGraphQLQuery, Capabilities{Rulesets: true})
+	for _, typ := range []string{"AddedToMergeQueueEvent", "RemovedFromMergeQueueEvent", "mergeQueue"} {
+		if strings.Contains(reduced, typ) {
+			t.Errorf("expected %s fragment to be removed", typ)
+		}
+	}
+	for _, typ := range []string{"AutomaticBaseChangeSucceededEvent", "UnlockedEvent"} {
+		if !strings.Contains(reduced, typ) {
+			t.Errorf("expected %s fragment to be kept", typ)
+		}
+	}
+	if strings.Count(reduced, "{") != strings.Count(reduced, "}") {
+		t.Error("reduced query has unbalanced braces")
+	}
+}
+
+func TestClient_CapabilitiesOldGHES(t *testing.T) {
+	rulesetCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/meta":
+			_, _ = w.Write([]byte(`{"installed_version": "3.9.5"}`))
+		case r.URL.Path == "/graphql":
+			var req struct {
+				Query string `json:"query"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("decoding request: %v", err)
+			}
+			if strings.Contains(req.Query, "MergeQueueEvent") {
+				t.Error("query sent to GHES 3.9 should not reference merge queue events")
+			}
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "title": "t", "state": "OPEN", "createdAt": "2023-01-01T00:00:00Z",
+				"author": {"login": "a"}, "headRef": {"target": {"oid": "abc"}}}}}}`))
+		case strings.HasSuffix(r.URL.Path, "/rulesets"):
+			rulesetCalls++
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			_, _ = w.Write([]byte(`{"check_runs": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	if _, err := client.PullRequest(context.Background(), "owner", "repo", 1); err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	if rulesetCalls != 0 {
+		t.Errorf("expected rulesets API to be skipped, got %d calls", rulesetCalls)
+	}
+
+	caps := client.Capabilities(context.Background())
+	if caps.ServerVersion != "3.9.5" || caps.Rulesets || caps.MergeQueue {
+		t.Errorf("unexpected capabilities: %+v", caps)
+	}
+}
+
+func TestClient_CapabilitiesProbeRetried(t *testing.T) {
+	metaCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metaCalls++
+		if metaCalls == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"installed_version": "3.9.5"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	if caps := client.serverCapabilities(ctx); !caps.MergeQueue {
+		t.Errorf("capabilities after a failed probe = %+v, want full support", caps)
+	}
+	client.serverCapabilities(ctx)
+	if metaCalls != 1 {
+		t.Errorf("meta calls = %d, want the failed probe not retried right away", metaCalls)
+	}
+
+	client.capsMu.Lock()
+	client.capsRetryAt = time.Time{}
+	client.capsMu.Unlock()
+	if caps := client.serverCapabilities(ctx); caps.MergeQueue || caps.ServerVersion != "3.9.5" {
+		t.Errorf("capabilities after retrying = %+v, want GHES 3.9 limits", caps)
+	}
+	client.serverCapabilities(ctx)
+	if metaCalls != 2 {
+		t.Errorf("meta calls = %d, want the successful probe kept", metaCalls)
+	}
+}
+
+func TestClient_RulesetsNotFoundDisablesFeature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	_, err := client.fetchRulesetsREST(context.Background(), "owner", "repo")
+	if !client.disableRulesets(context.Background(), err) {
+		t.Fatalf("expected 404 to be handled, err = %v", err)
+	}
+	caps := client.Capabilities(context.Background())
+	if caps.Rulesets {
+		t.Error("expected rulesets to be disabled")
+	}
+	if len(caps.Adjustments) != 1 {
+		t.Errorf("expected one adjustment, got %v", caps.Adjustments)
+	}
+}
+
+func TestClient_TokenCapabilities(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/meta" {
+			requests++
+		}
+		switch r.URL.Path {
+		case "/user":
+			w.Header().Set("X-OAuth-Scopes", "gist, public_repo")
+			_, _ = w.Write([]byte(`{"login": "octocat"}`))
+		case "/graphql":
+			_, _ = w.Write([]byte(`{"data": {"rateLimit": {"limit": 5000}}}`))
+		case "/rate_limit":
+			_, _ = w.Write([]byte(`{"resources": {
+				"core": {"limit": 5000, "remaining": 4990, "used": 10, "reset": 4102444800},
+				"graphql": {"limit": 5000, "remaining": 5000, "used": 0, "reset": 4102444800}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("ghp_test", WithCacheStore(null.New[string, PullRequestData]()), WithProfile(ProfileCI))
+	client.github = newTestGitHubClient(&http.Client{}, "ghp_test", server.URL)
+
+	// Capabilities doesn't probe the token
+	if caps := client.Capabilities(context.Background()); requests != 0 || caps.Login != "" || caps.Profile != ProfileCI {
+		t.Errorf("Capabilities() = %+v after %d token requests, want no token probe", caps, requests)
+	}
+
+	caps := client.TokenCapabilities(context.Background())
+	if caps.Login != "octocat" || caps.TokenType != TokenTypeClassic || caps.Profile != ProfileCI || !caps.GraphQL {
+		t.Errorf("TokenCapabilities() = %+v", caps)
+	}
+	if !slices.Equal(caps.Scopes, []string{"gist", "public_repo"}) || caps.Writes == nil || !*caps.Writes {
+		t.Errorf("scopes = %v, writes = %v; want public_repo to allow writes", caps.Scopes, caps.Writes)
+	}
+	if caps.RateLimit == nil || caps.RateLimit.Core.Remaining != 4990 || caps.RateLimit.GraphQL.Limit != 5000 {
+		t.Errorf("RateLimit = %+v", caps.RateLimit)
+	}
+	if len(caps.TokenProblems) != 0 {
+		t.Errorf("TokenProblems = %v", caps.TokenProblems)
+	}
+
+	for token, want := range map[string]string{"github_pat_x": TokenTypeFineGrained, "ghs_x": TokenTypeApp, "secret": ""} {
+		if got := tokenType(token); got != want {
+			t.Errorf("tokenType(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// maxCheckOutputText limits the check run output text kept per check run.
+	maxCheckOutputText = 64 << 10
+	// checkOutputCacheSize bounds the number of check run outputs held in memory.
+	checkOutputCacheSize = 256
+	// checkOutputCacheTTL is how long completed check run outputs are cached.
+	checkOutputCacheTTL = 24 * time.Hour
+)
+
+// CheckOutput is the full output of a check run. Failure details often live only in Text,
+// which Event descriptions omit.
+type CheckOutput struct {
+	Title         string `json:"title,omitempty"`
+	Summary       string `json:"summary,omitempty"`
+	Text          string `json:"text,omitempty"`
+	ID            int64  `json:"id"`
+	TextTruncated bool   `json:"text_truncated,omitempty"` // Text was cut to 64 KiB
+}
+
+// FetchCheckOutput fetches the output of a check run, as identified by Event.CheckRunID.
+// Output of completed check runs is cached in memory.
+func (c *Client) FetchCheckOutput(ctx context.Context, owner, repo string, checkRunID int64) (*CheckOutput, error) {
+	key := fmt.Sprintf("%s/%s/%d", owner, repo, checkRunID)
+	if cached, ok := c.checkOutputCache.Get(key); ok {
+		return cached, nil
+	}
+
+	var run struct {
+		Output struct {
+			Title   string `json:"title"`
+			Summary string `json:"summary"`
+			Text    string `json:"text"`
+		} `json:"output"`
+		Status string `json:"status"`
+		ID     int64  `json:"id"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/check-runs/%d", owner, repo, checkRunID)
+	if _, err := c.github.Get(ctx, path, &run); err != nil {
+		return nil, fmt.Errorf("fetching check run output: %w", err)
+	}
+
+	out := &CheckOutput{
+		ID:      run.ID,
+		Title:   run.Output.Title,
+		Summary: run.Output.Summary,
+		Text:    run.Output.Text,
+	}
+	out.Title, out.Summary, out.Text = c.redactText(out.Title), c.redactText(out.Summary), c.redactText(out.Text)
+	if len(out.Text) > maxCheckOutputText {
+		out.Text = truncateString(out.Text, maxCheckOutputText)
+		out.TextTruncated = true
+	}
+
+	// Output can still change until the run completes
+	if run.Status == "completed" {
+		c.checkOutputCache.Set(key, out)
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_FetchCheckOutput(t *testing.T) {
+	requests := 0
+	longText := strings.Repeat("x", maxCheckOutputText+100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/owner/repo/check-runs/42":
+			w.Write([]byte(`{"id": 42, "status": "completed", "output": {"title": "3 tests failed", "summary": "See details", "text": "FAIL: TestFoo\nexpected 1, got 2"}}`))
+		case "/repos/owner/repo/check-runs/43":
+			body, _ := json.Marshal(map[string]any{"id": 43, "status": "in_progress", "output": map[string]string{"text": longText}})
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	out, err := client.FetchCheckOutput(ctx, "owner", "repo", 42)
+	if err != nil {
+		t.Fatalf("FetchCheckOutput() error: %v", err)
+	}
+	if out.Title != "3 tests failed" || !strings.Contains(out.Text, "FAIL: TestFoo") || out.TextTruncated {
+		t.Errorf("FetchCheckOutput() = %+v", out)
+	}
+	if _, err := client.FetchCheckOutput(ctx, "owner", "repo", 42); err != nil {
+		t.Fatalf("FetchCheckOutput() error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected completed output to be cached, got %d requests", requests)
+	}
+
+	out, err = client.FetchCheckOutput(ctx, "owner", "repo", 43)
+	if err != nil {
+		t.Fatalf("FetchCheckOutput() error: %v", err)
+	}
+	if !out.TextTruncated || len(out.Text) != maxCheckOutputText {
+		t.Errorf("expected text truncated to %d bytes, got %d (truncated=%v)", maxCheckOutputText, len(out.Text), out.TextTruncated)
+	}
+	if _, err := client.FetchCheckOutput(ctx, "owner", "repo", 43); err != nil {
+		t.Fatalf("FetchCheckOutput() error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected in-progress output not to be cached, got %d requests", requests)
+	}
+
+	if _, err := client.FetchCheckOutput(ctx, "owner", "repo", 99); err == nil {
+		t.Error("expected error for unknown check run")
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
 )
 
 // TestCheckRunHistory_MultipleCommits tests that we capture check run failures
@@ -458,6 +461,78 @@ func TestCheckRunHistory_OutOfOrderEvents(t *testing.T) {
 	}
 }
 
+// TestCheckRunHistory_ChecksByCommit tests that checks are summarized per commit,
+// with rollup events lacking a commit attributed to the head.
+func TestCheckRunHistory_ChecksByCommit(t *testing.T) {
+	events := []Event{
+		{Kind: EventKindCommit, Body: "ignored", Target: "commit1"},
+		{Kind: EventKindCheckRun, Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Body: "ci/test", Outcome: "failure", Target: "commit1"},
+		{Kind: EventKindCheckRun, Timestamp: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), Body: "ci/test", Outcome: "success", Target: "commit2"},
+		{Kind: EventKindStatusCheck, Timestamp: time.Date(2025, 1, 1, 11, 5, 0, 0, time.UTC), Body: "ci/lint", Outcome: "failure"},
+	}
+
+	byCommit := calculateChecksByCommit(events, "commit2")
+	if len(byCommit) != 2 {
+		t.Fatalf("Expected 2 commits with checks, got %d", len(byCommit))
+	}
+	if _, ok := byCommit["commit1"].Failing["ci/test"]; !ok {
+		t.Error("Expected ci/test to be failing on commit1")
+	}
+	head := byCommit["commit2"]
+	if _, ok := head.Success["ci/test"]; !ok {
+		t.Error("Expected ci/test to be successful on commit2")
+	}
+	if _, ok := head.Failing["ci/lint"]; !ok {
+		t.Error("Expected ci/lint status without a commit to be attributed to the head")
+	}
+
+	if got := calculateChecksByCommit(events[:1], "commit2"); got != nil {
+		t.Errorf("Expected nil without check events, got %v", got)
+	}
+}
+
+// TestCheckRunHistory_Concurrency tests that per-commit check runs are fetched concurrently,
+// within the configured limit, and attributed to the right commit.
+func TestCheckRunHistory_Concurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		sha := strings.Split(strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/commits/"), "/")[0]
+		fmt.Fprintf(w, `{"check_runs": [{"id": 1, "name": "test-%s", "status": "completed", "conclusion": "success",
+			"started_at": "2025-01-01T10:00:00Z", "completed_at": "2025-01-01T10:05:00Z"}]}`, sha)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithConcurrency(2))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	prData := &PullRequestData{PullRequest: PullRequest{Number: 1, HeadSHA: "sha0"}}
+	for i := range 6 {
+		prData.Events = append(prData.Events, Event{Kind: EventKindCommit, Body: fmt.Sprintf("sha%d", i)})
+	}
+
+	events := client.fetchAllCheckRunsREST(context.Background(), "owner", "repo", prData, time.Now(), nil)
+	if len(events) != 6 {
+		t.Fatalf("Expected 6 check runs, got %d", len(events))
+	}
+	for i := range events {
+		if events[i].Body != "test-"+events[i].Target {
+			t.Errorf("Check run %q attributed to commit %q", events[i].Body, events[i].Target)
+		}
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("Expected 2 concurrent requests at peak, got %d", got)
+	}
+}
+
 // TestCalculateTestStateFromCheckSummary tests the calculateTestStateFromCheckSummary function.
 func TestCalculateTestStateFromCheckSummary(t *testing.T) {
 	client := &Client{}
@@ -528,3 +603,42 @@ func TestCalculateTestStateFromCheckSummary(t *testing.T) {
 		})
 	}
 }
+
+// TestCheckRunHistory_ForcePushes tests that check runs on force-pushed-away commits are set aside,
+// and that force pushes after an approval are flagged.
+func TestCheckRunHistory_ForcePushes(t *testing.T) {
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	pushed := client.parseGraphQLTimelineEvent(context.Background(), map[string]any{
+		"__typename":   "HeadRefForcePushedEvent",
+		"createdAt":    "2025-01-01T11:00:00Z",
+		"actor":        map[string]any{"login": "alice"},
+		"beforeCommit": map[string]any{"oid": "old"},
+		"afterCommit":  map[string]any{"oid": "new"},
+	}, "o", "r")
+	if pushed == nil || pushed.BeforeSHA != "old" || pushed.AfterSHA != "new" {
+		t.Fatalf("parseGraphQLTimelineEvent() = %+v, want a force push from old to new", pushed)
+	}
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Kind: EventKindCheckRun, Timestamp: base, Body: "test", Target: "old", Outcome: "failure"},
+		{Kind: EventKindReview, Timestamp: base.Add(30 * time.Minute), Actor: "bob", Outcome: string(ReviewStateApproved)},
+		*pushed,
+		{Kind: EventKindCheckRun, Timestamp: base.Add(2 * time.Hour), Body: "lint", Target: "new", Outcome: "success"},
+	}
+	pr := &PullRequest{HeadSHA: "new", Commits: []string{"new"}}
+	finalizePullRequest(pr, events, nil, "")
+
+	if !events[0].Orphaned || events[3].Orphaned {
+		t.Errorf("orphaned = %v and %v, want true for the old commit's run only", events[0].Orphaned, events[3].Orphaned)
+	}
+	if pr.ForcePushCount != 1 || !pr.ForcePushedAfterApproval {
+		t.Errorf("ForcePushCount = %d, ForcePushedAfterApproval = %v; want 1 and true", pr.ForcePushCount, pr.ForcePushedAfterApproval)
+	}
+	if _, ok := pr.CheckSummary.Failing["test"]; ok {
+		t.Error("CheckSummary counts the orphaned run's failure")
+	}
+	if _, ok := pr.ChecksByCommit["old"]; ok {
+		t.Error("ChecksByCommit has the orphaned commit")
+	}
+}
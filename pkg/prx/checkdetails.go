@@ -0,0 +1,103 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// CheckRunDetails is what a check run reported about its failure beyond its title and summary.
+type CheckRunDetails struct {
+	Annotations []github.CheckRunAnnotation `json:"annotations,omitempty"`
+	Output      []string                    `json:"output,omitempty"` // First lines of the output text
+	ID          int64                       `json:"id"`
+	// The output text had more lines than were kept
+	OutputTruncated bool `json:"output_truncated,omitempty"`
+}
+
+// WithCheckRunDetails appends the failure annotations and first lines of output of failing check
+// runs to their descriptions in CheckSummary.Failing, so "3 tests failed" says which ones.
+// This costs two REST requests per failing check, so it is off by default.
+func WithCheckRunDetails(lines int) Option {
+	return func(c *Client) {
+		c.checkRunDetailLines = lines
+	}
+}
+
+// CheckRunDetails fetches the annotations of a check run, as identified by Event.CheckRunID, and
+// up to lines lines of its output text.
+func (c *Client) CheckRunDetails(ctx context.Context, owner, repo string, checkRunID int64, lines int) (*CheckRunDetails, error) {
+	out, err := c.FetchCheckOutput(ctx, owner, repo, checkRunID)
+	if err != nil {
+		return nil, err
+	}
+	var annotations []github.CheckRunAnnotation
+	path := fmt.Sprintf("/repos/%s/%s/check-runs/%d/annotations?per_page=100", owner, repo, checkRunID)
+	if _, err := c.github.Get(ctx, path, &annotations); err != nil {
+		return nil, fmt.Errorf("fetching check run annotations: %w", err)
+	}
+	for i := range annotations {
+		annotations[i].Title = c.redactText(annotations[i].Title)
+		annotations[i].Message = c.redactText(annotations[i].Message)
+	}
+
+	details := &CheckRunDetails{ID: checkRunID, Annotations: annotations}
+	text := strings.TrimSpace(out.Text)
+	if text == "" {
+		return details, nil
+	}
+	details.Output = strings.Split(text, "\n")
+	if len(details.Output) > lines {
+		details.Output = details.Output[:max(lines, 0)]
+		details.OutputTruncated = true
+	}
+	details.OutputTruncated = details.OutputTruncated || out.TextTruncated
+	return details, nil
+}
+
+// attachCheckRunDetails appends the details of each failing check run to its description in the
+// check summary, using its latest run among events. Errors are logged and added to report.
+func (c *Client) attachCheckRunDetails(ctx context.Context, owner, repo string, pr *PullRequest, events []Event, report *FetchReport) {
+	if pr.CheckSummary == nil {
+		return
+	}
+	for name, description := range pr.CheckSummary.Failing {
+		var id int64
+		for i := range events {
+			if events[i].Kind == EventKindCheckRun && events[i].Body == name && events[i].CheckRunID != 0 {
+				id = events[i].CheckRunID
+			}
+		}
+		if id == 0 {
+			continue // A commit status, which has no details
+		}
+		details, err := c.CheckRunDetails(ctx, owner, repo, id, c.checkRunDetailLines)
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to fetch check run details", "check", name, "error", err)
+			report.add(FetchSectionCheckRunDetails, name, err)
+			continue
+		}
+		pr.CheckSummary.Failing[name] = details.describe(description)
+	}
+}
+
+// describe appends the failure annotations and output to a check's description.
+func (d *CheckRunDetails) describe(description string) string {
+	parts := []string{description}
+	for _, a := range d.Annotations {
+		if a.AnnotationLevel != "failure" {
+			continue
+		}
+		message := a.Message
+		if a.Title != "" {
+			message = a.Title + ": " + message
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d: %s", a.Path, a.StartLine, message))
+	}
+	if len(d.Output) > 0 {
+		parts = append(parts, strings.Join(d.Output, "\n"))
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
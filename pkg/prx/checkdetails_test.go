@@ -0,0 +1,72 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_CheckRunDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/check-runs/42":
+			w.Write([]byte(`{"id": 42, "status": "completed", "output": {"title": "3 tests failed", "text": "FAIL: TestFoo\nexpected 1, got 2\nFAIL: TestBar\n"}}`))
+		case "/repos/owner/repo/check-runs/42/annotations":
+			w.Write([]byte(`[
+				{"path": "foo_test.go", "annotation_level": "failure", "title": "TestFoo", "message": "expected 1, got 2", "start_line": 12},
+				{"path": "foo.go", "annotation_level": "warning", "message": "unused variable", "start_line": 3}
+			]`))
+		case "/repos/owner/repo/check-runs/43":
+			w.Write([]byte(`{"id": 43, "status": "completed", "output": {"title": "Lint failed"}}`))
+		case "/repos/owner/repo/check-runs/43/annotations":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithCheckRunDetails(2))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	details, err := client.CheckRunDetails(ctx, "owner", "repo", 42, 2)
+	if err != nil {
+		t.Fatalf("CheckRunDetails() error: %v", err)
+	}
+	if len(details.Annotations) != 2 || len(details.Output) != 2 || !details.OutputTruncated {
+		t.Errorf("CheckRunDetails() = %+v, want 2 annotations and 2 truncated lines of output", details)
+	}
+
+	pr := &PullRequest{CheckSummary: &CheckSummary{Failing: map[string]string{
+		"test":    "3 tests failed",
+		"lint":    "Lint failed",
+		"ci/jenk": "Build #12",
+	}}}
+	events := []Event{
+		{Kind: EventKindCheckRun, Body: "test", CheckRunID: 41},
+		{Kind: EventKindCheckRun, Body: "test", CheckRunID: 42},
+		{Kind: EventKindCheckRun, Body: "lint", CheckRunID: 43},
+		{Kind: EventKindStatusCheck, Body: "ci/jenk"},
+	}
+	var report FetchReport
+	client.attachCheckRunDetails(ctx, "owner", "repo", pr, events, &report)
+
+	want := "3 tests failed\nfoo_test.go:12: TestFoo: expected 1, got 2\nFAIL: TestFoo\nexpected 1, got 2"
+	if got := pr.CheckSummary.Failing["test"]; got != want {
+		t.Errorf("Failing[test] = %q, want %q", got, want)
+	}
+	if got := pr.CheckSummary.Failing["lint"]; got != "Lint failed" {
+		t.Errorf("Failing[lint] = %q, want the description unchanged", got)
+	}
+	if got := pr.CheckSummary.Failing["ci/jenk"]; got != "Build #12" {
+		t.Errorf("Failing[ci/jenk] = %q, want the description unchanged", got)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Section != FetchSectionCheckRunDetails || report.Warnings[0].Target != "lint" {
+		t.Errorf("FetchReport = %+v, want one warning for lint", report)
+	}
+}
@@ -258,4 +258,10 @@ func TestClient_PullRequestWithBranchProtection(t *testing.T) {
 	if prData.PullRequest.MergeableStateDescription == "" {
 		t.Error("Expected MergeableStateDescription to be set for blocked PR")
 	}
+
+	// The ruleset's pull request rule requires reviews, though there's no branch protection rule
+	if prData.PullRequest.RequiredApprovals != 2 || prData.PullRequest.ApprovalsSatisfied {
+		t.Errorf("RequiredApprovals = %d, ApprovalsSatisfied = %v; want 2 from the ruleset, unsatisfied",
+			prData.PullRequest.RequiredApprovals, prData.PullRequest.ApprovalsSatisfied)
+	}
 }
@@ -0,0 +1,161 @@
+package prx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// CheckSimulation is how requiring a set of checks would have affected past pull requests.
+type CheckSimulation struct {
+	RequiredChecks []string `json:"required_checks"` // Sorted
+	// Pull requests that would have merged later, or not at all, by number
+	Blocked []BlockedMerge `json:"blocked,omitempty"`
+	// Number of pull requests each check would have held up
+	BlockedBy    map[string]int `json:"blocked_by,omitempty"`
+	PullRequests int            `json:"pull_requests"` // Merged pull requests evaluated
+	// Total and longest time merges would have waited on checks that eventually passed
+	TotalDelay time.Duration `json:"total_delay"`
+	MaxDelay   time.Duration `json:"max_delay"`
+}
+
+// BlockedMerge is a merged pull request that the simulated check requirement would have held up.
+type BlockedMerge struct {
+	MergedAt time.Time     `json:"merged_at"`
+	Checks   []string      `json:"checks"`          // Required checks that weren't passing when it merged, sorted
+	Delay    time.Duration `json:"delay,omitempty"` // Until all of them passed; zero if Never
+	Number   int           `json:"number"`
+	Never    bool          `json:"never,omitempty"` // A check never passed on the merged commit
+}
+
+// SimulateRequiredChecks re-evaluates merged pull requests in prs as if required checks had been
+// required, so admins can see what a branch protection or ruleset change would have blocked. A check
+// counts as passing once it succeeded, or was skipped or neutral, on the merged head commit; a merge
+// before then would have waited for it, and one with no passing run wouldn't have been allowed.
+// Open and closed pull requests are ignored.
+func SimulateRequiredChecks(prs []*PullRequestData, required []string) *CheckSimulation {
+	required = slices.Compact(slices.Sorted(slices.Values(required)))
+	sim := &CheckSimulation{RequiredChecks: required}
+	for _, data := range prs {
+		pr := &data.PullRequest
+		if !pr.Merged || pr.MergedAt == nil {
+			continue
+		}
+		sim.PullRequests++
+
+		blocked := BlockedMerge{Number: pr.Number, MergedAt: *pr.MergedAt}
+		var passedAt time.Time
+		for _, check := range required {
+			passed, ok := checkPassedAt(data.Events, check, pr.HeadSHA)
+			switch {
+			case !ok:
+				blocked.Never = true
+			case passed.After(*pr.MergedAt):
+				if passed.After(passedAt) {
+					passedAt = passed
+				}
+			default:
+				continue
+			}
+			blocked.Checks = append(blocked.Checks, check)
+		}
+		if len(blocked.Checks) == 0 {
+			continue
+		}
+		if !blocked.Never {
+			blocked.Delay = passedAt.Sub(*pr.MergedAt)
+			sim.TotalDelay += blocked.Delay
+			sim.MaxDelay = max(sim.MaxDelay, blocked.Delay)
+		}
+		if sim.BlockedBy == nil {
+			sim.BlockedBy = make(map[string]int)
+		}
+		for _, check := range blocked.Checks {
+			sim.BlockedBy[check]++
+		}
+		sim.Blocked = append(sim.Blocked, blocked)
+	}
+	slices.SortFunc(sim.Blocked, func(a, b BlockedMerge) int { return a.Number - b.Number })
+	return sim
+}
+
+// checkPassedAt returns when check first passed on the commit sha, and whether it did.
+func checkPassedAt(events []Event, check, sha string) (time.Time, bool) {
+	var first time.Time
+	found := false
+	for i := range events {
+		e := &events[i]
+		if e.Kind != EventKindCheckRun && e.Kind != EventKindStatusCheck || e.Body != check {
+			continue
+		}
+		if e.Target != "" && e.Target != sha {
+			continue
+		}
+		switch e.Outcome {
+		case "success", "skipped", "neutral":
+		default:
+			continue
+		}
+		if !found || e.Timestamp.Before(first) {
+			first, found = e.Timestamp, true
+		}
+	}
+	return first, found
+}
+
+// RulesetRequiredChecks returns the status checks required by branch rulesets, given as JSON as
+// exported from GitHub's ruleset settings or returned by its REST API: a single ruleset or a list.
+func RulesetRequiredChecks(data []byte) ([]string, error) {
+	var rulesets []github.Ruleset
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		rulesets = make([]github.Ruleset, 1)
+		if err := json.Unmarshal(trimmed, &rulesets[0]); err != nil {
+			return nil, fmt.Errorf("parsing ruleset: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &rulesets); err != nil {
+		return nil, fmt.Errorf("parsing rulesets: %w", err)
+	}
+	return requiredChecksFromRulesets(rulesets), nil
+}
+
+// requiredChecksFromRulesets returns the status checks required by the branch rulesets, in order.
+func requiredChecksFromRulesets(rulesets []github.Ruleset) []string {
+	var required []string
+	for _, rs := range rulesets {
+		if rs.Target != "branch" {
+			continue
+		}
+		for _, rule := range rs.Rules {
+			if rule.Type != "required_status_checks" {
+				continue
+			}
+			for _, chk := range rule.Parameters.RequiredStatusChecks {
+				if !slices.Contains(required, chk.Context) {
+					required = append(required, chk.Context)
+				}
+			}
+		}
+	}
+	return required
+}
+
+// requiredApprovalsFromRulesets returns the most approving reviews required by the branch rulesets'
+// pull request rules, or 0 if none require any.
+func requiredApprovalsFromRulesets(rulesets []github.Ruleset) int {
+	approvals := 0
+	for _, rs := range rulesets {
+		if rs.Target != "branch" {
+			continue
+		}
+		for _, rule := range rs.Rules {
+			if rule.Type == "pull_request" {
+				approvals = max(approvals, rule.Parameters.RequiredApprovingReviewCount)
+			}
+		}
+	}
+	return approvals
+}
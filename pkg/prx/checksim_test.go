@@ -0,0 +1,91 @@
+package prx
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+func TestSimulateRequiredChecks(t *testing.T) {
+	base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	check := func(name, outcome, sha string, minutes int) Event {
+		return Event{Kind: EventKindCheckRun, Body: name, Outcome: outcome, Target: sha, Timestamp: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+	merged := func(number int, head string, events ...Event) *PullRequestData {
+		return &PullRequestData{
+			PullRequest: PullRequest{Number: number, Merged: true, MergedAt: &base, HeadSHA: head, State: "closed"},
+			Events:      events,
+		}
+	}
+	prs := []*PullRequestData{
+		// Passed before merging
+		merged(1, "a1", check("build", "success", "a1", -30), check("lint", "skipped", "a1", -5)),
+		// Lint finished 20 minutes after the merge; build 10 minutes after
+		merged(2, "b2", check("build", "success", "b2", 10), check("lint", "failure", "b2", -5), check("lint", "success", "b2", 20)),
+		// Lint only passed on an earlier commit
+		merged(3, "c2", check("build", "success", "", -1), check("lint", "success", "c1", -60), check("lint", "failure", "c2", -2)),
+		{PullRequest: PullRequest{Number: 4, State: "open"}},
+	}
+
+	sim := SimulateRequiredChecks(prs, []string{"lint", "build", "lint"})
+	if sim.PullRequests != 3 || len(sim.Blocked) != 2 {
+		t.Fatalf("SimulateRequiredChecks() = %+v, want 2 of 3 blocked", sim)
+	}
+	if b := sim.Blocked[0]; b.Number != 2 || b.Never || b.Delay != 20*time.Minute || !slices.Equal(b.Checks, []string{"build", "lint"}) {
+		t.Errorf("Blocked[0] = %+v", b)
+	}
+	if b := sim.Blocked[1]; b.Number != 3 || !b.Never || b.Delay != 0 || !slices.Equal(b.Checks, []string{"lint"}) {
+		t.Errorf("Blocked[1] = %+v", b)
+	}
+	if sim.TotalDelay != 20*time.Minute || sim.MaxDelay != 20*time.Minute || sim.BlockedBy["lint"] != 2 || sim.BlockedBy["build"] != 1 {
+		t.Errorf("SimulateRequiredChecks() totals = %+v", sim)
+	}
+}
+
+func TestRulesetRequiredChecks(t *testing.T) {
+	ruleset := `{"name": "main", "target": "branch", "rules": [
+		{"type": "deletion"},
+		{"type": "required_status_checks", "parameters": {"required_status_checks": [{"context": "build"}, {"context": "test"}]}}
+	]}`
+	got, err := RulesetRequiredChecks([]byte(ruleset))
+	if err != nil || !slices.Equal(got, []string{"build", "test"}) {
+		t.Errorf("RulesetRequiredChecks(object) = %v, %v", got, err)
+	}
+
+	list := `[` + ruleset + `, {"target": "tag", "rules": [
+		{"type": "required_status_checks", "parameters": {"required_status_checks": [{"context": "release"}]}}
+	]}, {"target": "branch", "rules": [
+		{"type": "required_status_checks", "parameters": {"required_status_checks": [{"context": "test"}, {"context": "lint"}]}}
+	]}]`
+	got, err = RulesetRequiredChecks([]byte(list))
+	if err != nil || !slices.Equal(got, []string{"build", "test", "lint"}) {
+		t.Errorf("RulesetRequiredChecks(list) = %v, %v", got, err)
+	}
+
+	if _, err := RulesetRequiredChecks([]byte(`{"rules": "none"}`)); err == nil {
+		t.Error("RulesetRequiredChecks() with invalid JSON succeeded")
+	}
+}
+
+func TestRequiredApprovalsFromRulesets(t *testing.T) {
+	var rulesets []github.Ruleset
+	if err := json.Unmarshal([]byte(`[
+		{"target": "branch", "rules": [{"type": "pull_request", "parameters": {"required_approving_review_count": 1}}]},
+		{"target": "branch", "rules": [
+			{"type": "required_status_checks", "parameters": {"required_status_checks": [{"context": "build"}]}},
+			{"type": "pull_request", "parameters": {"required_approving_review_count": 2}}
+		]},
+		{"target": "tag", "rules": [{"type": "pull_request", "parameters": {"required_approving_review_count": 5}}]}
+	]`), &rulesets); err != nil {
+		t.Fatal(err)
+	}
+	if got := requiredApprovalsFromRulesets(rulesets); got != 2 {
+		t.Errorf("requiredApprovalsFromRulesets() = %d, want the most any branch ruleset requires", got)
+	}
+	if got := requiredApprovalsFromRulesets(nil); got != 0 {
+		t.Errorf("requiredApprovalsFromRulesets(nil) = %d, want 0", got)
+	}
+}
@@ -10,11 +10,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/codeGROOVE-dev/fido"
@@ -33,6 +39,13 @@ const (
 	checkRunsCacheTTL     = 20 * 24 * time.Hour // 20 days - validity checked against reference time
 	collaboratorsCacheTTL = 3 * time.Hour       // 3 hours - repo-level, simple TTL
 	rulesetsCacheTTL      = 3 * time.Hour       // 3 hours - repo-level, simple TTL
+	repositoryCacheTTL    = 3 * time.Hour       // 3 hours - repo-level, simple TTL
+	codeownersCacheTTL    = 3 * time.Hour       // 3 hours - repo-level, simple TTL
+	responseCacheTTL      = 24 * time.Hour      // 1 day - revalidated with ETags on every use
+	responseCacheSize     = 1024                // REST responses kept for conditional requests
+
+	// defaultConcurrency is the number of per-commit REST requests made at once.
+	defaultConcurrency = 8
 )
 
 // cachedCheckRuns stores check run events with a timestamp for cache validation.
@@ -47,13 +60,75 @@ type PRStore = fido.Store[string, PullRequestData]
 
 // Client provides methods to fetch GitHub pull request events.
 type Client struct {
-	github             *github.Client
-	logger             *slog.Logger
-	collaboratorsCache *fido.Cache[string, map[string]string]
-	rulesetsCache      *fido.Cache[string, []string]
-	checkRunsCache     *fido.Cache[string, cachedCheckRuns]
-	prCache            *fido.TieredCache[string, PullRequestData]
-	token              string // Store token for recreating client with new transport
+	github              *github.Client
+	logger              *slog.Logger
+	collaboratorsCache  *fido.Cache[string, map[string]string]
+	teamsCache          *fido.Cache[string, map[string]string]
+	rulesetsCache       *fido.Cache[string, rulesetRequirements]
+	checkRunsCache      *fido.Cache[string, cachedCheckRuns]
+	ticketsCache        *fido.Cache[string, *Ticket]
+	checkOutputCache    *fido.Cache[string, *CheckOutput]
+	commitFilesCache    *fido.Cache[string, []string]
+	repositoryCache     *fido.Cache[string, *Repository]
+	codeownersCache     *fido.Cache[string, *Codeowners]
+	templatesCache      *fido.Cache[string, []PullRequestTemplate]
+	responseCache       *fido.Cache[string, *github.CachedResponse]
+	prCache             *fido.TieredCache[string, PullRequestData]
+	botCache            *fido.TieredCache[string, BotDecision]
+	policyCache         *fido.TieredCache[string, PolicyVerdict]
+	collaboratorsStore  *fido.TieredCache[string, map[string]string]   // optional persistence behind collaboratorsCache
+	rulesetsStore       *fido.TieredCache[string, rulesetRequirements] // optional persistence behind rulesetsCache
+	appTokens           *github.AppTokenSource
+	ticketResolver      TicketResolver
+	ticketPattern       *regexp.Regexp // Set by WithTicketPrefixes
+	metrics             Recorder
+	tracer              Tracer
+	featureFlagPatterns *FeatureFlagPatterns
+	tokenSource         github.TokenSource
+	apiVersion          string
+	baseURL             string // empty for github.com
+	graphQLURL          string
+	previews            []string
+	branchPatterns      []string
+	freezeWindows       []FreezeWindow
+	botOverrides        map[string]bool
+	partialData         map[string]bool // top-level pull request field -> whether GraphQL errors in it are tolerated
+	caps                *Capabilities   // Set by a successful probe; see serverCapabilities
+	capsRetryAt         time.Time       // When to probe again after a failed probe
+	rulesetsNotFound    bool            // The server answered the rulesets endpoint with 404
+	rateLimit           RateLimitState
+	rateLimitBudget     int
+	maxBodyLength       int // 0 disables truncation
+	maxDiffSize         int // 0 disables the limit below the API client's
+	concurrency         int
+	checkRunDetailLines int // 0 unless WithCheckRunDetails is used
+	commitEmailDomains  bool
+	rawPayloads         bool
+	commitFiles         bool
+	changedLineBlame    bool
+	capsMu              sync.Mutex
+	rateLimitMu         sync.Mutex
+	token               string // Store token for recreating client with new transport
+	// descriptionTemplates enables checking descriptions against pull request templates.
+	descriptionTemplates bool
+	// Set by WithRetry and WithRepositoryCacheTTL; zero values keep the defaults.
+	retryAttempts      uint
+	retryMaxDelay      time.Duration
+	repositoryCacheTTL time.Duration
+	profile            Profile // Set by WithProfile, for Capabilities
+	// Lower-cased logins of service accounts; see WithMachineUsers.
+	machineUsers map[string]bool
+	enrichers    []Enricher
+	classifiers  []EventClassifier
+	// Added by WithQuestionPatterns to the built-in question phrases.
+	questionPatterns  []*regexp.Regexp
+	questionsDisabled bool
+	// Secrets redacted from fetched text; nil unless WithSecretRedaction is used.
+	secretPatterns []*regexp.Regexp
+	// REST base URLs to fail over to, set by WithFailoverURLs.
+	failoverURLs []string
+	// excludeDeletedFileThreads leaves threads on files no longer in the pull request out of metrics.
+	excludeDeletedFileThreads bool
 }
 
 // Option is a function that configures a Client.
@@ -92,10 +167,133 @@ func WithCacheStore(store PRStore) Option {
 	}
 }
 
+// AppAuth configures authentication as a GitHub App installation.
+// Either PrivateKey or InstallationToken must be set.
+type AppAuth struct {
+	// InstallationToken, if set, supplies installation tokens and their expiry
+	// instead of minting them from PrivateKey.
+	InstallationToken func(ctx context.Context) (token string, expiresAt time.Time, err error)
+	PrivateKey        []byte // PEM-encoded app private key
+	AppID             int64
+	InstallationID    int64
+}
+
+// WithAppAuth authenticates as a GitHub App installation instead of with a static token.
+// Installation tokens are refreshed automatically before they expire.
+func WithAppAuth(auth AppAuth) Option {
+	return func(c *Client) {
+		if auth.InstallationToken != nil {
+			c.appTokens = github.NewInstallationTokenSource(auth.InstallationToken)
+		} else {
+			c.appTokens = github.NewAppTokenSource(auth.AppID, auth.InstallationID, auth.PrivateKey)
+		}
+		c.tokenSource = c.appTokens.Token
+	}
+}
+
+// WithTokenSource supplies the GitHub token dynamically, so long-running services can
+// rotate credentials (e.g. from Vault or the gh CLI) without recreating the Client.
+// The source is called for every request, and once more to retry a request rejected with 401.
+func WithTokenSource(source func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.appTokens = nil
+		c.tokenSource = source
+	}
+}
+
+// WithAPIVersion pins the GitHub REST API version sent in the X-GitHub-Api-Version header.
+// Defaults to github.DefaultAPIVersion. Older GitHub Enterprise Server releases may need an older version.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithAPIPreviews requests the named API previews (e.g. "merge-info") via the Accept header.
+func WithAPIPreviews(previews ...string) Option {
+	return func(c *Client) {
+		c.previews = previews
+	}
+}
+
+// WithCommitEmailDomains enables aggregation of commit author email domains into
+// PullRequest.CommitEmailDomains, e.g. to detect commits from personal addresses on corporate repos.
+// Only domains are recorded, never full addresses.
+func WithCommitEmailDomains() Option {
+	return func(c *Client) {
+		c.commitEmailDomains = true
+	}
+}
+
+// WithDeletedFileThreadsExcluded leaves review threads on files that are no longer in the pull
+// request, as after a large refactor, out of ThreadSummary's counts other than PathDeleted, and stops
+// their comments from counting as questions. Their comments are still returned, with
+// Event.PathDeleted set as without this option.
+func WithDeletedFileThreadsExcluded() Option {
+	return func(c *Client) {
+		c.excludeDeletedFileThreads = true
+	}
+}
+
+// WithMaxBodyLength sets the length in bytes at which comment, review, and pull request bodies
+// and commit messages are truncated. The default is 256; n <= 0 disables truncation.
+func WithMaxBodyLength(n int) Option {
+	return func(c *Client) {
+		c.maxBodyLength = max(n, 0)
+	}
+}
+
+// WithConcurrency sets how many per-commit REST requests, such as check runs, are made at once.
+// The default is 8; n < 1 fetches one commit at a time.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = max(n, 1)
+	}
+}
+
+// WithFullBodies disables truncation of bodies and commit messages, e.g. for NLP on comments.
+func WithFullBodies() Option {
+	return WithMaxBodyLength(0)
+}
+
+// WithBaseURL points the client at a GitHub Enterprise Server instance, e.g.
+// "https://github.example.com/api/v3". If graphqlURL is empty, it is derived from restURL:
+// "/api/v3" becomes "/api/graphql", and any other base URL gets "/graphql" appended.
+func WithBaseURL(restURL, graphqlURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(restURL, "/")
+		c.graphQLURL = strings.TrimSuffix(graphqlURL, "/")
+		if c.graphQLURL == "" {
+			c.graphQLURL = graphQLURLFor(c.baseURL)
+		}
+	}
+}
+
+// graphQLURLFor derives the GraphQL endpoint from a REST base URL, as described at WithBaseURL.
+func graphQLURLFor(restURL string) string {
+	if base, ok := strings.CutSuffix(restURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return restURL + "/graphql"
+}
+
+// WithFailoverURLs adds REST base URLs of mirrors that serve the same API as the primary endpoint
+// (api.github.com, or the one set by WithBaseURL), such as a caching proxy and the server behind it.
+// They are tried in order when the primary can't be reached or its gateway answers 502, 503, or 504,
+// and an endpoint that fails is skipped for 30 seconds before being tried again. The GraphQL endpoint
+// of each is derived as described at WithBaseURL.
+func WithFailoverURLs(restURLs ...string) Option {
+	return func(c *Client) {
+		for _, u := range restURLs {
+			c.failoverURLs = append(c.failoverURLs, strings.TrimSuffix(u, "/"))
+		}
+	}
+}
+
 // NewClient creates a new Client with the given GitHub token.
 // Caching is enabled by default with disk persistence.
 // Use WithCacheStore to provide a custom store (including null.New() to disable persistence).
-// If token is empty, WithHTTPClient option must be provided.
+// If token is empty, WithHTTPClient, WithAppAuth, or WithTokenSource option must be provided.
 func NewClient(token string, opts ...Option) *Client {
 	transport := &http.Transport{
 		MaxIdleConns:        maxIdleConns,
@@ -106,10 +304,21 @@ func NewClient(token string, opts ...Option) *Client {
 	}
 	c := &Client{
 		logger:             slog.Default(),
+		maxBodyLength:      maxTruncateLength,
+		concurrency:        defaultConcurrency,
+		maxDiffSize:        defaultMaxDiffSize,
 		token:              token,
 		collaboratorsCache: fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
-		rulesetsCache:      fido.New[string, []string](fido.TTL(rulesetsCacheTTL)),
+		teamsCache:         fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
+		rulesetsCache:      fido.New[string, rulesetRequirements](fido.TTL(rulesetsCacheTTL)),
 		checkRunsCache:     fido.New[string, cachedCheckRuns](fido.TTL(checkRunsCacheTTL)),
+		ticketsCache:       fido.New[string, *Ticket](fido.TTL(ticketsCacheTTL)),
+		checkOutputCache:   fido.New[string, *CheckOutput](fido.TTL(checkOutputCacheTTL), fido.Size(checkOutputCacheSize)),
+		commitFilesCache:   fido.New[string, []string](fido.TTL(commitFilesCacheTTL)),
+		repositoryCache:    fido.New[string, *Repository](fido.TTL(repositoryCacheTTL)),
+		codeownersCache:    fido.New[string, *Codeowners](fido.TTL(codeownersCacheTTL)),
+		templatesCache:     fido.New[string, []PullRequestTemplate](fido.TTL(templatesCacheTTL)),
+		responseCache:      fido.New[string, *github.CachedResponse](fido.TTL(responseCacheTTL), fido.Size(responseCacheSize)),
 		github: newGitHubClient(
 			&http.Client{
 				Transport: &github.Transport{Base: transport},
@@ -123,8 +332,16 @@ func NewClient(token string, opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.configureRepositoryCaches()
+	c.configureGitHubClient()
 
-	// Set up default cache if none was configured via options
+	// Set up default caches if none were configured via options
+	if c.botCache == nil {
+		c.botCache = createDefaultTieredCache[BotDecision](c.logger, "bot decisions", "prx-bot", botDecisionsCacheTTL, c.prCache != nil)
+	}
+	if c.policyCache == nil {
+		c.policyCache = createDefaultTieredCache[PolicyVerdict](c.logger, "policy verdicts", "prx-policy", policyVerdictsCacheTTL, c.prCache != nil)
+	}
 	if c.prCache == nil {
 		c.prCache = createDefaultCache(c.logger)
 	}
@@ -132,6 +349,54 @@ func NewClient(token string, opts ...Option) *Client {
 	return c
 }
 
+// configureGitHubClient applies client-level settings to the underlying GitHub client.
+// It runs after all options, since WithHTTPClient replaces the GitHub client.
+func (c *Client) configureGitHubClient() {
+	if c.baseURL != "" {
+		c.github.BaseURL = c.baseURL
+		c.github.GraphQLURL = c.graphQLURL
+	}
+	c.github.APIVersion = c.apiVersion
+	c.github.Previews = c.previews
+	if c.tokenSource != nil {
+		c.github.TokenSource = c.tokenSource
+	}
+	// Token exchange shares the configured transport, API endpoint, and version
+	if c.appTokens != nil {
+		c.appTokens.HTTPClient = c.github.HTTPClient
+		c.appTokens.BaseURL = c.github.BaseURL
+		c.appTokens.APIVersion = c.apiVersion
+		c.github.OnUnauthorized = c.appTokens.Invalidate
+	}
+	c.github.OnRateLimit = c.recordRateLimit
+	c.configureFailover()
+	c.configureRetry()
+	c.configureMetrics()
+	c.configureTracing()
+	if c.responseCache != nil {
+		c.github.ResponseCache = c.responseCache
+	}
+	c.github.Throttle = c.throttle
+}
+
+// configureFailover routes requests through the endpoints set by WithFailoverURLs, below the retry
+// transport so that each retry starts again with the first healthy endpoint.
+func (c *Client) configureFailover() {
+	t, ok := c.github.HTTPClient.Transport.(*github.Transport)
+	if !ok || len(c.failoverURLs) == 0 {
+		return
+	}
+	graphQLURL := c.github.GraphQLURL
+	if graphQLURL == "" {
+		graphQLURL = c.github.BaseURL + "/graphql"
+	}
+	endpoints := []github.Endpoint{{REST: c.github.BaseURL, GraphQL: graphQLURL}}
+	for _, u := range c.failoverURLs {
+		endpoints = append(endpoints, github.Endpoint{REST: u, GraphQL: graphQLURLFor(u)})
+	}
+	t.Base = &github.FailoverTransport{Base: t.Base, Endpoints: endpoints}
+}
+
 func createDefaultCache(log *slog.Logger) *fido.TieredCache[string, PullRequestData] {
 	dir, err := os.UserCacheDir()
 	if err != nil {
@@ -167,23 +432,48 @@ func (c *Client) PullRequestWithReferenceTime(
 	pr int,
 	refTime time.Time,
 ) (*PullRequestData, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "prx.PullRequest", repoAttributes(owner, repo, pr)...)
+	data, err := c.pullRequestWithReferenceTime(ctx, owner, repo, pr, refTime)
+	endSpan(span, err)
+	if c.metrics != nil {
+		c.metrics.Fetch(time.Since(start), err)
+	}
+	return data, err
+}
+
+func (c *Client) pullRequestWithReferenceTime(ctx context.Context, owner, repo string, pr int, refTime time.Time) (*PullRequestData, error) {
 	if c.prCache == nil {
-		return c.pullRequestViaGraphQL(ctx, owner, repo, pr, refTime)
+		data, err := c.pullRequestViaGraphQL(ctx, owner, repo, pr, refTime)
+		if err != nil {
+			return nil, err
+		}
+		assignSequence(data.Events, nil)
+		applyFreezeWindows(&data.PullRequest, c.freezeWindows, refTime)
+		return data, nil
 	}
 
-	key := prCacheKey(owner, repo, pr)
+	key := c.prCacheKey(owner, repo, pr)
 
-	if cached, found, err := c.prCache.Get(ctx, key); err != nil {
+	lookupCtx, span := c.startSpan(ctx, "prx.cache.get", SpanAttribute{Key: "cache.name", Value: MetricsCachePullRequest})
+	cached, found, err := c.prCache.Get(lookupCtx, key)
+	var previous []Event // events of a stale entry, whose sequence numbers are kept
+	span.SetAttributes(SpanAttribute{Key: "cache.hit", Value: found})
+	endSpan(span, err)
+	if err != nil {
 		c.logger.WarnContext(ctx, "cache get error", "error", err)
 	} else if found {
 		if !cached.CachedAt.Before(refTime) {
+			c.recordCacheLookup(MetricsCachePullRequest, true)
 			c.logger.InfoContext(ctx, "cache hit: GraphQL pull request",
 				"owner", owner, "repo", repo, "pr", pr, "cached_at", cached.CachedAt)
+			applyFreezeWindows(&cached.PullRequest, c.freezeWindows, refTime)
 			return &cached, nil
 		}
 		c.logger.InfoContext(ctx, "cache miss: GraphQL pull request expired",
 			"owner", owner, "repo", repo, "pr", pr,
 			"cached_at", cached.CachedAt, "reference_time", refTime)
+		previous = cached.Events
 		if err := c.prCache.Delete(ctx, key); err != nil {
 			c.logger.WarnContext(ctx, "failed to delete stale cache entry", "error", err)
 		}
@@ -192,26 +482,42 @@ func (c *Client) PullRequestWithReferenceTime(
 			"owner", owner, "repo", repo, "pr", pr)
 	}
 
+	c.recordCacheLookup(MetricsCachePullRequest, false)
 	result, err := c.prCache.Fetch(ctx, key, func(ctx context.Context) (PullRequestData, error) {
 		data, err := c.pullRequestViaGraphQL(ctx, owner, repo, pr, refTime)
 		if err != nil {
 			return PullRequestData{}, err
 		}
+		assignSequence(data.Events, previous)
 		data.CachedAt = time.Now()
 		return *data, nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	applyFreezeWindows(&result.PullRequest, c.freezeWindows, refTime)
 	return &result, nil
 }
 
 // Close releases cache resources.
 func (c *Client) Close() error {
+	var errs []error
+	if c.botCache != nil {
+		errs = append(errs, c.botCache.Close())
+	}
+	if c.policyCache != nil {
+		errs = append(errs, c.policyCache.Close())
+	}
 	if c.prCache != nil {
-		return c.prCache.Close()
+		errs = append(errs, c.prCache.Close())
+	}
+	if c.collaboratorsStore != nil {
+		errs = append(errs, c.collaboratorsStore.Close())
+	}
+	if c.rulesetsStore != nil {
+		errs = append(errs, c.rulesetsStore.Close())
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // NewCacheStore creates a cache store backed by the given directory.
@@ -238,6 +544,95 @@ func prCacheKey(owner, repo string, prNumber int) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// prCacheKey generates the cache key for PR data fetched by this client. Entries from another
+// server, or fetched with options that change the data, such as body truncation, raw payloads, or
+// commit files, are kept apart so the variants never mix.
+func (c *Client) prCacheKey(owner, repo string, prNumber int) string {
+	key := prCacheKey(owner, repo, prNumber)
+	var variant []string
+	if c.baseURL != "" && c.baseURL != github.API {
+		variant = append(variant, "server", c.baseURL)
+	}
+	if c.maxBodyLength != maxTruncateLength {
+		variant = append(variant, "body_length", strconv.Itoa(c.maxBodyLength))
+	}
+	if c.rawPayloads {
+		variant = append(variant, "raw")
+	}
+	if c.secretPatterns != nil {
+		variant = append(variant, "redacted")
+		for _, re := range c.secretPatterns[len(builtinSecretPatterns):] {
+			variant = append(variant, re.String())
+		}
+	}
+	if c.commitFiles {
+		variant = append(variant, "commit_files")
+	}
+	if c.changedLineBlame {
+		variant = append(variant, "changed_line_blame")
+	}
+	if c.checkRunDetailLines > 0 {
+		variant = append(variant, "check_run_details", strconv.Itoa(c.checkRunDetailLines))
+	}
+	if c.commitEmailDomains {
+		variant = append(variant, "commit_email_domains")
+	}
+	if len(c.machineUsers) > 0 {
+		logins := slices.Sorted(maps.Keys(c.machineUsers))
+		variant = append(variant, "machine_users", strings.Join(logins, ","))
+	}
+	for _, enrich := range c.enrichers {
+		variant = append(variant, "enricher", funcName(enrich))
+	}
+	for _, classify := range c.classifiers {
+		variant = append(variant, "classifier", funcName(classify))
+	}
+	if c.questionsDisabled {
+		variant = append(variant, "questions_disabled")
+	}
+	for _, re := range c.questionPatterns {
+		variant = append(variant, "question", re.String())
+	}
+	if c.excludeDeletedFileThreads {
+		variant = append(variant, "exclude_deleted_file_threads")
+	}
+	if p := c.featureFlagPatterns; p != nil {
+		variant = append(variant, "feature_flags", strings.Join(p.Paths, ","))
+		for _, re := range p.Calls {
+			variant = append(variant, re.String())
+		}
+	}
+	if len(c.branchPatterns) > 0 {
+		variant = append(variant, "branch_patterns", strings.Join(c.branchPatterns, ","))
+	}
+	if c.ticketResolver != nil {
+		variant = append(variant, "ticket_resolver", ticketResolverName(c.ticketResolver))
+	}
+	if c.ticketPattern != nil {
+		variant = append(variant, "ticket_pattern", c.ticketPattern.String())
+	}
+	if c.descriptionTemplates {
+		variant = append(variant, "description_templates")
+	}
+	for _, login := range slices.Sorted(maps.Keys(c.botOverrides)) {
+		variant = append(variant, "bot_override", login, strconv.FormatBool(c.botOverrides[login]))
+	}
+	for _, field := range slices.Sorted(maps.Keys(c.partialData)) {
+		variant = append(variant, "partial_data", field, strconv.FormatBool(c.partialData[field]))
+	}
+	if len(variant) == 0 {
+		return key
+	}
+	hash := sha256.Sum256([]byte(key + "/" + strings.Join(variant, "/")))
+	return hex.EncodeToString(hash[:])
+}
+
+// funcName names a function for cache keys. Closures are named after the function that declares
+// them, such as "main.run.func1", so closures of one function literal share a name.
+func funcName(f any) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
+
 // collaboratorsCacheKey generates a cache key for collaborators data.
 func collaboratorsCacheKey(owner, repo string) string {
 	return fmt.Sprintf("%s/%s", owner, repo)
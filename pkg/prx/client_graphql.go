@@ -2,10 +2,11 @@ package prx
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"sort"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
@@ -24,26 +25,73 @@ func (c *Client) pullRequestViaGraphQL(ctx context.Context, owner, repo string,
 		return nil, fmt.Errorf("GraphQL query failed: %w", err)
 	}
 
+	if c.ticketResolver != nil && len(prData.PullRequest.TicketRefs) > 0 {
+		prData.PullRequest.Tickets = c.resolveTickets(ctx, prData.PullRequest.TicketRefs, &prData.FetchReport)
+	}
+
+	if c.featureFlagPatterns != nil {
+		flags, err := c.fetchFeatureFlagChanges(ctx, owner, repo, prNumber)
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to detect feature flag changes", "error", err)
+			prData.FetchReport.add(FetchSectionFeatureFlags, "", err)
+		}
+		prData.PullRequest.FeatureFlags = flags
+	}
+
+	if repository, err := c.fetchRepository(ctx, owner, repo); err != nil {
+		c.logger.WarnContext(ctx, "failed to fetch repository metadata", "error", err)
+		prData.FetchReport.add(FetchSectionRepository, "", err)
+	} else {
+		prData.Repository = repository
+	}
+
+	if c.commitFiles && len(prData.PullRequest.Commits) > 0 {
+		prData.PullRequest.CommitFiles = c.fetchCommitFiles(ctx, owner, repo, prData.PullRequest.Commits, &prData.FetchReport)
+	}
+
+	if c.changedLineBlame {
+		authors, err := c.fetchChangedLineAuthors(ctx, owner, repo, prNumber)
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to blame changed lines", "error", err)
+			prData.FetchReport.add(FetchSectionBlame, "", err)
+		}
+		prData.PullRequest.ChangedLineAuthors = authors
+	}
+
 	// REST API calls for missing data (minimal)
-	// 1. Fetch rulesets (not available in GraphQL)
-	additionalRequired, err := c.fetchRulesetsREST(ctx, owner, repo)
+	// 1. Fetch rulesets (not available in GraphQL), unless the server lacks the API
+	var rulesets rulesetRequirements
+	if c.serverCapabilities(ctx).Rulesets {
+		rulesets, err = c.fetchRulesetsREST(ctx, owner, repo)
+	}
+	additionalRequired := rulesets.Checks
 	if err != nil {
-		c.logger.WarnContext(ctx, "failed to fetch rulesets", "error", err)
+		if !c.disableRulesets(ctx, err) {
+			c.logger.WarnContext(ctx, "failed to fetch rulesets", "error", err)
+			prData.FetchReport.add(FetchSectionRulesets, "", err)
+		}
 	} else if prData.PullRequest.CheckSummary != nil && len(additionalRequired) > 0 {
 		// Add to existing required checks
 		// Would need to recalculate with new required checks
 		c.logger.InfoContext(ctx, "added required checks from rulesets", "count", len(additionalRequired))
 	}
+	if rulesets.Approvals > prData.PullRequest.RequiredApprovals {
+		setRequiredApprovals(&prData.PullRequest, rulesets.Approvals)
+		setMergeableDescription(&prData.PullRequest)
+		setWaitingOn(&prData.PullRequest)
+	}
 
 	// Get existing required checks from GraphQL
-	existingRequired := c.existingRequiredChecks(prData)
+	existingRequired := existingRequiredChecks(prData)
 
 	// Combine with additional required checks from rulesets
 	existingRequired = append(existingRequired, additionalRequired...)
 
 	// 2. Fetch check runs via REST for all commits (GraphQL's statusCheckRollup is often null)
 	// This ensures we capture check run history including failures from earlier commits
-	checkRunEvents := c.fetchAllCheckRunsREST(ctx, owner, repo, prData, refTime)
+	checkRunEvents := c.fetchAllCheckRunsREST(ctx, owner, repo, prData, refTime, &prData.FetchReport)
+
+	markOrphanedChecks(checkRunEvents, &prData.PullRequest)
 
 	// Mark check runs as required based on combined list
 	for i := range checkRunEvents {
@@ -53,6 +101,7 @@ func (c *Client) pullRequestViaGraphQL(ctx context.Context, owner, repo string,
 	}
 
 	// Add check run events to the events list
+	c.enrichEvents(ctx, checkRunEvents, &prData.FetchReport)
 	prData.Events = append(prData.Events, checkRunEvents...)
 
 	// Recalculate check summary with the new check run data
@@ -67,6 +116,14 @@ func (c *Client) pullRequestViaGraphQL(ctx context.Context, owner, repo string,
 		return prData.Events[i].Timestamp.Before(prData.Events[j].Timestamp)
 	})
 
+	if c.checkRunDetailLines > 0 {
+		c.attachCheckRunDetails(ctx, owner, repo, &prData.PullRequest, prData.Events, &prData.FetchReport)
+	}
+
+	prData.ActivityHistogram = calculateActivityHistogram(prData.Events)
+	prData.Timing = calculateTimingSummary(&prData.PullRequest, prData.Events, refTime)
+	c.redactSecrets(prData)
+
 	apiCallsUsed := 2 // GraphQL + rulesets
 	if len(checkRunEvents) > 0 {
 		apiCallsUsed++ // + check runs
@@ -80,38 +137,36 @@ func (c *Client) pullRequestViaGraphQL(ctx context.Context, owner, repo string,
 	return prData, nil
 }
 
+// rulesetRequirements is what a repository's branch rulesets require before merging.
+type rulesetRequirements struct {
+	Checks    []string `json:"checks,omitempty"`    // Required status checks
+	Approvals int      `json:"approvals,omitempty"` // Required approving reviews
+}
+
 // fetchRulesetsREST fetches repository rulesets via REST API (not available in GraphQL).
 // Results are cached for 3 hours to reduce API calls. Uses Fetch to prevent thundering herds.
-func (c *Client) fetchRulesetsREST(ctx context.Context, owner, repo string) ([]string, error) {
+// With WithSQLiteCache, the cache also survives process restarts.
+func (c *Client) fetchRulesetsREST(ctx context.Context, owner, repo string) (rulesetRequirements, error) {
 	cacheKey := rulesetsCacheKey(owner, repo)
 
-	return c.rulesetsCache.Fetch(cacheKey, func() ([]string, error) {
+	return c.rulesetsCache.Fetch(cacheKey, readThrough(ctx, c, c.rulesetsStore, cacheKey, func() (rulesetRequirements, error) {
 		path := fmt.Sprintf("/repos/%s/%s/rulesets", owner, repo)
 		var rulesets []github.Ruleset
 
 		if _, err := c.github.Get(ctx, path, &rulesets); err != nil {
-			return nil, err
+			return rulesetRequirements{}, err
 		}
 
-		var required []string
-		for _, rs := range rulesets {
-			if rs.Target != "branch" {
-				continue
-			}
-			for _, rule := range rs.Rules {
-				if rule.Type == "required_status_checks" && rule.Parameters.RequiredStatusChecks != nil {
-					for _, chk := range rule.Parameters.RequiredStatusChecks {
-						required = append(required, chk.Context)
-					}
-				}
-			}
+		required := rulesetRequirements{
+			Checks:    requiredChecksFromRulesets(rulesets),
+			Approvals: requiredApprovalsFromRulesets(rulesets),
 		}
-
-		c.logger.InfoContext(ctx, "fetched required checks from rulesets",
-			"owner", owner, "repo", repo, "count", len(required), "checks", required)
+		c.logger.InfoContext(ctx, "fetched requirements from rulesets",
+			"owner", owner, "repo", repo, "count", len(required.Checks), "checks", required.Checks,
+			"approvals", required.Approvals)
 
 		return required, nil
-	})
+	}))
 }
 
 // truncateSHA returns the first 7 characters of a SHA, or the full string if shorter.
@@ -134,6 +189,7 @@ func (c *Client) fetchCheckRunsREST(ctx context.Context, owner, repo, sha string
 	// Check cache with reference time validation
 	if cached, ok := c.checkRunsCache.Get(cacheKey); ok {
 		if !cached.CachedAt.Before(refTime) {
+			c.recordCacheLookup(MetricsCacheCheckRuns, true)
 			c.logger.InfoContext(ctx, "cache hit: check runs",
 				"owner", owner, "repo", repo, "sha", truncateSHA(sha), "count", len(cached.Events))
 			return cached.Events, nil
@@ -143,54 +199,52 @@ func (c *Client) fetchCheckRunsREST(ctx context.Context, owner, repo, sha string
 			"cached_at", cached.CachedAt, "reference_time", refTime)
 	}
 
+	c.recordCacheLookup(MetricsCacheCheckRuns, false)
 	path := fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?per_page=100", owner, repo, sha)
 	var checkRuns github.CheckRuns
-	if _, err := c.github.Get(ctx, path, &checkRuns); err != nil {
+	var raw []json.RawMessage
+	if c.rawPayloads {
+		var body json.RawMessage
+		if _, err := c.github.Get(ctx, path, &body); err != nil {
+			return nil, fmt.Errorf("fetching check runs: %w", err)
+		}
+		var rawRuns struct {
+			CheckRuns []json.RawMessage `json:"check_runs"`
+		}
+		if err := json.Unmarshal(body, &rawRuns); err != nil {
+			return nil, fmt.Errorf("decoding check runs: %w", err)
+		}
+		if err := json.Unmarshal(body, &checkRuns); err != nil {
+			return nil, fmt.Errorf("decoding check runs: %w", err)
+		}
+		raw = rawRuns.CheckRuns
+	} else if _, err := c.github.Get(ctx, path, &checkRuns); err != nil {
 		return nil, fmt.Errorf("fetching check runs: %w", err)
 	}
 
 	var events []Event
-	for _, run := range checkRuns.CheckRuns {
+	for i, run := range checkRuns.CheckRuns {
 		if run == nil {
 			continue
 		}
 
-		var timestamp time.Time
-		var outcome string
-
-		switch {
-		case !run.CompletedAt.IsZero():
-			timestamp = run.CompletedAt
-			outcome = strings.ToLower(run.Conclusion)
-		case !run.StartedAt.IsZero():
-			timestamp = run.StartedAt
-			outcome = strings.ToLower(run.Status)
-		default:
+		event, ok := latestCheckRunEvent(&checkRun{
+			startedAt:   run.StartedAt,
+			completedAt: run.CompletedAt,
+			name:        run.Name,
+			headSHA:     sha,
+			status:      run.Status,
+			conclusion:  run.Conclusion,
+			title:       run.Output.Title,
+			summary:     run.Output.Summary,
+			detailsURL:  run.DetailsURL,
+			id:          run.ID,
+		})
+		if !ok {
 			// No timestamp available, skip this check run
 			continue
 		}
-
-		event := Event{
-			Kind:      EventKindCheckRun,
-			Timestamp: timestamp,
-			Actor:     "github",
-			Bot:       true,
-			Body:      run.Name,
-			Outcome:   outcome,
-		}
-
-		// Build description from output
-		switch {
-		case run.Output.Title != "" && run.Output.Summary != "":
-			event.Description = fmt.Sprintf("%s: %s", run.Output.Title, run.Output.Summary)
-		case run.Output.Title != "":
-			event.Description = run.Output.Title
-		case run.Output.Summary != "":
-			event.Description = run.Output.Summary
-		default:
-			// No description available
-		}
-
+		event.Raw = rawAt(raw, i)
 		events = append(events, event)
 	}
 
@@ -209,43 +263,68 @@ func (c *Client) fetchCheckRunsREST(ctx context.Context, owner, repo, sha string
 // fetchAllCheckRunsREST fetches check runs for all commits in the PR.
 // This ensures we capture the full history including failures from earlier commits
 // that may have been superseded by successful runs on later commits.
-// Errors fetching individual commits are logged but don't stop the overall process.
+// Errors fetching individual commits are logged and added to report, but don't stop the overall process.
 // The refTime parameter is used for cache validation.
-func (c *Client) fetchAllCheckRunsREST(ctx context.Context, owner, repo string, prData *PullRequestData, refTime time.Time) []Event {
-	// Collect all unique commit SHAs from the PR
-	shas := make(map[string]bool)
-
-	// Add HEAD SHA (most important)
+func (c *Client) fetchAllCheckRunsREST(
+	ctx context.Context, owner, repo string, prData *PullRequestData, refTime time.Time, report *FetchReport,
+) []Event {
+	ctx, span := c.startSpan(ctx, "prx.checkRuns", repoAttributes(owner, repo, prData.PullRequest.Number)...)
+	defer span.End()
+
+	// Collect all unique commit SHAs from the PR, HEAD first (most important)
+	var shas []string
 	if prData.PullRequest.HeadSHA != "" {
-		shas[prData.PullRequest.HeadSHA] = true
+		shas = append(shas, prData.PullRequest.HeadSHA)
 	}
-
-	// Add all other commit SHAs from commit events
 	for i := range prData.Events {
 		e := &prData.Events[i]
-		if e.Kind == EventKindCommit && e.Body != "" {
-			shas[e.Body] = true
+		if e.Kind == EventKindCommit && e.Body != "" && !slices.Contains(shas, e.Body) {
+			shas = append(shas, e.Body)
+		}
+	}
+	// Then the heads force-pushed away, whose check runs are kept as orphaned
+	for i := range prData.Events {
+		e := &prData.Events[i]
+		if e.Kind == EventKindHeadRefForcePushed && e.BeforeSHA != "" && !slices.Contains(shas, e.BeforeSHA) {
+			shas = append(shas, e.BeforeSHA)
 		}
 	}
 
-	// Fetch check runs for each unique commit
+	// Fetch check runs for each unique commit, at most c.concurrency at a time
+	results := make([][]Event, len(shas))
+	errs := make([]error, len(shas))
+	sem := make(chan struct{}, max(c.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, sha := range shas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			events, err := c.fetchCheckRunsREST(ctx, owner, repo, sha, refTime)
+			if err != nil {
+				c.logger.WarnContext(ctx, "failed to fetch check runs for commit", "sha", sha, "error", err)
+				errs[i] = err
+				return
+			}
+			results[i] = events
+		}()
+	}
+	wg.Wait()
+
 	var all []Event
 	seen := make(map[string]bool) // Track unique check runs by "name:timestamp"
-
-	for sha := range shas {
-		events, err := c.fetchCheckRunsREST(ctx, owner, repo, sha, refTime)
-		if err != nil {
-			c.logger.WarnContext(ctx, "failed to fetch check runs for commit", "sha", sha, "error", err)
-			continue
+	for i, events := range results {
+		if errs[i] != nil {
+			report.add(FetchSectionCheckRuns, shas[i], errs[i])
 		}
-
 		// Add only unique check runs (same check can run on multiple commits)
-		for i := range events {
-			ev := &events[i]
+		for j := range events {
+			ev := &events[j]
 			key := fmt.Sprintf("%s:%s", ev.Body, ev.Timestamp.Format(time.RFC3339Nano))
 			if !seen[key] {
 				seen[key] = true
-				ev.Target = sha
+				ev.Target = shas[i]
 				all = append(all, *ev)
 			}
 		}
@@ -255,7 +334,7 @@ func (c *Client) fetchAllCheckRunsREST(ctx context.Context, owner, repo string,
 }
 
 // existingRequiredChecks extracts required checks that were already identified.
-func (*Client) existingRequiredChecks(prData *PullRequestData) []string {
+func existingRequiredChecks(prData *PullRequestData) []string {
 	var required []string
 
 	// Extract from existing events that are marked as required
@@ -292,6 +371,7 @@ func (c *Client) recalculateCheckSummaryWithCheckRuns(_ /* ctx */ context.Contex
 	// Recalculate the entire check summary from ALL events (including the new check runs)
 	// This ensures we get the latest state based on timestamps
 	prData.PullRequest.CheckSummary = calculateCheckSummary(prData.Events, required)
+	prData.PullRequest.ChecksByCommit = calculateChecksByCommit(prData.Events, prData.PullRequest.HeadSHA)
 
 	// Update test state based on the recalculated check summary
 	prData.PullRequest.TestState = c.calculateTestStateFromCheckSummary(prData.PullRequest.CheckSummary)
@@ -0,0 +1,299 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+func TestWithAppAuth_InstallationTokenProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghs_provided" {
+			t.Errorf("Authorization = %q, want Bearer ghs_provided", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`)) //nolint:errcheck // test handler
+	}))
+	defer server.Close()
+
+	calls := 0
+	client := NewClient("",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithAppAuth(AppAuth{
+			InstallationToken: func(context.Context) (string, time.Time, error) {
+				calls++
+				return "ghs_provided", time.Now().Add(time.Hour), nil
+			},
+		}),
+	)
+	client.github.BaseURL = server.URL
+
+	for range 2 {
+		if _, _, err := client.github.Do(context.Background(), "/repos/owner/repo/rulesets"); err != nil {
+			t.Fatalf("Do() error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected installation token to be fetched once, got %d", calls)
+	}
+}
+
+func TestWithAppAuth_OptionOrder(t *testing.T) {
+	// WithHTTPClient replaces the underlying GitHub client; app auth must survive it.
+	client := NewClient("",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithAppAuth(AppAuth{AppID: 1, InstallationID: 2, PrivateKey: []byte("key")}),
+		WithHTTPClient(&http.Client{}),
+	)
+	if client.github.TokenSource == nil {
+		t.Fatal("expected token source to be configured")
+	}
+	if client.appTokens.HTTPClient != client.github.HTTPClient {
+		t.Error("expected token exchange to share the configured HTTP client")
+	}
+}
+
+func TestWithTokenSource_RotatesTokens(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`)) //nolint:errcheck // test handler
+	}))
+	defer server.Close()
+
+	n := 0
+	client := NewClient("",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithAppAuth(AppAuth{AppID: 1}), // overridden by the later option
+		WithTokenSource(func(context.Context) (string, error) {
+			n++
+			return "token-" + strconv.Itoa(n), nil
+		}),
+	)
+	client.github.BaseURL = server.URL
+
+	for range 2 {
+		if _, _, err := client.github.Do(context.Background(), "/rate_limit"); err != nil {
+			t.Fatalf("Do() error: %v", err)
+		}
+	}
+	want := []string{"Bearer token-1", "Bearer token-2"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("Authorization headers = %v, want %v", seen, want)
+	}
+	if client.appTokens != nil {
+		t.Error("expected WithTokenSource to replace app auth")
+	}
+}
+
+func TestWithTokenSource_Error(t *testing.T) {
+	client := NewClient("",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithTokenSource(func(context.Context) (string, error) {
+			return "", errors.New("vault unavailable")
+		}),
+	)
+	_, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err == nil || !strings.Contains(err.Error(), "vault unavailable") {
+		t.Errorf("expected token source error, got %v", err)
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	client := NewClient("token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithAPIVersion("2026-03-10"),
+		WithAPIPreviews("merge-info"),
+		WithHTTPClient(&http.Client{}),
+	)
+	if client.github.APIVersion != "2026-03-10" {
+		t.Errorf("APIVersion = %q, want 2026-03-10", client.github.APIVersion)
+	}
+	if !slices.Equal(client.github.Previews, []string{"merge-info"}) {
+		t.Errorf("Previews = %v, want [merge-info]", client.github.Previews)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		restURL     string
+		graphqlURL  string
+		wantREST    string
+		wantGraphQL string
+	}{
+		{
+			name:        "GHES default layout",
+			restURL:     "https://github.example.com/api/v3/",
+			wantREST:    "https://github.example.com/api/v3",
+			wantGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:        "other base URL",
+			restURL:     "https://proxy.example.com/github",
+			wantREST:    "https://proxy.example.com/github",
+			wantGraphQL: "https://proxy.example.com/github/graphql",
+		},
+		{
+			name:        "explicit GraphQL endpoint",
+			restURL:     "https://github.example.com/api/v3",
+			graphqlURL:  "https://graphql.example.com/",
+			wantREST:    "https://github.example.com/api/v3",
+			wantGraphQL: "https://graphql.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WithHTTPClient replaces the underlying GitHub client; the base URL must survive it.
+			client := NewClient("token",
+				WithCacheStore(null.New[string, PullRequestData]()),
+				WithBaseURL(tt.restURL, tt.graphqlURL),
+				WithHTTPClient(&http.Client{}),
+			)
+			if client.github.BaseURL != tt.wantREST {
+				t.Errorf("BaseURL = %q, want %q", client.github.BaseURL, tt.wantREST)
+			}
+			if client.github.GraphQLURL != tt.wantGraphQL {
+				t.Errorf("GraphQLURL = %q, want %q", client.github.GraphQLURL, tt.wantGraphQL)
+			}
+		})
+	}
+
+	// PRs from different servers are cached under different keys
+	ghes := NewClient("token", WithCacheStore(null.New[string, PullRequestData]()), WithBaseURL("https://github.example.com/api/v3", ""))
+	def := NewClient("token", WithCacheStore(null.New[string, PullRequestData]()))
+	if ghes.prCacheKey("o", "r", 1) == def.prCacheKey("o", "r", 1) {
+		t.Error("expected different cache keys for different servers")
+	}
+}
+
+func TestWithMaxBodyLength(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	tests := []struct {
+		name string
+		opt  Option
+		in   string
+		want string
+	}{
+		{name: "default", opt: nil, in: long, want: long[:256]},
+		{name: "custom", opt: WithMaxBodyLength(10), in: long, want: long[:10]},
+		{name: "full bodies", opt: WithFullBodies(), in: long, want: long},
+		{name: "negative disables", opt: WithMaxBodyLength(-1), in: long, want: long},
+		{name: "short body", opt: WithMaxBodyLength(10), in: "short", want: "short"},
+		{name: "keeps runes whole", opt: WithMaxBodyLength(4), in: "abc€def", want: "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithCacheStore(null.New[string, PullRequestData]())}
+			if tt.opt != nil {
+				opts = append(opts, tt.opt)
+			}
+			client := NewClient("test-token", opts...)
+			if got := client.truncate(tt.in); got != tt.want {
+				t.Errorf("truncate() = %q (%d bytes), want %d bytes", got, len(got), len(tt.want))
+			}
+		})
+	}
+
+	// Full and truncated bodies are cached under different keys
+	full := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithFullBodies())
+	def := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	if full.prCacheKey("o", "r", 1) == def.prCacheKey("o", "r", 1) {
+		t.Error("expected different cache keys for full and truncated bodies")
+	}
+	if def.prCacheKey("o", "r", 1) != prCacheKey("o", "r", 1) {
+		t.Error("expected default cache key to be unchanged")
+	}
+}
+
+func tagTeam(_ context.Context, e *Event) error {
+	e.Tags = map[string]string{"team": "core"}
+	return nil
+}
+
+func tagTicket(_ context.Context, e *Event) error {
+	e.Tags = map[string]string{"ticket": "PROJ-1"}
+	return nil
+}
+
+// TestPRCacheKeyVariants checks that options that change pull request data vary the cache key.
+func TestPRCacheKeyVariants(t *testing.T) {
+	def := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]())).prCacheKey("o", "r", 1)
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"commit email domains", []Option{WithCommitEmailDomains()}},
+		{"secret redaction", []Option{WithSecretRedaction()}},
+		{"more secret redaction", []Option{WithSecretRedaction(regexp.MustCompile(`internal-[a-z0-9]{12}`))}},
+		{"machine users", []Option{WithMachineUsers("release-bot")}},
+		{"other machine users", []Option{WithMachineUsers("ci-account")}},
+		{"enricher", []Option{WithEnricher(tagTeam)}},
+		{"other enricher", []Option{WithEnricher(tagTicket)}},
+		{"classifier", []Option{WithEventClassifier(func(string) map[string]any { return nil })}},
+		{"question patterns", []Option{WithQuestionPatterns(SpanishQuestionPatterns())}},
+		{"questions disabled", []Option{WithQuestionPatterns(QuestionPatterns{Disable: true})}},
+		{"deleted file threads", []Option{WithDeletedFileThreadsExcluded()}},
+		{"feature flags", []Option{WithFeatureFlagDetection(DefaultFeatureFlagPatterns())}},
+		{"other feature flags", []Option{WithFeatureFlagDetection(FeatureFlagPatterns{Paths: []string{"flags.json"}})}},
+		{"branch patterns", []Option{WithBranchPatterns("feature/*")}},
+		{"ticket prefixes", []Option{WithTicketPrefixes("ENG")}},
+		{"ticket resolver", []Option{WithTicketResolver(NewJiraResolver("https://example.atlassian.net", "", ""))}},
+		{"other ticket resolver", []Option{WithTicketResolver(NewJiraResolver("https://other.atlassian.net", "", ""))}},
+		{"description templates", []Option{WithDescriptionTemplates()}},
+		{"bot overrides", []Option{WithBotOverrides(map[string]bool{"robot": false})}},
+		{"partial data", []Option{WithPartialData(map[string]bool{"files": true})}},
+		{"changed line blame", []Option{WithChangedLineBlame()}},
+	}
+	keys := map[string]string{def: "default"}
+	for _, tt := range tests {
+		opts := append([]Option{WithCacheStore(null.New[string, PullRequestData]())}, tt.opts...)
+		key := NewClient("test-token", opts...).prCacheKey("o", "r", 1)
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s: same cache key as %s", tt.name, other)
+		}
+		keys[key] = tt.name
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithProfile(ProfileBulk))
+	if client.concurrency != 16 || client.rateLimitBudget != 500 || client.repositoryCacheTTL != 12*time.Hour {
+		t.Errorf("bulk profile: concurrency %d, budget %d, repository TTL %v", client.concurrency, client.rateLimitBudget, client.repositoryCacheTTL)
+	}
+	transport, ok := client.github.HTTPClient.Transport.(*github.Transport)
+	if !ok || transport.Attempts != 10 || transport.MaxDelay != 2*time.Minute {
+		t.Errorf("bulk profile: transport = %+v", client.github.HTTPClient.Transport)
+	}
+
+	// Later options override the profile, and retries survive WithHTTPClient
+	client = NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithProfile(ProfileInteractive),
+		WithMaxBodyLength(100), WithHTTPClient(&http.Client{}))
+	if client.maxBodyLength != 100 {
+		t.Errorf("maxBodyLength = %d, want 100 from the later option", client.maxBodyLength)
+	}
+	if transport, ok := client.github.HTTPClient.Transport.(*github.Transport); !ok || transport.Attempts != 3 {
+		t.Errorf("interactive profile: transport = %+v", client.github.HTTPClient.Transport)
+	}
+
+	client = NewClient("test-token", WithProfile(ProfileCI))
+	if client.rateLimitBudget != 100 || client.concurrency != defaultConcurrency {
+		t.Errorf("ci profile: budget %d, concurrency %d", client.rateLimitBudget, client.concurrency)
+	}
+
+	client = NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithProfile("fast"))
+	if client.retryAttempts != 0 || client.concurrency != defaultConcurrency {
+		t.Error("unknown profile changed the client")
+	}
+}
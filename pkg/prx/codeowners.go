@@ -0,0 +1,213 @@
+package prx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// codeownersPaths are the locations GitHub reads a CODEOWNERS file from, in order of precedence.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is a single CODEOWNERS line: a path pattern and the owners of matching files.
+type CodeownersRule struct {
+	pattern *regexp.Regexp
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners,omitempty"` // Empty for a rule that removes ownership
+	Line    int      `json:"line"`
+}
+
+// Codeowners is a parsed CODEOWNERS file.
+type Codeowners struct {
+	Rules []CodeownersRule `json:"rules"`
+}
+
+// ParseCodeowners parses a CODEOWNERS file. Lines with invalid patterns are skipped, as GitHub does.
+func ParseCodeowners(data []byte) *Codeowners {
+	co := &Codeowners{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		re, err := codeownersPattern(fields[0])
+		if err != nil {
+			continue
+		}
+		co.Rules = append(co.Rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:], Line: line, pattern: re})
+	}
+	return co
+}
+
+// Match returns the rule that owns path, which is the last matching rule, or nil if none does.
+func (co *Codeowners) Match(path string) *CodeownersRule {
+	if i := co.matchIndex(path); i >= 0 {
+		return &co.Rules[i]
+	}
+	return nil
+}
+
+// matchIndex returns the index of the rule that owns path, or -1.
+func (co *Codeowners) matchIndex(path string) int {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(co.Rules) - 1; i >= 0; i-- {
+		if co.Rules[i].pattern.MatchString(path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// codeownersPattern compiles a gitignore-style CODEOWNERS pattern. Patterns containing a slash
+// other than a trailing one are anchored to the repository root; others match at any depth.
+// A pattern also matches everything under a directory it matches, except that "dir/*" only
+// matches files directly in dir.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Codeowners fetches and parses the repository's CODEOWNERS file from its default branch.
+// It returns nil without an error if the repository has none. Results are cached per repository.
+func (c *Client) Codeowners(ctx context.Context, owner, repo string) (*Codeowners, error) {
+	return c.codeownersCache.Fetch(repositoryCacheKey(owner, repo), func() (*Codeowners, error) {
+		for _, path := range codeownersPaths {
+			data, err := c.github.FileContents(ctx, owner, repo, path)
+			var apiErr *github.Error
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("fetching %s: %w", path, err)
+			}
+			return ParseCodeowners(data), nil
+		}
+		return nil, nil
+	})
+}
+
+// OwnershipDrift describes a CODEOWNERS rule whose owners didn't write the code changed in the files it covers.
+type OwnershipDrift struct {
+	Authors   map[string]int `json:"authors"` // Changed lines in the rule's files, by who last changed them before
+	Pattern   string         `json:"pattern"`
+	Owners    []string       `json:"owners"`
+	Inactive  []string       `json:"inactive"`            // Individual owners who wrote none of those lines
+	Unchecked []string       `json:"unchecked,omitempty"` // Team and email owners, which can't be matched to authors
+	Line      int            `json:"line"`
+}
+
+// OwnershipReport accumulates, across pull requests, who historically wrote the lines changed in the
+// files covered by each CODEOWNERS rule, as found by blaming them. It's built from data gathered when
+// fetching pull requests with WithChangedLineBlame; pull requests fetched without it add nothing.
+// Lines last changed by bots are ignored. An OwnershipReport is not safe for concurrent use.
+type OwnershipReport struct {
+	codeowners *Codeowners
+	authors    map[int]map[string]int // rule index -> author login -> changed lines
+}
+
+// NewOwnershipReport returns an empty report for the given CODEOWNERS file.
+func NewOwnershipReport(codeowners *Codeowners) *OwnershipReport {
+	return &OwnershipReport{codeowners: codeowners, authors: make(map[int]map[string]int)}
+}
+
+// Add records the authors of the lines a pull request changed, from PullRequest.ChangedLineAuthors.
+func (r *OwnershipReport) Add(data *PullRequestData) {
+	if r.codeowners == nil {
+		return
+	}
+	for path, authors := range data.PullRequest.ChangedLineAuthors {
+		idx := r.codeowners.matchIndex(path)
+		if idx < 0 {
+			continue
+		}
+		for author, lines := range authors {
+			if r.authors[idx] == nil {
+				r.authors[idx] = make(map[string]int)
+			}
+			r.authors[idx][strings.ToLower(author)] += lines
+		}
+	}
+}
+
+// Drift returns, in CODEOWNERS order, the rules covering changed lines for which at least one
+// individual owner wrote none of those lines.
+func (r *OwnershipReport) Drift() []OwnershipDrift {
+	idxs := make([]int, 0, len(r.authors))
+	for idx := range r.authors {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+
+	var drift []OwnershipDrift
+	for _, idx := range idxs {
+		rule := &r.codeowners.Rules[idx]
+		d := OwnershipDrift{Pattern: rule.Pattern, Owners: rule.Owners, Line: rule.Line, Authors: r.authors[idx]}
+		for _, o := range rule.Owners {
+			login, individual := strings.CutPrefix(o, "@")
+			switch {
+			case !individual || strings.Contains(login, "/"):
+				d.Unchecked = append(d.Unchecked, o)
+			case d.Authors[strings.ToLower(login)] == 0:
+				d.Inactive = append(d.Inactive, o)
+			default:
+				// Owner wrote some of the changed lines
+			}
+		}
+		if len(d.Inactive) > 0 {
+			drift = append(drift, d)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,115 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+const testCodeowners = `# Default owners
+*               @alice
+
+*.go            @gopher @org/go-team
+/docs/          docs@example.com
+pkg/*           @bob
+**/testdata/**  @carol
+vendor/
+`
+
+func TestCodeowners_Match(t *testing.T) {
+	co := ParseCodeowners([]byte(testCodeowners))
+	if len(co.Rules) != 6 {
+		t.Fatalf("expected 6 rules, got %d", len(co.Rules))
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"README.md", "*"},
+		{"cmd/prx/main.go", "*.go"},
+		{"docs/guide/intro.md", "/docs/"},
+		{"internal/docs/notes.md", "*"},
+		{"pkg/README.md", "pkg/*"},
+		{"pkg/prx/README.md", "*"},
+		{"pkg/prx/testdata/pr.json", "**/testdata/**"},
+		{"vendor/x/y.go", "vendor/"},
+	}
+	for _, tt := range tests {
+		rule := co.Match(tt.path)
+		if rule == nil || rule.Pattern != tt.want {
+			t.Errorf("Match(%q) = %+v, want pattern %q", tt.path, rule, tt.want)
+		}
+	}
+	if owners := co.Match("vendor/x/y.go").Owners; len(owners) != 0 {
+		t.Errorf("expected vendor/ to have no owners, got %v", owners)
+	}
+	if rule := (&Codeowners{}).Match("main.go"); rule != nil {
+		t.Errorf("expected no match without rules, got %+v", rule)
+	}
+}
+
+func TestOwnershipReport_Drift(t *testing.T) {
+	report := NewOwnershipReport(ParseCodeowners([]byte(testCodeowners)))
+	report.Add(&PullRequestData{PullRequest: PullRequest{
+		Author: "dave",
+		ChangedLineAuthors: map[string]map[string]int{
+			"main.go":   {"Gopher": 3, "dave": 1},
+			"client.go": {"gopher": 2},
+			"README.md": {"dave": 4},
+		},
+	}})
+	report.Add(&PullRequestData{PullRequest: PullRequest{ChangedLineAuthors: map[string]map[string]int{"docs/index.md": {"erin": 2}}}})
+	// Pull requests fetched without blame add nothing, even though the author changed files
+	report.Add(&PullRequestData{PullRequest: PullRequest{Author: "alice", Files: []string{"README.md"}}})
+
+	// *.go is covered by its individual owner, and /docs/ only has an email owner, which can't be checked
+	drift := report.Drift()
+	if len(drift) != 1 {
+		t.Fatalf("expected drift for 1 rule, got %+v", drift)
+	}
+	if drift[0].Pattern != "*" || !slices.Equal(drift[0].Inactive, []string{"@alice"}) || drift[0].Authors["dave"] != 4 {
+		t.Errorf("drift[0] = %+v, want @alice inactive on * with dave's 4 lines", drift[0])
+	}
+}
+
+func TestClient_Codeowners(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/CODEOWNERS":
+			w.Write([]byte(`{"encoding": "base64", "content": "` + base64.StdEncoding.EncodeToString([]byte("*.go @gopher\n")) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	for range 2 {
+		co, err := client.Codeowners(context.Background(), "owner", "repo")
+		if err != nil {
+			t.Fatalf("Codeowners() error: %v", err)
+		}
+		if rule := co.Match("main.go"); rule == nil || !slices.Equal(rule.Owners, []string{"@gopher"}) {
+			t.Errorf("Match(main.go) = %+v, want @gopher", rule)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected .github/CODEOWNERS then CODEOWNERS to be fetched once, got %d requests", requests)
+	}
+
+	co, err := client.Codeowners(context.Background(), "owner", "empty")
+	if err != nil || co != nil {
+		t.Errorf("Codeowners() = %+v, %v; want nil without a CODEOWNERS file", co, err)
+	}
+}
@@ -3,6 +3,8 @@ package prx
 import (
 	"context"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/codeGROOVE-dev/fido"
@@ -235,3 +237,61 @@ func TestWriteAccessFromAssociationNonMember(t *testing.T) {
 		t.Error("Cache should not have been populated for non-MEMBER associations")
 	}
 }
+
+// TestWriteAccessFromTeams tests the team fallback when the collaborators API is forbidden
+//
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+func TestWriteAccessFromTeams(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Lists have a second page
+		next := func() { w.Header().Set("Link", `<`+r.URL.Path+`?per_page=100&page=2>; rel="next"`) }
+		switch page := r.URL.Query().Get("page"); r.URL.Path {
+		case "/repos/owner/repo/teams":
+			if page == "2" {
+				w.Write([]byte(`[{"slug": "docs", "permission": "pull"}]`))
+				return
+			}
+			next()
+			w.Write([]byte(`[{"slug": "core", "permission": "push"}]`))
+		case "/orgs/owner/teams/core/members":
+			if page == "2" {
+				w.Write([]byte(`[{"login": "dave"}]`))
+				return
+			}
+			next()
+			w.Write([]byte(`[{"login": "alice"}]`))
+		case "/orgs/owner/teams/docs/members":
+			w.Write([]byte(`[{"login": "alice"}, {"login": "bob"}]`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Must have push access to view repository collaborators."}`))
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		logger:             slog.Default(),
+		collaboratorsCache: fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
+		teamsCache:         fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
+		github:             newTestGitHubClient(&http.Client{}, "test-token", server.URL),
+	}
+
+	tests := []struct {
+		user     string
+		expected int
+	}{
+		{"alice", WriteAccessDefinitely}, // Highest permission across teams wins
+		{"bob", WriteAccessNo},
+		{"dave", WriteAccessDefinitely}, // On the second page of members
+		{"carol", WriteAccessLikely},    // In no team; may have a direct grant
+	}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			result := c.writeAccessFromAssociation(ctx, "owner", "repo", tt.user, "MEMBER")
+			if result != tt.expected {
+				t.Errorf("writeAccessFromAssociation(MEMBER, %s) = %d, want %d", tt.user, result, tt.expected)
+			}
+		})
+	}
+}
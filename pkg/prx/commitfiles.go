@@ -0,0 +1,51 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// commitFilesCacheTTL is how long commit file lists are cached; commits are immutable.
+const commitFilesCacheTTL = 24 * time.Hour
+
+// WithCommitFiles populates PullRequest.CommitFiles with the files each commit touched, so review
+// comments and check failures can be attributed to the commit that introduced them.
+// This costs one REST request per commit, so it is off by default.
+func WithCommitFiles() Option {
+	return func(c *Client) {
+		c.commitFiles = true
+	}
+}
+
+// fetchCommitFiles fetches the files changed by each commit, keyed by SHA. Errors fetching
+// individual commits are logged and added to report, and those commits omitted.
+func (c *Client) fetchCommitFiles(ctx context.Context, owner, repo string, shas []string, report *FetchReport) map[string][]string {
+	ctx, span := c.startSpan(ctx, "prx.commitFiles", SpanAttribute{Key: "github.commits", Value: len(shas)})
+	defer span.End()
+
+	result := make(map[string][]string, len(shas))
+	for _, sha := range shas {
+		key := fmt.Sprintf("%s/%s/%s", owner, repo, sha)
+		files, err := c.commitFilesCache.Fetch(key, func() ([]string, error) {
+			changed, err := c.github.CommitFiles(ctx, owner, repo, sha)
+			if err != nil {
+				return nil, err
+			}
+			paths := make([]string, 0, len(changed))
+			for _, f := range changed {
+				if f != nil {
+					paths = append(paths, f.Filename)
+				}
+			}
+			return paths, nil
+		})
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to fetch commit files", "sha", truncateSHA(sha), "error", err)
+			report.add(FetchSectionCommitFiles, sha, err)
+			continue
+		}
+		result[sha] = files
+	}
+	return result
+}
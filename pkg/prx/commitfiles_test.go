@@ -0,0 +1,74 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequestCommitFiles(t *testing.T) {
+	commitRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"commits": {"nodes": [
+					{"commit": {"oid": "aaa111", "committedDate": "2025-01-01T00:01:00Z", "message": "first"}},
+					{"commit": {"oid": "bbb222", "committedDate": "2025-01-01T00:02:00Z", "message": "second"}},
+					{"commit": {"oid": "ccc333", "committedDate": "2025-01-01T00:03:00Z", "message": "third"}}
+				]}}},
+				"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`))
+		case "/repos/owner/repo/commits/aaa111":
+			commitRequests++
+			w.Write([]byte(`{"sha": "aaa111", "files": [{"filename": "main.go"}, {"filename": "README.md"}]}`))
+		case "/repos/owner/repo/commits/bbb222":
+			commitRequests++
+			w.Write([]byte(`{"sha": "bbb222", "files": [{"filename": "main_test.go"}]}`))
+		case "/repos/owner/repo/commits/ccc333":
+			commitRequests++
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithCommitFiles())
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	got := data.PullRequest.CommitFiles
+	if !slices.Equal(got["aaa111"], []string{"main.go", "README.md"}) {
+		t.Errorf("CommitFiles[aaa111] = %v, want [main.go README.md]", got["aaa111"])
+	}
+	if !slices.Equal(got["bbb222"], []string{"main_test.go"}) {
+		t.Errorf("CommitFiles[bbb222] = %v, want [main_test.go]", got["bbb222"])
+	}
+	if files, ok := got["ccc333"]; ok {
+		t.Errorf("CommitFiles[ccc333] = %v, want commit omitted after fetch error", files)
+	}
+	if commitRequests < 3 {
+		t.Errorf("got %d commit requests, want at least 3", commitRequests)
+	}
+
+	// Without the option, no per-commit requests are made.
+	commitRequests = 0
+	plain := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	plain.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	data, err = plain.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	if data.PullRequest.CommitFiles != nil || commitRequests != 0 {
+		t.Errorf("CommitFiles = %v with %d requests, want none without WithCommitFiles", data.PullRequest.CommitFiles, commitRequests)
+	}
+}
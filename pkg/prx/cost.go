@@ -0,0 +1,94 @@
+package prx
+
+// defaultGraphQLQueryCost is the point cost of the pull request query on github.com,
+// used until a query's actual cost has been observed.
+const defaultGraphQLQueryCost = 1
+
+// CostEstimateOptions describes a batch of pull request fetches. Sizes are per pull request;
+// zero sizes assume a small pull request that fits in the first page of every connection.
+type CostEstimateOptions struct {
+	PullRequests  int     // Pull requests to fetch; defaults to 1
+	Repos         int     // Distinct repositories among them; defaults to 1
+	Commits       int     // Commits per pull request
+	Reviews       int     // Reviews per pull request
+	Comments      int     // Issue comments per pull request
+	TimelineItems int     // Timeline events per pull request
+	Files         int     // Changed files per pull request
+	CacheHitRate  float64 // Fraction of pull requests expected to be served from cache, 0 to 1
+}
+
+// CostEstimate is the predicted rate limit consumption of a batch of fetches.
+// It is an upper bound: caches shared across pull requests may avoid some requests.
+type CostEstimate struct {
+	GraphQLPoints int `json:"graphql_points"`
+	RESTCalls     int `json:"rest_calls"`
+}
+
+// Fits reports whether the estimated cost fits within the remaining rate limit in state.
+// Buckets that haven't been observed yet are assumed to have room.
+func (e CostEstimate) Fits(state RateLimitState) bool {
+	if state.GraphQL.known() && e.GraphQLPoints > state.GraphQL.Remaining {
+		return false
+	}
+	if state.Core.known() && e.RESTCalls > state.Core.Remaining {
+		return false
+	}
+	return true
+}
+
+// EstimateCost predicts the GraphQL points and REST calls needed to fetch the pull requests
+// described by opts with this client's configuration, without issuing any requests.
+// Once a query has been made, its observed cost replaces the default estimate for the main query.
+func (c *Client) EstimateCost(opts CostEstimateOptions) CostEstimate {
+	prs := max(opts.PullRequests, 1)
+	repos := max(opts.Repos, 1)
+	fetched := prs - int(float64(prs)*min(max(opts.CacheHitRate, 0), 1))
+
+	queryCost := c.RateLimitState().LastGraphQLCost
+	if queryCost <= 0 {
+		queryCost = defaultGraphQLQueryCost
+	}
+	points := queryCost
+	for _, n := range []int{opts.Commits, opts.Reviews, opts.Comments, opts.TimelineItems, opts.Files} {
+		points += followUpPages(n) // single-connection follow-up queries cost 1 point each
+	}
+
+	commits := max(opts.Commits, 1)
+	rest := commits // check runs for each commit, including the head
+	if c.featureFlagPatterns != nil {
+		rest += max(pageCount(opts.Files), 1)
+	}
+	if c.commitFiles {
+		rest += commits
+	}
+	if c.changedLineBlame {
+		rest += max(pageCount(opts.Files), 1) + 2 // files, then the pull request and comparison for the merge base
+		points += (max(opts.Files, 1) + blameBatchSize - 1) / blameBatchSize
+	}
+
+	// Repository metadata, rulesets, and collaborators are fetched once per repository
+	perRepo := 2 // repository metadata and collaborators
+	c.capsMu.Lock()
+	if (c.caps == nil || c.caps.Rulesets) && !c.rulesetsNotFound {
+		perRepo++
+	}
+	c.capsMu.Unlock()
+	if c.descriptionTemplates {
+		perRepo += len(pullRequestTemplateDirs) + 1 // directory listings and at least one template
+	}
+
+	return CostEstimate{
+		GraphQLPoints: fetched * points,
+		RESTCalls:     fetched*rest + min(repos, fetched)*perRepo,
+	}
+}
+
+// pageCount returns the number of 100-item pages needed for n items.
+func pageCount(n int) int {
+	return (n + 99) / 100
+}
+
+// followUpPages returns the number of pages fetched after the first for a connection of n items.
+func followUpPages(n int) int {
+	return min(max(pageCount(n)-1, 0), maxGraphQLPages)
+}
@@ -0,0 +1,80 @@
+package prx
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_EstimateCost(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		in   CostEstimateOptions
+		want CostEstimate
+	}{
+		{
+			name: "single small pull request",
+			want: CostEstimate{GraphQLPoints: 1, RESTCalls: 4},
+		},
+		{
+			name: "large pull request pages connections",
+			in:   CostEstimateOptions{Commits: 250, Comments: 100, TimelineItems: 101, Files: 3000},
+			want: CostEstimate{GraphQLPoints: 1 + 2 + 1 + 29, RESTCalls: 250 + 3},
+		},
+		{
+			name: "batch across repos with cache hits",
+			in:   CostEstimateOptions{PullRequests: 10, Repos: 3, Commits: 4, CacheHitRate: 0.5},
+			want: CostEstimate{GraphQLPoints: 5, RESTCalls: 5*4 + 3*3},
+		},
+		{
+			name: "optional REST fetches",
+			opts: []Option{WithCommitFiles(), WithFeatureFlagDetection(FeatureFlagPatterns{Calls: []*regexp.Regexp{}})},
+			in:   CostEstimateOptions{Commits: 3, Files: 150},
+			want: CostEstimate{GraphQLPoints: 1 + 1, RESTCalls: 3 + 2 + 3 + 3},
+		},
+		{
+			name: "changed line blame",
+			opts: []Option{WithChangedLineBlame()},
+			in:   CostEstimateOptions{Files: 150},
+			want: CostEstimate{GraphQLPoints: 1 + 1 + 8, RESTCalls: 1 + 2 + 2 + 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("token", append([]Option{WithCacheStore(null.New[string, PullRequestData]())}, tt.opts...)...)
+			if got := client.EstimateCost(tt.in); got != tt.want {
+				t.Errorf("EstimateCost() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// The observed query cost replaces the default
+	client := NewClient("token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.recordGraphQLCost(3, 4000, 5000, time.Now().Add(time.Hour))
+	if got := client.EstimateCost(CostEstimateOptions{PullRequests: 2}); got.GraphQLPoints != 6 {
+		t.Errorf("EstimateCost().GraphQLPoints = %d, want 6", got.GraphQLPoints)
+	}
+}
+
+func TestCostEstimate_Fits(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	state := RateLimitState{
+		Core:    RateLimitBucket{Limit: 5000, Remaining: 100, Reset: reset},
+		GraphQL: RateLimitBucket{Limit: 5000, Remaining: 10, Reset: reset},
+	}
+	if !(CostEstimate{GraphQLPoints: 10, RESTCalls: 100}).Fits(state) {
+		t.Error("expected estimate equal to remaining quota to fit")
+	}
+	if (CostEstimate{GraphQLPoints: 11}).Fits(state) {
+		t.Error("expected GraphQL overrun not to fit")
+	}
+	if (CostEstimate{RESTCalls: 101}).Fits(state) {
+		t.Error("expected REST overrun not to fit")
+	}
+	if !(CostEstimate{GraphQLPoints: 1000, RESTCalls: 1000}).Fits(RateLimitState{}) {
+		t.Error("expected unobserved rate limits to fit")
+	}
+}
@@ -0,0 +1,271 @@
+package prx
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// DiagnosticSeverity is the severity of a Diagnostic, numbered as in the Language Server Protocol.
+type DiagnosticSeverity int
+
+// Diagnostic severities.
+const (
+	DiagnosticSeverityError       DiagnosticSeverity = 1 // Failure annotations
+	DiagnosticSeverityWarning     DiagnosticSeverity = 2 // Warning annotations and unresolved review threads
+	DiagnosticSeverityInformation DiagnosticSeverity = 3 // Notice annotations
+	DiagnosticSeverityHint        DiagnosticSeverity = 4 // Unresolved review threads on code that has since changed
+)
+
+// Position is a zero-based line and character offset in a file.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span of a file. As in the Language Server Protocol, End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// CodeDescription links a Diagnostic to the review comment it came from.
+type CodeDescription struct {
+	Href string `json:"href"`
+}
+
+// Diagnostic is an unresolved review thread or a check run annotation, shaped like a Language Server
+// Protocol diagnostic (including its camelCase field names) so editor plugins can show it inline.
+type Diagnostic struct {
+	CodeDescription *CodeDescription   `json:"codeDescription,omitempty"`
+	Range           Range              `json:"range"`
+	Source          string             `json:"source"`         // "review", or the check run's name
+	Code            string             `json:"code,omitempty"` // Review thread author
+	Message         string             `json:"message"`
+	Severity        DiagnosticSeverity `json:"severity"`
+}
+
+// FileDiagnostics holds the diagnostics for one file, like a publishDiagnostics notification.
+type FileDiagnostics struct {
+	Path        string       `json:"path"` // Relative to the repository root
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+const diagnosticsQuery = `
+query($owner: String!, $repo: String!, $number: Int!) {
+	repository(owner: $owner, name: $repo) {
+		pullRequest(number: $number) {
+			headRefOid
+			reviewThreads(first: 100) {
+				nodes {
+					isResolved
+					isOutdated
+					path
+					line
+					startLine
+					originalLine
+					originalStartLine
+					comments(first: 1) {
+						totalCount
+						nodes {
+							body
+							url
+							author {
+								login
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+// diagnosticsThread is a review thread in the diagnostics query response. Lines are nil for threads
+// on a whole file, and Line and StartLine are nil once the thread is outdated.
+type diagnosticsThread struct {
+	Line              *int   `json:"line"`
+	StartLine         *int   `json:"startLine"`
+	OriginalLine      *int   `json:"originalLine"`
+	OriginalStartLine *int   `json:"originalStartLine"`
+	Path              string `json:"path"`
+	Comments          struct {
+		Nodes []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			Body string `json:"body"`
+			URL  string `json:"url"`
+		} `json:"nodes"`
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	IsResolved bool `json:"isResolved"`
+	IsOutdated bool `json:"isOutdated"`
+}
+
+// Diagnostics returns a pull request's open review feedback and the check run annotations on its
+// head commit as diagnostics, grouped by file and sorted by path and line.
+func (c *Client) Diagnostics(ctx context.Context, owner, repo string, prNumber int) ([]FileDiagnostics, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					HeadRefOid    string `json:"headRefOid"`
+					ReviewThreads struct {
+						Nodes []diagnosticsThread `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	variables := map[string]any{"owner": owner, "repo": repo, "number": prNumber}
+	if err := c.github.GraphQL(ctx, diagnosticsQuery, variables, &resp); err != nil {
+		return nil, fmt.Errorf("fetching review threads: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("fetching review threads: %s", resp.Errors[0].Message)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request %s/%s#%d not found", owner, repo, prNumber)
+	}
+
+	byPath := make(map[string][]Diagnostic)
+	for i := range pr.ReviewThreads.Nodes {
+		t := &pr.ReviewThreads.Nodes[i]
+		if t.IsResolved || len(t.Comments.Nodes) == 0 {
+			continue
+		}
+		byPath[t.Path] = append(byPath[t.Path], threadDiagnostic(t))
+	}
+
+	annotations, err := c.headAnnotations(ctx, owner, repo, pr.HeadRefOid)
+	if err != nil {
+		return nil, err
+	}
+	for name, list := range annotations {
+		for _, a := range list {
+			byPath[a.Path] = append(byPath[a.Path], annotationDiagnostic(name, a))
+		}
+	}
+
+	files := make([]FileDiagnostics, 0, len(byPath))
+	for path, diags := range byPath {
+		slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+			return cmp.Or(cmp.Compare(a.Range.Start.Line, b.Range.Start.Line), cmp.Compare(a.Source, b.Source))
+		})
+		files = append(files, FileDiagnostics{Path: path, Diagnostics: diags})
+	}
+	slices.SortFunc(files, func(a, b FileDiagnostics) int { return cmp.Compare(a.Path, b.Path) })
+	return files, nil
+}
+
+// headAnnotations fetches the annotations of each check run on a commit, keyed by check run name.
+func (c *Client) headAnnotations(ctx context.Context, owner, repo, sha string) (map[string][]github.CheckRunAnnotation, error) {
+	var runs struct {
+		CheckRuns []struct {
+			Name   string `json:"name"`
+			Output struct {
+				AnnotationsCount int `json:"annotations_count"`
+			} `json:"output"`
+			ID int64 `json:"id"`
+		} `json:"check_runs"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?per_page=100", owner, repo, sha)
+	if _, err := c.github.Get(ctx, path, &runs); err != nil {
+		return nil, fmt.Errorf("fetching check runs: %w", err)
+	}
+	annotations := make(map[string][]github.CheckRunAnnotation)
+	for _, run := range runs.CheckRuns {
+		if run.Output.AnnotationsCount == 0 {
+			continue
+		}
+		var list []github.CheckRunAnnotation
+		path := fmt.Sprintf("/repos/%s/%s/check-runs/%d/annotations?per_page=100", owner, repo, run.ID)
+		if _, err := c.github.Get(ctx, path, &list); err != nil {
+			return nil, fmt.Errorf("fetching annotations of check run %q: %w", run.Name, err)
+		}
+		annotations[run.Name] = append(annotations[run.Name], list...)
+	}
+	return annotations, nil
+}
+
+// threadDiagnostic converts an unresolved review thread, described by its first comment.
+// Outdated threads are placed where they were left, as hints, and threads on a whole file at its start.
+func threadDiagnostic(t *diagnosticsThread) Diagnostic {
+	first := t.Comments.Nodes[0]
+	message := first.Body
+	switch replies := t.Comments.TotalCount - 1; {
+	case replies == 1:
+		message += "\n\n(1 reply)"
+	case replies > 1:
+		message += fmt.Sprintf("\n\n(%d replies)", replies)
+	default:
+	}
+	d := Diagnostic{
+		Source:   "review",
+		Code:     first.Author.Login,
+		Message:  message,
+		Severity: DiagnosticSeverityWarning,
+	}
+	if first.URL != "" {
+		d.CodeDescription = &CodeDescription{Href: first.URL}
+	}
+
+	start, end := t.StartLine, t.Line
+	if t.IsOutdated {
+		start, end = t.OriginalStartLine, t.OriginalLine
+		d.Severity = DiagnosticSeverityHint
+	}
+	if end != nil {
+		from := *end
+		if start != nil {
+			from = *start
+		}
+		d.Range = lineRange(from, *end, 0, 0)
+	}
+	return d
+}
+
+// annotationDiagnostic converts a check run annotation.
+func annotationDiagnostic(checkName string, a github.CheckRunAnnotation) Diagnostic {
+	message := a.Message
+	if a.Title != "" {
+		message = a.Title + ": " + message
+	}
+	severity := DiagnosticSeverityInformation
+	switch a.AnnotationLevel {
+	case "failure":
+		severity = DiagnosticSeverityError
+	case "warning":
+		severity = DiagnosticSeverityWarning
+	default:
+	}
+	return Diagnostic{
+		Range:    lineRange(a.StartLine, a.EndLine, a.StartColumn, a.EndColumn),
+		Source:   checkName,
+		Message:  strings.TrimSpace(message),
+		Severity: severity,
+	}
+}
+
+// lineRange converts GitHub's one-based, inclusive lines and columns to a Range. Without columns,
+// the range covers the whole lines.
+func lineRange(startLine, endLine, startColumn, endColumn int) Range {
+	startLine = max(startLine, 1)
+	endLine = max(endLine, startLine)
+	if startColumn > 0 && endColumn >= startColumn && startLine == endLine {
+		return Range{
+			Start: Position{Line: startLine - 1, Character: startColumn - 1},
+			End:   Position{Line: endLine - 1, Character: endColumn},
+		}
+	}
+	return Range{Start: Position{Line: startLine - 1}, End: Position{Line: endLine}}
+}
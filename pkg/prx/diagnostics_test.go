@@ -0,0 +1,70 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_Diagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"headRefOid": "abc123", "reviewThreads": {"nodes": [
+				{"isResolved": false, "path": "main.go", "line": 12, "startLine": 10, "comments": {"totalCount": 3, "nodes": [
+					{"body": "Handle the error", "url": "https://github.com/owner/repo/pull/7#discussion_r1", "author": {"login": "alice"}}]}},
+				{"isResolved": true, "path": "main.go", "line": 3, "comments": {"totalCount": 1, "nodes": [{"body": "Done"}]}},
+				{"isResolved": false, "isOutdated": true, "path": "util.go", "originalLine": 5, "comments": {"totalCount": 1, "nodes": [
+					{"body": "Rename this", "author": {"login": "bob"}}]}}
+			]}}}}}`))
+		case "/repos/owner/repo/commits/abc123/check-runs":
+			w.Write([]byte(`{"check_runs": [
+				{"id": 11, "name": "lint", "output": {"annotations_count": 1}},
+				{"id": 12, "name": "test", "output": {"annotations_count": 0}}
+			]}`))
+		case "/repos/owner/repo/check-runs/11/annotations":
+			w.Write([]byte(`[{"path": "main.go", "annotation_level": "failure", "title": "errcheck", "message": "error not checked",
+				"start_line": 4, "end_line": 4, "start_column": 2, "end_column": 9}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	files, err := client.Diagnostics(context.Background(), "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("Diagnostics() error: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "main.go" || files[1].Path != "util.go" {
+		t.Fatalf("Diagnostics() = %+v, want main.go and util.go", files)
+	}
+
+	main := files[0].Diagnostics
+	if len(main) != 2 {
+		t.Fatalf("main.go diagnostics = %+v, want the annotation and one unresolved thread", main)
+	}
+	lint := main[0]
+	if lint.Source != "lint" || lint.Severity != DiagnosticSeverityError || lint.Message != "errcheck: error not checked" ||
+		lint.Range != (Range{Start: Position{Line: 3, Character: 1}, End: Position{Line: 3, Character: 9}}) {
+		t.Errorf("annotation diagnostic = %+v", lint)
+	}
+	review := main[1]
+	if review.Source != "review" || review.Code != "alice" || review.Severity != DiagnosticSeverityWarning ||
+		review.Message != "Handle the error\n\n(2 replies)" || review.CodeDescription == nil ||
+		review.Range != (Range{Start: Position{Line: 9}, End: Position{Line: 12}}) {
+		t.Errorf("review diagnostic = %+v", review)
+	}
+
+	outdated := files[1].Diagnostics[0]
+	if outdated.Severity != DiagnosticSeverityHint || outdated.Range != (Range{Start: Position{Line: 4}, End: Position{Line: 5}}) {
+		t.Errorf("outdated review diagnostic = %+v", outdated)
+	}
+}
@@ -0,0 +1,118 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// defaultMaxDiffSize is the largest diff or patch returned by default.
+const defaultMaxDiffSize = 5 * 1024 * 1024
+
+// ErrDiffTooLarge is returned by Diff and Patch when the diff exceeds the configured size limit,
+// or when GitHub declines to generate it because the pull request is too large.
+var ErrDiffTooLarge = errors.New("diff too large")
+
+// WithMaxDiffSize sets the largest diff or patch, in bytes, returned by Diff and Patch.
+// The default is 5MB; n <= 0 leaves only the API client's 10MB response limit.
+func WithMaxDiffSize(n int) Option {
+	return func(c *Client) {
+		c.maxDiffSize = n
+	}
+}
+
+// FileDiff is the part of a diff or patch that changes a single file.
+type FileDiff struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"` // Set for renames and copies
+	Diff    string `json:"diff"`               // Starting at the "diff --git" line
+}
+
+// Diff fetches the pull request's changes as a unified diff against its base branch.
+func (c *Client) Diff(ctx context.Context, owner, repo string, pr int) (string, error) {
+	return c.fetchDiff(ctx, owner, repo, pr, github.MediaTypeDiff)
+}
+
+// Patch fetches the pull request's commits as a git format-patch mailbox, one message per commit.
+func (c *Client) Patch(ctx context.Context, owner, repo string, pr int) (string, error) {
+	return c.fetchDiff(ctx, owner, repo, pr, github.MediaTypePatch)
+}
+
+// fetchDiff fetches a pull request in a diff media type, enforcing the size limit.
+func (c *Client) fetchDiff(ctx context.Context, owner, repo string, pr int, mediaType string) (string, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, pr)
+	data, _, err := c.github.DoMediaType(ctx, path, mediaType)
+	var apiErr *github.Error
+	switch {
+	case errors.Is(err, github.ErrResponseTooLarge):
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %w", owner, repo, pr, ErrDiffTooLarge)
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotAcceptable:
+		// GitHub answers 406 when a diff exceeds its own generation limits
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %w: %w", owner, repo, pr, ErrDiffTooLarge, err)
+	case err != nil:
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %w", owner, repo, pr, err)
+	}
+	if c.maxDiffSize > 0 && len(data) > c.maxDiffSize {
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %d bytes: %w", owner, repo, pr, len(data), ErrDiffTooLarge)
+	}
+	return string(data), nil
+}
+
+// SplitDiff splits a unified diff or patch into the changes to each file, in order.
+// Changes to the same file in several commits of a patch are returned separately.
+func SplitDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var b strings.Builder
+	flush := func() {
+		if cur != nil {
+			cur.Diff = b.String()
+			files = append(files, *cur)
+		}
+		cur = nil
+		b.Reset()
+	}
+
+	for line := range strings.SplitAfterSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			cur = &FileDiff{}
+			oldPath, newPath := diffGitPaths(strings.TrimSuffix(strings.TrimPrefix(line, "diff --git "), "\n"))
+			cur.Path = newPath
+			if oldPath != newPath {
+				cur.OldPath = oldPath
+			}
+		case cur == nil:
+			continue
+		case line == "-- \n":
+			// Signature ending a commit's message in a patch mailbox
+			flush()
+			continue
+		case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+			_, from, _ := strings.Cut(strings.TrimSuffix(line, "\n"), " from ")
+			cur.OldPath = from
+		case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+			_, to, _ := strings.Cut(strings.TrimSuffix(line, "\n"), " to ")
+			cur.Path = to
+		default:
+			// Hunk headers and content
+		}
+		b.WriteString(line)
+	}
+	flush()
+	return files
+}
+
+// diffGitPaths extracts the old and new paths from the arguments of a "diff --git" line.
+// Paths containing spaces are ambiguous there; rename and copy lines correct them.
+func diffGitPaths(args string) (oldPath, newPath string) {
+	if i := strings.Index(args, " b/"); strings.HasPrefix(args, "a/") && i >= 0 {
+		return args[2:i], args[i+3:]
+	}
+	return args, args
+}
@@ -0,0 +1,114 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-// old
++// new
+diff --git a/old name.txt b/new name.txt
+similarity index 100%
+rename from old name.txt
+rename to new name.txt
+`
+
+const testPatch = `From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001
+From: Dev <dev@example.com>
+Subject: [PATCH 1/2] First
+
+---
+ main.go | 2 +-
+
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-a
++b
+-- 
+2.45.0
+
+
+From 2222222222222222222222222222222222222222 Mon Sep 17 00:00:00 2001
+From: Dev <dev@example.com>
+Subject: [PATCH 2/2] Second
+
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-b
++c
+-- 
+2.45.0
+`
+
+func TestSplitDiff(t *testing.T) {
+	files := SplitDiff(testDiff)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].Path != "main.go" || files[0].OldPath != "" || !strings.HasSuffix(files[0].Diff, "+// new\n") {
+		t.Errorf("files[0] = %+v", files[0])
+	}
+	if files[1].Path != "new name.txt" || files[1].OldPath != "old name.txt" {
+		t.Errorf("files[1] = %+v, want rename of old name.txt", files[1])
+	}
+
+	commits := SplitDiff(testPatch)
+	if len(commits) != 2 {
+		t.Fatalf("expected a file diff per commit, got %d", len(commits))
+	}
+	if !strings.HasSuffix(commits[0].Diff, "+b\n") || strings.Contains(commits[0].Diff, "2.45.0") {
+		t.Errorf("expected patch trailer to be excluded, got %q", commits[0].Diff)
+	}
+}
+
+func TestClient_Diff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch accept := r.Header.Get("Accept"); {
+		case strings.HasPrefix(accept, "application/vnd.github.diff"):
+			w.Write([]byte(testDiff))
+		case strings.HasPrefix(accept, "application/vnd.github.patch"):
+			w.Write([]byte(testPatch))
+		default:
+			t.Errorf("unexpected Accept header %q", accept)
+		}
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...Option) *Client {
+		client := NewClient("test-token", append([]Option{WithCacheStore(null.New[string, PullRequestData]())}, opts...)...)
+		client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+		return client
+	}
+
+	ctx := context.Background()
+	if diff, err := newClient().Diff(ctx, "owner", "repo", 1); err != nil || diff != testDiff {
+		t.Errorf("Diff() = %q, %v", diff, err)
+	}
+	if patch, err := newClient().Patch(ctx, "owner", "repo", 1); err != nil || patch != testPatch {
+		t.Errorf("Patch() = %q, %v", patch, err)
+	}
+	if _, err := newClient(WithMaxDiffSize(100)).Diff(ctx, "owner", "repo", 1); !errors.Is(err, ErrDiffTooLarge) {
+		t.Errorf("Diff() error = %v, want ErrDiffTooLarge", err)
+	}
+}
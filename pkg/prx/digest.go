@@ -0,0 +1,265 @@
+package prx
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DigestFormat selects how a digest is rendered.
+type DigestFormat string
+
+// Digest formats, for chat or email.
+const (
+	DigestFormatMarkdown DigestFormat = "markdown"
+	DigestFormatHTML     DigestFormat = "html"
+)
+
+// DigestIdentity is whom a digest is for: a person, or a team and its members.
+type DigestIdentity struct {
+	Name   string   // Shown in the heading; defaults to the first login or team
+	Logins []string // The person, or the team's members
+	// Teams whose review requests and mentions count, as named in PullRequest.Reviewers (e.g. "Core")
+	// or as mentioned (e.g. "org/core")
+	Teams []string
+}
+
+// DigestPullRequest is a hydrated pull request and the repository it belongs to.
+type DigestPullRequest struct {
+	Data  *PullRequestData
+	Owner string
+	Repo  string
+	URL   string // Web page of the pull request; defaults to one on github.com
+}
+
+// DigestItem is a pull request needing attention, and why.
+type DigestItem struct {
+	Since  time.Time `json:"since"` // When it started waiting on the identity, or on others
+	Owner  string    `json:"owner"`
+	Repo   string    `json:"repo"`
+	Title  string    `json:"title"`
+	URL    string    `json:"url"`
+	Detail string    `json:"detail"`
+	Number int       `json:"number"`
+}
+
+// Digest is what needs someone's attention across a set of pull requests. Items are oldest first.
+type Digest struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Name        string    `json:"name"`
+	// Open pull requests whose review is requested from the identity
+	PendingReviews []DigestItem `json:"pending_reviews"`
+	// Questions addressed to the identity, by mention or on their own pull requests, with no later reply from them
+	UnansweredQuestions []DigestItem `json:"unanswered_questions"`
+	// The identity's open pull requests that are waiting on reviewers, checks, or a merger
+	Blocked []DigestItem `json:"blocked"`
+	// Locale formats ages in Write; English if nil
+	Locale *Locale `json:"-"`
+}
+
+// digestQuestionLength is how much of a question a digest quotes.
+const digestQuestionLength = 80
+
+// BuildDigest finds what needs id's attention in prs as of now. Drafts and closed pull requests are
+// skipped, as are pull requests whose Data is nil.
+func BuildDigest(id DigestIdentity, prs []DigestPullRequest, now time.Time) *Digest {
+	d := &Digest{GeneratedAt: now, Name: id.Name}
+	switch {
+	case d.Name != "":
+	case len(id.Logins) > 0:
+		d.Name = id.Logins[0]
+	case len(id.Teams) > 0:
+		d.Name = id.Teams[0]
+	}
+	for _, p := range prs {
+		if p.Data == nil || p.Data.PullRequest.State != "open" || p.Data.PullRequest.Draft {
+			continue
+		}
+		pr := &p.Data.PullRequest
+		item := DigestItem{Owner: p.Owner, Repo: p.Repo, Number: pr.Number, Title: pr.Title, URL: p.URL}
+		if item.URL == "" {
+			item.URL = fmt.Sprintf("https://github.com/%s/%s/pull/%d", p.Owner, p.Repo, pr.Number)
+		}
+		mine := id.isLogin(pr.Author)
+
+		if !mine {
+			for _, reviewer := range slices.Sorted(maps.Keys(pr.Reviewers)) {
+				if pr.Reviewers[reviewer] != ReviewStatePending || !(id.isLogin(reviewer) || id.isTeam(reviewer)) {
+					continue
+				}
+				item.Since = reviewRequestedAt(p.Data, reviewer)
+				item.Detail = fmt.Sprintf("Review requested from %s, by %s (+%d −%d)", reviewer, pr.Author, pr.Additions, pr.Deletions)
+				d.PendingReviews = append(d.PendingReviews, item)
+				break
+			}
+		}
+
+		for _, q := range unansweredQuestions(p.Data, &id, mine) {
+			item.Since = q.Timestamp
+			item.Detail = fmt.Sprintf("%s asked: %s", q.Actor, questionExcerpt(q.Body))
+			d.UnansweredQuestions = append(d.UnansweredQuestions, item)
+		}
+
+		if mine && pr.WaitingOn != WaitingOnNobody && pr.WaitingOn != WaitingOnAuthor {
+			item.Since = pr.LastAuthorActivity
+			if item.Since.IsZero() {
+				item.Since = pr.CreatedAt
+			}
+			item.Detail = "Waiting on " + string(pr.WaitingOn)
+			if pr.WaitingOnReason != "" {
+				item.Detail += ": " + pr.WaitingOnReason
+			}
+			d.Blocked = append(d.Blocked, item)
+		}
+	}
+	for _, items := range [][]DigestItem{d.PendingReviews, d.UnansweredQuestions, d.Blocked} {
+		slices.SortStableFunc(items, func(a, b DigestItem) int {
+			return cmp.Or(a.Since.Compare(b.Since), cmp.Compare(a.Owner, b.Owner), cmp.Compare(a.Repo, b.Repo), cmp.Compare(a.Number, b.Number))
+		})
+	}
+	return d
+}
+
+// isLogin reports whether login is one of the identity's logins.
+func (id *DigestIdentity) isLogin(login string) bool {
+	return slices.ContainsFunc(id.Logins, func(l string) bool { return strings.EqualFold(l, login) })
+}
+
+// isTeam reports whether name, a team as requested for review or mentioned, is one of the identity's
+// teams. An "org/slug" team also matches its slug alone.
+func (id *DigestIdentity) isTeam(name string) bool {
+	return slices.ContainsFunc(id.Teams, func(t string) bool {
+		_, slug, _ := strings.Cut(t, "/")
+		return strings.EqualFold(t, name) || slug != "" && strings.EqualFold(slug, name)
+	})
+}
+
+// reviewRequestedAt returns when review was last requested from reviewer, or when the pull request was
+// opened if there's no such event.
+func reviewRequestedAt(data *PullRequestData, reviewer string) time.Time {
+	for i := len(data.Events) - 1; i >= 0; i-- {
+		if e := &data.Events[i]; e.Kind == EventKindReviewRequested && strings.EqualFold(e.Target, reviewer) {
+			return e.Timestamp
+		}
+	}
+	return data.PullRequest.CreatedAt
+}
+
+// unansweredQuestions returns the questions in data addressed to id, by mention or, when mine, by
+// being asked on its own pull request without mentioning anyone, that id hasn't replied to since.
+func unansweredQuestions(data *PullRequestData, id *DigestIdentity, mine bool) []Event {
+	var questions []Event
+	for i := range data.Events {
+		e := &data.Events[i]
+		switch e.Kind {
+		case EventKindComment, EventKindReview, EventKindReviewComment:
+		default:
+			continue
+		}
+		if id.isLogin(e.Actor) {
+			questions = questions[:0] // Answered
+			continue
+		}
+		if !e.Question || e.Bot {
+			continue
+		}
+		addressed := mine && len(e.Mentions) == 0
+		for _, m := range e.Mentions {
+			addressed = addressed || id.isLogin(m) || id.isTeam(m)
+		}
+		if addressed {
+			questions = append(questions, *e)
+		}
+	}
+	return questions
+}
+
+// questionExcerpt returns the first line of body, shortened to digestQuestionLength characters.
+func questionExcerpt(body string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	if r := []rune(line); len(r) > digestQuestionLength {
+		line = string(r[:digestQuestionLength-1]) + "…"
+	}
+	return line
+}
+
+// digestSection is a section of a rendered digest.
+type digestSection struct {
+	heading string
+	items   []DigestItem
+}
+
+// sections returns the digest's sections in the order they're rendered.
+func (d *Digest) sections() []digestSection {
+	return []digestSection{
+		{"Reviews requested from you", d.PendingReviews},
+		{"Questions waiting on you", d.UnansweredQuestions},
+		{"Your pull requests waiting on others", d.Blocked},
+	}
+}
+
+// Write renders the digest as Markdown or as an HTML fragment, ages relative to GeneratedAt and
+// formatted for Locale. Empty sections are left out.
+func (d *Digest) Write(w io.Writer, format DigestFormat) error {
+	var b strings.Builder
+	switch format {
+	case DigestFormatMarkdown:
+		fmt.Fprintf(&b, "# What needs %s's attention\n", markdownEscape(d.Name))
+		for _, s := range d.sections() {
+			if len(s.items) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n## %s (%d)\n\n", s.heading, len(s.items))
+			for _, it := range s.items {
+				fmt.Fprintf(&b, "- [%s/%s#%d](%s) %s — %s (%s)\n", it.Owner, it.Repo, it.Number, it.URL,
+					markdownEscape(it.Title), markdownEscape(it.Detail), d.Locale.Duration(d.GeneratedAt.Sub(it.Since)))
+			}
+		}
+		if d.empty() {
+			b.WriteString("\nNothing needs your attention.\n")
+		}
+	case DigestFormatHTML:
+		fmt.Fprintf(&b, "<h1>What needs %s's attention</h1>\n", html.EscapeString(d.Name))
+		for _, s := range d.sections() {
+			if len(s.items) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "<h2>%s (%d)</h2>\n<ul>\n", s.heading, len(s.items))
+			for _, it := range s.items {
+				fmt.Fprintf(&b, "<li><a href=\"%s\">%s/%s#%d</a> %s — %s (%s)</li>\n", html.EscapeString(it.URL),
+					html.EscapeString(it.Owner), html.EscapeString(it.Repo), it.Number,
+					html.EscapeString(it.Title), html.EscapeString(it.Detail), d.Locale.Duration(d.GeneratedAt.Sub(it.Since)))
+			}
+			b.WriteString("</ul>\n")
+		}
+		if d.empty() {
+			b.WriteString("<p>Nothing needs your attention.</p>\n")
+		}
+	default:
+		return fmt.Errorf("unknown digest format %q", format)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing digest: %w", err)
+	}
+	return nil
+}
+
+// empty reports whether nothing needs attention.
+func (d *Digest) empty() bool {
+	return len(d.PendingReviews) == 0 && len(d.UnansweredQuestions) == 0 && len(d.Blocked) == 0
+}
+
+// markdownReplacer escapes the characters that would format or link text in Markdown.
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `&lt;`, `>`, `&gt;`,
+)
+
+// markdownEscape escapes text for inline Markdown.
+func markdownEscape(text string) string {
+	return markdownReplacer.Replace(text)
+}
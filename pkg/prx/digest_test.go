@@ -0,0 +1,99 @@
+package prx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(daysAgo int) time.Time { return now.Add(-time.Duration(daysAgo) * 24 * time.Hour) }
+	open := func(number int, author string, events ...Event) *PullRequestData {
+		return &PullRequestData{
+			PullRequest: PullRequest{Number: number, Title: "PR " + author, Author: author, State: "open", CreatedAt: at(10)},
+			Events:      events,
+		}
+	}
+
+	review := open(1, "bob")
+	review.PullRequest.Reviewers = map[string]ReviewState{"alice": ReviewStatePending, "carol": ReviewStateApproved}
+	review.Events = []Event{{Kind: EventKindReviewRequested, Target: "alice", Timestamp: at(3)}}
+	team := open(2, "carol")
+	team.PullRequest.Reviewers = map[string]ReviewState{"core": ReviewStatePending}
+	questions := open(3, "alice",
+		Event{Kind: EventKindComment, Actor: "bob", Body: "Why not use a map?\nIt would be faster.", Question: true, Timestamp: at(5)},
+		Event{Kind: EventKindComment, Actor: "alice", Body: "Good point, done", Timestamp: at(4)},
+		Event{Kind: EventKindReviewComment, Actor: "carol", Body: "Can you add a test?", Question: true, Timestamp: at(2)},
+		Event{Kind: EventKindComment, Actor: "carol", Body: "@dave does this match the spec?", Mentions: []string{"dave"}, Question: true, Timestamp: at(1)},
+		Event{Kind: EventKindComment, Actor: "ci-bot", Body: "Rerun?", Bot: true, Question: true, Timestamp: at(1)},
+	)
+	questions.PullRequest.WaitingOn = WaitingOnAuthor
+	blocked := open(4, "alice")
+	blocked.PullRequest.WaitingOn = WaitingOnReviewers
+	blocked.PullRequest.WaitingOnReason = "needs 1 more approval"
+	blocked.PullRequest.LastAuthorActivity = at(6)
+	mention := open(5, "erin", Event{
+		Kind: EventKindComment, Actor: "erin", Body: "@org/core could you take a look at the schema?",
+		Mentions: []string{"org/core"}, Question: true, Timestamp: at(7),
+	})
+	draft := open(6, "bob")
+	draft.PullRequest.Draft = true
+	draft.PullRequest.Reviewers = map[string]ReviewState{"alice": ReviewStatePending}
+
+	var prs []DigestPullRequest
+	for _, d := range []*PullRequestData{review, team, questions, blocked, mention, draft, nil} {
+		prs = append(prs, DigestPullRequest{Owner: "org", Repo: "app", Data: d})
+	}
+	d := BuildDigest(DigestIdentity{Logins: []string{"Alice"}, Teams: []string{"org/core"}}, prs, now)
+
+	numbers := func(items []DigestItem) []int {
+		var n []int
+		for _, it := range items {
+			n = append(n, it.Number)
+		}
+		return n
+	}
+	if got := numbers(d.PendingReviews); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("PendingReviews = %+v, want #2 (team, oldest) then #1", d.PendingReviews)
+	}
+	if d.PendingReviews[1].Since != at(3) || d.PendingReviews[1].URL != "https://github.com/org/app/pull/1" {
+		t.Errorf("PendingReviews[1] = %+v", d.PendingReviews[1])
+	}
+	if got := numbers(d.UnansweredQuestions); len(got) != 2 || got[0] != 5 || got[1] != 3 {
+		t.Errorf("UnansweredQuestions = %+v, want the team mention on #5 and carol's question on #3", d.UnansweredQuestions)
+	}
+	if d.UnansweredQuestions[1].Detail != "carol asked: Can you add a test?" {
+		t.Errorf("UnansweredQuestions[1].Detail = %q", d.UnansweredQuestions[1].Detail)
+	}
+	if len(d.Blocked) != 1 || d.Blocked[0].Number != 4 || d.Blocked[0].Detail != "Waiting on reviewers: needs 1 more approval" {
+		t.Errorf("Blocked = %+v", d.Blocked)
+	}
+
+	var md strings.Builder
+	if err := d.Write(&md, DigestFormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# What needs Alice's attention\n",
+		"## Reviews requested from you (2)\n",
+		"- [org/app#1](https://github.com/org/app/pull/1) PR bob — Review requested from alice, by bob (+0 −0) (3 days)\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown digest missing %q:\n%s", want, md.String())
+		}
+	}
+
+	blocked.PullRequest.Title = "Fix <script> & escaping"
+	var out strings.Builder
+	if err := BuildDigest(DigestIdentity{Logins: []string{"alice"}}, prs[3:4], now).Write(&out, DigestFormatHTML); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `<li><a href="https://github.com/org/app/pull/4">org/app#4</a> Fix &lt;script&gt; &amp; escaping`) ||
+		strings.Contains(out.String(), "Reviews requested") {
+		t.Errorf("HTML digest:\n%s", out.String())
+	}
+	if err := d.Write(&out, "pdf"); err == nil {
+		t.Error("Write() with an unknown format succeeded")
+	}
+}
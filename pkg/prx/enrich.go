@@ -0,0 +1,69 @@
+package prx
+
+import (
+	"context"
+	"strconv"
+)
+
+// Enricher adds a deployment's own classifications to an event, such as links to internal tickets
+// or the owning team, typically in Tags. It may change any field of the event.
+type Enricher func(ctx context.Context, e *Event) error
+
+// WithEnricher runs enrich on every event as pull requests are assembled from GitHub, or updated from
+// webhooks, before summaries such as ApprovalSummary are calculated; enrichers run in the order given.
+// Events read from the cache were enriched when fetched, and are cached apart by the names of the
+// enrichers' functions; clients whose enrichers are closures of one function literal over different
+// state should use separate cache stores. An error leaves the event as the enricher left it and is
+// logged; fetches also record it, once per enricher, in FetchReport under FetchSectionEnrichers.
+func WithEnricher(enrich Enricher) Option {
+	return func(c *Client) {
+		c.enrichers = append(c.enrichers, enrich)
+	}
+}
+
+// enrichEvents runs the configured enrichers on events, recording failures in report, which may be nil.
+func (c *Client) enrichEvents(ctx context.Context, events []Event, report *FetchReport) {
+	for i, enrich := range c.enrichers {
+		failed := false
+		for j := range events {
+			err := enrich(ctx, &events[j])
+			if err == nil || failed {
+				continue
+			}
+			failed = true
+			c.logger.WarnContext(ctx, "event enricher failed", "enricher", i, "kind", events[j].Kind, "error", err)
+			report.add(FetchSectionEnrichers, strconv.Itoa(i), err)
+		}
+	}
+}
+
+// EventClassifier classifies the body of a comment, review, or review comment, such as its sentiment
+// or tone, returning annotations for the event or nil. It may call a heuristic or a language model.
+type EventClassifier func(body string) map[string]any
+
+// WithEventClassifier runs classify on the full body of each comment, review, and review comment, and
+// attaches its output to Event.Annotations before enrichers run. With several classifiers, their
+// annotations are merged, later classifiers winning on conflicting keys. Events read from the cache
+// were classified when fetched, and are cached apart by classifier function name as with WithEnricher.
+func WithEventClassifier(classify EventClassifier) Option {
+	return func(c *Client) {
+		c.classifiers = append(c.classifiers, classify)
+	}
+}
+
+// classifyBody returns the configured classifiers' annotations for body, or nil for an empty body.
+func (c *Client) classifyBody(body string) map[string]any {
+	if body == "" {
+		return nil
+	}
+	var annotations map[string]any
+	for _, classify := range c.classifiers {
+		for k, v := range classify(body) {
+			if annotations == nil {
+				annotations = make(map[string]any)
+			}
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,107 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_EnrichEvents(t *testing.T) {
+	teams := map[string]string{"alice": "payments"}
+	var order []string
+	c := &Client{logger: slog.Default()}
+	for _, opt := range []Option{
+		WithEnricher(func(_ context.Context, e *Event) error {
+			order = append(order, "team")
+			team, ok := teams[e.Actor]
+			if !ok {
+				return errors.New("unknown actor " + e.Actor)
+			}
+			if e.Tags == nil {
+				e.Tags = make(map[string]string)
+			}
+			e.Tags["team"] = team
+			return nil
+		}),
+		WithEnricher(func(_ context.Context, e *Event) error {
+			order = append(order, "ticket")
+			if strings.Contains(e.Body, "PAY-1") {
+				e.Tags["ticket"] = "https://tickets.example.com/PAY-1"
+			}
+			return nil
+		}),
+	} {
+		opt(c)
+	}
+
+	events := []Event{
+		{Kind: EventKindComment, Actor: "alice", Body: "Fixes PAY-1"},
+		{Kind: EventKindComment, Actor: "mallory"},
+		{Kind: EventKindComment, Actor: "eve"},
+	}
+	var report FetchReport
+	c.enrichEvents(context.Background(), events, &report)
+
+	if events[0].Tags["team"] != "payments" || events[0].Tags["ticket"] != "https://tickets.example.com/PAY-1" {
+		t.Errorf("events[0].Tags = %v", events[0].Tags)
+	}
+	if events[1].Tags != nil {
+		t.Errorf("events[1].Tags = %v, want none after failure", events[1].Tags)
+	}
+	if strings.Join(order, ",") != "team,team,team,ticket,ticket,ticket" {
+		t.Errorf("enrichers ran in order %v", order)
+	}
+	// Each failing enricher is reported once
+	if len(report.Warnings) != 1 || report.Warnings[0].Section != FetchSectionEnrichers || report.Warnings[0].Target != "0" ||
+		report.Warnings[0].Error != "unknown actor mallory" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestWithEventClassifier(t *testing.T) {
+	var bodies []string
+	client := NewClient("test-token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithMaxBodyLength(10),
+		WithEventClassifier(func(body string) map[string]any {
+			bodies = append(bodies, body)
+			if strings.Contains(body, "thanks") {
+				return map[string]any{"tone": "positive", "model": "heuristic"}
+			}
+			return nil
+		}),
+		WithEventClassifier(func(string) map[string]any {
+			return map[string]any{"model": "v2"}
+		}),
+	)
+
+	var data graphQLPullRequestComplete
+	if err := json.Unmarshal([]byte(`{
+		"body": "",
+		"reviews": {"nodes": [{"state": "APPROVED", "body": "", "author": {"login": "bob"}, "authorAssociation": "NONE"}]},
+		"comments": {"nodes": [{"body": "Looks great, thanks for fixing this!", "author": {"login": "bob"}, "authorAssociation": "NONE"}]}
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	events := client.convertGraphQLToEventsComplete(context.Background(), &data, "owner", "repo")
+
+	annotations := make(map[EventKind]map[string]any)
+	for _, e := range events {
+		annotations[e.Kind] = e.Annotations
+	}
+	if got := annotations[EventKindComment]; got["tone"] != "positive" || got["model"] != "v2" {
+		t.Errorf("comment annotations = %v, want tone from the first classifier and model from the second", got)
+	}
+	if got := annotations[EventKindReview]; got != nil {
+		t.Errorf("annotations for an empty review body = %v, want none", got)
+	}
+	// Classifiers see the full body, not the truncated Event.Body
+	if len(bodies) != 1 || bodies[0] != "Looks great, thanks for fixing this!" {
+		t.Errorf("classified bodies = %q", bodies)
+	}
+}
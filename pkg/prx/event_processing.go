@@ -81,7 +81,7 @@ func calculateCheckSummary(events []Event, requiredChecks []string) *CheckSummar
 	// Events should be sorted chronologically, but we explicitly track timestamps to be safe
 	for i := range events {
 		e := &events[i]
-		if (e.Kind == EventKindStatusCheck || e.Kind == EventKindCheckRun) && e.Body != "" {
+		if (e.Kind == EventKindStatusCheck || e.Kind == EventKindCheckRun) && e.Body != "" && !e.Orphaned {
 			existing, exists := latestChecks[e.Body]
 			// Update if:
 			// 1. First occurrence (!exists)
@@ -143,17 +143,92 @@ func calculateCheckSummary(events []Event, requiredChecks []string) *CheckSummar
 	return summary
 }
 
+// calculateChecksByCommit summarizes the checks of each commit, keyed by SHA. Check events without
+// a commit come from the head commit's status rollup and are attributed to headSHA. Required checks
+// that never reported are only reflected in the overall CheckSummary.
+func calculateChecksByCommit(events []Event, headSHA string) map[string]*CheckSummary {
+	byCommit := make(map[string][]Event)
+	for i := range events {
+		e := &events[i]
+		if e.Kind != EventKindStatusCheck && e.Kind != EventKindCheckRun || e.Orphaned {
+			continue
+		}
+		sha := e.Target
+		if sha == "" {
+			sha = headSHA
+		}
+		if sha != "" {
+			byCommit[sha] = append(byCommit[sha], *e)
+		}
+	}
+	if len(byCommit) == 0 {
+		return nil
+	}
+
+	summaries := make(map[string]*CheckSummary, len(byCommit))
+	for sha, checks := range byCommit {
+		summaries[sha] = calculateCheckSummary(checks, nil)
+	}
+	return summaries
+}
+
+// markOrphanedChecks flags check runs on commits that are no longer in the pull request, such as
+// commits that were force-pushed away. Check runs without a commit are on the head commit.
+func markOrphanedChecks(events []Event, pr *PullRequest) {
+	if len(pr.Commits) == 0 {
+		return
+	}
+	commits := make(map[string]bool, len(pr.Commits)+1)
+	for _, sha := range pr.Commits {
+		commits[sha] = true
+	}
+	commits[pr.HeadSHA] = true
+	for i := range events {
+		e := &events[i]
+		if e.Kind == EventKindCheckRun && e.Target != "" && !commits[e.Target] {
+			e.Orphaned = true
+		}
+	}
+}
+
+// setForcePushes counts the force pushes to the head branch among events, which are in
+// chronological order, and whether any came after an approval.
+func setForcePushes(pr *PullRequest, events []Event) {
+	pr.ForcePushCount, pr.ForcePushedAfterApproval = 0, false
+	approved := false
+	for i := range events {
+		e := &events[i]
+		switch {
+		case e.Kind == EventKindReview && e.Outcome == string(ReviewStateApproved):
+			approved = true
+		case e.Kind == EventKindHeadRefForcePushed:
+			pr.ForcePushCount++
+			pr.ForcePushedAfterApproval = pr.ForcePushedAfterApproval || approved
+		default:
+		}
+	}
+}
+
 // calculateApprovalSummary analyzes review events and categorizes approvals by reviewer's write access.
-func calculateApprovalSummary(events []Event) *ApprovalSummary {
+// Approvals of a commit other than headSHA are stale, as are those before the latest push when the
+// reviewed commit isn't known.
+func calculateApprovalSummary(events []Event, headSHA string) *ApprovalSummary {
 	summary := &ApprovalSummary{}
 
 	// Track the latest review state from each user
 	latestReviews := make(map[string]Event)
+	var lastPush time.Time
 
 	for i := range events {
 		e := &events[i]
-		if e.Kind == EventKindReview && e.Outcome != "" {
+		switch {
+		case e.Kind == EventKindReview && e.Outcome != "":
 			latestReviews[e.Actor] = *e
+		case e.Kind == EventKindCommit || e.Kind == EventKindHeadRefForcePushed:
+			if e.Timestamp.After(lastPush) {
+				lastPush = e.Timestamp
+			}
+		default:
 		}
 	}
 
@@ -162,6 +237,23 @@ func calculateApprovalSummary(events []Event) *ApprovalSummary {
 		review := latestReviews[actor]
 		switch review.Outcome {
 		case "approved":
+			// Automated approvals aren't reviews
+			if review.ActorType == ActorTypeMachineUser || review.ActorType == ActorTypeGitHubApp || review.ActorType == ActorTypeSuspendedApp {
+				continue
+			}
+			if review.CommitSHA != "" {
+				if summary.ApprovedAtSHA == nil {
+					summary.ApprovedAtSHA = make(map[string]string)
+				}
+				summary.ApprovedAtSHA[actor] = review.CommitSHA
+			}
+			stale := review.Timestamp.Before(lastPush)
+			if review.CommitSHA != "" && headSHA != "" {
+				stale = review.CommitSHA != headSHA
+			}
+			if stale {
+				summary.StaleApprovals++
+			}
 			// Use the WriteAccess field that was already populated in the event
 			switch review.WriteAccess {
 			case WriteAccessDefinitely:
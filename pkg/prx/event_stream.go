@@ -0,0 +1,93 @@
+package prx
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"time"
+)
+
+// Events streams a pull request's events as pages of results arrive, rather than buffering
+// them all into PullRequestData. It suits very large pull requests and UIs that render
+// progressively.
+//
+// Events are yielded one page at a time, each page in chronological order, so the sequence
+// as a whole is not sorted, and Seq numbers events in the order they are yielded rather than
+// chronologically as PullRequest does. Check runs for the pull request's commits are fetched
+// via REST after all GraphQL pages. When the pull request has more files than fit on the first
+// page, the first page is yielded once the file list is complete, so PathDeleted is set as by
+// PullRequest.
+//
+// Results are not cached, and unlike PullRequest, write access is not upgraded using evidence
+// from other pages. Secrets are redacted as configured by WithSecretRedaction, and enrichers run
+// on each event. A fetch error is yielded once, ending the sequence.
+func (c *Client) Events(ctx context.Context, owner, repo string, prNumber int) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		data, caps, err := c.executeGraphQLFirstPage(ctx, owner, repo, prNumber)
+		if err != nil {
+			yield(Event{}, err)
+			return
+		}
+
+		// Commit events are kept to look up check runs once all pages are in
+		commits := &PullRequestData{PullRequest: PullRequest{HeadSHA: data.HeadRef.Target.OID}}
+		var seq int64
+		emit := func(events []Event) bool {
+			events = filterEvents(events)
+			c.redactSecrets(&PullRequestData{Events: events})
+			sort.Slice(events, func(i, j int) bool {
+				return events[i].Timestamp.Before(events[j].Timestamp)
+			})
+			c.enrichEvents(ctx, events, &FetchReport{})
+			for i := range events {
+				seq++
+				events[i].Seq = seq
+				if events[i].Kind == EventKindCommit {
+					commits.Events = append(commits.Events, events[i])
+				}
+				if !yield(events[i], nil) {
+					return false
+				}
+			}
+			return true
+		}
+
+		// File pages come first, and the first page waits for them; later pages are given the
+		// complete file list, since their review threads may be on any file.
+		emitted := false
+		emitFirst := func() bool {
+			emitted = true
+			return emit(c.convertGraphQLToEventsComplete(ctx, data, owner, repo))
+		}
+		if !data.Files.PageInfo.HasNextPage && !emitFirst() {
+			return
+		}
+
+		stopped := false
+		err = c.forEachRemainingPage(ctx, owner, repo, prNumber, data, caps, func(page *graphQLPullRequestComplete) bool {
+			data.Files.Nodes = append(data.Files.Nodes, page.Files.Nodes...)
+			if data.Files.PageInfo.HasNextPage {
+				return true
+			}
+			if !emitted && !emitFirst() {
+				stopped = true
+				return false
+			}
+			page.Files = data.Files
+			stopped = !emit(c.convertGraphQLConnectionEvents(ctx, page, owner, repo))
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+		if !emitted && !emitFirst() {
+			return
+		}
+		if err != nil {
+			yield(Event{}, err)
+			return
+		}
+
+		emit(c.fetchAllCheckRunsREST(ctx, owner, repo, commits, time.Now(), nil))
+	}
+}
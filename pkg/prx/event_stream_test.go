@@ -0,0 +1,172 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_Events(t *testing.T) {
+	graphQLRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+			return
+		}
+		graphQLRequests++
+		rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+		if graphQLRequests == 1 {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"headRef": {"target": {"oid": "abc123"}},
+				` + commentPage(0, 100, true) + `}}, ` + rateLimit + `}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {` + commentPage(100, 50, false) + `}}, ` + rateLimit + `}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	counts := make(map[EventKind]int)
+	var seq int64
+	for e, err := range client.Events(context.Background(), "owner", "repo", 1) {
+		if err != nil {
+			t.Fatalf("Events() error: %v", err)
+		}
+		counts[e.Kind]++
+		if seq++; e.Seq != seq {
+			t.Errorf("%s event Seq = %d, want %d", e.Kind, e.Seq, seq)
+		}
+	}
+	if counts[EventKindPROpened] != 1 || counts[EventKindComment] != 150 {
+		t.Errorf("event counts = %v, want 1 pr_opened and 150 comments", counts)
+	}
+	if graphQLRequests != 2 {
+		t.Errorf("expected 2 GraphQL requests, got %d", graphQLRequests)
+	}
+
+	// Stopping early skips the remaining pages.
+	graphQLRequests = 0
+	for range client.Events(context.Background(), "owner", "repo", 1) {
+		break
+	}
+	if graphQLRequests != 1 {
+		t.Errorf("expected 1 GraphQL request after early break, got %d", graphQLRequests)
+	}
+}
+
+func TestClient_EventsPathDeleted(t *testing.T) {
+	rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+	thread := func(path, author string) string {
+		return `{"path": "` + path + `", "comments": {"nodes": [{"createdAt": "2025-01-01T01:00:00Z", "body": "Why?", "author": {"login": "` + author + `"}}]}}`
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+		var page string
+		switch len(queries) {
+		case 1:
+			page = `"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"headRef": {"target": {"oid": "abc123"}},
+				"files": {"pageInfo": {"hasNextPage": true, "endCursor": "f1"}, "nodes": [{"path": "kept.go"}]},
+				"reviewThreads": {"pageInfo": {"hasNextPage": true, "endCursor": "t1"}, "nodes": [` +
+				thread("kept.go", "alice") + `, ` + thread("later.go", "bob") + `, ` + thread("moved.go", "carol") + `]}`
+		case 2:
+			page = `"files": {"nodes": [{"path": "later.go"}]}`
+		default:
+			page = `"reviewThreads": {"nodes": [` + thread("moved.go", "dave") + `, ` + thread("later.go", "erin") + `]}`
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {` + page + `}}, ` + rateLimit + `}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	deleted := make(map[string]bool)
+	for e, err := range client.Events(context.Background(), "owner", "repo", 1) {
+		if err != nil {
+			t.Fatalf("Events() error: %v", err)
+		}
+		if e.Kind == EventKindReviewComment {
+			deleted[e.Actor] = e.PathDeleted
+		}
+	}
+	want := map[string]bool{"alice": false, "bob": false, "carol": true, "dave": true, "erin": false}
+	if !maps.Equal(deleted, want) {
+		t.Errorf("PathDeleted by commenter = %v, want %v", deleted, want)
+	}
+	if len(queries) != 3 || !strings.Contains(queries[1], "FileFields") {
+		t.Errorf("expected the files page to be fetched second, of 3 queries; got %d", len(queries))
+	}
+}
+
+func TestClient_EventsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"errors": [{"message": "Could not resolve to a PullRequest"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	n := 0
+	for _, err := range client.Events(context.Background(), "owner", "repo", 1) {
+		n++
+		if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+			t.Errorf("expected resolve error, got %v", err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("expected exactly one yielded error, got %d", n)
+	}
+}
+
+func TestClient_EventsRedactsSecrets(t *testing.T) {
+	const secret = "ghp_" + "abcdefghijklmnopqrstuvwxyz0123456789"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+			"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+			"headRef": {"target": {"oid": "abc123"}},
+			"comments": {"nodes": [{"createdAt": "2025-01-01T01:00:00Z", "author": {"login": "alice"}, "body": "token ` + secret + `"}]}
+		}}, "rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithSecretRedaction())
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	comments := 0
+	for e, err := range client.Events(context.Background(), "owner", "repo", 1) {
+		if err != nil {
+			t.Fatalf("Events() error: %v", err)
+		}
+		if strings.Contains(e.Body, secret) {
+			t.Errorf("%s event body %q contains the secret", e.Kind, e.Body)
+		}
+		if e.Kind == EventKindComment {
+			comments++
+		}
+	}
+	if comments != 1 {
+		t.Errorf("got %d comments, want 1", comments)
+	}
+}
@@ -1,83 +1,149 @@
 package prx
 
 import (
+	"encoding/json"
 	"time"
 )
 
-// Event kind constants for PR timeline events.
+// EventKind is what happened in an event, such as EventKindReview. It marshals as a plain string,
+// and kinds that aren't one of the constants below, including the empty kind, round-trip unchanged so
+// that data from newer versions still decodes; use IsValid to check for them.
+type EventKind string
+
+// Event kinds. Keep eventKinds in step.
 const (
-	EventKindCommit        = "commit"         // EventKindCommit represents a commit event.
-	EventKindComment       = "comment"        // EventKindComment represents a comment event.
-	EventKindReview        = "review"         // EventKindReview represents a review event.
-	EventKindReviewComment = "review_comment" // EventKindReviewComment represents a review comment event.
+	EventKindCommit        EventKind = "commit"         // EventKindCommit represents a commit event.
+	EventKindComment       EventKind = "comment"        // EventKindComment represents a comment event.
+	EventKindReview        EventKind = "review"         // EventKindReview represents a review event.
+	EventKindReviewComment EventKind = "review_comment" // EventKindReviewComment represents a review comment event.
 
-	EventKindLabeled   = "labeled"   // EventKindLabeled represents a label added event.
-	EventKindUnlabeled = "unlabeled" // EventKindUnlabeled represents a label removed event.
+	EventKindLabeled   EventKind = "labeled"   // EventKindLabeled represents a label added event.
+	EventKindUnlabeled EventKind = "unlabeled" // EventKindUnlabeled represents a label removed event.
 
-	EventKindAssigned   = "assigned"   // EventKindAssigned represents an assignment event.
-	EventKindUnassigned = "unassigned" // EventKindUnassigned represents an unassignment event.
+	EventKindAssigned   EventKind = "assigned"   // EventKindAssigned represents an assignment event.
+	EventKindUnassigned EventKind = "unassigned" // EventKindUnassigned represents an unassignment event.
 
-	EventKindMilestoned   = "milestoned"   // EventKindMilestoned represents a milestone added event.
-	EventKindDemilestoned = "demilestoned" // EventKindDemilestoned represents a milestone removed event.
+	EventKindMilestoned   EventKind = "milestoned"   // EventKindMilestoned represents a milestone added event.
+	EventKindDemilestoned EventKind = "demilestoned" // EventKindDemilestoned represents a milestone removed event.
 
-	EventKindReviewRequested      = "review_requested"       // EventKindReviewRequested represents a review request event.
-	EventKindReviewRequestRemoved = "review_request_removed" // EventKindReviewRequestRemoved represents a review request removed event.
+	EventKindReviewRequested      EventKind = "review_requested"       // EventKindReviewRequested represents a review request event.
+	EventKindReviewRequestRemoved EventKind = "review_request_removed" // EventKindReviewRequestRemoved represents a review request removed event.
 
-	EventKindPROpened       = "pr_opened"        // EventKindPROpened represents a PR opened event.
-	EventKindPRClosed       = "pr_closed"        // EventKindPRClosed represents a PR closed event.
-	EventKindPRMerged       = "pr_merged"        // EventKindPRMerged represents a PR merge event.
-	EventKindMerged         = "merged"           // EventKindMerged represents a merge event from timeline.
-	EventKindReadyForReview = "ready_for_review" // EventKindReadyForReview represents a ready for review event.
-	EventKindConvertToDraft = "convert_to_draft" // EventKindConvertToDraft represents a convert to draft event.
-	EventKindClosed         = "closed"           // EventKindClosed represents a PR closed event.
-	EventKindReopened       = "reopened"         // EventKindReopened represents a PR reopened event.
-	EventKindRenamedTitle   = "renamed_title"    // EventKindRenamedTitle represents a title rename event.
+	EventKindPROpened       EventKind = "pr_opened"        // EventKindPROpened represents a PR opened event.
+	EventKindPRClosed       EventKind = "pr_closed"        // EventKindPRClosed represents a PR closed event.
+	EventKindPRMerged       EventKind = "pr_merged"        // EventKindPRMerged represents a PR merge event.
+	EventKindMerged         EventKind = "merged"           // EventKindMerged represents a merge event from timeline.
+	EventKindReadyForReview EventKind = "ready_for_review" // EventKindReadyForReview represents a ready for review event.
+	EventKindConvertToDraft EventKind = "convert_to_draft" // EventKindConvertToDraft represents a convert to draft event.
+	EventKindClosed         EventKind = "closed"           // EventKindClosed represents a PR closed event.
+	EventKindReopened       EventKind = "reopened"         // EventKindReopened represents a PR reopened event.
+	EventKindRenamedTitle   EventKind = "renamed_title"    // EventKindRenamedTitle represents a title rename event.
 
-	EventKindMentioned       = "mentioned"        // EventKindMentioned represents a mention event.
-	EventKindReferenced      = "referenced"       // EventKindReferenced represents a reference event.
-	EventKindCrossReferenced = "cross_referenced" // EventKindCrossReferenced represents a cross-reference event.
+	EventKindMentioned       EventKind = "mentioned"        // EventKindMentioned represents a mention event.
+	EventKindReferenced      EventKind = "referenced"       // EventKindReferenced represents a reference event.
+	EventKindCrossReferenced EventKind = "cross_referenced" // EventKindCrossReferenced represents a cross-reference event.
 
-	EventKindPinned      = "pinned"      // EventKindPinned represents a pin event.
-	EventKindUnpinned    = "unpinned"    // EventKindUnpinned represents an unpin event.
-	EventKindTransferred = "transferred" // EventKindTransferred represents a transfer event.
+	EventKindPinned      EventKind = "pinned"      // EventKindPinned represents a pin event.
+	EventKindUnpinned    EventKind = "unpinned"    // EventKindUnpinned represents an unpin event.
+	EventKindTransferred EventKind = "transferred" // EventKindTransferred represents a transfer event.
 
-	EventKindSubscribed   = "subscribed"   // EventKindSubscribed represents a subscription event.
-	EventKindUnsubscribed = "unsubscribed" // EventKindUnsubscribed represents an unsubscription event.
+	EventKindSubscribed   EventKind = "subscribed"   // EventKindSubscribed represents a subscription event.
+	EventKindUnsubscribed EventKind = "unsubscribed" // EventKindUnsubscribed represents an unsubscription event.
 
-	EventKindHeadRefDeleted     = "head_ref_deleted"      // EventKindHeadRefDeleted represents a head ref deletion event.
-	EventKindHeadRefRestored    = "head_ref_restored"     // EventKindHeadRefRestored represents a head ref restoration event.
-	EventKindHeadRefForcePushed = "head_ref_force_pushed" // EventKindHeadRefForcePushed represents a head ref force push event.
+	EventKindHeadRefDeleted     EventKind = "head_ref_deleted"      // EventKindHeadRefDeleted represents a head ref deletion event.
+	EventKindHeadRefRestored    EventKind = "head_ref_restored"     // EventKindHeadRefRestored represents a head ref restoration event.
+	EventKindHeadRefForcePushed EventKind = "head_ref_force_pushed" // EventKindHeadRefForcePushed represents a head ref force push event.
 
-	EventKindBaseRefChanged     = "base_ref_changed"      // EventKindBaseRefChanged represents a base ref change event.
-	EventKindBaseRefForcePushed = "base_ref_force_pushed" // EventKindBaseRefForcePushed represents a base ref force push event.
+	EventKindBaseRefChanged     EventKind = "base_ref_changed"      // EventKindBaseRefChanged represents a base ref change event.
+	EventKindBaseRefForcePushed EventKind = "base_ref_force_pushed" // EventKindBaseRefForcePushed represents a base ref force push event.
 
-	EventKindReviewDismissed = "review_dismissed" // EventKindReviewDismissed represents a review dismissed event.
+	EventKindReviewDismissed EventKind = "review_dismissed" // EventKindReviewDismissed represents a review dismissed event.
 
-	EventKindLocked   = "locked"   // EventKindLocked represents a lock event.
-	EventKindUnlocked = "unlocked" // EventKindUnlocked represents an unlock event.
+	EventKindLocked   EventKind = "locked"   // EventKindLocked represents a lock event.
+	EventKindUnlocked EventKind = "unlocked" // EventKindUnlocked represents an unlock event.
 
-	EventKindAutoMergeEnabled      = "auto_merge_enabled"       // EventKindAutoMergeEnabled represents an auto merge enabled event.
-	EventKindAutoMergeDisabled     = "auto_merge_disabled"      // EventKindAutoMergeDisabled represents an auto merge disabled event.
-	EventKindAddedToMergeQueue     = "added_to_merge_queue"     // EventKindAddedToMergeQueue represents an added to merge queue event.
-	EventKindRemovedFromMergeQueue = "removed_from_merge_queue" // EventKindRemovedFromMergeQueue represents removal from merge queue.
+	EventKindAutoMergeEnabled      EventKind = "auto_merge_enabled"       // EventKindAutoMergeEnabled represents an auto merge enabled event.
+	EventKindAutoMergeDisabled     EventKind = "auto_merge_disabled"      // EventKindAutoMergeDisabled represents an auto merge disabled event.
+	EventKindAddedToMergeQueue     EventKind = "added_to_merge_queue"     // EventKindAddedToMergeQueue represents an added to merge queue event.
+	EventKindRemovedFromMergeQueue EventKind = "removed_from_merge_queue" // EventKindRemovedFromMergeQueue represents removal from merge queue.
 
 	// EventKindAutomaticBaseChangeSucceeded represents a successful base change.
-	EventKindAutomaticBaseChangeSucceeded = "automatic_base_change_succeeded"
+	EventKindAutomaticBaseChangeSucceeded EventKind = "automatic_base_change_succeeded"
 	// EventKindAutomaticBaseChangeFailed represents a failed base change.
-	EventKindAutomaticBaseChangeFailed = "automatic_base_change_failed"
+	EventKindAutomaticBaseChangeFailed EventKind = "automatic_base_change_failed"
 
-	EventKindDeployed = "deployed" // EventKindDeployed represents a deployment event.
+	EventKindDeployed EventKind = "deployed" // EventKindDeployed represents a deployment event.
 	// EventKindDeploymentEnvironmentChanged represents a deployment environment change event.
-	EventKindDeploymentEnvironmentChanged = "deployment_environment_changed"
+	EventKindDeploymentEnvironmentChanged EventKind = "deployment_environment_changed"
 
-	EventKindConnected    = "connected"    // EventKindConnected represents a connected event.
-	EventKindDisconnected = "disconnected" // EventKindDisconnected represents a disconnected event.
-	EventKindUserBlocked  = "user_blocked" // EventKindUserBlocked represents a user blocked event.
+	EventKindConnected    EventKind = "connected"    // EventKindConnected represents a connected event.
+	EventKindDisconnected EventKind = "disconnected" // EventKindDisconnected represents a disconnected event.
+	EventKindUserBlocked  EventKind = "user_blocked" // EventKindUserBlocked represents a user blocked event.
 
-	EventKindStatusCheck = "status_check" // EventKindStatusCheck represents a status check event (from APIs).
-	EventKindCheckRun    = "check_run"    // EventKindCheckRun represents a check run event (from APIs).
+	EventKindStatusCheck EventKind = "status_check" // EventKindStatusCheck represents a status check event (from APIs).
+	EventKindCheckRun    EventKind = "check_run"    // EventKindCheckRun represents a check run event (from APIs).
 )
 
+// eventKinds are the valid event kinds.
+var eventKinds = map[EventKind]struct{}{
+	EventKindCommit:                       {},
+	EventKindComment:                      {},
+	EventKindReview:                       {},
+	EventKindReviewComment:                {},
+	EventKindLabeled:                      {},
+	EventKindUnlabeled:                    {},
+	EventKindAssigned:                     {},
+	EventKindUnassigned:                   {},
+	EventKindMilestoned:                   {},
+	EventKindDemilestoned:                 {},
+	EventKindReviewRequested:              {},
+	EventKindReviewRequestRemoved:         {},
+	EventKindPROpened:                     {},
+	EventKindPRClosed:                     {},
+	EventKindPRMerged:                     {},
+	EventKindMerged:                       {},
+	EventKindReadyForReview:               {},
+	EventKindConvertToDraft:               {},
+	EventKindClosed:                       {},
+	EventKindReopened:                     {},
+	EventKindRenamedTitle:                 {},
+	EventKindMentioned:                    {},
+	EventKindReferenced:                   {},
+	EventKindCrossReferenced:              {},
+	EventKindPinned:                       {},
+	EventKindUnpinned:                     {},
+	EventKindTransferred:                  {},
+	EventKindSubscribed:                   {},
+	EventKindUnsubscribed:                 {},
+	EventKindHeadRefDeleted:               {},
+	EventKindHeadRefRestored:              {},
+	EventKindHeadRefForcePushed:           {},
+	EventKindBaseRefChanged:               {},
+	EventKindBaseRefForcePushed:           {},
+	EventKindReviewDismissed:              {},
+	EventKindLocked:                       {},
+	EventKindUnlocked:                     {},
+	EventKindAutoMergeEnabled:             {},
+	EventKindAutoMergeDisabled:            {},
+	EventKindAddedToMergeQueue:            {},
+	EventKindRemovedFromMergeQueue:        {},
+	EventKindAutomaticBaseChangeSucceeded: {},
+	EventKindAutomaticBaseChangeFailed:    {},
+	EventKindDeployed:                     {},
+	EventKindDeploymentEnvironmentChanged: {},
+	EventKindConnected:                    {},
+	EventKindDisconnected:                 {},
+	EventKindUserBlocked:                  {},
+	EventKindStatusCheck:                  {},
+	EventKindCheckRun:                     {},
+}
+
+// IsValid reports whether k is one of the EventKind constants.
+func (k EventKind) IsValid() bool {
+	_, ok := eventKinds[k]
+	return ok
+}
+
 // WriteAccess constants for the Event.WriteAccess field.
 const (
 	WriteAccessNo         = -2 // User confirmed to not have write access
@@ -91,16 +157,74 @@ const (
 // Each event captures who did what and when, with additional context depending on the event type.
 type Event struct {
 	Timestamp   time.Time `json:"timestamp"`
-	Kind        string    `json:"kind"`
+	Kind        EventKind `json:"kind"`
 	Actor       string    `json:"actor"`
+	ActorType   ActorType `json:"actor_type,omitempty"` // Kind of account behind Actor; empty if unknown
 	Target      string    `json:"target,omitempty"`
 	Outcome     string    `json:"outcome,omitempty"`
 	Body        string    `json:"body,omitempty"`
 	Description string    `json:"description,omitempty"`
+	Mentions    []string  `json:"mentions,omitempty"`     // Users and teams @mentioned in the body, outside of code
+	CheckRunID  int64     `json:"check_run_id,omitempty"` // For check runs: ID for FetchCheckOutput
+	Seq         int64     `json:"seq,omitempty"`          // Per-PR sequence number, stable across refreshes
 	WriteAccess int       `json:"write_access,omitempty"`
 	Bot         bool      `json:"bot,omitempty"`
 	TargetIsBot bool      `json:"target_is_bot,omitempty"`
 	Question    bool      `json:"question,omitempty"`
+	Suggestion  bool      `json:"suggestion,omitempty"` // For review comments: contains a suggested change
 	Required    bool      `json:"required,omitempty"`
 	Outdated    bool      `json:"outdated,omitempty"` // For review comments: indicates comment is on outdated code
+	// Reactions counts reactions to comments and reviews by name, e.g. "+1" or "hooray".
+	Reactions map[string]int `json:"reactions,omitempty"`
+	// ThreadResolved reports, for review comments, whether their review thread has been resolved.
+	ThreadResolved bool `json:"thread_resolved,omitempty"`
+	// PathDeleted reports, for review comments, whether the file they are on is no longer in the pull
+	// request, such as after it was deleted or moved.
+	PathDeleted bool `json:"path_deleted,omitempty"`
+	// BeforeSHA and AfterSHA are, for force pushes, the head commit before and after the push.
+	BeforeSHA string `json:"before_sha,omitempty"`
+	AfterSHA  string `json:"after_sha,omitempty"`
+	// Orphaned reports, for check runs, whether their commit is no longer in the pull request, as
+	// after a force push. They are left out of CheckSummary.
+	Orphaned bool `json:"orphaned,omitempty"`
+	// CommitSHA is, for reviews, the commit that was reviewed.
+	CommitSHA string `json:"commit_sha,omitempty"`
+	// WorkflowRunID is, for check runs of GitHub Actions jobs, the workflow run the job is part of.
+	// See RerunFailedJobs.
+	WorkflowRunID int64 `json:"workflow_run_id,omitempty"`
+	// WorkflowJobID is, for check runs of GitHub Actions jobs, the job's ID, as used by the Actions
+	// jobs API. It's taken from the check run's details URL, and is 0 if that doesn't name the job.
+	WorkflowJobID int64 `json:"workflow_job_id,omitempty"`
+	// Tags holds classifications added by enrichers, such as an owning team; see WithEnricher.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Annotations holds what event classifiers made of a comment or review body; see WithEventClassifier.
+	Annotations map[string]any `json:"annotations,omitempty"`
+	// Raw is the original GitHub JSON node for this event. Set only with WithRawPayloads.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// assignSequence numbers events for incremental consumers. Events that were present in previous
+// keep their sequence number; new events are numbered after the highest previous one, in order.
+// A consumer that has processed up to sequence n therefore only needs events with Seq > n.
+func assignSequence(events, previous []Event) {
+	known := make(map[string][]int64, len(previous))
+	var last int64
+	for i := range previous {
+		if previous[i].Seq == 0 {
+			continue
+		}
+		k := eventKey(&previous[i])
+		known[k] = append(known[k], previous[i].Seq)
+		last = max(last, previous[i].Seq)
+	}
+	for i := range events {
+		k := eventKey(&events[i])
+		if seqs := known[k]; len(seqs) > 0 {
+			events[i].Seq = seqs[0]
+			known[k] = seqs[1:]
+			continue
+		}
+		last++
+		events[i].Seq = last
+	}
 }
@@ -0,0 +1,47 @@
+package prx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventKind(t *testing.T) {
+	for _, k := range []EventKind{EventKindCommit, EventKindMerged, EventKindRenamedTitle, EventKindCrossReferenced, EventKindCheckRun} {
+		if !k.IsValid() {
+			t.Errorf("%q.IsValid() = false, want true", k)
+		}
+	}
+	for _, k := range []EventKind{"", "merge", "renamed", "Comment", "cross-referenced"} {
+		if k.IsValid() {
+			t.Errorf("%q.IsValid() = true, want false", k)
+		}
+	}
+
+	b, err := json.Marshal(Event{Kind: EventKindRenamedTitle})
+	if err != nil {
+		t.Fatalf("marshaling event: %v", err)
+	}
+	var e Event
+	if err := json.Unmarshal(b, &e); err != nil || e.Kind != EventKindRenamedTitle {
+		t.Errorf("round trip: kind = %q, error = %v; want %q", e.Kind, err, EventKindRenamedTitle)
+	}
+
+	for _, kind := range []EventKind{"", "renamed"} {
+		b, err := json.Marshal(Event{Kind: kind})
+		if err != nil {
+			t.Fatalf("marshaling an event with kind %q: %v", kind, err)
+		}
+		var e Event
+		if err := json.Unmarshal(b, &e); err != nil || e.Kind != kind {
+			t.Errorf("JSON round trip: kind = %q, error = %v; want %q", e.Kind, err, kind)
+		}
+		data, err := (&PullRequestData{Events: []Event{{Kind: kind}}}).ToProto()
+		if err != nil {
+			t.Fatalf("encoding an event with kind %q to protobuf: %v", kind, err)
+		}
+		var decoded PullRequestData
+		if err := decoded.FromProto(data); err != nil || len(decoded.Events) != 1 || decoded.Events[0].Kind != kind {
+			t.Errorf("protobuf round trip: events = %+v, error = %v; want kind %q", decoded.Events, err, kind)
+		}
+	}
+}
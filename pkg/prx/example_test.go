@@ -61,7 +61,7 @@ func ExampleClient_PullRequest() {
 		data.PullRequest.ChangedFiles)
 
 	// Count events by type
-	eventCounts := make(map[string]int)
+	eventCounts := make(map[prx.EventKind]int)
 	for i := range data.Events {
 		eventCounts[data.Events[i].Kind]++
 	}
@@ -0,0 +1,109 @@
+package prx
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// FeatureFlagPatterns configures detection of feature flag changes.
+type FeatureFlagPatterns struct {
+	// Paths are path.Match globs matched against each changed file's full path and base name.
+	Paths []string
+	// Calls match flag SDK usage in added or removed lines; the first capture group is the flag name.
+	Calls []*regexp.Regexp
+}
+
+// DefaultFeatureFlagPatterns returns patterns for common flag configuration files and for the
+// LaunchDarkly, Unleash, and OpenFeature SDKs.
+func DefaultFeatureFlagPatterns() FeatureFlagPatterns {
+	return FeatureFlagPatterns{
+		Paths: []string{
+			"flags.json", "flags.yaml", "flags.yml",
+			"feature_flags.*", "feature-flags.*", "featureflags.*",
+			"*.flagd.json", "*.flagd.yaml",
+			".launchdarkly/*", "flags/*",
+		},
+		Calls: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\b(?:bool|string|int|float|double|json)?variation(?:detail)?\(\s*["']([\w.:-]+)["']`),
+			regexp.MustCompile(`(?i)\bis_?enabled\(\s*["']([\w.:-]+)["']`),
+			regexp.MustCompile(`(?i)\b(?:get)?(?:boolean|string|integer|number|float|object)(?:value|details)\(\s*(?:ctx,\s*)?["']([\w.:-]+)["']`),
+		},
+	}
+}
+
+// FeatureFlagChanges describes the feature flag changes in a pull request.
+type FeatureFlagChanges struct {
+	ConfigFiles []string `json:"config_files,omitempty"` // Changed files matching flag configuration paths
+	Flags       []string `json:"flags,omitempty"`        // Flag names referenced in changed lines, sorted
+}
+
+// WithFeatureFlagDetection flags pull requests that change feature flag configuration or SDK calls,
+// populating PullRequest.FeatureFlags. Detection fetches the pull request's patches, costing one
+// REST request per 100 changed files.
+func WithFeatureFlagDetection(patterns FeatureFlagPatterns) Option {
+	return func(c *Client) {
+		c.featureFlagPatterns = &patterns
+	}
+}
+
+// fetchFeatureFlagChanges fetches the pull request's files and detects feature flag changes.
+func (c *Client) fetchFeatureFlagChanges(ctx context.Context, owner, repo string, prNumber int) (*FeatureFlagChanges, error) {
+	files, err := c.github.PullRequestFiles(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	changes := &FeatureFlagChanges{}
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		if matchesAnyPath(f.Filename, c.featureFlagPatterns.Paths) {
+			changes.ConfigFiles = append(changes.ConfigFiles, f.Filename)
+		}
+		for _, name := range flagNamesInPatch(f.Patch, c.featureFlagPatterns.Calls) {
+			if !slices.Contains(changes.Flags, name) {
+				changes.Flags = append(changes.Flags, name)
+			}
+		}
+	}
+	if len(changes.ConfigFiles) == 0 && len(changes.Flags) == 0 {
+		return nil, nil //nolint:nilnil // not flag-related
+	}
+	slices.Sort(changes.Flags)
+	return changes, nil
+}
+
+// matchesAnyPath reports whether file, or its base name, matches any of the glob patterns.
+func matchesAnyPath(file string, patterns []string) bool {
+	base := path.Base(file)
+	for _, p := range patterns {
+		if ok, err := path.Match(p, file); err == nil && ok {
+			return true
+		}
+		if ok, err := path.Match(p, base); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// flagNamesInPatch extracts flag names from the added and removed lines of a unified diff.
+func flagNamesInPatch(patch string, calls []*regexp.Regexp) []string {
+	var names []string
+	for line := range strings.Lines(patch) {
+		if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") {
+			continue
+		}
+		for _, re := range calls {
+			for _, m := range re.FindAllStringSubmatch(line[1:], -1) {
+				if len(m) > 1 && m[1] != "" {
+					names = append(names, m[1])
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestFlagNamesInPatch(t *testing.T) {
+	patch := `@@ -1,4 +1,6 @@
+ if client.BoolVariation("unchanged-flag", user, false) {
++if ldClient.boolVariation('new-checkout', user, false) {
+-  unleash.isEnabled("old.banner")
++  flag, _ := of.BooleanValue(ctx, "dark-mode", false, evalCtx)
++  value := client.getStringValue("theme", "light")
++  fmt.Println("variation(not-a-call)")
+`
+	got := flagNamesInPatch(patch, DefaultFeatureFlagPatterns().Calls)
+	want := []string{"new-checkout", "old.banner", "dark-mode", "theme"}
+	if !slices.Equal(got, want) {
+		t.Errorf("flagNamesInPatch() = %v, want %v", got, want)
+	}
+}
+
+func TestMatchesAnyPath(t *testing.T) {
+	paths := DefaultFeatureFlagPatterns().Paths
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{file: "config/flags.yaml", want: true},
+		{file: "deploy/feature_flags.json", want: true},
+		{file: "flags/checkout.json", want: true},
+		{file: "app/rollout.flagd.json", want: true},
+		{file: "src/flags.go", want: false},
+		{file: "README.md", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchesAnyPath(tt.file, paths); got != tt.want {
+			t.Errorf("matchesAnyPath(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestClient_FetchFeatureFlagChanges(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls/1/files" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls/1/files?per_page=100&page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"filename": "config/flags.yaml", "status": "modified", "patch": "+checkout: true"}]`))
+			return
+		}
+		w.Write([]byte(`[{"filename": "main.go", "status": "modified", "patch": "+if ld.BoolVariation(\"zeta\", u, false) {}\n+if ld.BoolVariation(\"alpha\", u, false) {}"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithFeatureFlagDetection(DefaultFeatureFlagPatterns()),
+	)
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	got, err := client.fetchFeatureFlagChanges(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("fetchFeatureFlagChanges() error: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected feature flag changes")
+	}
+	if !slices.Equal(got.ConfigFiles, []string{"config/flags.yaml"}) {
+		t.Errorf("ConfigFiles = %v", got.ConfigFiles)
+	}
+	if !slices.Equal(got.Flags, []string{"alpha", "zeta"}) {
+		t.Errorf("Flags = %v, want [alpha zeta]", got.Flags)
+	}
+}
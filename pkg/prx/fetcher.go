@@ -0,0 +1,21 @@
+package prx
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Fetcher is the part of Client that reads pull requests. Accept it rather than *Client in code
+// that only reads, so that tests can pass a prxtest.FakeClient instead of a client backed by an
+// HTTP server.
+type Fetcher interface {
+	PullRequest(ctx context.Context, owner, repo string, prNumber int) (*PullRequestData, error)
+	PullRequestWithReferenceTime(ctx context.Context, owner, repo string, pr int, refTime time.Time) (*PullRequestData, error)
+	PullRequests(ctx context.Context, requests []BatchRequest, refTime time.Time, concurrency int) iter.Seq[BatchResult]
+	PullRequestNumbers(ctx context.Context, owner, repo, state string) ([]int, error)
+	Events(ctx context.Context, owner, repo string, prNumber int) iter.Seq2[Event, error]
+	Diff(ctx context.Context, owner, repo string, pr int) (string, error)
+}
+
+var _ Fetcher = (*Client)(nil)
@@ -0,0 +1,61 @@
+package prx
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// Sections of a pull request fetched separately from the main query, which may fail on their own.
+const (
+	FetchSectionCheckRuns       = "check_runs"        // Target is the commit SHA
+	FetchSectionCommitFiles     = "commit_files"      // Target is the commit SHA
+	FetchSectionRulesets        = "rulesets"          // Required checks from repository rulesets
+	FetchSectionRepository      = "repository"        // Repository metadata
+	FetchSectionFeatureFlags    = "feature_flags"     // Feature flag detection over the diff
+	FetchSectionTickets         = "tickets"           // Target is the ticket key
+	FetchSectionGraphQL         = "graphql"           // Target is the path of the field with errors
+	FetchSectionEnrichers       = "enrichers"         // Target is the failing WithEnricher's position, from 0
+	FetchSectionCheckRunDetails = "check_run_details" // Target is the check name
+	FetchSectionBlame           = "blame"             // Blame of the lines changed by the pull request
+)
+
+// FetchWarning describes a part of a pull request that couldn't be fetched.
+type FetchWarning struct {
+	Section    string `json:"section"`
+	Target     string `json:"target,omitempty"`
+	Error      string `json:"error"`
+	StatusCode int    `json:"status_code,omitempty"` // HTTP status of a failed GitHub request
+}
+
+// FetchReport lists the parts of a pull request that couldn't be fetched. The rest of the data
+// is still returned, so callers can tell, e.g., a pull request without check runs from one whose
+// check runs couldn't be read.
+type FetchReport struct {
+	Warnings []FetchWarning `json:"warnings,omitempty"`
+}
+
+// Complete reports whether every part of the pull request was fetched.
+func (r *FetchReport) Complete() bool {
+	return len(r.Warnings) == 0
+}
+
+// Failed reports whether any part of section couldn't be fetched.
+func (r *FetchReport) Failed(section string) bool {
+	return slices.ContainsFunc(r.Warnings, func(w FetchWarning) bool { return w.Section == section })
+}
+
+// add records a failure to fetch section, for target if it applies to part of it.
+// It is a no-op on a nil report, for callers that don't collect warnings.
+func (r *FetchReport) add(section, target string, err error) {
+	if r == nil {
+		return
+	}
+	w := FetchWarning{Section: section, Target: target, Error: err.Error()}
+	var apiErr *github.Error
+	if errors.As(err, &apiErr) {
+		w.StatusCode = apiErr.StatusCode
+	}
+	r.Warnings = append(r.Warnings, w)
+}
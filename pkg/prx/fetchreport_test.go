@@ -0,0 +1,49 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequestFetchReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql":
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"headRef": {"name": "feature", "target": {"oid": "headsha"}}}}}}`))
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+		case r.URL.Path == "/repos/owner/repo":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	if data.FetchReport.Complete() || !data.FetchReport.Failed(FetchSectionCheckRuns) {
+		t.Fatalf("FetchReport = %+v, want check runs failure", data.FetchReport)
+	}
+	w := data.FetchReport.Warnings[0]
+	if w.Target != "headsha" || w.StatusCode != http.StatusForbidden || w.Error == "" {
+		t.Errorf("warning = %+v, want 403 for headsha", w)
+	}
+	if data.FetchReport.Failed(FetchSectionRepository) {
+		t.Error("expected repository metadata to be fetched")
+	}
+}
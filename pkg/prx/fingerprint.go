@@ -0,0 +1,49 @@
+package prx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// Fingerprint returns a hash of the pull request data that changes only when the pull request does,
+// so pollers and caches can cheaply tell that nothing changed and skip further processing. It ignores
+// when and how completely the data was fetched (CachedAt and FetchReport) and Timing, which counts
+// draft time up to the fetch, and doesn't depend on the order of events.
+func (d *PullRequestData) Fingerprint() string {
+	h := sha256.New()
+	stable := *d
+	stable.CachedAt = time.Time{}
+	stable.FetchReport = FetchReport{}
+	stable.Timing = nil
+	stable.Events = nil
+	writeCanonical(h, &stable)
+
+	// Events with equal timestamps may be listed in either order
+	events := make([][]byte, len(d.Events))
+	for i := range d.Events {
+		var b bytes.Buffer
+		writeCanonical(&b, &d.Events[i])
+		events[i] = b.Bytes()
+	}
+	slices.SortFunc(events, bytes.Compare)
+	for _, e := range events {
+		h.Write(e) //nolint:errcheck,gosec // Hash writes don't fail
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeCanonical writes v as JSON, whose map keys are sorted, followed by a newline. Values that
+// can't be encoded as JSON, such as functions in event annotations, are written with fmt instead.
+func writeCanonical(w io.Writer, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b = fmt.Appendf(nil, "%+v", v)
+	}
+	w.Write(append(b, '\n')) //nolint:errcheck,gosec // Writes to hashes and buffers don't fail
+}
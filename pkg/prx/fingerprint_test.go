@@ -0,0 +1,50 @@
+package prx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	newData := func() *PullRequestData {
+		data := GenerateSample(SampleOptions{Seed: 11, PullRequests: 1})[0]
+		return &data
+	}
+	base := newData()
+	want := base.Fingerprint()
+	if len(want) != 64 {
+		t.Fatalf("Fingerprint() = %q, want a SHA-256 hex digest", want)
+	}
+
+	refetched := newData()
+	refetched.CachedAt = time.Now()
+	refetched.FetchReport.Warnings = []FetchWarning{{Section: FetchSectionRepository, Error: "timeout"}}
+	refetched.Timing = &TimingSummary{TimeInDraft: time.Hour}
+	refetched.Events = append(refetched.Events,
+		Event{Kind: EventKindLabeled, Timestamp: base.PullRequest.CreatedAt, Target: "a"},
+		Event{Kind: EventKindLabeled, Timestamp: base.PullRequest.CreatedAt, Target: "b"})
+	reordered := newData()
+	reordered.Events = append(reordered.Events,
+		Event{Kind: EventKindLabeled, Timestamp: base.PullRequest.CreatedAt, Target: "b"},
+		Event{Kind: EventKindLabeled, Timestamp: base.PullRequest.CreatedAt, Target: "a"})
+	if got, want := refetched.Fingerprint(), reordered.Fingerprint(); got != want {
+		t.Errorf("Fingerprint() differs for fetch metadata or event order: %s != %s", got, want)
+	}
+
+	changes := map[string]func(*PullRequestData){
+		"title":         func(d *PullRequestData) { d.PullRequest.Title += "!" },
+		"label":         func(d *PullRequestData) { d.PullRequest.Labels = append(d.PullRequest.Labels, "urgent") },
+		"event body":    func(d *PullRequestData) { d.Events[0].Body += " edited" },
+		"new event":     func(d *PullRequestData) { d.Events = append(d.Events, Event{Kind: EventKindComment, Actor: "x"}) },
+		"head commit":   func(d *PullRequestData) { d.PullRequest.HeadSHA = "0123abc" },
+		"pruned":        func(d *PullRequestData) { d.Pruned = &PrunedEvents{Total: 1} },
+		"dropped event": func(d *PullRequestData) { d.Events = d.Events[1:] },
+	}
+	for name, change := range changes {
+		data := newData()
+		change(data)
+		if data.Fingerprint() == want {
+			t.Errorf("Fingerprint() unchanged after changing the %s", name)
+		}
+	}
+}
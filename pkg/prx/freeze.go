@@ -0,0 +1,192 @@
+package prx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFreezeDuration bounds recurring freeze windows, limiting how far back a schedule is searched.
+const maxFreezeDuration = 31 * 24 * time.Hour
+
+// FreezeWindow is a period during which pull requests should not be merged, such as a
+// release freeze. It is either a fixed window between Start and End, or a recurring
+// window created with NewCronFreezeWindow.
+type FreezeWindow struct {
+	Start    time.Time
+	End      time.Time
+	schedule *cronSchedule
+	location *time.Location
+	Reason   string // e.g. "Q4 release freeze"
+	duration time.Duration
+}
+
+// NewCronFreezeWindow creates a recurring freeze window that begins whenever the five-field
+// cron expression (minute hour day-of-month month day-of-week) fires in loc, and lasts for d.
+// For example, "0 17 * * 5" with a 63 hour duration freezes from Friday 17:00 to Monday 08:00.
+// A nil loc means UTC.
+func NewCronFreezeWindow(expr string, d time.Duration, loc *time.Location, reason string) (FreezeWindow, error) {
+	if d <= 0 || d > maxFreezeDuration {
+		return FreezeWindow{}, fmt.Errorf("freeze duration %v out of range (0, %v]", d, maxFreezeDuration)
+	}
+	schedule, err := parseCron(expr)
+	if err != nil {
+		return FreezeWindow{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return FreezeWindow{schedule: schedule, duration: d, location: loc, Reason: reason}, nil
+}
+
+// activeUntil reports whether the window is active at t, and if so when it ends.
+func (w *FreezeWindow) activeUntil(t time.Time) (time.Time, bool) {
+	if w.schedule == nil {
+		if !t.Before(w.Start) && t.Before(w.End) {
+			return w.End, true
+		}
+		return time.Time{}, false
+	}
+
+	// Find the latest firing within the window's duration before t
+	t = t.In(w.location)
+	for s := t.Truncate(time.Minute); t.Sub(s) < w.duration; s = s.Add(-time.Minute) {
+		if w.schedule.matches(s) {
+			return s.Add(w.duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// WithFreezeWindows configures deployment freeze windows. A pull request fetched while a
+// window is active is reported as not mergeable, with FrozenUntil set and the freeze noted
+// in MergeableStateDescription. Freezes are evaluated at the reference time of each fetch.
+func WithFreezeWindows(windows ...FreezeWindow) Option {
+	return func(c *Client) {
+		c.freezeWindows = windows
+	}
+}
+
+// applyFreezeWindows marks pr as blocked if any freeze window is active at t.
+func applyFreezeWindows(pr *PullRequest, windows []FreezeWindow, t time.Time) {
+	pr.FrozenUntil = nil
+	var until time.Time
+	var reason string
+	for i := range windows {
+		end, ok := windows[i].activeUntil(t)
+		if ok && end.After(until) {
+			until = end
+			reason = windows[i].Reason
+		}
+	}
+	if until.IsZero() || pr.State != "open" {
+		return
+	}
+
+	pr.FrozenUntil = &until
+	mergeable := false
+	pr.Mergeable = &mergeable
+
+	desc := "blocked by deployment freeze until " + until.UTC().Format(time.RFC3339)
+	if reason != "" {
+		desc += " (" + reason + ")"
+	}
+	if pr.MergeableState == "clean" || pr.MergeableStateDescription == "" {
+		pr.MergeableStateDescription = "PR is " + desc
+	} else {
+		pr.MergeableStateDescription += "; " + desc
+	}
+}
+
+// cronSchedule is a parsed five-field cron expression. Each field is a bit set of allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// matches reports whether the schedule fires at t's minute.
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domOK := s.dom&(1<<t.Day()) != 0
+	dowOK := s.dow&(1<<int(t.Weekday())) != 0
+	// As in cron, a day matches either restricted day field when both are restricted
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowOK
+	case s.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// parseCron parses a cron expression supporting "*", values, ranges, lists, and steps.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Sunday may be written as 0 or 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bit set.
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid value %q", b)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, errors.New("value out of range in " + strconv.Quote(part))
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
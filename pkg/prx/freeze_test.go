@@ -0,0 +1,99 @@
+package prx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"0 17 * * 5", "*/15 9-17 * * 1-5", "0 0 1,15 * *", "30 6 * 12 0,7"}
+	for _, expr := range valid {
+		if _, err := parseCron(expr); err != nil {
+			t.Errorf("parseCron(%q) error: %v", expr, err)
+		}
+	}
+	invalid := []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"}
+	for _, expr := range invalid {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) expected error", expr)
+		}
+	}
+}
+
+func TestCronFreezeWindow(t *testing.T) {
+	// Friday 17:00 through Monday 08:00 UTC
+	w, err := NewCronFreezeWindow("0 17 * * 5", 63*time.Hour, nil, "weekend")
+	if err != nil {
+		t.Fatalf("NewCronFreezeWindow() error: %v", err)
+	}
+
+	friday := time.Date(2025, 3, 14, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at     time.Time
+		active bool
+	}{
+		{at: friday.Add(-time.Minute), active: false},
+		{at: friday, active: true},
+		{at: friday.Add(40 * time.Hour), active: true},
+		{at: friday.Add(63 * time.Hour), active: false},
+	}
+	for _, tt := range tests {
+		until, ok := w.activeUntil(tt.at)
+		if ok != tt.active {
+			t.Errorf("activeUntil(%v) active = %v, want %v", tt.at, ok, tt.active)
+		}
+		if ok && !until.Equal(friday.Add(63*time.Hour)) {
+			t.Errorf("activeUntil(%v) = %v, want %v", tt.at, until, friday.Add(63*time.Hour))
+		}
+	}
+
+	if _, err := NewCronFreezeWindow("0 17 * * 5", 0, nil, ""); err == nil {
+		t.Error("expected error for zero duration")
+	}
+}
+
+func TestApplyFreezeWindows(t *testing.T) {
+	start := time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+	windows := []FreezeWindow{{Start: start, End: end, Reason: "holiday freeze"}}
+
+	mergeable := true
+	pr := PullRequest{State: "open", MergeableState: "clean", Mergeable: &mergeable,
+		MergeableStateDescription: "PR is ready to merge"}
+	applyFreezeWindows(&pr, windows, start.Add(time.Hour))
+
+	if pr.FrozenUntil == nil || !pr.FrozenUntil.Equal(end) {
+		t.Errorf("FrozenUntil = %v, want %v", pr.FrozenUntil, end)
+	}
+	if pr.Mergeable == nil || *pr.Mergeable {
+		t.Error("expected frozen PR to be not mergeable")
+	}
+	if !mergeable {
+		t.Error("applyFreezeWindows modified the caller's Mergeable value")
+	}
+	want := "PR is blocked by deployment freeze until 2026-01-03T00:00:00Z (holiday freeze)"
+	if pr.MergeableStateDescription != want {
+		t.Errorf("MergeableStateDescription = %q, want %q", pr.MergeableStateDescription, want)
+	}
+
+	// Existing blockers are kept
+	pr = PullRequest{State: "open", MergeableState: "dirty", MergeableStateDescription: "PR has merge conflicts that need to be resolved"}
+	applyFreezeWindows(&pr, windows, start)
+	if !strings.HasPrefix(pr.MergeableStateDescription, "PR has merge conflicts") ||
+		!strings.Contains(pr.MergeableStateDescription, "deployment freeze") {
+		t.Errorf("MergeableStateDescription = %q", pr.MergeableStateDescription)
+	}
+
+	// Outside the window, and for closed PRs, nothing changes
+	pr = PullRequest{State: "open", MergeableState: "clean"}
+	applyFreezeWindows(&pr, windows, end)
+	if pr.FrozenUntil != nil || pr.Mergeable != nil {
+		t.Errorf("expected no freeze after window, got %+v", pr)
+	}
+	pr = PullRequest{State: "closed"}
+	applyFreezeWindows(&pr, windows, start)
+	if pr.FrozenUntil != nil {
+		t.Error("expected closed PR not to be frozen")
+	}
+}
@@ -0,0 +1,52 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ReportSink stores a rendered report, such as a pull request's markdown report, and returns a URL
+// where it can be viewed. Implement it to publish reports to other storage, e.g. a bucket; prx has
+// no bucket sink, since ObjectStorage has no content types or public URLs.
+type ReportSink interface {
+	Upload(ctx context.Context, name, content string) (string, error)
+}
+
+// GistSink is a ReportSink that uploads each report as a secret gist, which anyone with its URL can view.
+// The client's token needs the gist scope; GitHub Apps can't create gists.
+type GistSink struct {
+	client      *Client
+	description string
+}
+
+// NewGistSink returns a ReportSink that creates secret gists with the given description.
+func NewGistSink(client *Client, description string) *GistSink {
+	return &GistSink{client: client, description: description}
+}
+
+// Upload creates a secret gist containing content as the file name and returns its URL.
+func (s *GistSink) Upload(ctx context.Context, name, content string) (string, error) {
+	return s.client.CreateGist(ctx, s.description, map[string]string{name: content}, false)
+}
+
+// CreateGist creates a gist from files, a map of file name to content, and returns its URL.
+// Secret gists are unlisted rather than private: anyone with the URL can view them.
+func (c *Client) CreateGist(ctx context.Context, description string, files map[string]string, public bool) (string, error) {
+	if len(files) == 0 {
+		return "", errors.New("creating gist: no files")
+	}
+	gistFiles := make(map[string]map[string]string, len(files))
+	for name, content := range files {
+		gistFiles[name] = map[string]string{"content": content}
+	}
+	body := map[string]any{"description": description, "public": public, "files": gistFiles}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.github.Post(ctx, "/gists", body, &created); err != nil {
+		return "", fmt.Errorf("creating gist: %w", err)
+	}
+	return created.HTMLURL, nil
+}
@@ -0,0 +1,51 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestGistSink_Upload(t *testing.T) {
+	var body struct {
+		Files       map[string]map[string]string `json:"files"`
+		Description string                       `json:"description"`
+		Public      bool                         `json:"public"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/gists" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"id": "abc", "html_url": "https://gist.github.com/abc"}`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	var sink ReportSink = NewGistSink(client, "prx report")
+	got, err := sink.Upload(context.Background(), "report.json", `{"pull_request": {}}`)
+	if err != nil {
+		t.Fatalf("Upload() error: %v", err)
+	}
+	if got != "https://gist.github.com/abc" {
+		t.Errorf("Upload() = %q", got)
+	}
+	if body.Public || body.Description != "prx report" || body.Files["report.json"]["content"] != `{"pull_request": {}}` {
+		t.Errorf("unexpected request body %+v", body)
+	}
+
+	if _, err := client.CreateGist(context.Background(), "empty", nil, false); err == nil {
+		t.Error("expected an error creating a gist without files")
+	}
+}
@@ -0,0 +1,218 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTLifetime is how long a GitHub App JWT is valid (GitHub allows at most 10 minutes).
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the JWT issue time to tolerate clock drift.
+	appJWTClockSkew = 60 * time.Second
+	// tokenRefreshWindow is how long before expiry an installation token is refreshed.
+	tokenRefreshWindow = 5 * time.Minute
+)
+
+// TokenPattern matches GitHub tokens: personal access, OAuth, app installation, and refresh tokens.
+var TokenPattern = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)
+
+// TokenSource returns a bearer token to use for a request.
+type TokenSource func(ctx context.Context) (string, error)
+
+// InstallationTokenFunc returns a GitHub App installation token and the time it expires.
+type InstallationTokenFunc func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+// AppTokenSource mints and caches GitHub App installation tokens.
+// Tokens are refreshed automatically shortly before they expire.
+type AppTokenSource struct {
+	expiresAt      time.Time
+	HTTPClient     *http.Client
+	key            *rsa.PrivateKey
+	provider       InstallationTokenFunc
+	BaseURL        string
+	APIVersion     string
+	token          string
+	privateKeyPEM  []byte
+	AppID          int64
+	InstallationID int64
+	mu             sync.Mutex
+}
+
+// NewAppTokenSource creates a token source that authenticates as the given app installation
+// using the app's PEM-encoded private key.
+func NewAppTokenSource(appID, installationID int64, privateKeyPEM []byte) *AppTokenSource {
+	return &AppTokenSource{
+		AppID:          appID,
+		InstallationID: installationID,
+		privateKeyPEM:  privateKeyPEM,
+	}
+}
+
+// NewInstallationTokenSource creates a token source that obtains installation tokens from provider.
+// The returned expiry is used to decide when to call provider again.
+func NewInstallationTokenSource(provider InstallationTokenFunc) *AppTokenSource {
+	return &AppTokenSource{provider: provider}
+}
+
+// Token returns a valid installation token, refreshing it if it is about to expire.
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expiresAt) > tokenRefreshWindow {
+		return s.token, nil
+	}
+
+	var token string
+	var expiresAt time.Time
+	var err error
+	if s.provider != nil {
+		token, expiresAt, err = s.provider(ctx)
+	} else {
+		token, expiresAt, err = s.createInstallationToken(ctx)
+	}
+	if err != nil {
+		return "", fmt.Errorf("obtaining installation token: %w", err)
+	}
+	if token == "" {
+		return "", errors.New("obtaining installation token: empty token")
+	}
+
+	s.token = token
+	s.expiresAt = expiresAt
+	return token, nil
+}
+
+// Invalidate discards the cached token so the next call to Token fetches a fresh one.
+func (s *AppTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	s.expiresAt = time.Time{}
+}
+
+// createInstallationToken exchanges an app JWT for an installation access token.
+func (s *AppTokenSource) createInstallationToken(ctx context.Context) (string, time.Time, error) {
+	if s.key == nil {
+		key, err := parsePrivateKey(s.privateKeyPEM)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		s.key = key
+	}
+
+	jwt, err := signAppJWT(s.key, s.AppID, time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = API
+	}
+	apiURL := fmt.Sprintf("%s/app/installations/%d/access_tokens", baseURL, s.InstallationID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, http.NoBody)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	version := s.APIVersion
+	if version == "" {
+		version = DefaultAPIVersion
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", mediaTypeREST)
+	req.Header.Set("X-GitHub-Api-Version", version)
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // best-effort close
+	}()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)) //nolint:errcheck // best-effort read for error details
+		return "", time.Time{}, &Error{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(body),
+			URL:        apiURL,
+		}
+	}
+
+	var result struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Token     string    `json:"token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return "", time.Time{}, fmt.Errorf("decoding installation token: %w", err)
+	}
+	return result.Token, result.ExpiresAt, nil
+}
+
+// parsePrivateKey decodes a PEM-encoded RSA private key in PKCS#1 or PKCS#8 form.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// signAppJWT creates an RS256-signed JWT identifying the app, as required by GitHub.
+func signAppJWT(key *rsa.PrivateKey, appID int64, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing app JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
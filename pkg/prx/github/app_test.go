@@ -0,0 +1,204 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppTokenSource_CreatesAndCachesToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		if len(parts) != 3 {
+			t.Fatalf("expected 3 JWT segments, got %d", len(parts))
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("decoding signature: %v", err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Errorf("JWT signature invalid: %v", err)
+		}
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatalf("decoding claims: %v", err)
+		}
+		var c map[string]any
+		if err := json.Unmarshal(claims, &c); err != nil {
+			t.Fatalf("unmarshaling claims: %v", err)
+		}
+		if c["iss"] != "7" {
+			t.Errorf("iss = %v, want 7", c["iss"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "ghs_installation%d", "expires_at": %q}`, calls, time.Now().Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	src := NewAppTokenSource(7, 42, keyPEM)
+	src.HTTPClient = server.Client()
+	src.BaseURL = server.URL
+
+	for range 3 {
+		token, err := src.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() error: %v", err)
+		}
+		if token != "ghs_installation1" {
+			t.Errorf("Token() = %q, want ghs_installation1", token)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 token exchange, got %d", calls)
+	}
+
+	src.Invalidate()
+	token, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() after Invalidate error: %v", err)
+	}
+	if token != "ghs_installation2" {
+		t.Errorf("Token() after Invalidate = %q, want ghs_installation2", token)
+	}
+}
+
+func TestAppTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	calls := 0
+	src := NewInstallationTokenSource(func(context.Context) (string, time.Time, error) {
+		calls++
+		// Expires inside the refresh window, so every call should refresh
+		return fmt.Sprintf("token-%d", calls), time.Now().Add(time.Minute), nil
+	})
+
+	first, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error: %v", err)
+	}
+	second, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error: %v", err)
+	}
+	if first == second {
+		t.Errorf("expected token near expiry to be refreshed, got %q twice", first)
+	}
+}
+
+func TestAppTokenSource_InvalidKey(t *testing.T) {
+	src := NewAppTokenSource(1, 2, []byte("not a key"))
+	if _, err := src.Token(context.Background()); err == nil {
+		t.Fatal("expected error for invalid private key")
+	}
+}
+
+func TestClient_TokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer dynamic-token" {
+			t.Errorf("Authorization = %q, want Bearer dynamic-token", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient:  server.Client(),
+		Token:       "static-token",
+		BaseURL:     server.URL,
+		TokenSource: func(context.Context) (string, error) { return "dynamic-token", nil },
+	}
+	if _, _, err := client.Do(context.Background(), "/test"); err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	var out map[string]any
+	if err := client.GraphQL(context.Background(), "query{}", nil, &out); err != nil {
+		t.Fatalf("GraphQL() error: %v", err)
+	}
+}
+
+func TestClient_RetriesUnauthorizedWithFreshToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	token := "stale"
+	invalidated := 0
+	client := &Client{
+		HTTPClient: server.Client(),
+		BaseURL:    server.URL,
+		TokenSource: func(context.Context) (string, error) {
+			return token, nil
+		},
+		OnUnauthorized: func() {
+			invalidated++
+			token = "fresh"
+		},
+	}
+
+	if _, _, err := client.Do(context.Background(), "/test"); err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	if invalidated != 1 {
+		t.Errorf("expected OnUnauthorized to be called once, got %d", invalidated)
+	}
+
+	// A second 401 is returned to the caller rather than retried forever.
+	token = "stale"
+	client.OnUnauthorized = nil
+	var out map[string]any
+	err := client.GraphQL(context.Background(), "query{}", nil, &out)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("GraphQL() error = %v, want 401", err)
+	}
+}
+
+func TestClient_StaticTokenDoesNotRetryUnauthorized(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), Token: "static", BaseURL: server.URL}
+	if _, _, err := client.Do(context.Background(), "/test"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request, got %d", calls)
+	}
+}
@@ -4,12 +4,15 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,8 +21,20 @@ import (
 const (
 	// API is the default GitHub API base URL.
 	API = "https://api.github.com"
+	// DefaultAPIVersion is the REST API version sent in the X-GitHub-Api-Version header.
+	DefaultAPIVersion = "2022-11-28"
+	// mediaTypeREST is the Accept media type for REST requests.
+	mediaTypeREST = "application/vnd.github.v3+json"
+	// MediaTypeDiff is the Accept media type for a pull request or commit as a unified diff.
+	MediaTypeDiff = "application/vnd.github.diff"
+	// MediaTypePatch is the Accept media type for a pull request or commit as a git format-patch mailbox.
+	MediaTypePatch = "application/vnd.github.patch"
+	// mediaTypeGraphQL is the Accept media type for GraphQL requests.
+	mediaTypeGraphQL = "application/vnd.github.v4+json"
 	// maxResponseSize limits API response size to prevent memory exhaustion.
 	maxResponseSize = 10 * 1024 * 1024 // 10MB
+	// maxCachedResponseSize limits the bodies kept in a ResponseCache.
+	maxCachedResponseSize = 1024 * 1024 // 1MB
 	// maxErrorBodySize limits error response body reading for debugging.
 	maxErrorBodySize = 1024
 	// tokenPreviewPrefixLen is the number of characters to show at the start of a masked token.
@@ -30,50 +45,183 @@ const (
 	tokenPreviewMinLen = 8
 )
 
+// ErrResponseTooLarge is returned for REST responses larger than the client accepts.
+var ErrResponseTooLarge = fmt.Errorf("response larger than %d bytes", maxResponseSize)
+
 // Error represents an error response from the GitHub API.
 type Error struct {
-	Status     string
-	Body       string
-	URL        string
-	StatusCode int
+	Status        string
+	Body          string
+	URL           string
+	ServerVersion string // X-GitHub-Enterprise-Version of the responding server, empty for github.com
+	StatusCode    int
 }
 
 func (e *Error) Error() string {
 	return fmt.Sprintf("github API error: %s", e.Status)
 }
 
+// UnsupportedAPIVersion reports whether the server rejected the requested X-GitHub-Api-Version,
+// which typically means a GitHub Enterprise Server release older than the pinned version.
+func (e *Error) UnsupportedAPIVersion() bool {
+	return e.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Body), "api version")
+}
+
 // Response wraps a GitHub API response with pagination info.
 type Response struct {
-	NextPage int
+	// OAuthScopes lists the scopes of classic and OAuth app tokens, from the X-OAuth-Scopes header.
+	// It is nil for tokens without scopes, such as fine-grained and GitHub App tokens.
+	OAuthScopes []string
+	NextPage    int
+	// NotModified reports that the server answered 304 and the body came from the ResponseCache.
+	NotModified bool
+}
+
+// CachedResponse is a REST response body kept for conditional requests, with its validators.
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+	NextPage     int
+}
+
+// ResponseCache stores REST responses for conditional requests, keyed by URL and Accept header.
+// GitHub doesn't count requests answered with 304 Not Modified against the rate limit.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, value *CachedResponse)
 }
 
 // Client is a low-level client for interacting with the GitHub API.
 type Client struct {
 	HTTPClient *http.Client
-	Token      string
-	BaseURL    string
+	// TokenSource, if set, supplies the bearer token for each request and takes precedence over Token.
+	// Requests rejected with 401 are retried once with a freshly obtained token.
+	TokenSource TokenSource
+	// OnUnauthorized, if set, is called after a 401 response before the request is retried,
+	// so cached credentials can be discarded.
+	OnUnauthorized func()
+	Token          string
+	BaseURL        string
+	// GraphQLURL is the GraphQL endpoint; BaseURL + "/graphql" is used when empty.
+	GraphQLURL string
+	// APIVersion is sent as X-GitHub-Api-Version; DefaultAPIVersion is used when empty.
+	APIVersion string
+	// Previews lists API preview names (e.g. "merge-info") to request via the Accept header.
+	Previews []string
+	// OnRateLimit, if set, is called with the rate limit headers of every response.
+	OnRateLimit func(RateLimit)
+	// OnResponse, if set, is called after every request with the resource it consumed (ResourceCore
+	// or ResourceGraphQL), the response status code, or 0 if no response was received, and the latency.
+	OnResponse func(resource string, statusCode int, elapsed time.Duration)
+	// TraceRequest, if set, is called before every request with the resource it consumes, its
+	// method and URL. The returned context is used for the request, and the returned function is
+	// called with the response status code, or 0 if no response was received, and the error.
+	TraceRequest func(ctx context.Context, resource, method, url string) (context.Context, func(statusCode int, err error))
+	// Throttle, if set, is called before every request with the rate limit resource it consumes
+	// (ResourceCore or ResourceGraphQL). It may block, or return an error to abort the request.
+	Throttle func(ctx context.Context, resource string) error
+	// ResponseCache, if set, makes REST requests conditional on the ETag or Last-Modified
+	// of a previous response, reusing its body when the server answers 304 Not Modified.
+	ResponseCache ResponseCache
+}
+
+// setHeaders applies authentication, media type, and API version headers to req.
+func (c *Client) setHeaders(req *http.Request, token, mediaType string) {
+	accept := []string{mediaType}
+	for _, p := range c.Previews {
+		accept = append(accept, "application/vnd.github."+p+"-preview+json")
+	}
+	version := c.APIVersion
+	if version == "" {
+		version = DefaultAPIVersion
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", strings.Join(accept, ", "))
+	req.Header.Set("X-GitHub-Api-Version", version)
+}
+
+// retryUnauthorized reports whether a failed request should be retried with a fresh token.
+func (c *Client) retryUnauthorized(err error) bool {
+	var apiErr *Error
+	if c.TokenSource == nil || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	if c.OnUnauthorized != nil {
+		c.OnUnauthorized()
+	}
+	return true
+}
+
+// bearerToken returns the token to authenticate the next request with.
+func (c *Client) bearerToken(ctx context.Context) (string, error) {
+	if c.TokenSource == nil {
+		return c.Token, nil
+	}
+	token, err := c.TokenSource(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting token: %w", err)
+	}
+	return token, nil
 }
 
 // Do performs an HTTP GET request to the GitHub API.
 func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error) {
+	return c.DoMediaType(ctx, path, mediaTypeREST)
+}
+
+// DoMediaType performs an HTTP GET request to the GitHub API, accepting the given media type,
+// e.g. MediaTypeDiff.
+func (c *Client) DoMediaType(ctx context.Context, path, mediaType string) ([]byte, *Response, error) {
+	data, resp, err := c.do(ctx, path, mediaType)
+	if err != nil && c.retryUnauthorized(err) {
+		slog.InfoContext(ctx, "GitHub API request unauthorized, retrying with refreshed token", "path", path)
+		return c.do(ctx, path, mediaType)
+	}
+	return data, resp, err
+}
+
+func (c *Client) do(ctx context.Context, path, mediaType string) ([]byte, *Response, error) {
+	if err := c.throttle(ctx, ResourceCore); err != nil {
+		return nil, nil, err
+	}
+
 	baseURL := c.BaseURL
 	if baseURL == "" {
 		baseURL = API
 	}
 	apiURL := baseURL + path
 
+	token, err := c.bearerToken(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, http.NoBody)
 	if err != nil {
 		return nil, nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	c.setHeaders(req, token, mediaType)
+
+	cacheKey := apiURL + " " + req.Header.Get("Accept")
+	var cached *CachedResponse
+	if c.ResponseCache != nil {
+		if r, ok := c.ResponseCache.Get(cacheKey); ok {
+			cached = r
+			if r.ETag != "" {
+				req.Header.Set("If-None-Match", r.ETag)
+			}
+			if r.LastModified != "" {
+				req.Header.Set("If-Modified-Since", r.LastModified)
+			}
+		}
+	}
 
 	// Log request details (mask token for security)
 	tokenPreview := ""
-	if c.Token != "" {
-		if len(c.Token) > tokenPreviewMinLen {
-			tokenPreview = c.Token[:tokenPreviewPrefixLen] + "..." + c.Token[len(c.Token)-tokenPreviewSuffixLen:]
+	if token != "" {
+		if len(token) > tokenPreviewMinLen {
+			tokenPreview = token[:tokenPreviewPrefixLen] + "..." + token[len(token)-tokenPreviewSuffixLen:]
 		} else {
 			tokenPreview = "***"
 		}
@@ -83,14 +231,18 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 		"method", "GET",
 		"url", apiURL,
 		"headers", map[string]string{
-			"Authorization": "Bearer " + tokenPreview,
-			"Accept":        req.Header.Get("Accept"),
-			"User-Agent":    req.Header.Get("User-Agent"),
+			"Authorization":        "Bearer " + tokenPreview,
+			"Accept":               req.Header.Get("Accept"),
+			"User-Agent":           req.Header.Get("User-Agent"),
+			"X-GitHub-Api-Version": req.Header.Get("X-GitHub-Api-Version"),
 		})
 
+	req, endTrace := c.traceRequest(req, ResourceCore)
 	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
 	elapsed := time.Since(start)
+	c.observeResponse(ResourceCore, resp, elapsed)
+	endTrace(resp, err)
 	if err != nil {
 		slog.ErrorContext(ctx, "GitHub API request failed", "url", apiURL, "error", err, "elapsed", elapsed)
 		return nil, nil, err
@@ -101,6 +253,8 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 		}
 	}()
 
+	c.observeRateLimit(resp, ResourceCore)
+
 	// Log rate limit headers for all responses
 	rateLimitHeaders := map[string]string{
 		"X-RateLimit-Limit":     resp.Header.Get("X-Ratelimit-Limit"),
@@ -117,6 +271,10 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 		"elapsed", elapsed,
 		"rate_limits", rateLimitHeaders)
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, &Response{NextPage: cached.NextPage, NotModified: true, OAuthScopes: oauthScopes(resp.Header)}, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
 		if readErr != nil {
@@ -137,8 +295,8 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 			}
 		}
 
-		// Log collaborator 403 errors as warnings since they're expected for repos without push access
-		if resp.StatusCode == http.StatusForbidden && strings.Contains(apiURL, "/collaborators") {
+		// Log collaborator and team 403 errors as warnings since they're expected for repos without push access
+		if resp.StatusCode == http.StatusForbidden && (strings.Contains(apiURL, "/collaborators") || strings.Contains(apiURL, "/teams")) {
 			slog.WarnContext(ctx, "GitHub API access denied",
 				"status", resp.Status,
 				"status_code", resp.StatusCode,
@@ -154,17 +312,21 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 				"headers", errorHeaders)
 		}
 		return nil, nil, &Error{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			Body:       string(body),
-			URL:        apiURL,
+			StatusCode:    resp.StatusCode,
+			Status:        resp.Status,
+			Body:          string(body),
+			URL:           apiURL,
+			ServerVersion: resp.Header.Get("X-Github-Enterprise-Version"),
 		}
 	}
 
-	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
 	if err != nil {
 		return nil, nil, err
 	}
+	if len(data) > maxResponseSize {
+		return nil, nil, fmt.Errorf("fetching %s: %w", apiURL, ErrResponseTooLarge)
+	}
 
 	// Parse Link header for pagination
 	nextPageNum := 0
@@ -185,7 +347,59 @@ func (c *Client) Do(ctx context.Context, path string) ([]byte, *Response, error)
 		}
 	}
 
-	return data, &Response{NextPage: nextPageNum}, nil
+	if c.ResponseCache != nil {
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if (etag != "" || lastModified != "") && len(data) <= maxCachedResponseSize {
+			c.ResponseCache.Set(cacheKey, &CachedResponse{ETag: etag, LastModified: lastModified, Body: data, NextPage: nextPageNum})
+		}
+	}
+
+	return data, &Response{NextPage: nextPageNum, OAuthScopes: oauthScopes(resp.Header)}, nil
+}
+
+// oauthScopes parses the X-OAuth-Scopes header, which is absent for tokens without scopes.
+func oauthScopes(h http.Header) []string {
+	values, ok := h[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil
+	}
+	scopes := []string{}
+	for _, v := range values {
+		for scope := range strings.SplitSeq(v, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// observeResponse reports a completed request to OnResponse; resp is nil if the request failed.
+func (c *Client) observeResponse(resource string, resp *http.Response, elapsed time.Duration) {
+	if c.OnResponse == nil {
+		return
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.OnResponse(resource, status, elapsed)
+}
+
+// traceRequest starts tracing req with TraceRequest, returning the request to send and a function
+// to call with its outcome.
+func (c *Client) traceRequest(req *http.Request, resource string) (*http.Request, func(*http.Response, error)) {
+	if c.TraceRequest == nil {
+		return req, func(*http.Response, error) {}
+	}
+	ctx, end := c.TraceRequest(req.Context(), resource, req.Method, req.URL.String())
+	return req.WithContext(ctx), func(resp *http.Response, err error) {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		end(status, err)
+	}
 }
 
 // Get makes a GET request to the GitHub API and decodes the response into v.
@@ -211,14 +425,114 @@ func (c *Client) Raw(ctx context.Context, path string) (json.RawMessage, *Respon
 	return json.RawMessage(data), resp, nil
 }
 
-// GraphQL executes a GraphQL query against the GitHub API.
-// The query and variables are sent as JSON, and the response is decoded into result.
-func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+// Post makes a POST request to the GitHub API with body encoded as JSON, and decodes the response
+// into v unless it is nil.
+func (c *Client) Post(ctx context.Context, path string, body, v any) error {
+	err := c.post(ctx, path, body, v)
+	if err != nil && c.retryUnauthorized(err) {
+		slog.InfoContext(ctx, "GitHub API request unauthorized, retrying with refreshed token", "path", path)
+		return c.post(ctx, path, body, v)
+	}
+	return err
+}
+
+func (c *Client) post(ctx context.Context, path string, body, v any) error {
+	if err := c.throttle(ctx, ResourceCore); err != nil {
+		return err
+	}
+
 	baseURL := c.BaseURL
 	if baseURL == "" {
 		baseURL = API
 	}
-	apiURL := baseURL + "/graphql"
+	apiURL := baseURL + path
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	token, err := c.bearerToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	c.setHeaders(req, token, mediaTypeREST)
+	req.Header.Set("Content-Type", "application/json")
+
+	slog.InfoContext(ctx, "GitHub API request starting", "method", "POST", "url", apiURL)
+
+	req, endTrace := c.traceRequest(req, ResourceCore)
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	elapsed := time.Since(start)
+	c.observeResponse(ResourceCore, resp, elapsed)
+	endTrace(resp, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "GitHub API request failed", "url", apiURL, "error", err, "elapsed", elapsed)
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.DebugContext(ctx, "failed to close response body", "error", closeErr, "url", apiURL)
+		}
+	}()
+
+	slog.InfoContext(ctx, "GitHub API response received", "status", resp.Status, "url", apiURL, "elapsed", elapsed)
+	c.observeRateLimit(resp, ResourceCore)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		bodyStr := string(body)
+		if readErr != nil {
+			bodyStr = fmt.Sprintf("(failed to read body: %v)", readErr)
+		}
+		return &Error{
+			StatusCode:    resp.StatusCode,
+			Status:        resp.Status,
+			Body:          bodyStr,
+			URL:           apiURL,
+			ServerVersion: resp.Header.Get("X-Github-Enterprise-Version"),
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// GraphQL executes a GraphQL query against the GitHub API.
+// The query and variables are sent as JSON, and the response is decoded into result.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+	err := c.graphQL(ctx, query, variables, result)
+	if err != nil && c.retryUnauthorized(err) {
+		slog.InfoContext(ctx, "GitHub GraphQL request unauthorized, retrying with refreshed token")
+		return c.graphQL(ctx, query, variables, result)
+	}
+	return err
+}
+
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+	if err := c.throttle(ctx, ResourceGraphQL); err != nil {
+		return err
+	}
+
+	apiURL := c.GraphQLURL
+	if apiURL == "" {
+		baseURL := c.BaseURL
+		if baseURL == "" {
+			baseURL = API
+		}
+		apiURL = baseURL + "/graphql"
+	}
 
 	requestBody := map[string]any{
 		"query":     query,
@@ -229,19 +543,26 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 		return fmt.Errorf("marshaling GraphQL request: %w", err)
 	}
 
+	token, err := c.bearerToken(ctx)
+	if err != nil {
+		return err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return fmt.Errorf("creating GraphQL request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	c.setHeaders(req, token, mediaTypeGraphQL)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.github.v4+json")
 
 	slog.InfoContext(ctx, "GitHub GraphQL request starting", "url", apiURL)
 
+	req, endTrace := c.traceRequest(req, ResourceGraphQL)
 	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
 	elapsed := time.Since(start)
+	c.observeResponse(ResourceGraphQL, resp, elapsed)
+	endTrace(resp, err)
 	if err != nil {
 		slog.ErrorContext(ctx, "GitHub GraphQL request failed", "url", apiURL, "error", err, "elapsed", elapsed)
 		return fmt.Errorf("executing GraphQL request: %w", err)
@@ -253,6 +574,7 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 	}()
 
 	slog.InfoContext(ctx, "GitHub GraphQL response received", "status", resp.Status, "url", apiURL, "elapsed", elapsed)
+	c.observeRateLimit(resp, ResourceGraphQL)
 
 	if resp.StatusCode != http.StatusOK {
 		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
@@ -261,10 +583,11 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 			bodyStr = fmt.Sprintf("(failed to read body: %v)", readErr)
 		}
 		return &Error{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			Body:       bodyStr,
-			URL:        apiURL,
+			StatusCode:    resp.StatusCode,
+			Status:        resp.Status,
+			Body:          bodyStr,
+			URL:           apiURL,
+			ServerVersion: resp.Header.Get("X-Github-Enterprise-Version"),
 		}
 	}
 
@@ -275,6 +598,15 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 	return nil
 }
 
+// Meta fetches server metadata, including the installed version on GitHub Enterprise Server.
+func (c *Client) Meta(ctx context.Context) (*Meta, error) {
+	var meta Meta
+	if _, err := c.Get(ctx, "/meta", &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
 // Collaborators fetches all users with repository access and their permission levels.
 // Returns a map of username -> permission level ("admin", "write", "read", "none").
 // Uses affiliation=all to include direct collaborators, org members, and outside collaborators.
@@ -318,3 +650,188 @@ func (c *Client) Collaborators(ctx context.Context, owner, repo string) (map[str
 
 	return result, nil
 }
+
+// TeamPermissions fetches the members of the teams with access to a repository and, for each member,
+// the highest permission level any of those teams grants ("admin", "maintain", "write", "triage", or "read").
+// Unlike Collaborators, it doesn't require admin access, but it doesn't see direct grants or the
+// organization's base permission. Secret teams the token can't see are left out.
+func (c *Client) TeamPermissions(ctx context.Context, owner, repo string) (map[string]string, error) {
+	teams, err := getPages[struct {
+		Slug       string `json:"slug"`
+		Permission string `json:"permission"` // "pull", "triage", "push", "maintain", or "admin"
+	}](ctx, c, fmt.Sprintf("/repos/%s/%s/teams?per_page=100", owner, repo))
+	if err != nil {
+		return nil, err
+	}
+
+	levels := []string{"read", "triage", "write", "maintain", "admin"}
+	result := make(map[string]string)
+	for _, team := range teams {
+		permission := team.Permission
+		switch permission {
+		case "pull":
+			permission = "read"
+		case "push":
+			permission = "write"
+		default:
+		}
+		members, err := getPages[struct {
+			Login string `json:"login"`
+		}](ctx, c, fmt.Sprintf("/orgs/%s/teams/%s/members?per_page=100", owner, team.Slug))
+		if err != nil {
+			return nil, fmt.Errorf("fetching members of team %s: %w", team.Slug, err)
+		}
+		for _, m := range members {
+			if slices.Index(levels, permission) > slices.Index(levels, result[m.Login]) {
+				result[m.Login] = permission
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// maxListPages caps getPages at 10,000 items.
+const maxListPages = 100
+
+// getPages fetches every page of a list, following the Link header's next page. path must have a query.
+func getPages[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var all []T
+	page := 1
+	for range maxListPages {
+		var items []T
+		resp, err := c.Get(ctx, fmt.Sprintf("%s&page=%d", path, page), &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+// maxPullRequestFilePages caps PullRequestFiles; GitHub lists at most 3000 files per pull request.
+const maxPullRequestFilePages = 30
+
+// PullRequestFiles fetches the files changed by a pull request, including their patches.
+func (c *Client) PullRequestFiles(ctx context.Context, owner, repo string, number int) ([]*PullRequestFile, error) {
+	var all []*PullRequestFile
+	page := 1
+	for range maxPullRequestFilePages {
+		path := fmt.Sprintf("/repos/%s/%s/pulls/%d/files?per_page=100&page=%d", owner, repo, number, page)
+		var files []*PullRequestFile
+		resp, err := c.Get(ctx, path, &files)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, files...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+// MergeBase returns the SHA of the commit a pull request's changes are shown against: the merge base
+// of its base branch and head.
+func (c *Client) MergeBase(ctx context.Context, owner, repo string, number int) (string, error) {
+	var pr struct {
+		Base struct {
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if _, err := c.Get(ctx, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), &pr); err != nil {
+		return "", err
+	}
+	var comparison struct {
+		MergeBaseCommit struct {
+			SHA string `json:"sha"`
+		} `json:"merge_base_commit"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s?per_page=1", owner, repo, pr.Base.SHA, pr.Head.SHA)
+	if _, err := c.Get(ctx, path, &comparison); err != nil {
+		return "", err
+	}
+	if comparison.MergeBaseCommit.SHA == "" {
+		return "", fmt.Errorf("no merge base for %s/%s#%d", owner, repo, number)
+	}
+	return comparison.MergeBaseCommit.SHA, nil
+}
+
+// CommitFiles fetches the files changed by a commit. GitHub lists at most 3000 files per commit.
+func (c *Client) CommitFiles(ctx context.Context, owner, repo, sha string) ([]*PullRequestFile, error) {
+	var all []*PullRequestFile
+	page := 1
+	for range maxPullRequestFilePages {
+		path := fmt.Sprintf("/repos/%s/%s/commits/%s?per_page=100&page=%d", owner, repo, sha, page)
+		var commit Commit
+		resp, err := c.Get(ctx, path, &commit)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, commit.Files...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+// FileContents fetches a file from the repository's default branch.
+func (c *Client) FileContents(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	var content Content
+	if _, err := c.Get(ctx, fmt.Sprintf("/repos/%s/%s/contents/%s", owner, repo, path), &content); err != nil {
+		return nil, err
+	}
+	if content.Encoding != "base64" {
+		return []byte(content.Content), nil
+	}
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+}
+
+// Directory lists a directory on the repository's default branch; path is empty for the root.
+func (c *Client) Directory(ctx context.Context, owner, repo, path string) ([]*ContentEntry, error) {
+	var entries []*ContentEntry
+	if _, err := c.Get(ctx, fmt.Sprintf("/repos/%s/%s/contents/%s", owner, repo, path), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// maxNotificationPages caps Notifications at 2500 threads; GitHub serves at most 50 per page.
+const maxNotificationPages = 50
+
+// Notifications fetches the authenticated user's notification threads, most recently updated first.
+// params are passed through to the API, e.g. all, participating, since, and before.
+// GitHub only serves notifications to user tokens, not to GitHub App installations.
+func (c *Client) Notifications(ctx context.Context, params url.Values) ([]*Notification, error) {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("per_page", "50")
+	var all []*Notification
+	page := 1
+	for range maxNotificationPages {
+		q.Set("page", strconv.Itoa(page))
+		var threads []*Notification
+		resp, err := c.Get(ctx, "/notifications?"+q.Encode(), &threads)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, threads...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return all, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -135,6 +136,58 @@ func TestClient_Get(t *testing.T) {
 	}
 }
 
+// mapResponseCache is a ResponseCache backed by a map.
+type mapResponseCache map[string]*CachedResponse
+
+func (m mapResponseCache) Get(key string) (*CachedResponse, bool) {
+	r, ok := m[key]
+	return r, ok
+}
+
+func (m mapResponseCache) Set(key string, value *CachedResponse) {
+	m[key] = value
+}
+
+func TestClient_ConditionalRequests(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://api.github.com/users?page=2>; rel="next"`)
+		w.Write([]byte(`{"login": "testuser", "type": "User"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient:    server.Client(),
+		Token:         "test-token",
+		BaseURL:       server.URL,
+		ResponseCache: mapResponseCache{},
+	}
+
+	for i := range 2 {
+		var user User
+		resp, err := client.Get(context.Background(), "/users/testuser", &user)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+		if user.Login != "testuser" || resp.NextPage != 2 {
+			t.Errorf("request %d: got login %q, next page %d; want testuser, 2", i, user.Login, resp.NextPage)
+		}
+		if resp.NotModified != (i == 1) {
+			t.Errorf("request %d: NotModified = %v", i, resp.NotModified)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("got %d requests with %d not modified, want 2 with 1", requests, notModified)
+	}
+}
+
 func TestClient_Raw(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -436,3 +489,110 @@ func TestClient_ContextCancellation(t *testing.T) {
 		t.Error("Expected context cancellation error but got none")
 	}
 }
+
+func TestClient_APIVersionAndPreviews(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiVersion  string
+		previews    []string
+		wantVersion string
+		wantAccept  string
+	}{
+		{
+			name:        "defaults",
+			wantVersion: DefaultAPIVersion,
+			wantAccept:  "application/vnd.github.v3+json",
+		},
+		{
+			name:        "pinned version with preview",
+			apiVersion:  "2026-03-10",
+			previews:    []string{"merge-info"},
+			wantVersion: "2026-03-10",
+			wantAccept:  "application/vnd.github.v3+json, application/vnd.github.merge-info-preview+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("X-GitHub-Api-Version"); got != tt.wantVersion {
+					t.Errorf("X-GitHub-Api-Version = %q, want %q", got, tt.wantVersion)
+				}
+				if r.URL.Path == "/graphql" {
+					if got := r.Header.Get("Accept"); !strings.HasPrefix(got, "application/vnd.github.v4+json") {
+						t.Errorf("GraphQL Accept = %q", got)
+					}
+				} else if got := r.Header.Get("Accept"); got != tt.wantAccept {
+					t.Errorf("Accept = %q, want %q", got, tt.wantAccept)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Token:      "test-token",
+				BaseURL:    server.URL,
+				APIVersion: tt.apiVersion,
+				Previews:   tt.previews,
+			}
+			if _, _, err := client.Do(context.Background(), "/test"); err != nil {
+				t.Fatalf("Do() error: %v", err)
+			}
+			var out map[string]any
+			if err := client.GraphQL(context.Background(), "query{}", nil, &out); err != nil {
+				t.Fatalf("GraphQL() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_GraphQLURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Token:      "test-token",
+		BaseURL:    server.URL + "/api/v3",
+		GraphQLURL: server.URL + "/api/graphql",
+	}
+	if _, _, err := client.Do(context.Background(), "/test"); err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	var out map[string]any
+	if err := client.GraphQL(context.Background(), "query{}", nil, &out); err != nil {
+		t.Fatalf("GraphQL() error: %v", err)
+	}
+	if want := []string{"/api/v3/test", "/api/graphql"}; !slices.Equal(paths, want) {
+		t.Errorf("request paths = %v, want %v", paths, want)
+	}
+}
+
+func TestError_UnsupportedAPIVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-GitHub-Enterprise-Version", "3.9.0")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "API version '2099-01-01' is not supported."}`))
+	}))
+	defer server.Close()
+
+	client := &Client{HTTPClient: server.Client(), Token: "test-token", BaseURL: server.URL, APIVersion: "2099-01-01"}
+	_, _, err := client.Do(context.Background(), "/test")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if !apiErr.UnsupportedAPIVersion() {
+		t.Error("expected UnsupportedAPIVersion() to be true")
+	}
+	if apiErr.ServerVersion != "3.9.0" {
+		t.Errorf("ServerVersion = %q, want 3.9.0", apiErr.ServerVersion)
+	}
+}
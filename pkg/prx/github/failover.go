@@ -0,0 +1,219 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverCooldown is how long FailoverTransport skips an endpoint after it fails.
+const DefaultFailoverCooldown = 30 * time.Second
+
+// Endpoint is an API endpoint that serves the GitHub API, such as api.github.com or a caching proxy
+// in front of it.
+type Endpoint struct {
+	// REST is the REST API base URL, e.g. "https://api.github.com".
+	REST string
+	// GraphQL is the GraphQL endpoint, e.g. "https://api.github.com/graphql".
+	GraphQL string
+}
+
+// FailoverTransport wraps an http.RoundTripper to send requests for any of several equivalent
+// endpoints to the first healthy one, in order of preference. An endpoint is unhealthy for Cooldown
+// after it can't be reached or answers 502, 503, or 504, and requests fail over to the next one.
+// If every endpoint is unhealthy, they are all tried again in order.
+//
+// Only reads, and GraphQL queries, are replayed on the next endpoint whatever went wrong. Writes,
+// including GraphQL mutations, may already have been applied by an endpoint that failed to answer, so
+// they only fail over when the endpoint couldn't be connected to, and the request was never sent.
+type FailoverTransport struct {
+	Base http.RoundTripper
+	// Endpoints are in order of preference. Requests for any of them are rewritten to the one used.
+	Endpoints []Endpoint
+	// Cooldown, if set, overrides DefaultFailoverCooldown.
+	Cooldown time.Duration
+
+	mu        sync.Mutex
+	downUntil map[int]time.Time // Indexes of unhealthy endpoints, and until when
+}
+
+// RoundTrip implements the http.RoundTripper interface with failover between endpoints.
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	suffix, graphQL, ok := t.match(req.URL)
+	if !ok {
+		return base.RoundTrip(req)
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+		if err != nil {
+			return nil, err
+		}
+		if closeErr := req.Body.Close(); closeErr != nil {
+			slog.DebugContext(req.Context(), "failed to close request body", "error", closeErr, "url", req.URL.String())
+		}
+	}
+
+	replayable := idempotent(req.Method, graphQL, bodyBytes)
+	order := t.order()
+	for n, i := range order {
+		target := t.Endpoints[i].REST + suffix
+		if graphQL {
+			target = t.Endpoints[i].GraphQL
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		u.RawQuery = req.URL.RawQuery
+
+		attempt := req.Clone(req.Context())
+		attempt.URL = u
+		attempt.Host = ""
+		if bodyBytes != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+		resp, err := base.RoundTrip(attempt) //nolint:bodyclose // Response body is handled by caller in successful cases
+		if !unavailable(req, resp, err) {
+			if err == nil {
+				t.setHealthy(i)
+			}
+			return resp, err
+		}
+		t.setUnhealthy(i)
+		if n == len(order)-1 || !replayable && !notSent(err) {
+			return resp, err
+		}
+		if resp != nil {
+			if closeErr := resp.Body.Close(); closeErr != nil {
+				slog.DebugContext(req.Context(), "failed to close response body for failover", "error", closeErr)
+			}
+		}
+		slog.WarnContext(req.Context(), "GitHub API endpoint unavailable, failing over",
+			"endpoint", t.Endpoints[i].REST,
+			"next", t.Endpoints[order[n+1]].REST,
+			"error", err,
+			"status", statusCode(resp))
+	}
+	return nil, errors.New("no API endpoints configured")
+}
+
+// match reports whether u is for one of the endpoints, and if so, its path below the REST base URL
+// or whether it's for the GraphQL endpoint.
+func (t *FailoverTransport) match(u *url.URL) (suffix string, graphQL, ok bool) {
+	bare := *u
+	bare.RawQuery, bare.Fragment = "", ""
+	s := bare.String()
+	for _, e := range t.Endpoints {
+		if s == e.GraphQL {
+			return "", true, true
+		}
+	}
+	for _, e := range t.Endpoints {
+		if rest, found := strings.CutPrefix(s, e.REST); found && (rest == "" || rest[0] == '/') {
+			return rest, false, true
+		}
+	}
+	return "", false, false
+}
+
+// order returns the indexes of the healthy endpoints, followed by the unhealthy ones.
+func (t *FailoverTransport) order() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	healthy := make([]int, 0, len(t.Endpoints))
+	var unhealthy []int
+	for i := range t.Endpoints {
+		if now.Before(t.downUntil[i]) {
+			unhealthy = append(unhealthy, i)
+		} else {
+			healthy = append(healthy, i)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (t *FailoverTransport) setHealthy(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.downUntil, i)
+}
+
+func (t *FailoverTransport) setUnhealthy(i int) {
+	cooldown := t.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.downUntil == nil {
+		t.downUntil = make(map[int]time.Time)
+	}
+	t.downUntil[i] = time.Now().Add(cooldown)
+}
+
+// unavailable reports whether a request's outcome means its endpoint is unavailable: it couldn't be
+// reached, or a gateway in front of it gave up. Requests canceled by the caller don't count.
+func unavailable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// idempotent reports whether a request can be sent again without repeating a change: it reads, or is
+// a GraphQL query rather than a mutation.
+func idempotent(method string, graphQL bool, body []byte) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		if !graphQL {
+			return false
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return false
+		}
+		return !strings.HasPrefix(strings.TrimSpace(req.Query), "mutation")
+	default:
+		return false
+	}
+}
+
+// notSent reports whether err means the request never reached the endpoint, because no connection
+// could be made to it.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// statusCode returns the response's status code, or 0 if there is no response.
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
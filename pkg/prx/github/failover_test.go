@@ -0,0 +1,140 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailoverTransport(t *testing.T) {
+	var primaryDown atomic.Bool
+	var primaryHits, mirrorHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("primary " + r.URL.RequestURI()))
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte("mirror " + r.URL.RequestURI() + " " + string(body)))
+	}))
+	defer mirror.Close()
+
+	transport := &FailoverTransport{
+		Endpoints: []Endpoint{
+			{REST: primary.URL + "/api/v3", GraphQL: primary.URL + "/api/graphql"},
+			{REST: mirror.URL, GraphQL: mirror.URL + "/graphql"},
+		},
+		Cooldown: 50 * time.Millisecond,
+	}
+	client := &http.Client{Transport: transport}
+	do := func(method, url, body string) string {
+		t.Helper()
+		var reqBody io.Reader
+		if body != "" {
+			reqBody = strings.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), method, url, reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	if got, want := do(http.MethodGet, primary.URL+"/api/v3/repos/o/r?page=2", ""), "primary /api/v3/repos/o/r?page=2"; got != want {
+		t.Errorf("healthy primary: got %q, want %q", got, want)
+	}
+
+	primaryDown.Store(true)
+	if got, want := do(http.MethodGet, primary.URL+"/api/v3/repos/o/r?page=2", ""), "mirror /repos/o/r?page=2 "; got != want {
+		t.Errorf("failover: got %q, want %q", got, want)
+	}
+	if got, want := do(http.MethodPost, primary.URL+"/api/graphql", `{"query":"{}"}`), `mirror /graphql {"query":"{}"}`; got != want {
+		t.Errorf("GraphQL while primary is unhealthy: got %q, want %q", got, want)
+	}
+	if n := primaryHits.Load(); n != 2 {
+		t.Errorf("primary got %d requests, want 2: it should be skipped while unhealthy", n)
+	}
+
+	// Writes that reached a failing endpoint may have been applied, so they aren't replayed
+	time.Sleep(100 * time.Millisecond)
+	for _, w := range []struct{ method, url, body string }{
+		{http.MethodPut, primary.URL + "/api/v3/repos/o/r/pulls/1/merge", `{}`},
+		{http.MethodPost, primary.URL + "/api/graphql", `{"query":"mutation { closePullRequest }"}`},
+	} {
+		if got := do(w.method, w.url, w.body); got != "" {
+			t.Errorf("%s %s on a failing endpoint: got %q, want the 502 returned", w.method, w.url, got)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if n := mirrorHits.Load(); n != 2 {
+		t.Errorf("mirror got %d requests, want writes not failed over", n)
+	}
+
+	primaryDown.Store(false)
+	time.Sleep(100 * time.Millisecond)
+	if got, want := do(http.MethodGet, mirror.URL+"/user", ""), "primary /api/v3/user"; got != want {
+		t.Errorf("after cooldown: got %q, want %q", got, want)
+	}
+	if n := mirrorHits.Load(); n != 2 {
+		t.Errorf("mirror got %d requests, want 2", n)
+	}
+
+	// Requests for other hosts pass through untouched
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("other"))
+	}))
+	defer other.Close()
+	if got := do(http.MethodGet, other.URL+"/x", ""); got != "other" {
+		t.Errorf("unrelated host: got %q, want %q", got, "other")
+	}
+}
+
+func TestFailoverTransport_Unreachable(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	downURL := down.URL
+	down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer up.Close()
+
+	// Writes fail over too, since the request was never sent
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		client := &http.Client{Transport: &FailoverTransport{Endpoints: []Endpoint{
+			{REST: downURL, GraphQL: downURL + "/graphql"},
+			{REST: up.URL, GraphQL: up.URL + "/graphql"},
+		}}}
+		req, err := http.NewRequestWithContext(t.Context(), method, downURL+"/repos/o/r/statuses/abc", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s error = %v, want failover to the reachable endpoint", method, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s status = %d, want %d", method, resp.StatusCode, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit resources reported in the X-RateLimit-Resource header.
+const (
+	ResourceCore    = "core"
+	ResourceGraphQL = "graphql"
+)
+
+// RateLimit is a snapshot of the rate limit headers of a response.
+type RateLimit struct {
+	Reset     time.Time
+	Resource  string
+	Limit     int
+	Remaining int
+	Used      int
+}
+
+// parseRateLimit extracts rate limit information from response headers.
+// It reports false if the response carries no rate limit headers.
+func parseRateLimit(h http.Header, defaultResource string) (RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(h.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	rl := RateLimit{
+		Resource:  h.Get("X-Ratelimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+	}
+	if rl.Resource == "" {
+		rl.Resource = defaultResource
+	}
+	if used, err := strconv.Atoi(h.Get("X-Ratelimit-Used")); err == nil {
+		rl.Used = used
+	}
+	if reset, err := strconv.ParseInt(h.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
+// observeRateLimit reports the rate limit headers of resp to OnRateLimit, if set.
+func (c *Client) observeRateLimit(resp *http.Response, defaultResource string) {
+	if c.OnRateLimit == nil {
+		return
+	}
+	if rl, ok := parseRateLimit(resp.Header, defaultResource); ok {
+		c.OnRateLimit(rl)
+	}
+}
+
+// throttle calls Throttle, if set, before a request against resource.
+func (c *Client) throttle(ctx context.Context, resource string) error {
+	if c.Throttle == nil {
+		return nil
+	}
+	return c.Throttle(ctx, resource)
+}
+
+// RateLimits fetches the rate limit of each resource, keyed by resource name. Requests for it
+// don't count against the rate limit. GitHub Enterprise Server answers 404 if rate limiting is disabled.
+func (c *Client) RateLimits(ctx context.Context) (map[string]RateLimit, error) {
+	var body struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Used      int   `json:"used"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if _, err := c.Get(ctx, "/rate_limit", &body); err != nil {
+		return nil, fmt.Errorf("fetching rate limits: %w", err)
+	}
+	limits := make(map[string]RateLimit, len(body.Resources))
+	for resource, r := range body.Resources {
+		limits[resource] = RateLimit{
+			Reset:     time.Unix(r.Reset, 0),
+			Resource:  resource,
+			Limit:     r.Limit,
+			Remaining: r.Remaining,
+			Used:      r.Used,
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseRateLimit(h, ResourceCore); ok {
+		t.Error("expected no rate limit without headers")
+	}
+
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "4990")
+	h.Set("X-RateLimit-Used", "10")
+	h.Set("X-RateLimit-Reset", "1735689600")
+	rl, ok := parseRateLimit(h, ResourceCore)
+	if !ok {
+		t.Fatal("expected rate limit to be parsed")
+	}
+	want := RateLimit{Reset: time.Unix(1735689600, 0), Resource: ResourceCore, Limit: 5000, Remaining: 4990, Used: 10}
+	if rl != want {
+		t.Errorf("parseRateLimit() = %+v, want %+v", rl, want)
+	}
+
+	h.Set("X-RateLimit-Resource", "graphql")
+	if rl, _ := parseRateLimit(h, ResourceCore); rl.Resource != ResourceGraphQL {
+		t.Errorf("Resource = %q, want graphql", rl.Resource)
+	}
+}
+
+func TestClient_RateLimitHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`)) //nolint:errcheck // test handler
+	}))
+	defer server.Close()
+
+	var observed []RateLimit
+	var throttled []string
+	client := &Client{
+		HTTPClient:  server.Client(),
+		BaseURL:     server.URL,
+		OnRateLimit: func(rl RateLimit) { observed = append(observed, rl) },
+		Throttle: func(_ context.Context, resource string) error {
+			throttled = append(throttled, resource)
+			return nil
+		},
+	}
+
+	if _, _, err := client.Do(context.Background(), "/test"); err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	var out map[string]any
+	if err := client.GraphQL(context.Background(), "query{}", nil, &out); err != nil {
+		t.Fatalf("GraphQL() error: %v", err)
+	}
+
+	if len(observed) != 2 || observed[0].Resource != ResourceCore || observed[1].Resource != ResourceGraphQL {
+		t.Errorf("observed = %+v", observed)
+	}
+	if len(throttled) != 2 || throttled[0] != ResourceCore || throttled[1] != ResourceGraphQL {
+		t.Errorf("throttled = %v", throttled)
+	}
+
+	errBudget := errors.New("over budget")
+	client.Throttle = func(context.Context, string) error { return errBudget }
+	if _, _, err := client.Do(context.Background(), "/test"); !errors.Is(err, errBudget) {
+		t.Errorf("expected throttle error, got %v", err)
+	}
+}
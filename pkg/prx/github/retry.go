@@ -27,6 +27,11 @@ const (
 // Transport wraps an http.RoundTripper with retry logic using exponential backoff with jitter.
 type Transport struct {
 	Base http.RoundTripper
+	// OnRetry, if set, is called with the status code of every response that triggers a retry.
+	OnRetry func(statusCode int)
+	// Attempts and MaxDelay, if set, override the default of 10 attempts with delays of up to 2 minutes.
+	Attempts uint
+	MaxDelay time.Duration
 }
 
 // RoundTrip implements the http.RoundTripper interface with retry logic.
@@ -53,6 +58,14 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 	}
 
+	attempts, maxDelay := uint(retryAttempts), retryMaxDelay
+	if t.Attempts > 0 {
+		attempts = t.Attempts
+	}
+	if t.MaxDelay > 0 {
+		maxDelay = t.MaxDelay
+	}
+
 	var resp *http.Response
 	var lastErr error
 
@@ -113,6 +126,9 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 					"status", resp.StatusCode,
 					"url", req.URL.String(),
 					"reason", retryReason)
+				if t.OnRetry != nil {
+					t.OnRetry(resp.StatusCode)
+				}
 				lastErr = &retryableError{StatusCode: resp.StatusCode}
 				return lastErr
 			}
@@ -120,9 +136,9 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 			return nil
 		},
 		retry.Context(req.Context()),
-		retry.Attempts(retryAttempts),
-		retry.Delay(retryDelay),
-		retry.MaxDelay(retryMaxDelay),
+		retry.Attempts(attempts),
+		retry.Delay(min(retryDelay, maxDelay)),
+		retry.MaxDelay(maxDelay),
 		retry.DelayType(retry.BackOffDelay),
 		retry.MaxJitter(retryMaxJitter),
 		retry.RetryIf(func(err error) bool { //nolint:contextcheck // Context is accessed via closure from req.Context()
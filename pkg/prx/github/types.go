@@ -10,17 +10,32 @@ type User struct {
 
 // CheckRun represents a GitHub check run from the REST API.
 type CheckRun struct {
+	ID          int64     `json:"id"`
 	Name        string    `json:"name"`
 	StartedAt   time.Time `json:"started_at"`
 	CompletedAt time.Time `json:"completed_at"`
 	Conclusion  string    `json:"conclusion"`
 	Status      string    `json:"status"`
+	DetailsURL  string    `json:"details_url"`
 	Output      struct {
 		Title   string `json:"title"`
 		Summary string `json:"summary"`
 	} `json:"output"`
 }
 
+// CheckRunAnnotation represents a check run annotation on a line range of a file.
+// Columns are only set for annotations on a single line.
+type CheckRunAnnotation struct {
+	Path            string `json:"path"`
+	AnnotationLevel string `json:"annotation_level"` // "notice", "warning", or "failure"
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	StartColumn     int    `json:"start_column"`
+	EndColumn       int    `json:"end_column"`
+}
+
 // CheckRuns represents a list of GitHub check runs.
 type CheckRuns struct {
 	CheckRuns []*CheckRun `json:"check_runs"`
@@ -36,6 +51,71 @@ type Ruleset struct {
 			RequiredStatusChecks []struct {
 				Context string `json:"context"`
 			} `json:"required_status_checks"`
+			RequiredApprovingReviewCount int `json:"required_approving_review_count"` // Of "pull_request" rules
 		} `json:"parameters"`
 	} `json:"rules"`
 }
+
+// Meta represents the response of the /meta endpoint.
+// InstalledVersion is only populated by GitHub Enterprise Server.
+type Meta struct {
+	InstalledVersion string `json:"installed_version"`
+}
+
+// PullRequestFile represents a file changed by a pull request.
+// Patch is omitted by GitHub for binary and very large diffs.
+type PullRequestFile struct {
+	Filename         string `json:"filename"`
+	Status           string `json:"status"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Patch            string `json:"patch,omitempty"`
+}
+
+// Repository represents repository metadata from the REST API.
+// Visibility is omitted by older GitHub Enterprise Server releases.
+type Repository struct {
+	DefaultBranch string   `json:"default_branch"`
+	Visibility    string   `json:"visibility"`
+	Language      string   `json:"language"`
+	Topics        []string `json:"topics"`
+	Archived      bool     `json:"archived"`
+	Private       bool     `json:"private"`
+}
+
+// Commit represents a single commit from the REST API.
+// Files share the PullRequestFile shape.
+type Commit struct {
+	SHA   string             `json:"sha"`
+	Files []*PullRequestFile `json:"files"`
+}
+
+// Content represents a file from the repository contents API.
+type Content struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// ContentEntry represents a file or directory in a repository contents listing.
+type ContentEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"` // "file", "dir", "symlink", or "submodule"
+}
+
+// Notification represents a notification thread from the REST API.
+// LastReadAt is nil for threads that have never been read.
+type Notification struct {
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastReadAt *time.Time `json:"last_read_at"`
+	Subject    struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`  // API URL of the issue or pull request, e.g. https://api.github.com/repos/o/r/pulls/1
+		Type  string `json:"type"` // "PullRequest", "Issue", "Release", etc.
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+	Unread bool   `json:"unread"`
+}
@@ -1,8 +1,11 @@
 package prx
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -24,18 +27,45 @@ func (c *Client) fetchPullRequestCompleteViaGraphQL(ctx context.Context, owner,
 		return events[i].Timestamp.Before(events[j].Timestamp)
 	})
 	upgradeWriteAccess(events)
+	var report FetchReport
+	c.enrichEvents(ctx, events, &report)
 
 	testState := c.calculateTestStateFromGraphQL(data)
 	finalizePullRequest(&pr, events, requiredChecks, testState)
 
-	return &PullRequestData{
+	prData := &PullRequestData{
 		PullRequest: pr,
 		Events:      events,
-	}, nil
+		FetchReport: report,
+	}
+	for i := range data.errors {
+		e := &data.errors[i]
+		prData.FetchReport.Warnings = append(prData.FetchReport.Warnings,
+			FetchWarning{Section: FetchSectionGraphQL, Target: e.Path, Error: e.Type + ": " + e.Message})
+	}
+	return prData, nil
 }
 
 // executeGraphQL executes the GraphQL query and handles errors.
-func (c *Client) executeGraphQL(ctx context.Context, owner, repo string, prNumber int) (*graphQLPullRequestComplete, error) {
+func (c *Client) executeGraphQL(ctx context.Context, owner, repo string, prNumber int) (_ *graphQLPullRequestComplete, err error) {
+	ctx, span := c.startSpan(ctx, "prx.graphql", repoAttributes(owner, repo, prNumber)...)
+	defer func() { endSpan(span, err) }()
+
+	data, caps, err := c.executeGraphQLFirstPage(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.fetchRemainingPages(ctx, owner, repo, prNumber, data, caps); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// executeGraphQLFirstPage executes the GraphQL query, returning up to 100 items per connection
+// along with the capabilities the query was adapted to.
+func (c *Client) executeGraphQLFirstPage(
+	ctx context.Context, owner, repo string, prNumber int,
+) (*graphQLPullRequestComplete, Capabilities, error) {
 	variables := map[string]any{
 		"owner":  owner,
 		"repo":   repo,
@@ -43,62 +73,272 @@ func (c *Client) executeGraphQL(ctx context.Context, owner, repo string, prNumbe
 	}
 
 	var result graphQLCompleteResponse
-	if err := c.github.GraphQL(ctx, completeGraphQLQuery, variables, &result); err != nil {
-		return nil, err
+	caps := c.serverCapabilities(ctx)
+	query := adaptGraphQLQuery(completeGraphQLQuery, caps)
+	if err := c.queryGraphQL(ctx, query, variables, &result); err != nil {
+		return nil, caps, err
 	}
 
-	if len(result.Errors) > 0 {
-		var errMsgs []string
-		var hasPermissionError bool
-		for _, e := range result.Errors {
-			errMsgs = append(errMsgs, e.Message)
-			msg := strings.ToLower(e.Message)
-			if strings.Contains(msg, "not accessible by integration") ||
-				strings.Contains(msg, "resource not accessible") ||
-				strings.Contains(msg, "forbidden") ||
-				strings.Contains(msg, "insufficient permissions") ||
-				strings.Contains(msg, "requires authentication") {
-				hasPermissionError = true
-			}
-		}
+	rl := result.Data.RateLimit
+	c.recordGraphQLCost(rl.Cost, rl.Remaining, rl.Limit, rl.ResetAt)
+	c.logger.DebugContext(ctx, "GraphQL query cost", "cost", rl.Cost, "remaining", rl.Remaining)
 
-		errStr := strings.Join(errMsgs, "; ")
+	if len(result.Errors) > 0 {
+		errs := classifyGraphQLErrors(result.Errors)
 		if result.Data.Repository.PullRequest.Number == 0 {
-			if hasPermissionError {
-				return nil, fmt.Errorf(
-					"fetching PR %s/%s#%d via GraphQL failed due to insufficient permissions: %s "+
+			if errs.HasType(GraphQLErrorForbidden) {
+				return nil, caps, fmt.Errorf(
+					"fetching PR %s/%s#%d via GraphQL failed due to insufficient permissions: %w "+
 						"(note: some fields like branchProtectionRule or refUpdateRule may require push access "+
 						"even on public repositories; check token scopes or try using a token with 'repo' or 'public_repo' scope)",
-					owner, repo, prNumber, errStr)
+					owner, repo, prNumber, errs)
 			}
-			return nil, fmt.Errorf("fetching PR %s/%s#%d via GraphQL: %s", owner, repo, prNumber, errStr)
+			return nil, caps, fmt.Errorf("fetching PR %s/%s#%d via GraphQL: %w", owner, repo, prNumber, errs)
 		}
 
-		if hasPermissionError {
-			c.logger.WarnContext(ctx, "GraphQL query returned permission errors but PR data was retrieved - some fields may be missing",
-				"owner", owner,
-				"repo", repo,
-				"pr", prNumber,
-				"errors", errStr,
-				"note", "fields like branchProtectionRule or refUpdateRule require push access")
-		} else {
-			c.logger.WarnContext(ctx, "GraphQL query returned errors but PR data was retrieved",
-				"owner", owner,
-				"repo", repo,
-				"pr", prNumber,
-				"errors", errStr)
+		for i := range errs {
+			if !c.partialDataAcceptable(&errs[i]) {
+				return nil, caps, fmt.Errorf("fetching PR %s/%s#%d via GraphQL: partial data not accepted for %q: %w",
+					owner, repo, prNumber, errs[i].Section, errs)
+			}
+		}
+
+		c.logger.WarnContext(ctx, "GraphQL query returned errors but PR data was retrieved - some fields may be missing",
+			"owner", owner,
+			"repo", repo,
+			"pr", prNumber,
+			"errors", errs.Error())
+		result.Data.Repository.PullRequest.errors = errs
+	}
+
+	return &result.Data.Repository.PullRequest, caps, nil
+}
+
+// fetchRemainingPages completes connections that have more than one page of items.
+func (c *Client) fetchRemainingPages(
+	ctx context.Context, owner, repo string, prNumber int, data *graphQLPullRequestComplete, caps Capabilities,
+) error {
+	return c.forEachRemainingPage(ctx, owner, repo, prNumber, data, caps, func(page *graphQLPullRequestComplete) bool {
+		data.Commits.Nodes = append(data.Commits.Nodes, page.Commits.Nodes...)
+		data.Reviews.Nodes = append(data.Reviews.Nodes, page.Reviews.Nodes...)
+		data.Comments.Nodes = append(data.Comments.Nodes, page.Comments.Nodes...)
+		data.appendReviewThreads(page)
+		data.TimelineItems.Nodes = append(data.TimelineItems.Nodes, page.TimelineItems.Nodes...)
+		data.Files.Nodes = append(data.Files.Nodes, page.Files.Nodes...)
+		data.ClosingIssuesReferences.Nodes = append(data.ClosingIssuesReferences.Nodes, page.ClosingIssuesReferences.Nodes...)
+		data.raw.append(&page.raw)
+		return true
+	})
+}
+
+// forEachRemainingPage fetches the follow-up pages of each connection in data that has more
+// than one page of items, calling fn with each page. The page info in data is advanced as pages
+// are fetched. Files come first, so the file list is complete before any further review threads.
+// Review threads with more than one page of comments are then completed, each further page of
+// comments passed to fn as a page holding just that thread. It stops early, without error, when
+// fn returns false.
+func (c *Client) forEachRemainingPage(
+	ctx context.Context, owner, repo string, prNumber int, data *graphQLPullRequestComplete, caps Capabilities,
+	fn func(page *graphQLPullRequestComplete) bool,
+) error {
+	pages := []struct {
+		info       *graphQLPageInfo
+		pageInfo   func(page *graphQLPullRequestComplete) graphQLPageInfo
+		connection string
+		fragName   string
+		fragment   string
+	}{
+		{
+			connection: "files", fragName: "FileFields", fragment: fileFieldsFragment, info: &data.Files.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.Files.PageInfo },
+		},
+		{
+			connection: "commits", fragName: "CommitFields", fragment: commitFieldsFragment, info: &data.Commits.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.Commits.PageInfo },
+		},
+		{
+			connection: "reviews", fragName: "ReviewFields", fragment: reviewFieldsFragment, info: &data.Reviews.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.Reviews.PageInfo },
+		},
+		{
+			connection: "comments", fragName: "CommentFields", fragment: commentFieldsFragment, info: &data.Comments.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.Comments.PageInfo },
+		},
+		{
+			connection: "reviewThreads", fragName: "ReviewThreadFields", fragment: reviewThreadFieldsFragment,
+			info:     &data.ReviewThreads.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.ReviewThreads.PageInfo },
+		},
+		{
+			connection: "timelineItems", fragName: "TimelineItemFields", fragment: timelineItemFieldsFragment,
+			info:     &data.TimelineItems.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.TimelineItems.PageInfo },
+		},
+		{
+			connection: "closingIssuesReferences", fragName: "ClosingIssueFields", fragment: closingIssueFieldsFragment,
+			info:     &data.ClosingIssuesReferences.PageInfo,
+			pageInfo: func(page *graphQLPullRequestComplete) graphQLPageInfo { return page.ClosingIssuesReferences.PageInfo },
+		},
+	}
+
+	// Threads with more comments than their first page holds, from every page of threads
+	var longThreads []graphQLReviewThread
+	collectLongThreads := func(page *graphQLPullRequestComplete) {
+		for _, t := range page.ReviewThreads.Nodes {
+			if t.Comments.PageInfo.HasNextPage {
+				longThreads = append(longThreads, t)
+			}
 		}
 	}
+	collectLongThreads(data)
 
-	return &result.Data.Repository.PullRequest, nil
+	for _, p := range pages {
+		if !p.info.HasNextPage {
+			continue
+		}
+		query := adaptGraphQLQuery(connectionPageQuery(p.connection, p.fragName, p.fragment), caps)
+		n := 0
+		for ; p.info.HasNextPage && n < maxGraphQLPages; n++ {
+			variables := map[string]any{
+				"owner":  owner,
+				"repo":   repo,
+				"number": prNumber,
+				"cursor": p.info.EndCursor,
+			}
+			var result graphQLCompleteResponse
+			if err := c.queryGraphQL(ctx, query, variables, &result); err != nil {
+				return fmt.Errorf("fetching %s page for PR %s/%s#%d: %w", p.connection, owner, repo, prNumber, err)
+			}
+			if len(result.Errors) > 0 {
+				return fmt.Errorf("fetching %s page for PR %s/%s#%d: %w",
+					p.connection, owner, repo, prNumber, classifyGraphQLErrors(result.Errors))
+			}
+			rl := result.Data.RateLimit
+			c.recordGraphQLCost(rl.Cost, rl.Remaining, rl.Limit, rl.ResetAt)
+
+			page := &result.Data.Repository.PullRequest
+			*p.info = p.pageInfo(page)
+			collectLongThreads(page)
+			if !fn(page) {
+				return nil
+			}
+		}
+		if p.info.HasNextPage {
+			c.logger.WarnContext(ctx, "GraphQL connection truncated at page limit",
+				"owner", owner, "repo", repo, "pr", prNumber, "connection", p.connection, "pages", maxGraphQLPages)
+		}
+		c.logger.InfoContext(ctx, "fetched additional GraphQL pages",
+			"owner", owner, "repo", repo, "pr", prNumber, "connection", p.connection, "pages", n)
+	}
+
+	for i := range longThreads {
+		more, err := c.forEachThreadCommentsPage(ctx, owner, repo, prNumber, &longThreads[i], fn)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachThreadCommentsPage fetches the follow-up pages of a review thread's comments, calling fn
+// with a page holding just the thread and those comments. It reports false if fn stopped it.
+func (c *Client) forEachThreadCommentsPage(
+	ctx context.Context, owner, repo string, prNumber int, thread *graphQLReviewThread,
+	fn func(page *graphQLPullRequestComplete) bool,
+) (bool, error) {
+	info := thread.Comments.PageInfo
+	n := 0
+	for ; info.HasNextPage && n < maxGraphQLPages; n++ {
+		var result struct {
+			Data struct {
+				Node struct {
+					Comments struct {
+						PageInfo graphQLPageInfo   `json:"pageInfo"`
+						Nodes    []json.RawMessage `json:"nodes"`
+					} `json:"comments"`
+				} `json:"node"`
+				RateLimit struct {
+					ResetAt   time.Time `json:"resetAt"`
+					Cost      int       `json:"cost"`
+					Remaining int       `json:"remaining"`
+					Limit     int       `json:"limit"`
+				} `json:"rateLimit"`
+			} `json:"data"`
+			Errors []graphQLResponseError `json:"errors"`
+		}
+		variables := map[string]any{"id": thread.ID, "cursor": info.EndCursor}
+		if err := c.github.GraphQL(ctx, reviewThreadCommentsPageQuery, variables, &result); err != nil {
+			return false, fmt.Errorf("fetching review thread comments page for PR %s/%s#%d: %w", owner, repo, prNumber, err)
+		}
+		if len(result.Errors) > 0 {
+			return false, fmt.Errorf("fetching review thread comments page for PR %s/%s#%d: %w",
+				owner, repo, prNumber, classifyGraphQLErrors(result.Errors))
+		}
+		rl := result.Data.RateLimit
+		c.recordGraphQLCost(rl.Cost, rl.Remaining, rl.Limit, rl.ResetAt)
+
+		comments := result.Data.Node.Comments
+		info = comments.PageInfo
+		t := graphQLReviewThread{ID: thread.ID, Path: thread.Path, IsResolved: thread.IsResolved, IsOutdated: thread.IsOutdated}
+		t.Comments.PageInfo = info
+		t.Comments.Nodes = make([]graphQLReviewThreadComment, len(comments.Nodes))
+		for i, node := range comments.Nodes {
+			if err := json.Unmarshal(node, &t.Comments.Nodes[i]); err != nil {
+				return false, fmt.Errorf("decoding review thread comment for PR %s/%s#%d: %w", owner, repo, prNumber, err)
+			}
+		}
+		page := &graphQLPullRequestComplete{}
+		page.ReviewThreads.Nodes = []graphQLReviewThread{t}
+		if c.rawPayloads {
+			page.raw.ReviewThreadComments = [][]json.RawMessage{comments.Nodes}
+		}
+		if !fn(page) {
+			return false, nil
+		}
+	}
+	if info.HasNextPage {
+		c.logger.WarnContext(ctx, "GraphQL connection truncated at page limit",
+			"owner", owner, "repo", repo, "pr", prNumber, "connection", "reviewThreads.comments", "pages", maxGraphQLPages)
+	}
+	return true, nil
+}
+
+// appendReviewThreads adds the review threads of a follow-up page to data. Threads already in data,
+// matched by node ID, are instead extended with the page's comments.
+func (data *graphQLPullRequestComplete) appendReviewThreads(page *graphQLPullRequestComplete) {
+	for j := range page.ReviewThreads.Nodes {
+		t := &page.ReviewThreads.Nodes[j]
+		var raw []json.RawMessage
+		if j < len(page.raw.ReviewThreadComments) {
+			raw = page.raw.ReviewThreadComments[j]
+		}
+		i := -1
+		if t.ID != "" {
+			i = slices.IndexFunc(data.ReviewThreads.Nodes, func(existing graphQLReviewThread) bool { return existing.ID == t.ID })
+		}
+		if i < 0 {
+			data.ReviewThreads.Nodes = append(data.ReviewThreads.Nodes, *t)
+			if len(page.raw.ReviewThreadComments) > 0 {
+				data.raw.ReviewThreadComments = append(data.raw.ReviewThreadComments, raw)
+			}
+			continue
+		}
+		existing := &data.ReviewThreads.Nodes[i]
+		existing.Comments.Nodes = append(existing.Comments.Nodes, t.Comments.Nodes...)
+		existing.Comments.PageInfo = t.Comments.PageInfo
+		if i < len(data.raw.ReviewThreadComments) {
+			data.raw.ReviewThreadComments[i] = append(data.raw.ReviewThreadComments[i], raw...)
+		}
+	}
 }
 
 // convertGraphQLToPullRequest converts GraphQL data to PullRequest.
 func (c *Client) convertGraphQLToPullRequest(ctx context.Context, data *graphQLPullRequestComplete, owner, repo string) PullRequest {
 	pr := PullRequest{
+		NodeID:       data.ID,
 		Number:       data.Number,
 		Title:        data.Title,
-		Body:         truncate(data.Body),
+		Body:         c.truncate(data.Body),
 		Author:       data.Author.Login,
 		State:        strings.ToLower(data.State),
 		CreatedAt:    data.CreatedAt,
@@ -108,6 +348,30 @@ func (c *Client) convertGraphQLToPullRequest(ctx context.Context, data *graphQLP
 		Deletions:    data.Deletions,
 		ChangedFiles: data.ChangedFiles,
 		HeadSHA:      data.HeadRef.Target.OID,
+		HeadRef:      data.HeadRef.Name,
+		TicketRefs:   ticketRefs(data.HeadRef.Name, c.ticketPattern),
+		Raw:          data.raw.PullRequest,
+	}
+	pr.RepoArchived = data.BaseRepository.IsArchived
+	pr.ConversationLocked = data.Locked
+	if rule := data.BaseRef.BranchProtectionRule; rule != nil && rule.RequiresApprovingReviews {
+		pr.RequiredApprovals = rule.RequiredApprovingReviewCount
+	}
+	pr.MergeQueue = graphQLMergeQueueState(data)
+
+	if len(c.branchPatterns) > 0 && data.HeadRef.Name != "" {
+		valid := branchNameValid(data.HeadRef.Name, c.branchPatterns)
+		pr.BranchNameValid = &valid
+	}
+
+	if c.descriptionTemplates {
+		templates, err := c.PullRequestTemplates(ctx, owner, repo)
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to fetch pull request templates for description check",
+				"owner", owner, "repo", repo, "error", err)
+		} else {
+			pr.DescriptionCheck = CheckDescription(data.Body, templates)
+		}
 	}
 
 	if data.ClosedAt != nil {
@@ -138,7 +402,7 @@ func (c *Client) convertGraphQLToPullRequest(ctx context.Context, data *graphQLP
 
 	if data.Author.Login != "" {
 		pr.AuthorWriteAccess = c.writeAccessFromAssociation(ctx, owner, repo, data.Author.Login, data.AuthorAssociation)
-		pr.AuthorBot = isBot(data.Author)
+		pr.AuthorBot = c.classifyBot(ctx, data.Author)
 	}
 
 	pr.Assignees = make([]string, 0)
@@ -150,15 +414,87 @@ func (c *Client) convertGraphQLToPullRequest(ctx context.Context, data *graphQLP
 		pr.Labels = append(pr.Labels, label.Name)
 	}
 
+	for _, issue := range data.ClosingIssuesReferences.Nodes {
+		if strings.EqualFold(issue.Repository.NameWithOwner, owner+"/"+repo) {
+			pr.ClosesIssues = append(pr.ClosesIssues, issue.Number)
+		} else {
+			pr.ClosesIssueRefs = append(pr.ClosesIssueRefs, fmt.Sprintf("%s#%d", issue.Repository.NameWithOwner, issue.Number))
+		}
+	}
+
 	for _, node := range data.Commits.Nodes {
 		pr.Commits = append(pr.Commits, node.Commit.OID)
 	}
 
+	for _, node := range data.Files.Nodes {
+		pr.Files = append(pr.Files, node.Path)
+	}
+
+	if c.commitEmailDomains {
+		pr.CommitEmailDomains = commitEmailDomains(data)
+	}
+
 	pr.Reviewers = buildReviewersMap(data)
+	pr.ThreadSummary = threadSummary(data, c.excludeDeletedFileThreads)
 
 	return pr
 }
 
+// threadSummary counts the pull request's review threads by state, or returns nil if there are none.
+// If excludeDeleted is set, threads on files no longer in the pull request are only counted as such.
+func threadSummary(data *graphQLPullRequestComplete, excludeDeleted bool) *ThreadSummary {
+	if len(data.ReviewThreads.Nodes) == 0 {
+		return nil
+	}
+	deleted := deletedPaths(data)
+	summary := &ThreadSummary{}
+	for i := range data.ReviewThreads.Nodes {
+		thread := &data.ReviewThreads.Nodes[i]
+		if deleted(thread.Path) {
+			summary.PathDeleted++
+			if excludeDeleted {
+				continue
+			}
+		}
+		summary.Total++
+		if thread.IsResolved {
+			summary.Resolved++
+		} else {
+			summary.Unresolved++
+		}
+		if thread.IsOutdated {
+			summary.Outdated++
+		}
+	}
+	return summary
+}
+
+// deletedPaths returns a function reporting whether a review thread's file is no longer in the pull
+// request. Unless all of the pull request's files were fetched, it reports false for every file.
+func deletedPaths(data *graphQLPullRequestComplete) func(path string) bool {
+	if len(data.Files.Nodes) == 0 || data.Files.PageInfo.HasNextPage {
+		return func(string) bool { return false }
+	}
+	files := make(map[string]bool, len(data.Files.Nodes))
+	for _, node := range data.Files.Nodes {
+		files[node.Path] = true
+	}
+	return func(path string) bool { return path != "" && !files[path] }
+}
+
+// commitEmailDomains counts commits by the domain of their author's email address.
+func commitEmailDomains(data *graphQLPullRequestComplete) map[string]int {
+	domains := make(map[string]int)
+	for _, node := range data.Commits.Nodes {
+		at := strings.LastIndexByte(node.Commit.Author.Email, '@')
+		if at < 0 || at == len(node.Commit.Author.Email)-1 {
+			continue
+		}
+		domains[strings.ToLower(node.Commit.Author.Email[at+1:])]++
+	}
+	return domains
+}
+
 // buildReviewersMap constructs a map of reviewer login to their review state.
 func buildReviewersMap(data *graphQLPullRequestComplete) map[string]ReviewState {
 	reviewers := make(map[string]ReviewState)
@@ -204,21 +540,52 @@ func (c *Client) convertGraphQLToEventsComplete(ctx context.Context, data *graph
 		Kind:        EventKindPROpened,
 		Timestamp:   data.CreatedAt,
 		Actor:       data.Author.Login,
-		Body:        truncate(data.Body),
-		Bot:         isBot(data.Author),
+		ActorType:   c.classifyActor(ctx, data.Author),
+		Body:        c.truncate(data.Body),
+		Mentions:    extractMentions(data.Body),
+		Bot:         c.classifyBot(ctx, data.Author),
 		WriteAccess: c.writeAccessFromAssociation(ctx, owner, repo, data.Author.Login, data.AuthorAssociation),
 	})
+	if data.Author.Login == "" {
+		attributeGhost(&events[0], "")
+	}
 
-	for _, node := range data.Commits.Nodes {
+	events = append(events, c.convertGraphQLConnectionEvents(ctx, data, owner, repo)...)
+
+	if data.ClosedAt != nil && !data.IsDraft {
+		event := Event{
+			Kind:      EventKindPRClosed,
+			Timestamp: *data.ClosedAt,
+		}
+		if data.MergedBy != nil {
+			event.Actor = data.MergedBy.Login
+			event.Kind = EventKindPRMerged
+			event.Bot = c.classifyBot(ctx, *data.MergedBy)
+			event.ActorType = c.classifyActor(ctx, *data.MergedBy)
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// convertGraphQLConnectionEvents converts the items of data's connections (commits, reviews,
+// comments, checks, and timeline items) to Events. It also applies to follow-up pages.
+func (c *Client) convertGraphQLConnectionEvents(ctx context.Context, data *graphQLPullRequestComplete, owner, repo string) []Event {
+	var events []Event
+
+	for i, node := range data.Commits.Nodes {
 		event := Event{
 			Kind:        EventKindCommit,
 			Timestamp:   node.Commit.CommittedDate,
 			Body:        node.Commit.OID,
-			Description: truncate(node.Commit.Message),
+			Description: c.truncate(node.Commit.Message),
+			Raw:         rawAt(data.raw.Commits, i),
 		}
 		if node.Commit.Author.User != nil {
 			event.Actor = node.Commit.Author.User.Login
-			event.Bot = isBot(*node.Commit.Author.User)
+			event.Bot = c.classifyBot(ctx, *node.Commit.Author.User)
+			event.ActorType = c.classifyActor(ctx, *node.Commit.Author.User)
 		} else {
 			event.Actor = node.Commit.Author.Name
 		}
@@ -234,47 +601,56 @@ func (c *Client) convertGraphQLToEventsComplete(ctx context.Context, data *graph
 		if review.SubmittedAt != nil {
 			timestamp = *review.SubmittedAt
 		}
-		event := Event{
-			Kind:        EventKindReview,
-			Timestamp:   timestamp,
-			Actor:       review.Author.Login,
-			Body:        truncate(review.Body),
-			Outcome:     strings.ToLower(review.State),
-			Question:    containsQuestion(review.Body),
-			Bot:         isBot(review.Author),
-			WriteAccess: c.writeAccessFromAssociation(ctx, owner, repo, review.Author.Login, review.AuthorAssociation),
-		}
+		event := c.postEvent(ctx, EventKindReview, &post{
+			createdAt:   timestamp,
+			author:      review.Author,
+			body:        review.Body,
+			association: review.AuthorAssociation,
+		}, owner, repo)
+		event.Outcome = outcome(review.State)
+		if review.Commit != nil {
+			event.CommitSHA = review.Commit.OID
+		}
+		event.Reactions = reactionCounts(review.ReactionGroups)
+		event.Raw = rawAt(data.raw.Reviews, i)
 		events = append(events, event)
 	}
 
+	deleted := deletedPaths(data)
 	for i := range data.ReviewThreads.Nodes {
 		thread := &data.ReviewThreads.Nodes[i]
+		pathDeleted := deleted(thread.Path)
 		for j := range thread.Comments.Nodes {
 			comment := &thread.Comments.Nodes[j]
-			event := Event{
-				Kind:        EventKindReviewComment,
-				Timestamp:   comment.CreatedAt,
-				Actor:       comment.Author.Login,
-				Body:        truncate(comment.Body),
-				Question:    containsQuestion(comment.Body),
-				Bot:         isBot(comment.Author),
-				WriteAccess: c.writeAccessFromAssociation(ctx, owner, repo, comment.Author.Login, comment.AuthorAssociation),
-				Outdated:    comment.Outdated,
+			event := c.postEvent(ctx, EventKindReviewComment, &post{
+				createdAt:   comment.CreatedAt,
+				author:      comment.Author,
+				body:        comment.Body,
+				association: comment.AuthorAssociation,
+			}, owner, repo)
+			event.Reactions = reactionCounts(comment.ReactionGroups)
+			event.Outdated = comment.Outdated
+			event.ThreadResolved = thread.IsResolved
+			event.PathDeleted = pathDeleted
+			if pathDeleted && c.excludeDeletedFileThreads {
+				event.Question = false
+			}
+			if i < len(data.raw.ReviewThreadComments) {
+				event.Raw = rawAt(data.raw.ReviewThreadComments[i], j)
 			}
 			events = append(events, event)
 		}
 	}
 
-	for _, comment := range data.Comments.Nodes {
-		event := Event{
-			Kind:        EventKindComment,
-			Timestamp:   comment.CreatedAt,
-			Actor:       comment.Author.Login,
-			Body:        truncate(comment.Body),
-			Question:    containsQuestion(comment.Body),
-			Bot:         isBot(comment.Author),
-			WriteAccess: c.writeAccessFromAssociation(ctx, owner, repo, comment.Author.Login, comment.AuthorAssociation),
-		}
+	for i, comment := range data.Comments.Nodes {
+		event := c.postEvent(ctx, EventKindComment, &post{
+			createdAt:   comment.CreatedAt,
+			author:      comment.Author,
+			body:        comment.Body,
+			association: comment.AuthorAssociation,
+		}, owner, repo)
+		event.Reactions = reactionCounts(comment.ReactionGroups)
+		event.Raw = rawAt(data.raw.Comments, i)
 		events = append(events, event)
 	}
 
@@ -283,55 +659,45 @@ func (c *Client) convertGraphQLToEventsComplete(ctx context.Context, data *graph
 			node := &data.HeadRef.Target.StatusCheckRollup.Contexts.Nodes[i]
 			switch node.TypeName {
 			case "CheckRun":
-				var description string
-				switch {
-				case node.Title != "" && node.Summary != "":
-					description = fmt.Sprintf("%s: %s", node.Title, node.Summary)
-				case node.Title != "":
-					description = node.Title
-				case node.Summary != "":
-					description = node.Summary
-				default:
-					// No description available
+				run := checkRun{
+					name:       node.Name,
+					headSHA:    data.HeadRef.Target.OID,
+					status:     node.Status,
+					conclusion: node.Conclusion,
+					title:      node.Title,
+					summary:    node.Summary,
+					detailsURL: node.DetailsURL,
+					id:         int64(node.DatabaseID),
 				}
-
 				if node.StartedAt != nil {
-					events = append(events, Event{
-						Kind:        EventKindCheckRun,
-						Timestamp:   *node.StartedAt,
-						Body:        node.Name,
-						Outcome:     strings.ToLower(node.Status),
-						Bot:         true,
-						Description: description,
-					})
+					run.startedAt = *node.StartedAt
+					event := checkRunEvent(&run, false)
+					event.Raw = rawAt(data.raw.Checks, i)
+					events = append(events, event)
 				}
-
 				if node.CompletedAt != nil {
-					events = append(events, Event{
-						Kind:        EventKindCheckRun,
-						Timestamp:   *node.CompletedAt,
-						Body:        node.Name,
-						Outcome:     strings.ToLower(node.Conclusion),
-						Bot:         true,
-						Description: description,
-					})
+					run.completedAt = *node.CompletedAt
+					event := checkRunEvent(&run, true)
+					event.Raw = rawAt(data.raw.Checks, i)
+					events = append(events, event)
 				}
 
 			case "StatusContext":
 				if node.CreatedAt == nil {
 					continue
 				}
-				event := Event{
-					Kind:        EventKindStatusCheck,
-					Timestamp:   *node.CreatedAt,
-					Outcome:     strings.ToLower(node.State),
-					Body:        node.Context,
-					Description: node.Description,
+				status := commitStatus{
+					createdAt:   *node.CreatedAt,
+					sha:         data.HeadRef.Target.OID,
+					context:     node.Context,
+					state:       node.State,
+					description: node.Description,
 				}
 				if node.Creator != nil {
-					event.Actor = node.Creator.Login
-					event.Bot = isBot(*node.Creator)
+					status.creator = *node.Creator
 				}
+				event := c.statusEvent(ctx, &status)
+				event.Raw = rawAt(data.raw.Checks, i)
 				events = append(events, event)
 
 			default:
@@ -340,264 +706,160 @@ func (c *Client) convertGraphQLToEventsComplete(ctx context.Context, data *graph
 		}
 	}
 
-	for _, item := range data.TimelineItems.Nodes {
+	for i, item := range data.TimelineItems.Nodes {
 		event := c.parseGraphQLTimelineEvent(ctx, item, owner, repo)
 		if event != nil {
+			event.Raw = rawAt(data.raw.TimelineItems, i)
 			events = append(events, *event)
 		}
 	}
 
-	if data.ClosedAt != nil && !data.IsDraft {
-		event := Event{
-			Kind:      EventKindPRClosed,
-			Timestamp: *data.ClosedAt,
-		}
-		if data.MergedBy != nil {
-			event.Actor = data.MergedBy.Login
-			event.Kind = EventKindPRMerged
-			event.Bot = isBot(*data.MergedBy)
-		}
-		events = append(events, event)
-	}
-
 	return events
 }
 
 // parseGraphQLTimelineEvent parses a single timeline event.
-//
-//nolint:gocognit,maintidx,revive // High complexity justified - must handle all GitHub timeline event types
-func (*Client) parseGraphQLTimelineEvent(_ context.Context, item map[string]any, _, _ string) *Event {
+func (c *Client) parseGraphQLTimelineEvent(ctx context.Context, item map[string]any, _, _ string) *Event {
 	typename, ok := item["__typename"].(string)
 	if !ok {
 		return nil
 	}
-
-	getTime := func(key string) *time.Time {
-		if str, ok := item[key].(string); ok {
-			if t, err := time.Parse(time.RFC3339, str); err == nil {
-				return &t
-			}
-		}
+	kind, ok := timelineEventKinds[typename]
+	if !ok {
 		return nil
 	}
 
-	getActor := func() string {
-		if actor, ok := item["actor"].(map[string]any); ok {
-			if login, ok := actor["login"].(string); ok {
-				return login
-			}
-		}
-		return "unknown"
-	}
-
-	isActorBot := func() bool {
-		if actor, ok := item["actor"].(map[string]any); ok {
-			var actorObj graphQLActor
-			if login, ok := actor["login"].(string); ok {
-				actorObj.Login = login
-			}
-			if id, ok := actor["id"].(string); ok {
-				actorObj.ID = id
-			}
-			if typ, ok := actor["__typename"].(string); ok {
-				actorObj.Type = typ
-			}
-			return isBot(actorObj)
-		}
-		return false
+	str, ok := item["createdAt"].(string)
+	if !ok {
+		return nil
 	}
-
-	createdAt := getTime("createdAt")
-	if createdAt == nil {
+	createdAt, err := time.Parse(time.RFC3339, str)
+	if err != nil {
 		return nil
 	}
 
+	actorObj := timelineActor(item["actor"])
 	event := &Event{
-		Timestamp: *createdAt,
-		Actor:     getActor(),
-		Bot:       isActorBot(),
+		Kind:      kind,
+		Timestamp: createdAt,
+		Actor:     cmp.Or(actorObj.Login, "unknown"),
+		ActorType: c.classifyActor(ctx, actorObj),
+		Bot:       c.classifyBot(ctx, actorObj),
 	}
 
-	switch typename {
-	case "AssignedEvent":
-		event.Kind = EventKindAssigned
-		if assignee, ok := item["assignee"].(map[string]any); ok {
-			if login, ok := assignee["login"].(string); ok {
-				event.Target = login
-			}
-		}
+	switch kind {
+	case EventKindAssigned, EventKindUnassigned:
+		c.target(ctx, event, timelineActor(item["assignee"]), "")
 
-	case "UnassignedEvent":
-		event.Kind = EventKindUnassigned
-		if assignee, ok := item["assignee"].(map[string]any); ok {
-			if login, ok := assignee["login"].(string); ok {
-				event.Target = login
-			}
-		}
-
-	case "LabeledEvent":
-		event.Kind = EventKindLabeled
-		if label, ok := item["label"].(map[string]any); ok {
-			if name, ok := label["name"].(string); ok {
-				event.Target = name
+	case EventKindReviewRequested, EventKindReviewRequestRemoved:
+		var team string
+		if reviewer, ok := item["requestedReviewer"].(map[string]any); ok {
+			if name, ok := reviewer["name"].(string); ok {
+				team = name
 			}
 		}
+		c.target(ctx, event, timelineActor(item["requestedReviewer"]), team)
 
-	case "UnlabeledEvent":
-		event.Kind = EventKindUnlabeled
+	case EventKindLabeled, EventKindUnlabeled:
 		if label, ok := item["label"].(map[string]any); ok {
 			if name, ok := label["name"].(string); ok {
 				event.Target = name
 			}
 		}
 
-	case "MilestonedEvent":
-		event.Kind = EventKindMilestoned
+	case EventKindMilestoned, EventKindDemilestoned:
 		if title, ok := item["milestoneTitle"].(string); ok {
 			event.Target = title
 		}
 
-	case "DemilestonedEvent":
-		event.Kind = EventKindDemilestoned
-		if title, ok := item["milestoneTitle"].(string); ok {
-			event.Target = title
-		}
-
-	case "ReviewRequestedEvent":
-		event.Kind = EventKindReviewRequested
-		if reviewer, ok := item["requestedReviewer"].(map[string]any); ok {
-			if login, ok := reviewer["login"].(string); ok {
-				event.Target = login
-			} else if name, ok := reviewer["name"].(string); ok {
-				event.Target = name
-			}
-		}
-
-	case "ReviewRequestRemovedEvent":
-		event.Kind = EventKindReviewRequestRemoved
-		if reviewer, ok := item["requestedReviewer"].(map[string]any); ok {
-			if login, ok := reviewer["login"].(string); ok {
-				event.Target = login
-			} else if name, ok := reviewer["name"].(string); ok {
-				event.Target = name
-			}
-		}
-
-	case "MentionedEvent":
-		event.Kind = EventKindMentioned
+	case EventKindMentioned:
 		event.Body = "User was mentioned"
 
-	case "ReadyForReviewEvent":
-		event.Kind = EventKindReadyForReview
-
-	case "ConvertToDraftEvent":
-		event.Kind = EventKindConvertToDraft
-
-	case "ClosedEvent":
-		event.Kind = EventKindClosed
-
-	case "ReopenedEvent":
-		event.Kind = EventKindReopened
-
-	case "MergedEvent":
-		event.Kind = EventKindMerged
-
-	case "AutoMergeEnabledEvent":
-		event.Kind = EventKindAutoMergeEnabled
-
-	case "AutoMergeDisabledEvent":
-		event.Kind = EventKindAutoMergeDisabled
-
-	case "ReviewDismissedEvent":
-		event.Kind = EventKindReviewDismissed
+	case EventKindReviewDismissed:
 		if msg, ok := item["dismissalMessage"].(string); ok {
 			event.Body = msg
 		}
 
-	case "BaseRefChangedEvent":
-		event.Kind = EventKindBaseRefChanged
-
-	case "BaseRefForcePushedEvent":
-		event.Kind = EventKindBaseRefForcePushed
-
-	case "HeadRefForcePushedEvent":
-		event.Kind = EventKindHeadRefForcePushed
+	case EventKindRemovedFromMergeQueue:
+		if reason, ok := item["reason"].(string); ok {
+			event.Body = reason
+		}
 
-	case "HeadRefDeletedEvent":
-		event.Kind = EventKindHeadRefDeleted
+	case EventKindHeadRefForcePushed:
+		// Commits are null once garbage collected
+		if commit, ok := item["beforeCommit"].(map[string]any); ok {
+			if oid, ok := commit["oid"].(string); ok {
+				event.BeforeSHA = oid
+			}
+		}
+		if commit, ok := item["afterCommit"].(map[string]any); ok {
+			if oid, ok := commit["oid"].(string); ok {
+				event.AfterSHA = oid
+			}
+		}
 
-	case "HeadRefRestoredEvent":
-		event.Kind = EventKindHeadRefRestored
+	case EventKindCrossReferenced:
+		if source, ok := item["source"].(map[string]any); ok {
+			event.Target = referencedSubject(source)
+		}
 
-	case "RenamedTitleEvent":
-		event.Kind = EventKindRenamedTitle
+	case EventKindRenamedTitle:
 		if prev, ok := item["previousTitle"].(string); ok {
 			if curr, ok := item["currentTitle"].(string); ok {
 				event.Body = fmt.Sprintf("Renamed from %q to %q", prev, curr)
 			}
 		}
 
-	case "LockedEvent":
-		event.Kind = EventKindLocked
-
-	case "UnlockedEvent":
-		event.Kind = EventKindUnlocked
-
-	case "AddedToMergeQueueEvent":
-		event.Kind = EventKindAddedToMergeQueue
-
-	case "RemovedFromMergeQueueEvent":
-		event.Kind = EventKindRemovedFromMergeQueue
-
-	case "AutomaticBaseChangeSucceededEvent":
-		event.Kind = EventKindAutomaticBaseChangeSucceeded
-
-	case "AutomaticBaseChangeFailedEvent":
-		event.Kind = EventKindAutomaticBaseChangeFailed
-
-	case "ConnectedEvent":
-		event.Kind = EventKindConnected
-
-	case "DisconnectedEvent":
-		event.Kind = EventKindDisconnected
-
-	case "CrossReferencedEvent":
-		event.Kind = EventKindCrossReferenced
-
-	case "ReferencedEvent":
-		event.Kind = EventKindReferenced
-
-	case "SubscribedEvent":
-		event.Kind = EventKindSubscribed
-
-	case "UnsubscribedEvent":
-		event.Kind = EventKindUnsubscribed
-
-	case "DeployedEvent":
-		event.Kind = EventKindDeployed
-
-	case "DeploymentEnvironmentChangedEvent":
-		event.Kind = EventKindDeploymentEnvironmentChanged
-
-	case "PinnedEvent":
-		event.Kind = EventKindPinned
-
-	case "UnpinnedEvent":
-		event.Kind = EventKindUnpinned
-
-	case "TransferredEvent":
-		event.Kind = EventKindTransferred
-
-	case "UserBlockedEvent":
-		event.Kind = EventKindUserBlocked
+	default:
+	}
 
+	switch {
+	case actorObj.Login != "":
+	case event.Kind == EventKindHeadRefForcePushed, event.Kind == EventKindBaseRefForcePushed:
+		// GitHub records no actor for pushes made with a deploy key
+		event.ActorType = ActorTypeDeployKey
 	default:
-		return nil
+		attributeGhost(event, "")
 	}
 
 	return event
 }
 
+// referencedSubject returns the issue or pull request in a timeline item's field as "owner/repo#number",
+// or "" if it isn't one.
+func referencedSubject(subject map[string]any) string {
+	number, ok := subject["number"].(float64)
+	if !ok {
+		return ""
+	}
+	var nameWithOwner string
+	if repository, ok := subject["repository"].(map[string]any); ok {
+		if name, ok := repository["nameWithOwner"].(string); ok {
+			nameWithOwner = name
+		}
+	}
+	return fmt.Sprintf("%s#%d", nameWithOwner, int(number))
+}
+
+// timelineActor returns the user, bot, or other account in a timeline item's field, such as its
+// actor or assignee. Its login is empty if the field is absent, as for deleted accounts.
+func timelineActor(field any) graphQLActor {
+	var actor graphQLActor
+	m, ok := field.(map[string]any)
+	if !ok {
+		return actor
+	}
+	if login, ok := m["login"].(string); ok {
+		actor.Login = login
+	}
+	if id, ok := m["id"].(string); ok {
+		actor.ID = id
+	}
+	if typ, ok := m["__typename"].(string); ok {
+		actor.Type = typ
+	}
+	return actor
+}
+
 // writeAccessFromAssociation calculates write access from association.
 func (c *Client) writeAccessFromAssociation(ctx context.Context, owner, repo, user, association string) int {
 	if user == "" {
@@ -618,7 +880,8 @@ func (c *Client) writeAccessFromAssociation(ctx context.Context, owner, repo, us
 
 // checkCollaboratorPermission checks if a user has write access.
 func (c *Client) checkCollaboratorPermission(ctx context.Context, owner, repo, user string) int {
-	collabs, err := c.collaboratorsCache.Fetch(collaboratorsCacheKey(owner, repo), func() (map[string]string, error) {
+	key := collaboratorsCacheKey(owner, repo)
+	collabs, err := c.collaboratorsCache.Fetch(key, readThrough(ctx, c, c.collaboratorsStore, key, func() (map[string]string, error) {
 		result, fetchErr := c.github.Collaborators(ctx, owner, repo)
 		if fetchErr != nil {
 			c.logger.WarnContext(ctx, "failed to fetch collaborators for write access check",
@@ -628,14 +891,14 @@ func (c *Client) checkCollaboratorPermission(ctx context.Context, owner, repo, u
 				"error", fetchErr)
 
 			// On any error (including 403 Forbidden), return the error
-			// so that checkCollaboratorPermission returns WriteAccessLikely
+			// so that checkCollaboratorPermission falls back to team permissions
 			return nil, fetchErr
 		}
 
 		return result, nil
-	})
+	}))
 	if err != nil {
-		return WriteAccessLikely
+		return c.checkTeamPermission(ctx, owner, repo, user)
 	}
 
 	switch collabs[user] {
@@ -648,6 +911,38 @@ func (c *Client) checkCollaboratorPermission(ctx context.Context, owner, repo, u
 	}
 }
 
+// checkTeamPermission checks a user's write access through the repository's teams, for tokens that
+// can't list collaborators. Members of teams granting only read or triage access are taken not to
+// have write access. Team permissions don't cover direct grants or the organization's base
+// permission, so members of no team with access stay WriteAccessLikely.
+func (c *Client) checkTeamPermission(ctx context.Context, owner, repo, user string) int {
+	key := collaboratorsCacheKey(owner, repo)
+	members, err := c.teamsCache.Fetch(key, func() (map[string]string, error) {
+		result, fetchErr := c.github.TeamPermissions(ctx, owner, repo)
+		if fetchErr != nil {
+			c.logger.WarnContext(ctx, "failed to fetch team permissions for write access check",
+				"owner", owner,
+				"repo", repo,
+				"user", user,
+				"error", fetchErr)
+			return nil, fetchErr
+		}
+		return result, nil
+	})
+	if err != nil {
+		return WriteAccessLikely
+	}
+
+	switch members[user] {
+	case "admin", "maintain", "write":
+		return WriteAccessDefinitely
+	case "read", "triage":
+		return WriteAccessNo
+	default:
+		return WriteAccessLikely
+	}
+}
+
 // extractRequiredChecksFromGraphQL gets required checks from GraphQL response.
 func (*Client) extractRequiredChecksFromGraphQL(data *graphQLPullRequestComplete) []string {
 	seen := make(map[string]bool)
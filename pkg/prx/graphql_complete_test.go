@@ -2,9 +2,11 @@ package prx
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -158,112 +160,38 @@ func TestConvertGraphQLReviewCommentsWithOutdated(t *testing.T) {
 	ctx := context.Background()
 
 	// Create test data with review threads containing outdated comments
-	data := &graphQLPullRequestComplete{
-		ReviewThreads: struct {
-			Nodes []struct {
-				Comments struct {
-					Nodes []struct {
-						CreatedAt         time.Time    `json:"createdAt"`
-						Author            graphQLActor `json:"author"`
-						ID                string       `json:"id"`
-						Body              string       `json:"body"`
-						Outdated          bool         `json:"outdated"`
-						AuthorAssociation string       `json:"authorAssociation"`
-					} `json:"nodes"`
-				} `json:"comments"`
-				IsResolved bool `json:"isResolved"`
-				IsOutdated bool `json:"isOutdated"`
-			} `json:"nodes"`
-		}{
-			Nodes: []struct {
-				Comments struct {
-					Nodes []struct {
-						CreatedAt         time.Time    `json:"createdAt"`
-						Author            graphQLActor `json:"author"`
-						ID                string       `json:"id"`
-						Body              string       `json:"body"`
-						Outdated          bool         `json:"outdated"`
-						AuthorAssociation string       `json:"authorAssociation"`
-					} `json:"nodes"`
-				} `json:"comments"`
-				IsResolved bool `json:"isResolved"`
-				IsOutdated bool `json:"isOutdated"`
-			}{
-				{
-					IsOutdated: true,
-					IsResolved: true,
-					Comments: struct {
-						Nodes []struct {
-							CreatedAt         time.Time    `json:"createdAt"`
-							Author            graphQLActor `json:"author"`
-							ID                string       `json:"id"`
-							Body              string       `json:"body"`
-							Outdated          bool         `json:"outdated"`
-							AuthorAssociation string       `json:"authorAssociation"`
-						} `json:"nodes"`
-					}{
-						Nodes: []struct {
-							CreatedAt         time.Time    `json:"createdAt"`
-							Author            graphQLActor `json:"author"`
-							ID                string       `json:"id"`
-							Body              string       `json:"body"`
-							Outdated          bool         `json:"outdated"`
-							AuthorAssociation string       `json:"authorAssociation"`
-						}{
-							{
-								ID:                "comment1",
-								Body:              "Should be Unlock() I think?",
-								CreatedAt:         time.Date(2025, 7, 18, 16, 46, 27, 0, time.UTC),
-								Outdated:          true,
-								Author:            graphQLActor{Login: "reviewer1"},
-								AuthorAssociation: "CONTRIBUTOR",
-							},
-							{
-								ID:                "comment2",
-								Body:              "eh yeah, absolutely! Good catch!",
-								CreatedAt:         time.Date(2025, 7, 18, 16, 50, 21, 0, time.UTC),
-								Outdated:          true,
-								Author:            graphQLActor{Login: "author1"},
-								AuthorAssociation: "OWNER",
-							},
-						},
-					},
-				},
-				{
-					IsOutdated: false,
-					IsResolved: false,
-					Comments: struct {
-						Nodes []struct {
-							CreatedAt         time.Time    `json:"createdAt"`
-							Author            graphQLActor `json:"author"`
-							ID                string       `json:"id"`
-							Body              string       `json:"body"`
-							Outdated          bool         `json:"outdated"`
-							AuthorAssociation string       `json:"authorAssociation"`
-						} `json:"nodes"`
-					}{
-						Nodes: []struct {
-							CreatedAt         time.Time    `json:"createdAt"`
-							Author            graphQLActor `json:"author"`
-							ID                string       `json:"id"`
-							Body              string       `json:"body"`
-							Outdated          bool         `json:"outdated"`
-							AuthorAssociation string       `json:"authorAssociation"`
-						}{
-							{
-								ID:                "comment3",
-								Body:              "This looks good to me",
-								CreatedAt:         time.Date(2025, 7, 19, 10, 0, 0, 0, time.UTC),
-								Outdated:          false,
-								Author:            graphQLActor{Login: "reviewer2"},
-								AuthorAssociation: "MEMBER",
-							},
-						},
-					},
-				},
-			},
+	resolved := graphQLReviewThread{IsOutdated: true, IsResolved: true}
+	resolved.Comments.Nodes = []graphQLReviewThreadComment{
+		{
+			ID:                "comment1",
+			Body:              "Should be Unlock() I think?",
+			CreatedAt:         time.Date(2025, 7, 18, 16, 46, 27, 0, time.UTC),
+			Outdated:          true,
+			Author:            graphQLActor{Login: "reviewer1"},
+			AuthorAssociation: "CONTRIBUTOR",
+		},
+		{
+			ID:                "comment2",
+			Body:              "eh yeah, absolutely! Good catch!",
+			CreatedAt:         time.Date(2025, 7, 18, 16, 50, 21, 0, time.UTC),
+			Outdated:          true,
+			Author:            graphQLActor{Login: "author1"},
+			AuthorAssociation: "OWNER",
+		},
+	}
+	unresolved := graphQLReviewThread{IsOutdated: false, IsResolved: false}
+	unresolved.Comments.Nodes = []graphQLReviewThreadComment{
+		{
+			ID:                "comment3",
+			Body:              "This looks good to me",
+			CreatedAt:         time.Date(2025, 7, 19, 10, 0, 0, 0, time.UTC),
+			Outdated:          false,
+			Author:            graphQLActor{Login: "reviewer2"},
+			AuthorAssociation: "MEMBER",
 		},
 	}
+	data := &graphQLPullRequestComplete{}
+	data.ReviewThreads.Nodes = []graphQLReviewThread{resolved, unresolved}
 
 	// Convert GraphQL data to events
 	events := client.convertGraphQLToEventsComplete(ctx, data, "testowner", "testrepo")
@@ -304,4 +232,160 @@ func TestConvertGraphQLReviewCommentsWithOutdated(t *testing.T) {
 	if reviewComments[2].Body != "This looks good to me" {
 		t.Errorf("Expected third comment body 'This looks good to me', got '%s'", reviewComments[2].Body)
 	}
+
+	// Verify thread resolution is carried onto each comment
+	if !reviewComments[0].ThreadResolved || !reviewComments[1].ThreadResolved {
+		t.Errorf("Expected comments in the resolved thread to have ThreadResolved set")
+	}
+	if reviewComments[2].ThreadResolved {
+		t.Errorf("Expected comment in the unresolved thread to NOT have ThreadResolved set")
+	}
+
+	want := ThreadSummary{Total: 2, Resolved: 1, Unresolved: 1, Outdated: 1}
+	if got := threadSummary(data, false); got == nil || *got != want {
+		t.Errorf("threadSummary() = %+v, want %+v", got, want)
+	}
+	if got := threadSummary(&graphQLPullRequestComplete{}, false); got != nil {
+		t.Errorf("threadSummary() with no threads = %+v, want nil", got)
+	}
+}
+
+func TestDeletedFileThreads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	const threads = `"reviewThreads": {"nodes": [
+		{"path": "kept.go", "comments": {"nodes": [{"body": "Why not a map?", "author": {"login": "alice"}}]}},
+		{"path": "moved.go", "comments": {"nodes": [{"body": "Can this be shorter?", "author": {"login": "bob"}}]}}
+	]}`
+	tests := []struct {
+		name        string
+		files       string
+		exclude     bool
+		wantSummary ThreadSummary
+		wantDeleted bool // For the comment on moved.go
+	}{
+		{
+			name:        "counted",
+			files:       `{"nodes": [{"path": "kept.go"}]}`,
+			wantSummary: ThreadSummary{Total: 2, Unresolved: 2, PathDeleted: 1},
+			wantDeleted: true,
+		},
+		{
+			name:        "excluded",
+			files:       `{"nodes": [{"path": "kept.go"}]}`,
+			exclude:     true,
+			wantSummary: ThreadSummary{Total: 1, Unresolved: 1, PathDeleted: 1},
+			wantDeleted: true,
+		},
+		{
+			name:        "files truncated",
+			files:       `{"pageInfo": {"hasNextPage": true}, "nodes": [{"path": "kept.go"}]}`,
+			exclude:     true,
+			wantSummary: ThreadSummary{Total: 2, Unresolved: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data graphQLPullRequestComplete
+			if err := json.Unmarshal([]byte(`{"files": `+tt.files+`, `+threads+`}`), &data); err != nil {
+				t.Fatal(err)
+			}
+			client := &Client{
+				logger:                    slog.Default(),
+				collaboratorsCache:        fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
+				github:                    newTestGitHubClient(&http.Client{}, "test-token", server.URL),
+				excludeDeletedFileThreads: tt.exclude,
+			}
+
+			if got := threadSummary(&data, tt.exclude); got == nil || *got != tt.wantSummary {
+				t.Errorf("threadSummary() = %+v, want %+v", got, tt.wantSummary)
+			}
+			for _, e := range client.convertGraphQLToEventsComplete(context.Background(), &data, "o", "r") {
+				if e.Kind != EventKindReviewComment {
+					continue
+				}
+				deleted := e.Actor == "bob"
+				if e.PathDeleted != (deleted && tt.wantDeleted) {
+					t.Errorf("%s's comment: PathDeleted = %v, want %v", e.Actor, e.PathDeleted, deleted && tt.wantDeleted)
+				}
+				// Both comments are questions, unless excluded for being on a deleted file
+				if wantQuestion := !(deleted && tt.wantDeleted && tt.exclude); e.Question != wantQuestion {
+					t.Errorf("%s's comment: Question = %v, want %v", e.Actor, e.Question, wantQuestion)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiredApprovals(t *testing.T) {
+	client := &Client{logger: slog.Default()}
+	approval := Event{Kind: EventKindReview, Actor: "alice", Outcome: "approved", WriteAccess: WriteAccessDefinitely}
+	tests := []struct {
+		name          string
+		rule          string
+		events        []Event
+		wantRequired  int
+		wantSatisfied bool
+	}{
+		{name: "no protection rule", rule: `null`, wantSatisfied: true},
+		{
+			name: "reviews not required", rule: `{"requiresApprovingReviews": false, "requiredApprovingReviewCount": 2}`,
+			wantSatisfied: true,
+		},
+		{
+			name: "too few approvals", rule: `{"requiresApprovingReviews": true, "requiredApprovingReviewCount": 2}`,
+			events: []Event{approval}, wantRequired: 2,
+		},
+		{
+			name: "enough approvals", rule: `{"requiresApprovingReviews": true, "requiredApprovingReviewCount": 1}`,
+			events: []Event{approval}, wantRequired: 1, wantSatisfied: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data graphQLPullRequestComplete
+			if err := json.Unmarshal([]byte(`{"baseRef": {"branchProtectionRule": `+tt.rule+`}}`), &data); err != nil {
+				t.Fatal(err)
+			}
+			pr := client.convertGraphQLToPullRequest(context.Background(), &data, "o", "r")
+			finalizePullRequest(&pr, tt.events, nil, "")
+			if pr.RequiredApprovals != tt.wantRequired || pr.ApprovalsSatisfied != tt.wantSatisfied {
+				t.Errorf("RequiredApprovals = %d, ApprovalsSatisfied = %v; want %d and %v",
+					pr.RequiredApprovals, pr.ApprovalsSatisfied, tt.wantRequired, tt.wantSatisfied)
+			}
+		})
+	}
+}
+
+func TestLinkedIssues(t *testing.T) {
+	client := &Client{logger: slog.Default()}
+	ctx := context.Background()
+	var data graphQLPullRequestComplete
+	if err := json.Unmarshal([]byte(`{"closingIssuesReferences": {"nodes": [
+		{"number": 7, "repository": {"nameWithOwner": "Owner/Repo"}},
+		{"number": 8, "repository": {"nameWithOwner": "other/repo"}},
+		{"number": 12, "repository": {"nameWithOwner": "owner/repo"}}
+	]}}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	pr := client.convertGraphQLToPullRequest(ctx, &data, "owner", "repo")
+	if want := []int{7, 12}; !slices.Equal(pr.ClosesIssues, want) {
+		t.Errorf("ClosesIssues = %v, want %v", pr.ClosesIssues, want)
+	}
+	if want := []string{"other/repo#8"}; !slices.Equal(pr.ClosesIssueRefs, want) {
+		t.Errorf("ClosesIssueRefs = %v, want %v", pr.ClosesIssueRefs, want)
+	}
+
+	event := client.parseGraphQLTimelineEvent(ctx, map[string]any{
+		"__typename": "CrossReferencedEvent",
+		"createdAt":  "2025-01-01T00:00:00Z",
+		"actor":      map[string]any{"login": "alice"},
+		"source":     map[string]any{"__typename": "Issue", "number": float64(42), "repository": map[string]any{"nameWithOwner": "other/repo"}},
+	}, "owner", "repo")
+	if event == nil || event.Target != "other/repo#42" {
+		t.Errorf("cross reference = %+v, want target other/repo#42", event)
+	}
 }
@@ -0,0 +1,96 @@
+package prx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GraphQL error types reported by GitHub. Errors without a type are reported as GraphQLErrorOther.
+const (
+	GraphQLErrorNotFound  = "NOT_FOUND"
+	GraphQLErrorForbidden = "FORBIDDEN"
+	GraphQLErrorInternal  = "INTERNAL"
+	GraphQLErrorOther     = "OTHER"
+)
+
+// graphQLResponseError is an error entry of a GraphQL response.
+type graphQLResponseError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"` // Field names and list indexes
+}
+
+// GraphQLError is a GraphQL error classified by type and by the pull request field it affects.
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"`    // Dotted field path, e.g. "repository.pullRequest.commits.nodes.0"
+	Section string `json:"section,omitempty"` // Top-level pull request field, e.g. "commits"; empty for the whole query
+}
+
+// GraphQLErrors are the errors returned by a GraphQL query.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Message
+		if e[i].Path != "" {
+			msgs[i] = e[i].Path + ": " + msgs[i]
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// HasType reports whether any of the errors has the given type.
+func (e GraphQLErrors) HasType(typ string) bool {
+	for i := range e {
+		if e[i].Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyGraphQLErrors converts response errors, attributing each to the pull request field in its path.
+func classifyGraphQLErrors(errs []graphQLResponseError) GraphQLErrors {
+	classified := make(GraphQLErrors, 0, len(errs))
+	for _, e := range errs {
+		ge := GraphQLError{Type: e.Type, Message: e.Message}
+		if ge.Type == "" {
+			ge.Type = GraphQLErrorOther
+		}
+		parts := make([]string, len(e.Path))
+		for i, p := range e.Path {
+			parts[i] = fmt.Sprint(p)
+		}
+		ge.Path = strings.Join(parts, ".")
+		if len(parts) > 2 && parts[0] == "repository" && parts[1] == "pullRequest" {
+			ge.Section = parts[2]
+		}
+		classified = append(classified, ge)
+	}
+	return classified
+}
+
+// WithPartialData sets whether a pull request is returned when the GraphQL query reports errors for
+// some of its fields. acceptable maps top-level pull request fields, such as "commits" or "reviewThreads",
+// to whether errors in them are tolerated; the "" key sets the default for other fields. By default, all
+// partial data is accepted as long as the pull request itself was returned. Tolerated errors are
+// listed in PullRequestData.FetchReport.
+func WithPartialData(acceptable map[string]bool) Option {
+	return func(c *Client) {
+		c.partialData = acceptable
+	}
+}
+
+// partialDataAcceptable reports whether a pull request with err in its data may be returned.
+func (c *Client) partialDataAcceptable(err *GraphQLError) bool {
+	if ok, found := c.partialData[err.Section]; found {
+		return ok
+	}
+	if ok, found := c.partialData[""]; found {
+		return ok
+	}
+	return true
+}
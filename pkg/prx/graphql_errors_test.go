@@ -0,0 +1,74 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClassifyGraphQLErrors(t *testing.T) {
+	errs := classifyGraphQLErrors([]graphQLResponseError{
+		{Type: "FORBIDDEN", Message: "Resource not accessible", Path: []any{"repository", "pullRequest", "baseRef", "branchProtectionRule"}},
+		{Type: "NOT_FOUND", Message: "Could not resolve", Path: []any{"repository"}},
+		{Message: "Something went wrong", Path: []any{"repository", "pullRequest", "commits", "nodes", float64(3)}},
+	})
+	want := GraphQLErrors{
+		{Type: GraphQLErrorForbidden, Message: "Resource not accessible", Path: "repository.pullRequest.baseRef.branchProtectionRule", Section: "baseRef"},
+		{Type: GraphQLErrorNotFound, Message: "Could not resolve", Path: "repository"},
+		{Type: GraphQLErrorOther, Message: "Something went wrong", Path: "repository.pullRequest.commits.nodes.3", Section: "commits"},
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("errs[%d] = %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+	if !errs.HasType(GraphQLErrorForbidden) || errs.HasType(GraphQLErrorInternal) {
+		t.Error("HasType() mismatch")
+	}
+}
+
+func TestClient_PartialData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"}}}},
+				"errors": [{"type": "FORBIDDEN", "message": "Resource not accessible by integration",
+					"path": ["repository", "pullRequest", "baseRef", "branchProtectionRule"]}]}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...Option) *Client {
+		client := NewClient("test-token", append([]Option{WithCacheStore(null.New[string, PullRequestData]())}, opts...)...)
+		client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+		return client
+	}
+
+	// Tolerated by default, and reported
+	data, err := newClient().PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	if !data.FetchReport.Failed(FetchSectionGraphQL) || data.FetchReport.Warnings[0].Target != "repository.pullRequest.baseRef.branchProtectionRule" {
+		t.Errorf("FetchReport = %+v, want tolerated GraphQL error", data.FetchReport)
+	}
+
+	// Rejected when the section's data is required
+	_, err = newClient(WithPartialData(map[string]bool{"baseRef": false})).PullRequest(context.Background(), "owner", "repo", 1)
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) || !gqlErrs.HasType(GraphQLErrorForbidden) {
+		t.Errorf("PullRequest() error = %v, want GraphQLErrors", err)
+	}
+
+	// Other sections stay acceptable when only a different one is strict
+	if _, err := newClient(WithPartialData(map[string]bool{"commits": false})).PullRequest(context.Background(), "owner", "repo", 1); err != nil {
+		t.Errorf("PullRequest() error: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -62,7 +63,12 @@ func TestClient_PullRequestWithReviews(t *testing.T) {
 										"state": "CHANGES_REQUESTED",
 										"submittedAt": "2023-01-02T11:00:00Z",
 										"body": "Please fix the tests",
-										"authorAssociation": "CONTRIBUTOR"
+										"authorAssociation": "CONTRIBUTOR",
+										"reactionGroups": [
+											{"content": "THUMBS_UP", "reactors": {"totalCount": 2}},
+											{"content": "THUMBS_DOWN", "reactors": {"totalCount": 1}},
+											{"content": "HOORAY", "reactors": {"totalCount": 0}}
+										]
 									},
 									{
 										"author": {"login": "reviewer3", "__typename": "User", "id": "R3"},
@@ -82,7 +88,8 @@ func TestClient_PullRequestWithReviews(t *testing.T) {
 												"author": {"login": "reviewer1", "__typename": "User", "id": "R1"},
 												"body": "Please update this",
 												"createdAt": "2023-01-02T09:00:00Z",
-												"authorAssociation": "MEMBER"
+												"authorAssociation": "MEMBER",
+												"reactionGroups": [{"content": "EYES", "reactors": {"totalCount": 1}}]
 											}
 										]
 									}
@@ -95,7 +102,8 @@ func TestClient_PullRequestWithReviews(t *testing.T) {
 										"author": {"login": "commenter1", "__typename": "User", "id": "C1"},
 										"body": "This is a comment",
 										"createdAt": "2023-01-02T08:00:00Z",
-										"authorAssociation": "COLLABORATOR"
+										"authorAssociation": "COLLABORATOR",
+										"reactionGroups": [{"content": "HOORAY", "reactors": {"totalCount": 3}}]
 									}
 								]
 							},
@@ -166,7 +174,7 @@ func TestClient_PullRequestWithReviews(t *testing.T) {
 	}
 
 	// Count different event types
-	eventTypes := make(map[string]int)
+	eventTypes := make(map[EventKind]int)
 	for i := range prData.Events {
 		eventTypes[prData.Events[i].Kind]++
 	}
@@ -180,6 +188,24 @@ func TestClient_PullRequestWithReviews(t *testing.T) {
 	if eventTypes["comment"] == 0 {
 		t.Error("Expected comment events")
 	}
+
+	// Reactions are counted by REST name, omitting unused ones
+	wantReactions := map[string]map[string]int{
+		"Please fix the tests": {"+1": 2, "-1": 1},
+		"Please update this":   {"eyes": 1},
+		"This is a comment":    {"hooray": 3},
+		"LGTM!":                nil,
+	}
+	for i := range prData.Events {
+		e := &prData.Events[i]
+		want, ok := wantReactions[e.Body]
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(e.Reactions, want) {
+			t.Errorf("%s %q: Reactions = %v, want %v", e.Kind, e.Body, e.Reactions, want)
+		}
+	}
 }
 
 func TestClient_PullRequestWithBots(t *testing.T) {
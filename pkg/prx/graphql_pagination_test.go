@@ -0,0 +1,209 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func commentPage(start, count int, hasNext bool) string {
+	nodes := make([]string, count)
+	for i := range count {
+		nodes[i] = fmt.Sprintf(`{"id": "c%d", "body": "comment %d", "createdAt": "2025-01-01T00:%02d:%02dZ", "author": {"login": "user"}}`,
+			start+i, start+i, (start+i)/60%60, (start+i)%60)
+	}
+	return fmt.Sprintf(`"comments": {"totalCount": 250, "pageInfo": {"hasNextPage": %t, "endCursor": "cursor%d"}, "nodes": [%s]}`,
+		hasNext, start+count, strings.Join(nodes, ","))
+}
+
+func TestClient_PullRequestPaginatesConnections(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var req struct {
+			Variables map[string]any `json:"variables"`
+			Query     string         `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+
+		cursor, ok := req.Variables["cursor"].(string)
+		if !ok {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				` + commentPage(0, 100, true) + `}}, ` + rateLimit + `}}`))
+			return
+		}
+		if !strings.Contains(req.Query, "...CommentFields") {
+			t.Errorf("follow-up query does not select comment fields: %s", req.Query)
+		}
+		cursors = append(cursors, cursor)
+		page := commentPage(100, 100, true)
+		if cursor == "cursor200" {
+			page = commentPage(200, 50, false)
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {` + page + `}}, ` + rateLimit + `}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+
+	if want := []string{"cursor100", "cursor200"}; strings.Join(cursors, ",") != strings.Join(want, ",") {
+		t.Errorf("follow-up cursors = %v, want %v", cursors, want)
+	}
+	comments := 0
+	for i := range data.Events {
+		if data.Events[i].Kind == EventKindComment {
+			comments++
+		}
+	}
+	if comments != 250 {
+		t.Errorf("got %d comment events, want 250", comments)
+	}
+}
+
+// threadPage returns a page of count comments of review thread id, numbered from start.
+func threadPage(id string, start, count int, hasNext bool) string {
+	nodes := make([]string, count)
+	for i := range count {
+		nodes[i] = fmt.Sprintf(`{"id": "%s-c%d", "body": "note %d", "createdAt": "2025-01-01T01:%02d:%02dZ", "author": {"login": "reviewer"}}`,
+			id, start+i, start+i, (start+i)/60%60, (start+i)%60)
+	}
+	return fmt.Sprintf(`"comments": {"pageInfo": {"hasNextPage": %t, "endCursor": "%s-cursor%d"}, "nodes": [%s]}`,
+		hasNext, id, start+count, strings.Join(nodes, ","))
+}
+
+func TestClient_PullRequestPaginatesReviewThreads(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var req struct {
+			Variables map[string]any `json:"variables"`
+			Query     string         `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+
+		cursor, _ := req.Variables["cursor"].(string)
+		switch {
+		case strings.Contains(req.Query, "node(id: $id)"):
+			requests = append(requests, fmt.Sprintf("%v@%s", req.Variables["id"], cursor))
+			page := threadPage("T2", 2, 3, false)
+			if req.Variables["id"] == "T1" {
+				page = threadPage("T1", 100, 30, false)
+			}
+			w.Write([]byte(`{"data": {"node": {` + page + `}, ` + rateLimit + `}}`))
+		case cursor != "":
+			requests = append(requests, "threads@"+cursor)
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"hasNextPage": false, "endCursor": "threads2"},
+				"nodes": [{"id": "T2", "path": "b.go", "isResolved": false, ` + threadPage("T2", 0, 2, true) + `}]
+			}}}, ` + rateLimit + `}}`))
+		default:
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"reviewThreads": {
+					"pageInfo": {"hasNextPage": true, "endCursor": "threads1"},
+					"nodes": [{"id": "T1", "path": "a.go", "isResolved": true, ` + threadPage("T1", 0, 100, true) + `}]
+				}}}, ` + rateLimit + `}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+
+	if want := []string{"threads@threads1", "T1@T1-cursor100", "T2@T2-cursor2"}; strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("follow-up requests = %v, want %v", requests, want)
+	}
+	resolved, open := 0, 0
+	for i := range data.Events {
+		if e := &data.Events[i]; e.Kind == EventKindReviewComment {
+			if e.ThreadResolved {
+				resolved++
+			} else {
+				open++
+			}
+		}
+	}
+	if resolved != 130 || open != 5 {
+		t.Errorf("got %d resolved and %d open review comments, want 130 and 5", resolved, open)
+	}
+	if s := data.PullRequest.ThreadSummary; s == nil || s.Total != 2 || s.Resolved != 1 {
+		t.Errorf("ThreadSummary = %+v, want 2 threads, 1 resolved", s)
+	}
+}
+
+func TestClient_PullRequestPaginatesClosingIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var req struct {
+			Variables map[string]any `json:"variables"`
+			Query     string         `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+
+		if _, ok := req.Variables["cursor"]; !ok {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"closingIssuesReferences": {"pageInfo": {"hasNextPage": true, "endCursor": "issues1"},
+					"nodes": [{"number": 4, "repository": {"nameWithOwner": "owner/repo"}}]}}}, ` + rateLimit + `}}`))
+			return
+		}
+		if !strings.Contains(req.Query, "...ClosingIssueFields") || req.Variables["cursor"] != "issues1" {
+			t.Errorf("unexpected follow-up query %v: %s", req.Variables, req.Query)
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+			"closingIssuesReferences": {"pageInfo": {"hasNextPage": false},
+				"nodes": [{"number": 9, "repository": {"nameWithOwner": "other/tracker"}}, {"number": 5, "repository": {"nameWithOwner": "owner/repo"}}]}}}, ` + rateLimit + `}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+	if got := fmt.Sprint(data.PullRequest.ClosesIssues); got != "[4 5]" {
+		t.Errorf("ClosesIssues = %s, want [4 5]", got)
+	}
+	if got := fmt.Sprint(data.PullRequest.ClosesIssueRefs); got != "[other/tracker#9]" {
+		t.Errorf("ClosesIssueRefs = %s, want [other/tracker#9]", got)
+	}
+}
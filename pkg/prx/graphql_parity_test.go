@@ -89,8 +89,8 @@ func comparePullRequestData(t *testing.T, rest, graphql *PullRequestData) {
 }
 
 // countEventsByType counts events by their Kind
-func countEventsByType(events []Event) map[string]int {
-	counts := make(map[string]int)
+func countEventsByType(events []Event) map[EventKind]int {
+	counts := make(map[EventKind]int)
 	for i := range events {
 		counts[events[i].Kind]++
 	}
@@ -268,6 +268,7 @@ func TestWriteAccessMapping(t *testing.T) {
 	c := &Client{
 		logger:             slog.Default(),
 		collaboratorsCache: fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
+		teamsCache:         fido.New[string, map[string]string](fido.TTL(collaboratorsCacheTTL)),
 		github:             newTestGitHubClient(&http.Client{}, "test-token", server.URL),
 	}
 
@@ -277,7 +278,7 @@ func TestWriteAccessMapping(t *testing.T) {
 	}{
 		{"OWNER", WriteAccessDefinitely},
 		{"COLLABORATOR", WriteAccessDefinitely},
-		{"MEMBER", WriteAccessLikely}, // Falls back to likely when collaborators API unavailable
+		{"MEMBER", WriteAccessLikely}, // Falls back to likely when collaborators and teams APIs are unavailable
 		{"CONTRIBUTOR", WriteAccessUnlikely},
 		{"NONE", WriteAccessUnlikely},
 		{"FIRST_TIME_CONTRIBUTOR", WriteAccessUnlikely},
@@ -307,6 +308,7 @@ func TestRequiredChecksExtraction(t *testing.T) {
 				RequiredStatusCheckContexts  []string `json:"requiredStatusCheckContexts"`
 				RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount"`
 				RequiresStatusChecks         bool     `json:"requiresStatusChecks"`
+				RequiresApprovingReviews     bool     `json:"requiresApprovingReviews"`
 			} `json:"branchProtectionRule"`
 			Target struct {
 				OID string `json:"oid"`
@@ -322,6 +324,7 @@ func TestRequiredChecksExtraction(t *testing.T) {
 				RequiredStatusCheckContexts  []string `json:"requiredStatusCheckContexts"`
 				RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount"`
 				RequiresStatusChecks         bool     `json:"requiresStatusChecks"`
+				RequiresApprovingReviews     bool     `json:"requiresApprovingReviews"`
 			}{
 				RequiredStatusCheckContexts: []string{"build", "test"}, // "test" is duplicate
 			},
@@ -1,9 +1,20 @@
 package prx
 
+import "fmt"
+
+// maxGraphQLPages caps the follow-up pages fetched per connection (100 items each).
+const maxGraphQLPages = 50
+
 // completeGraphQLQuery is the GraphQL query that fetches all PR data.
 // This replaces 13+ REST API calls with a single comprehensive query.
-const completeGraphQLQuery = `
-query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $reviewCursor: String, $timelineCursor: String, $commentCursor: String) {
+// Connections with more than 100 items are completed with connectionPageQuery.
+const completeGraphQLQuery = completeGraphQLQueryBody + commitFieldsFragment + reviewFieldsFragment +
+	reviewThreadFieldsFragment + commentFieldsFragment + timelineItemFieldsFragment + fileFieldsFragment +
+	closingIssueFieldsFragment
+
+// completeGraphQLQueryBody is the operation of completeGraphQLQuery, without fragment definitions.
+const completeGraphQLQueryBody = `
+query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $reviewCursor: String, $timelineCursor: String, $commentCursor: String, $reviewThreadCursor: String, $fileCursor: String, $closingIssueCursor: String) {
 	repository(owner: $owner, name: $repo) {
 		pullRequest(number: $number) {
 			id
@@ -16,6 +27,7 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 			closedAt
 			mergedAt
 			isDraft
+			locked
 			additions
 			deletions
 			changedFiles
@@ -34,6 +46,27 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 				}
 			}
 
+			baseRepository {
+				isArchived
+			}
+
+			mergeQueueEntry {
+				position
+				state
+				enqueuedAt
+			}
+
+			mergeQueue {
+				configuration {
+					mergeMethod
+					mergingStrategy
+					minimumEntriesToMerge
+					maximumEntriesToMerge
+					maximumEntriesToBuild
+					checkResponseTimeout
+				}
+			}
+
 			mergedBy {
 				__typename
 				login
@@ -154,21 +187,7 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 					endCursor
 				}
 				nodes {
-					commit {
-						oid
-						message
-						committedDate
-						author {
-							name
-							email
-							user {
-								login
-								... on User {
-									id
-								}
-							}
-						}
-					}
+					...CommitFields
 				}
 			}
 
@@ -178,48 +197,17 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 					endCursor
 				}
 				nodes {
-					id
-					state
-					body
-					createdAt
-					submittedAt
-					authorAssociation
-					author {
-						__typename
-						login
-						... on User {
-							id
-						}
-						... on Bot {
-							id
-						}
-					}
+					...ReviewFields
 				}
 			}
 
-			reviewThreads(first: 100) {
+			reviewThreads(first: 100, after: $reviewThreadCursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
 				nodes {
-					isResolved
-					isOutdated
-					comments(first: 100) {
-						nodes {
-							id
-							body
-							createdAt
-							outdated
-							authorAssociation
-							author {
-								__typename
-								login
-								... on User {
-									id
-								}
-								... on Bot {
-									id
-								}
-							}
-						}
-					}
+					...ReviewThreadFields
 				}
 			}
 
@@ -229,20 +217,7 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 					endCursor
 				}
 				nodes {
-					id
-					body
-					createdAt
-					authorAssociation
-					author {
-						__typename
-						login
-						... on User {
-							id
-						}
-						... on Bot {
-							id
-						}
-					}
+					...CommentFields
 				}
 			}
 
@@ -252,390 +227,27 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 					endCursor
 				}
 				nodes {
-					__typename
-					... on AssignedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-							... on User {
-								id
-							}
-							... on Bot {
-								id
-							}
-						}
-						assignee {
-							... on User {
-								login
-								id
-							}
-							... on Bot {
-								login
-								id
-							}
-						}
-					}
-					... on UnassignedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-						assignee {
-							... on User {
-								login
-								id
-							}
-						}
-					}
-					... on LabeledEvent {
-						id
-						createdAt
-						label {
-							name
-						}
-						actor {
-							__typename
-							login
-						}
-					}
-					... on UnlabeledEvent {
-						id
-						createdAt
-						label {
-							name
-						}
-						actor {
-							__typename
-							login
-						}
-					}
-					... on MilestonedEvent {
-						id
-						createdAt
-						milestoneTitle
-						actor {
-							__typename
-							login
-						}
-					}
-					... on DemilestonedEvent {
-						id
-						createdAt
-						milestoneTitle
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ReviewRequestedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-						requestedReviewer {
-							... on User {
-								login
-								id
-							}
-							... on Team {
-								name
-								id
-							}
-							... on Bot {
-								login
-								id
-							}
-						}
-					}
-					... on ReviewRequestRemovedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-						requestedReviewer {
-							... on User {
-								login
-							}
-							... on Team {
-								name
-							}
-						}
-					}
-					... on ClosedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ReopenedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on MergedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on MentionedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ReadyForReviewEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ConvertToDraftEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on AutoMergeEnabledEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on AutoMergeDisabledEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ReviewDismissedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-						dismissalMessage
-					}
-					... on HeadRefDeletedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on RenamedTitleEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-						previousTitle
-						currentTitle
-					}
-					... on BaseRefChangedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on BaseRefForcePushedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on HeadRefForcePushedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on HeadRefRestoredEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on LockedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on UnlockedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on AddedToMergeQueueEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on RemovedFromMergeQueueEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on AutomaticBaseChangeSucceededEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on AutomaticBaseChangeFailedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ConnectedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on DisconnectedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on CrossReferencedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on ReferencedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on SubscribedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on UnsubscribedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on DeployedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on DeploymentEnvironmentChangedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on PinnedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on UnpinnedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on TransferredEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
-					... on UserBlockedEvent {
-						id
-						createdAt
-						actor {
-							__typename
-							login
-						}
-					}
+					...TimelineItemFields
+				}
+			}
+
+			files(first: 100, after: $fileCursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...FileFields
+				}
+			}
+
+			closingIssuesReferences(first: 100, after: $closingIssueCursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...ClosingIssueFields
 				}
 			}
 		}
@@ -648,3 +260,612 @@ query($owner: String!, $repo: String!, $number: Int!, $prCursor: String, $review
 		limit
 	}
 }`
+
+// commitFieldsFragment selects the fields of each commit node.
+const commitFieldsFragment = `
+fragment CommitFields on PullRequestCommit {
+	commit {
+		oid
+		message
+		committedDate
+		author {
+			name
+			email
+			user {
+				login
+				... on User {
+					id
+				}
+			}
+		}
+	}
+}
+`
+
+// reviewFieldsFragment selects the fields of each review node.
+const reviewFieldsFragment = `
+fragment ReviewFields on PullRequestReview {
+	id
+	state
+	body
+	createdAt
+	submittedAt
+	authorAssociation
+	commit {
+		oid
+	}
+	author {
+		__typename
+		login
+		... on User {
+			id
+		}
+		... on Bot {
+			id
+		}
+	}
+	reactionGroups {
+		content
+		reactors {
+			totalCount
+		}
+	}
+}
+`
+
+// reviewThreadFieldsFragment selects the fields of each review thread node, with the first page of
+// its comments. Threads with more comments are completed with reviewThreadCommentsPageQuery.
+const reviewThreadFieldsFragment = `
+fragment ReviewThreadFields on PullRequestReviewThread {
+	id
+	isResolved
+	isOutdated
+	path
+	comments(first: 100) {
+		pageInfo {
+			hasNextPage
+			endCursor
+		}
+		nodes {
+			...ReviewThreadCommentFields
+		}
+	}
+}
+` + reviewThreadCommentFieldsFragment
+
+// reviewThreadCommentFieldsFragment selects the fields of each review comment node in a thread.
+const reviewThreadCommentFieldsFragment = `
+fragment ReviewThreadCommentFields on PullRequestReviewComment {
+	id
+	body
+	createdAt
+	outdated
+	authorAssociation
+	author {
+		__typename
+		login
+		... on User {
+			id
+		}
+		... on Bot {
+			id
+		}
+	}
+	reactionGroups {
+		content
+		reactors {
+			totalCount
+		}
+	}
+}
+`
+
+// reviewThreadCommentsPageQuery fetches one page of a review thread's comments, by the thread's node ID.
+const reviewThreadCommentsPageQuery = `
+query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on PullRequestReviewThread {
+			comments(first: 100, after: $cursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...ReviewThreadCommentFields
+				}
+			}
+		}
+	}
+
+	rateLimit {
+		cost
+		remaining
+		resetAt
+		limit
+	}
+}
+` + reviewThreadCommentFieldsFragment
+
+// commentFieldsFragment selects the fields of each issue comment node.
+const commentFieldsFragment = `
+fragment CommentFields on IssueComment {
+	id
+	body
+	createdAt
+	authorAssociation
+	author {
+		__typename
+		login
+		... on User {
+			id
+		}
+		... on Bot {
+			id
+		}
+	}
+	reactionGroups {
+		content
+		reactors {
+			totalCount
+		}
+	}
+}
+`
+
+// fileFieldsFragment selects the fields of each changed file node.
+const fileFieldsFragment = `
+fragment FileFields on PullRequestChangedFile {
+	path
+}
+`
+
+// closingIssueFieldsFragment selects the fields of each issue that merging the pull request will close.
+const closingIssueFieldsFragment = `
+fragment ClosingIssueFields on Issue {
+	number
+	repository {
+		nameWithOwner
+	}
+}
+`
+
+// timelineItemFieldsFragment selects the fields of each timeline item node.
+const timelineItemFieldsFragment = `
+fragment TimelineItemFields on PullRequestTimelineItems {
+	__typename
+	... on AssignedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+			... on User {
+				id
+			}
+			... on Bot {
+				id
+			}
+		}
+		assignee {
+			... on User {
+				login
+				id
+			}
+			... on Bot {
+				login
+				id
+			}
+		}
+	}
+	... on UnassignedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		assignee {
+			... on User {
+				login
+				id
+			}
+		}
+	}
+	... on LabeledEvent {
+		id
+		createdAt
+		label {
+			name
+		}
+		actor {
+			__typename
+			login
+		}
+	}
+	... on UnlabeledEvent {
+		id
+		createdAt
+		label {
+			name
+		}
+		actor {
+			__typename
+			login
+		}
+	}
+	... on MilestonedEvent {
+		id
+		createdAt
+		milestoneTitle
+		actor {
+			__typename
+			login
+		}
+	}
+	... on DemilestonedEvent {
+		id
+		createdAt
+		milestoneTitle
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ReviewRequestedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		requestedReviewer {
+			... on User {
+				login
+				id
+			}
+			... on Team {
+				name
+				id
+			}
+			... on Bot {
+				login
+				id
+			}
+		}
+	}
+	... on ReviewRequestRemovedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		requestedReviewer {
+			... on User {
+				login
+			}
+			... on Team {
+				name
+			}
+		}
+	}
+	... on ClosedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ReopenedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on MergedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on MentionedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ReadyForReviewEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ConvertToDraftEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on AutoMergeEnabledEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on AutoMergeDisabledEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ReviewDismissedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		dismissalMessage
+	}
+	... on HeadRefDeletedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on RenamedTitleEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		previousTitle
+		currentTitle
+	}
+	... on BaseRefChangedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on BaseRefForcePushedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on HeadRefForcePushedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		beforeCommit {
+			oid
+		}
+		afterCommit {
+			oid
+		}
+	}
+	... on HeadRefRestoredEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on LockedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on UnlockedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on AddedToMergeQueueEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on RemovedFromMergeQueueEvent {
+		id
+		createdAt
+		reason
+		actor {
+			__typename
+			login
+		}
+	}
+	... on AutomaticBaseChangeSucceededEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on AutomaticBaseChangeFailedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on ConnectedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on DisconnectedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on CrossReferencedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+		source {
+			__typename
+			... on Issue {
+				number
+				repository {
+					nameWithOwner
+				}
+			}
+			... on PullRequest {
+				number
+				repository {
+					nameWithOwner
+				}
+			}
+		}
+	}
+	... on ReferencedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on SubscribedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on UnsubscribedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on DeployedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on DeploymentEnvironmentChangedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on PinnedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on UnpinnedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on TransferredEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+	... on UserBlockedEvent {
+		id
+		createdAt
+		actor {
+			__typename
+			login
+		}
+	}
+}
+`
+
+// connectionPageQuery builds a query for one page of a pull request connection
+// (e.g. "commits"), selecting nodes with the named fragment.
+func connectionPageQuery(connection, fragmentName, fragment string) string {
+	return fmt.Sprintf(`
+query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+	repository(owner: $owner, name: $repo) {
+		pullRequest(number: $number) {
+			%s(first: 100, after: $cursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					...%s
+				}
+			}
+		}
+	}
+
+	rateLimit {
+		cost
+		remaining
+		resetAt
+		limit
+	}
+}
+`, connection, fragmentName) + fragment
+}
@@ -0,0 +1,508 @@
+package prx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// schemaFixture is GitHub's GraphQL schema, as introspection JSON trimmed to the types our queries
+// use. Refresh it with scripts/update-github-schema.sh.
+const schemaFixture = "testdata/github_schema.json"
+
+// TestGraphQLQuerySchema checks the queries we send against GitHub's schema, so that renamed or
+// deprecated fields are caught here rather than as nulls in production.
+func TestGraphQLQuerySchema(t *testing.T) {
+	schema := loadGraphQLSchema(t)
+
+	blame, _ := blameQuery([]string{"main.go", "docs/README.md"})
+	queries := map[string]string{
+		"complete":                     completeGraphQLQuery,
+		"complete without merge queue": adaptGraphQLQuery(completeGraphQLQuery, Capabilities{Rulesets: true}),
+		"commits page":                 connectionPageQuery("commits", "CommitFields", commitFieldsFragment),
+		"reviews page":                 connectionPageQuery("reviews", "ReviewFields", reviewFieldsFragment),
+		"comments page":                connectionPageQuery("comments", "CommentFields", commentFieldsFragment),
+		"reviewThreads page":           connectionPageQuery("reviewThreads", "ReviewThreadFields", reviewThreadFieldsFragment),
+		"review thread comments page":  reviewThreadCommentsPageQuery,
+		"blame":                        blame,
+		"timelineItems page":           connectionPageQuery("timelineItems", "TimelineItemFields", timelineItemFieldsFragment),
+		"files page":                   connectionPageQuery("files", "FileFields", fileFieldsFragment),
+		"closingIssuesReferences page": connectionPageQuery("closingIssuesReferences", "ClosingIssueFields", closingIssueFieldsFragment),
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			for _, err := range schema.validate(query) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGraphQLSchemaValidate(t *testing.T) {
+	schema := loadGraphQLSchema(t)
+	for _, f := range schema.types["PullRequest"].Fields {
+		if f.Name == "locked" {
+			f.IsDeprecated = true
+			f.DeprecationReason = "Use `activeLockReason` instead."
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string // Empty if the query is valid
+	}{
+		{
+			name:  "valid",
+			query: `query($n: Int!) { repository(owner: "o", name: "r") { pullRequest(number: $n) { t: title author { __typename login ... on User { id } } } } }`,
+		},
+		{
+			name:    "unknown field",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { titel } } }`,
+			wantErr: "repository.pullRequest.titel: PullRequest has no field titel",
+		},
+		{
+			name:    "unknown argument",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { labels(top: 5) { nodes { name } } } } }`,
+			wantErr: "repository.pullRequest.labels: no argument top",
+		},
+		{
+			name:    "deprecated field",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { locked } } }`,
+			wantErr: "repository.pullRequest.locked: deprecated: Use `activeLockReason` instead.",
+		},
+		{
+			name:    "impossible inline fragment",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { author { ... on Team { name } } } } }`,
+			wantErr: "repository.pullRequest.author: fragment on Team can never apply to Actor",
+		},
+		{
+			name:    "selection on a scalar",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { title { length } } } }`,
+			wantErr: "repository.pullRequest.title: String is a leaf type and takes no selections",
+		},
+		{
+			name:    "no selection on an object",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { author } } }`,
+			wantErr: "repository.pullRequest.author: Actor needs a selection of fields",
+		},
+		{
+			name:    "field on a union",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { reviewRequests(first: 1) { nodes { requestedReviewer { login } } } } } }`,
+			wantErr: "repository.pullRequest.reviewRequests.nodes.requestedReviewer.login: RequestedReviewer has no field login",
+		},
+		{
+			name:    "unknown fragment",
+			query:   `{ repository(owner: "o", name: "r") { pullRequest(number: 1) { ...Missing } } }`,
+			wantErr: "repository.pullRequest: unknown fragment Missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.validate(tt.query)
+			if tt.wantErr == "" {
+				for _, err := range errs {
+					t.Errorf("validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Error() != tt.wantErr {
+				t.Errorf("validate() = %v, want [%s]", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+// graphQLSchema is the part of an introspection result that queries are checked against.
+type graphQLSchema struct {
+	types     map[string]*schemaType
+	queryType string
+}
+
+type schemaType struct {
+	Kind          string         `json:"kind"`
+	Name          string         `json:"name"`
+	Fields        []*schemaField `json:"fields"`
+	PossibleTypes []schemaRef    `json:"possibleTypes"`
+}
+
+type schemaField struct {
+	Type              schemaRef `json:"type"`
+	Name              string    `json:"name"`
+	DeprecationReason string    `json:"deprecationReason"`
+	Args              []struct {
+		Name string `json:"name"`
+	} `json:"args"`
+	IsDeprecated bool `json:"isDeprecated"`
+}
+
+type schemaRef struct {
+	OfType *schemaRef `json:"ofType"`
+	Kind   string     `json:"kind"`
+	Name   string     `json:"name"`
+}
+
+// named returns the name of the type with any list and non-null wrappers removed.
+func (r schemaRef) named() string {
+	for r.OfType != nil {
+		r = *r.OfType
+	}
+	return r.Name
+}
+
+func loadGraphQLSchema(t *testing.T) *graphQLSchema {
+	t.Helper()
+	b, err := os.ReadFile(schemaFixture)
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+	var introspection struct {
+		Data struct {
+			Schema struct {
+				QueryType struct {
+					Name string `json:"name"`
+				} `json:"queryType"`
+				Types []*schemaType `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(b, &introspection); err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	s := &graphQLSchema{types: make(map[string]*schemaType), queryType: introspection.Data.Schema.QueryType.Name}
+	for _, typ := range introspection.Data.Schema.Types {
+		s.types[typ.Name] = typ
+	}
+	return s
+}
+
+// possible returns the object types a type can be at runtime.
+func (s *graphQLSchema) possible(name string) map[string]bool {
+	typ := s.types[name]
+	if typ == nil {
+		return nil
+	}
+	if typ.Kind == "OBJECT" {
+		return map[string]bool{name: true}
+	}
+	names := make(map[string]bool, len(typ.PossibleTypes))
+	for _, p := range typ.PossibleTypes {
+		names[p.Name] = true
+	}
+	return names
+}
+
+// validate checks a query document against the schema: that its fields and arguments exist and
+// aren't deprecated, that fragments can apply where they are spread, and that exactly the
+// non-leaf fields have selections.
+func (s *graphQLSchema) validate(query string) []error {
+	doc, err := parseGraphQLDocument(query)
+	if err != nil {
+		return []error{err}
+	}
+	v := &schemaValidator{schema: s, fragments: doc.fragments}
+	for _, op := range doc.operations {
+		v.selections(s.queryType, op, "")
+	}
+	return v.errs
+}
+
+type schemaValidator struct {
+	schema    *graphQLSchema
+	fragments map[string]gqlFragment
+	errs      []error
+}
+
+func (v *schemaValidator) errorf(path, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *schemaValidator) selections(parent string, sels []gqlSelection, path string) {
+	for _, sel := range sels {
+		switch {
+		case sel.spread != "":
+			frag, ok := v.fragments[sel.spread]
+			if !ok {
+				v.errorf(path, "unknown fragment %s", sel.spread)
+				continue
+			}
+			if v.applies(frag.on, parent, path) {
+				v.selections(frag.on, frag.selections, path)
+			}
+		case sel.on != "":
+			if v.applies(sel.on, parent, path) {
+				v.selections(sel.on, sel.selections, path)
+			}
+		default:
+			v.field(parent, sel, path)
+		}
+	}
+}
+
+// applies reports whether a fragment on one type can apply to another, reporting why not.
+func (v *schemaValidator) applies(on, parent, path string) bool {
+	if v.schema.types[on] == nil {
+		v.errorf(path, "fragment on unknown type %s", on)
+		return false
+	}
+	for name := range v.schema.possible(on) {
+		if v.schema.possible(parent)[name] {
+			return true
+		}
+	}
+	v.errorf(path, "fragment on %s can never apply to %s", on, parent)
+	return false
+}
+
+func (v *schemaValidator) field(parent string, sel gqlSelection, path string) {
+	if path != "" {
+		path += "."
+	}
+	path += sel.field
+	if sel.field == "__typename" {
+		return
+	}
+
+	var field *schemaField
+	for _, f := range v.schema.types[parent].Fields {
+		if f.Name == sel.field {
+			field = f
+		}
+	}
+	if field == nil {
+		v.errorf(path, "%s has no field %s", parent, sel.field)
+		return
+	}
+	if field.IsDeprecated {
+		v.errorf(path, "deprecated: %s", field.DeprecationReason)
+	}
+	for _, arg := range sel.args {
+		found := false
+		for _, a := range field.Args {
+			found = found || a.Name == arg
+		}
+		if !found {
+			v.errorf(path, "no argument %s", arg)
+		}
+	}
+
+	typ := v.schema.types[field.Type.named()]
+	if typ == nil {
+		v.errorf(path, "unknown type %s", field.Type.named())
+		return
+	}
+	leaf := typ.Kind == "SCALAR" || typ.Kind == "ENUM"
+	switch {
+	case leaf && sel.selections != nil:
+		v.errorf(path, "%s is a leaf type and takes no selections", typ.Name)
+	case !leaf && sel.selections == nil:
+		v.errorf(path, "%s needs a selection of fields", typ.Name)
+	case !leaf:
+		v.selections(typ.Name, sel.selections, path)
+	default:
+	}
+}
+
+// gqlDocument is a parsed GraphQL document: the selections of its operations, and its fragments.
+type gqlDocument struct {
+	fragments  map[string]gqlFragment
+	operations [][]gqlSelection
+}
+
+type gqlFragment struct {
+	on         string
+	selections []gqlSelection
+}
+
+// gqlSelection is a field, an inline fragment (on), or a fragment spread.
+type gqlSelection struct {
+	field      string
+	on         string
+	spread     string
+	args       []string
+	selections []gqlSelection // Nil for fields without a selection set
+}
+
+// gqlParser parses the subset of GraphQL that our queries use. Variable definitions, argument
+// values, and directives are skipped.
+type gqlParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseGraphQLDocument(query string) (*gqlDocument, error) {
+	p := &gqlParser{tokens: tokenizeGraphQL(query)}
+	doc := &gqlDocument{fragments: make(map[string]gqlFragment)}
+	for p.peek() != "" {
+		switch p.peek() {
+		case "{":
+		case "query":
+			p.next()
+			for p.peek() != "{" && p.peek() != "" {
+				if p.peek() == "(" {
+					p.skipBalanced("(", ")")
+					continue
+				}
+				p.next()
+			}
+		case "fragment":
+			p.next()
+			name := p.next()
+			if on := p.next(); on != "on" {
+				return nil, fmt.Errorf("fragment %s: got %q, want on", name, on)
+			}
+			typ := p.next()
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, fmt.Errorf("fragment %s: %w", name, err)
+			}
+			doc.fragments[name] = gqlFragment{on: typ, selections: sels}
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected %q at top level", p.peek())
+		}
+		sels, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		doc.operations = append(doc.operations, sels)
+	}
+	return doc, nil
+}
+
+func (p *gqlParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *gqlParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// skipBalanced skips from an open token to its matching close token.
+func (p *gqlParser) skipBalanced(open, closing string) {
+	depth := 0
+	for tok := p.next(); tok != ""; tok = p.next() {
+		switch tok {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return
+			}
+		default:
+		}
+	}
+}
+
+func (p *gqlParser) selectionSet() ([]gqlSelection, error) {
+	if tok := p.next(); tok != "{" {
+		return nil, fmt.Errorf("got %q, want {", tok)
+	}
+	sels := []gqlSelection{}
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, fmt.Errorf("unterminated selection set")
+		}
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	p.next()
+	return sels, nil
+}
+
+func (p *gqlParser) selection() (gqlSelection, error) {
+	var sel gqlSelection
+	if p.peek() == "..." {
+		p.next()
+		if p.peek() != "on" {
+			sel.spread = p.next()
+			return sel, nil
+		}
+		p.next()
+		sel.on = p.next()
+		var err error
+		sel.selections, err = p.selectionSet()
+		return sel, err
+	}
+
+	sel.field = p.next()
+	if p.peek() == ":" { // Alias
+		p.next()
+		sel.field = p.next()
+	}
+	if p.peek() == "(" {
+		start := p.pos
+		p.skipBalanced("(", ")")
+		args := p.tokens[start+1 : p.pos-1]
+		for i := 0; i+1 < len(args); i++ {
+			if args[i+1] == ":" && (i == 0 || args[i-1] != "$") {
+				sel.args = append(sel.args, args[i])
+			}
+		}
+	}
+	for p.peek() == "@" { // Directives
+		p.next()
+		p.next()
+		if p.peek() == "(" {
+			p.skipBalanced("(", ")")
+		}
+	}
+	if p.peek() == "{" {
+		var err error
+		sel.selections, err = p.selectionSet()
+		return sel, err
+	}
+	return sel, nil
+}
+
+// tokenizeGraphQL splits a GraphQL document into names, punctuators, and literals, dropping
+// whitespace, commas, and comments.
+func tokenizeGraphQL(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, s[i:min(j+1, len(s))])
+			i = j + 1
+		case c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] == '.' || s[j] >= '0' && s[j] <= '9' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z') {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
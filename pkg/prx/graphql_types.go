@@ -20,9 +20,7 @@ type graphQLCompleteResponse struct {
 			Limit     int       `json:"limit"`
 		} `json:"rateLimit"`
 	} `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
+	Errors []graphQLResponseError `json:"errors"`
 }
 
 // graphQLPullRequestComplete includes all PR fields from the GraphQL response.
@@ -51,6 +49,28 @@ type graphQLPullRequestComplete struct {
 	ChangedFiles int `json:"changedFiles"`
 
 	IsDraft bool `json:"isDraft"`
+	Locked  bool `json:"locked"`
+
+	BaseRepository struct {
+		IsArchived bool `json:"isArchived"`
+	} `json:"baseRepository"`
+
+	MergeQueueEntry *struct {
+		EnqueuedAt *time.Time `json:"enqueuedAt"`
+		State      string     `json:"state"`
+		Position   int        `json:"position"`
+	} `json:"mergeQueueEntry"`
+
+	MergeQueue *struct {
+		Configuration *struct {
+			MergeMethod           string `json:"mergeMethod"`
+			MergingStrategy       string `json:"mergingStrategy"`
+			MinimumEntriesToMerge int    `json:"minimumEntriesToMerge"`
+			MaximumEntriesToMerge int    `json:"maximumEntriesToMerge"`
+			MaximumEntriesToBuild int    `json:"maximumEntriesToBuild"`
+			CheckResponseTimeout  int    `json:"checkResponseTimeout"`
+		} `json:"configuration"`
+	} `json:"mergeQueue"`
 
 	Assignees struct {
 		Nodes []graphQLActor `json:"nodes"`
@@ -62,6 +82,16 @@ type graphQLPullRequestComplete struct {
 		} `json:"nodes"`
 	} `json:"labels"`
 
+	ClosingIssuesReferences struct {
+		PageInfo graphQLPageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+			Number int `json:"number"`
+		} `json:"nodes"`
+	} `json:"closingIssuesReferences"`
+
 	ReviewRequests struct {
 		Nodes []struct {
 			RequestedReviewer struct {
@@ -79,6 +109,7 @@ type graphQLPullRequestComplete struct {
 			RequiredStatusCheckContexts  []string `json:"requiredStatusCheckContexts"`
 			RequiredApprovingReviewCount int      `json:"requiredApprovingReviewCount"`
 			RequiresStatusChecks         bool     `json:"requiresStatusChecks"`
+			RequiresApprovingReviews     bool     `json:"requiresApprovingReviews"`
 		} `json:"branchProtectionRule"`
 		Target struct {
 			OID string `json:"oid"`
@@ -118,41 +149,34 @@ type graphQLPullRequestComplete struct {
 	Reviews struct {
 		PageInfo graphQLPageInfo `json:"pageInfo"`
 		Nodes    []struct {
-			ID                string       `json:"id"`
-			State             string       `json:"state"`
-			Body              string       `json:"body"`
-			CreatedAt         time.Time    `json:"createdAt"`
-			SubmittedAt       *time.Time   `json:"submittedAt"`
-			AuthorAssociation string       `json:"authorAssociation"`
-			Author            graphQLActor `json:"author"`
+			ID                string                 `json:"id"`
+			State             string                 `json:"state"`
+			Body              string                 `json:"body"`
+			CreatedAt         time.Time              `json:"createdAt"`
+			SubmittedAt       *time.Time             `json:"submittedAt"`
+			AuthorAssociation string                 `json:"authorAssociation"`
+			Author            graphQLActor           `json:"author"`
+			ReactionGroups    []graphQLReactionGroup `json:"reactionGroups"`
+			Commit            *struct {
+				OID string `json:"oid"`
+			} `json:"commit"` // Null once garbage collected
 		} `json:"nodes"`
 	} `json:"reviews"`
 
 	ReviewThreads struct {
-		Nodes []struct {
-			Comments struct {
-				Nodes []struct {
-					CreatedAt         time.Time    `json:"createdAt"`
-					Author            graphQLActor `json:"author"`
-					ID                string       `json:"id"`
-					Body              string       `json:"body"`
-					Outdated          bool         `json:"outdated"`
-					AuthorAssociation string       `json:"authorAssociation"`
-				} `json:"nodes"`
-			} `json:"comments"`
-			IsResolved bool `json:"isResolved"`
-			IsOutdated bool `json:"isOutdated"`
-		} `json:"nodes"`
+		PageInfo graphQLPageInfo       `json:"pageInfo"`
+		Nodes    []graphQLReviewThread `json:"nodes"`
 	} `json:"reviewThreads"`
 
 	Comments struct {
 		PageInfo graphQLPageInfo `json:"pageInfo"`
 		Nodes    []struct {
-			ID                string       `json:"id"`
-			Body              string       `json:"body"`
-			CreatedAt         time.Time    `json:"createdAt"`
-			AuthorAssociation string       `json:"authorAssociation"`
-			Author            graphQLActor `json:"author"`
+			ID                string                 `json:"id"`
+			Body              string                 `json:"body"`
+			CreatedAt         time.Time              `json:"createdAt"`
+			AuthorAssociation string                 `json:"authorAssociation"`
+			Author            graphQLActor           `json:"author"`
+			ReactionGroups    []graphQLReactionGroup `json:"reactionGroups"`
 		} `json:"nodes"`
 	} `json:"comments"`
 
@@ -160,13 +184,23 @@ type graphQLPullRequestComplete struct {
 		PageInfo graphQLPageInfo  `json:"pageInfo"`
 		Nodes    []map[string]any `json:"nodes"`
 	} `json:"timelineItems"`
+
+	Files struct {
+		PageInfo graphQLPageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Path string `json:"path"`
+		} `json:"nodes"`
+	} `json:"files"`
+
+	raw    graphQLRawPayloads // Populated only with WithRawPayloads
+	errors GraphQLErrors      // Tolerated errors for fields of the first page
 }
 
 // graphQLActor represents any GitHub actor (User, Bot, Organization).
 type graphQLActor struct {
 	Login string `json:"login"`
 	ID    string `json:"id,omitempty"`
-	Type  string `json:"type,omitempty"`
+	Type  string `json:"__typename,omitempty"`
 }
 
 // isBot determines if an actor is a bot.
@@ -226,7 +260,70 @@ type graphQLStatusCheckNode struct {
 	DatabaseID  int    `json:"databaseId,omitempty"`
 }
 
+// graphQLReactionGroup is the count of one reaction type on a comment or review.
+type graphQLReactionGroup struct {
+	Content  string `json:"content"`
+	Reactors struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"reactors"`
+}
+
+// reactionNames maps GraphQL reaction content to the names used by the REST API.
+var reactionNames = map[string]string{
+	"THUMBS_UP":   "+1",
+	"THUMBS_DOWN": "-1",
+	"LAUGH":       "laugh",
+	"HOORAY":      "hooray",
+	"CONFUSED":    "confused",
+	"HEART":       "heart",
+	"ROCKET":      "rocket",
+	"EYES":        "eyes",
+}
+
+// reactionCounts converts reaction groups to counts keyed by REST reaction name, omitting
+// reactions nobody used. It returns nil if there are no reactions.
+func reactionCounts(groups []graphQLReactionGroup) map[string]int {
+	var counts map[string]int
+	for _, g := range groups {
+		if g.Reactors.TotalCount == 0 {
+			continue
+		}
+		name, ok := reactionNames[g.Content]
+		if !ok {
+			name = strings.ToLower(g.Content)
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[name] = g.Reactors.TotalCount
+	}
+	return counts
+}
+
 // graphQLPageInfo for pagination.
+// graphQLReviewThread is a review thread with a page of its comments.
+type graphQLReviewThread struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	Comments struct {
+		PageInfo graphQLPageInfo              `json:"pageInfo"`
+		Nodes    []graphQLReviewThreadComment `json:"nodes"`
+	} `json:"comments"`
+	IsResolved bool `json:"isResolved"`
+	IsOutdated bool `json:"isOutdated"`
+}
+
+// graphQLReviewThreadComment is a review comment in a review thread.
+type graphQLReviewThreadComment struct {
+	CreatedAt         time.Time              `json:"createdAt"`
+	Author            graphQLActor           `json:"author"`
+	ID                string                 `json:"id"`
+	Body              string                 `json:"body"`
+	Outdated          bool                   `json:"outdated"`
+	AuthorAssociation string                 `json:"authorAssociation"`
+	ReactionGroups    []graphQLReactionGroup `json:"reactionGroups"`
+}
+
 type graphQLPageInfo struct {
 	EndCursor   string `json:"endCursor"`
 	HasNextPage bool   `json:"hasNextPage"`
@@ -0,0 +1,80 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pull request lifecycle mutations, each taking the pull request's node ID.
+const (
+	markReadyForReviewMutation = `
+mutation($pullRequestId: ID!) {
+	markPullRequestReadyForReview(input: {pullRequestId: $pullRequestId}) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+	convertToDraftMutation = `
+mutation($pullRequestId: ID!) {
+	convertPullRequestToDraft(input: {pullRequestId: $pullRequestId}) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+	closePullRequestMutation = `
+mutation($pullRequestId: ID!) {
+	closePullRequest(input: {pullRequestId: $pullRequestId}) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+	reopenPullRequestMutation = `
+mutation($pullRequestId: ID!) {
+	reopenPullRequest(input: {pullRequestId: $pullRequestId}) {
+		pullRequest {
+			id
+		}
+	}
+}`
+)
+
+// MarkReadyForReview takes a draft pull request out of draft, notifying its reviewers.
+func (c *Client) MarkReadyForReview(ctx context.Context, owner, repo string, prNumber int) error {
+	return c.updatePullRequest(ctx, owner, repo, prNumber, "marking ready for review", markReadyForReviewMutation)
+}
+
+// ConvertToDraft converts a pull request to a draft, which can't be merged until it's marked ready.
+func (c *Client) ConvertToDraft(ctx context.Context, owner, repo string, prNumber int) error {
+	return c.updatePullRequest(ctx, owner, repo, prNumber, "converting to draft", convertToDraftMutation)
+}
+
+// ClosePullRequest closes a pull request without merging it.
+func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, prNumber int) error {
+	return c.updatePullRequest(ctx, owner, repo, prNumber, "closing", closePullRequestMutation)
+}
+
+// ReopenPullRequest reopens a closed, unmerged pull request.
+func (c *Client) ReopenPullRequest(ctx context.Context, owner, repo string, prNumber int) error {
+	return c.updatePullRequest(ctx, owner, repo, prNumber, "reopening", reopenPullRequestMutation)
+}
+
+// updatePullRequest runs a mutation taking the pull request's node ID and drops it from the cache.
+// Pull requests in archived repositories are refused with ErrRepoArchived before anything is changed;
+// locked ones aren't, since locking only restricts comments.
+func (c *Client) updatePullRequest(ctx context.Context, owner, repo string, prNumber int, action, mutation string) error {
+	id, err := c.pullRequestID(ctx, owner, repo, prNumber, action)
+	if err != nil {
+		return err
+	}
+	if err := c.mutateGraphQL(ctx, mutation, map[string]any{"pullRequestId": id}); err != nil {
+		return fmt.Errorf("%s PR %s/%s#%d: %w", action, owner, repo, prNumber, err)
+	}
+	c.invalidatePullRequest(ctx, owner, repo, prNumber)
+	return nil
+}
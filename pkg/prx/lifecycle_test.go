@@ -0,0 +1,88 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequestLifecycle(t *testing.T) {
+	var mutations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !strings.Contains(req.Query, "mutation") {
+			switch req.Variables["number"] {
+			case float64(404):
+				w.Write([]byte(`{"data": {"repository": {"pullRequest": null}}}`))
+				return
+			case float64(8):
+				w.Write([]byte(`{"data": {"repository": {"pullRequest": {"id": "PR_node", "baseRepository": {"isArchived": true}}}}}`))
+				return
+			case float64(9):
+				w.Write([]byte(`{"data": {"repository": {"pullRequest": {"id": "PR_node", "locked": true}}}}`))
+				return
+			}
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"id": "PR_node"}}}}`))
+			return
+		}
+		if req.Variables["pullRequestId"] != "PR_node" {
+			t.Errorf("pullRequestId = %v", req.Variables["pullRequestId"])
+		}
+		name, _, _ := strings.Cut(strings.TrimSpace(strings.SplitN(req.Query, "{", 3)[1]), "(")
+		mutations = append(mutations, name)
+		if name == "reopenPullRequest" {
+			w.Write([]byte(`{"errors": [{"message": "Could not open the pull request."}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	for _, fn := range []func(context.Context, string, string, int) error{
+		client.ConvertToDraft, client.MarkReadyForReview, client.ClosePullRequest,
+	} {
+		if err := fn(ctx, "owner", "repo", 7); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []string{"convertPullRequestToDraft", "markPullRequestReadyForReview", "closePullRequest"}
+	if strings.Join(mutations, ",") != strings.Join(want, ",") {
+		t.Errorf("mutations = %v, want %v", mutations, want)
+	}
+
+	err := client.ReopenPullRequest(ctx, "owner", "repo", 7)
+	if err == nil || !strings.Contains(err.Error(), "reopening PR owner/repo#7: Could not open") {
+		t.Errorf("ReopenPullRequest() error = %v", err)
+	}
+	if err := client.ClosePullRequest(ctx, "owner", "repo", 404); err == nil {
+		t.Error("expected an error for a missing pull request")
+	}
+	if err := client.ClosePullRequest(ctx, "owner", "repo", 8); !errors.Is(err, ErrRepoArchived) {
+		t.Errorf("ClosePullRequest() on an archived repository error = %v, want ErrRepoArchived", err)
+	}
+	if len(mutations) != 4 {
+		t.Errorf("mutations = %v; want none sent for archived repositories", mutations)
+	}
+	// Locking only restricts comments, so state changes still go through
+	if err := client.ClosePullRequest(ctx, "owner", "repo", 9); err != nil {
+		t.Errorf("ClosePullRequest() on a locked conversation error = %v, want nil", err)
+	}
+	if err := client.MarkReadyForReview(ctx, "owner", "repo", 9); err != nil {
+		t.Errorf("MarkReadyForReview() on a locked conversation error = %v, want nil", err)
+	}
+}
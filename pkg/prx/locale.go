@@ -0,0 +1,143 @@
+package prx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale formats numbers, durations, and relative times for people, in human-facing output such as
+// digests. A nil *Locale formats in English.
+type Locale struct {
+	// Tag is the locale's language, such as "en" or "de".
+	Tag     string
+	group   string // Thousands separator
+	justNow string
+	// Formats of durations and of how long ago, by unit (minutes, hours, days), for one and for more
+	durations [3][2]string
+	ago       [3][2]string
+}
+
+// Units of localized durations.
+const (
+	localeMinutes = iota
+	localeHours
+	localeDays
+)
+
+// locales are the supported locales, by language.
+var locales = map[string]*Locale{
+	"en": {
+		Tag: "en", group: ",", justNow: "just now",
+		durations: [3][2]string{{"%s minute", "%s minutes"}, {"%s hour", "%s hours"}, {"%s day", "%s days"}},
+		ago:       [3][2]string{{"%s minute ago", "%s minutes ago"}, {"%s hour ago", "%s hours ago"}, {"%s day ago", "%s days ago"}},
+	},
+	"de": {
+		Tag: "de", group: ".", justNow: "gerade eben",
+		durations: [3][2]string{{"%s Minute", "%s Minuten"}, {"%s Stunde", "%s Stunden"}, {"%s Tag", "%s Tage"}},
+		ago:       [3][2]string{{"vor %s Minute", "vor %s Minuten"}, {"vor %s Stunde", "vor %s Stunden"}, {"vor %s Tag", "vor %s Tagen"}},
+	},
+	"es": {
+		Tag: "es", group: ".", justNow: "ahora mismo",
+		durations: [3][2]string{{"%s minuto", "%s minutos"}, {"%s hora", "%s horas"}, {"%s día", "%s días"}},
+		ago:       [3][2]string{{"hace %s minuto", "hace %s minutos"}, {"hace %s hora", "hace %s horas"}, {"hace %s día", "hace %s días"}},
+	},
+	"fr": {
+		Tag: "fr", group: "\u202f", justNow: "à l’instant",
+		durations: [3][2]string{{"%s minute", "%s minutes"}, {"%s heure", "%s heures"}, {"%s jour", "%s jours"}},
+		ago:       [3][2]string{{"il y a %s minute", "il y a %s minutes"}, {"il y a %s heure", "il y a %s heures"}, {"il y a %s jour", "il y a %s jours"}},
+	},
+	"ja": {
+		Tag: "ja", group: ",", justNow: "たった今",
+		durations: [3][2]string{{"%s分", "%s分"}, {"%s時間", "%s時間"}, {"%s日", "%s日"}},
+		ago:       [3][2]string{{"%s分前", "%s分前"}, {"%s時間前", "%s時間前"}, {"%s日前", "%s日前"}},
+	},
+	"pt": {
+		Tag: "pt", group: ".", justNow: "agora mesmo",
+		durations: [3][2]string{{"%s minuto", "%s minutos"}, {"%s hora", "%s horas"}, {"%s dia", "%s dias"}},
+		ago:       [3][2]string{{"há %s minuto", "há %s minutos"}, {"há %s hora", "há %s horas"}, {"há %s dia", "há %s dias"}},
+	},
+}
+
+// LookupLocale returns the locale for a language tag such as "de", "pt-BR", or, as in the LANG
+// environment variable, "fr_FR.UTF-8". Only the language is used. It reports false if the language
+// isn't supported: English, German, Spanish, French, Japanese, or Portuguese.
+func LookupLocale(tag string) (*Locale, bool) {
+	lang, _, _ := strings.Cut(tag, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	l, ok := locales[strings.ToLower(lang)]
+	return l, ok
+}
+
+// Number formats n with the locale's thousands separator, e.g. "12,345" or "12.345".
+func (l *Locale) Number(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteString(l.orDefault().group)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Duration formats d in its largest whole unit, e.g. "3 days" or "5 hours". Durations under a minute
+// are "just now".
+func (l *Locale) Duration(d time.Duration) string {
+	l = l.orDefault()
+	unit, n, ok := localeUnit(d)
+	if !ok {
+		return l.justNow
+	}
+	return l.plural(l.durations[unit], n)
+}
+
+// Ago formats how long ago something happened, e.g. "3 days ago" or "vor 3 Tagen".
+func (l *Locale) Ago(d time.Duration) string {
+	l = l.orDefault()
+	unit, n, ok := localeUnit(d)
+	if !ok {
+		return l.justNow
+	}
+	return l.plural(l.ago[unit], n)
+}
+
+func (l *Locale) orDefault() *Locale {
+	if l == nil {
+		return locales["en"]
+	}
+	return l
+}
+
+// plural formats n with the singular or plural of a format.
+func (l *Locale) plural(formats [2]string, n int) string {
+	if n == 1 {
+		return fmt.Sprintf(formats[0], l.Number(n))
+	}
+	return fmt.Sprintf(formats[1], l.Number(n))
+}
+
+// localeUnit returns the largest whole unit of d and how many of it there are, or false if d is under a minute.
+func localeUnit(d time.Duration) (unit, n int, ok bool) {
+	switch {
+	case d >= 24*time.Hour:
+		return localeDays, int(d / (24 * time.Hour)), true
+	case d >= time.Hour:
+		return localeHours, int(d / time.Hour), true
+	case d >= time.Minute:
+		return localeMinutes, int(d / time.Minute), true
+	default:
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,43 @@
+package prx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocale(t *testing.T) {
+	de, ok := LookupLocale("de_DE.UTF-8")
+	if !ok || de.Tag != "de" {
+		t.Fatalf("LookupLocale(de_DE.UTF-8) = %+v, %v; want German", de, ok)
+	}
+	fr, _ := LookupLocale("fr-CA")
+	if _, ok := LookupLocale("xx"); ok {
+		t.Error("LookupLocale(xx) found a locale")
+	}
+
+	var en *Locale
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{en.Number(1234567), "1,234,567"},
+		{en.Number(-1234), "-1,234"},
+		{en.Number(999), "999"},
+		{de.Number(12345), "12.345"},
+		{fr.Number(12345), "12 345"},
+		{en.Duration(3*24*time.Hour + time.Hour), "3 days"},
+		{en.Duration(time.Hour), "1 hour"},
+		{en.Duration(30 * time.Second), "just now"},
+		{en.Ago(5 * time.Minute), "5 minutes ago"},
+		{de.Duration(3 * 24 * time.Hour), "3 Tage"},
+		{de.Ago(3 * 24 * time.Hour), "vor 3 Tagen"},
+		{de.Ago(24 * time.Hour), "vor 1 Tag"},
+		{fr.Ago(2 * time.Hour), "il y a 2 heures"},
+		{en.Ago(1500 * 24 * time.Hour), "1,500 days ago"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MergeMethod is how a pull request's commits are added to its base branch.
+type MergeMethod string
+
+// Merge methods accepted by Merge and EnableAutoMerge.
+const (
+	MergeMethodMerge  MergeMethod = "MERGE"
+	MergeMethodSquash MergeMethod = "SQUASH"
+	MergeMethodRebase MergeMethod = "REBASE"
+)
+
+// ErrNotMergeable is returned by Merge and EnableAutoMerge when prx's analysis of the pull request
+// shows it can't be merged; the error message gives the reason.
+var ErrNotMergeable = errors.New("pull request is not mergeable")
+
+const mergePullRequestMutation = `
+mutation($input: MergePullRequestInput!) {
+	mergePullRequest(input: $input) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+const enableAutoMergeMutation = `
+mutation($input: EnablePullRequestAutoMergeInput!) {
+	enablePullRequestAutoMerge(input: $input) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+const disableAutoMergeMutation = `
+mutation($pullRequestId: ID!) {
+	disablePullRequestAutoMerge(input: {pullRequestId: $pullRequestId}) {
+		pullRequest {
+			id
+		}
+	}
+}`
+
+const pullRequestIDQuery = `
+query($owner: String!, $repo: String!, $number: Int!) {
+	repository(owner: $owner, name: $repo) {
+		pullRequest(number: $number) {
+			id
+			baseRepository {
+				isArchived
+			}
+		}
+	}
+}`
+
+// Merge merges a pull request. It first fetches the pull request and refuses, with ErrNotMergeable,
+// if it's closed, a draft, conflicting, blocked by branch protection, or in a deployment freeze
+// (see WithFreezeWindows). The merge only succeeds if the head is still the commit that was analyzed.
+func (c *Client) Merge(ctx context.Context, owner, repo string, prNumber int, method MergeMethod) error {
+	pr, err := c.mergeCandidate(ctx, owner, repo, prNumber, method)
+	if err != nil {
+		return err
+	}
+	if reason := mergeBlocker(pr, false); reason != "" {
+		return fmt.Errorf("merging PR %s/%s#%d: %w: %s", owner, repo, prNumber, ErrNotMergeable, reason)
+	}
+	return c.merge(ctx, owner, repo, pr, method)
+}
+
+// merge merges pr, provided its head hasn't moved.
+func (c *Client) merge(ctx context.Context, owner, repo string, pr *PullRequest, method MergeMethod) error {
+	input := map[string]any{"pullRequestId": pr.NodeID, "mergeMethod": string(method), "expectedHeadOid": pr.HeadSHA}
+	if err := c.mutateGraphQL(ctx, mergePullRequestMutation, map[string]any{"input": input}); err != nil {
+		return fmt.Errorf("merging PR %s/%s#%d: %w", owner, repo, pr.Number, err)
+	}
+	c.invalidatePullRequest(ctx, owner, repo, pr.Number)
+	return nil
+}
+
+// EnableAutoMerge makes GitHub merge a pull request once its requirements, such as required checks
+// and reviews, are met. GitHub refuses auto-merge for pull requests that can already be merged, so
+// those are merged immediately instead, and merged is true. Closed, draft, conflicting, and frozen
+// pull requests are refused with ErrNotMergeable. The repository must allow auto-merge.
+func (c *Client) EnableAutoMerge(ctx context.Context, owner, repo string, prNumber int, method MergeMethod) (merged bool, err error) {
+	pr, err := c.mergeCandidate(ctx, owner, repo, prNumber, method)
+	if err != nil {
+		return false, err
+	}
+	if reason := mergeBlocker(pr, true); reason != "" {
+		return false, fmt.Errorf("enabling auto-merge on PR %s/%s#%d: %w: %s", owner, repo, prNumber, ErrNotMergeable, reason)
+	}
+	if mergeBlocker(pr, false) == "" {
+		if err := c.merge(ctx, owner, repo, pr, method); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	input := map[string]any{"pullRequestId": pr.NodeID, "mergeMethod": string(method), "expectedHeadOid": pr.HeadSHA}
+	if err := c.mutateGraphQL(ctx, enableAutoMergeMutation, map[string]any{"input": input}); err != nil {
+		return false, fmt.Errorf("enabling auto-merge on PR %s/%s#%d: %w", owner, repo, prNumber, err)
+	}
+	return false, nil
+}
+
+// DisableAutoMerge cancels auto-merge on a pull request.
+func (c *Client) DisableAutoMerge(ctx context.Context, owner, repo string, prNumber int) error {
+	return c.updatePullRequest(ctx, owner, repo, prNumber, "disabling auto-merge on", disableAutoMergeMutation)
+}
+
+// mergeCandidate validates method and fetches the pull request's current state.
+func (c *Client) mergeCandidate(ctx context.Context, owner, repo string, prNumber int, method MergeMethod) (*PullRequest, error) {
+	if !slices.Contains([]MergeMethod{MergeMethodMerge, MergeMethodSquash, MergeMethodRebase}, method) {
+		return nil, fmt.Errorf("invalid merge method %q", method)
+	}
+	data, err := c.PullRequest(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	if data.PullRequest.NodeID == "" {
+		return nil, fmt.Errorf("pull request %s/%s#%d has no node ID", owner, repo, prNumber)
+	}
+	return &data.PullRequest, nil
+}
+
+// MergeBlocker returns why the pull request can't be merged now, based on its state, merge state,
+// and any deployment freeze, or "" if it can be.
+func (pr *PullRequest) MergeBlocker() string {
+	return mergeBlocker(pr, false)
+}
+
+// mergeBlocker returns why pr can't be merged now, or, if pending is set, why it can't be merged
+// once pending checks and reviews complete. It returns "" if nothing blocks the merge.
+func mergeBlocker(pr *PullRequest, pending bool) string {
+	switch {
+	case pr.Merged:
+		return "already merged"
+	case pr.State != "open":
+		return "pull request is " + pr.State
+	case pr.Draft:
+		return "pull request is a draft"
+	case pr.RepoArchived:
+		return "repository is archived"
+	case pr.FrozenUntil != nil:
+		return pr.MergeableStateDescription
+	case pr.MergeableState == "dirty":
+		return "merge conflicts with the base branch"
+	case pending:
+		return ""
+	case pr.MergeableState == "" || pr.MergeableState == "unknown":
+		return "GitHub is still computing mergeability"
+	case pr.MergeableState != "clean" && pr.MergeableState != "has_hooks", pr.Mergeable != nil && !*pr.Mergeable:
+		if pr.MergeableStateDescription != "" {
+			return pr.MergeableStateDescription
+		}
+		return "merge state is " + pr.MergeableState
+	default:
+		return ""
+	}
+}
+
+// pullRequestID fetches a pull request's GraphQL node ID for a change described by action. Pull requests
+// in archived repositories can't be changed, and are refused with ErrRepoArchived. Locked conversations
+// only restrict comments, so they don't stop a change to the pull request's state.
+func (c *Client) pullRequestID(ctx context.Context, owner, repo string, prNumber int, action string) (string, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					ID             string `json:"id"`
+					BaseRepository struct {
+						IsArchived bool `json:"isArchived"`
+					} `json:"baseRepository"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	variables := map[string]any{"owner": owner, "repo": repo, "number": prNumber}
+	if err := c.github.GraphQL(ctx, pullRequestIDQuery, variables, &resp); err != nil {
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %w", owner, repo, prNumber, err)
+	}
+	if len(resp.Errors) > 0 {
+		return "", fmt.Errorf("fetching PR %s/%s#%d: %s", owner, repo, prNumber, resp.Errors[0].Message)
+	}
+	pr := resp.Data.Repository.PullRequest
+	switch {
+	case pr == nil:
+		return "", fmt.Errorf("pull request %s/%s#%d not found", owner, repo, prNumber)
+	case pr.BaseRepository.IsArchived:
+		return "", fmt.Errorf("%s PR %s/%s#%d: %w", action, owner, repo, prNumber, ErrRepoArchived)
+	default:
+		return pr.ID, nil
+	}
+}
+
+// invalidatePullRequest drops a pull request from the cache after changing it.
+func (c *Client) invalidatePullRequest(ctx context.Context, owner, repo string, prNumber int) {
+	if c.prCache == nil {
+		return
+	}
+	if err := c.prCache.Delete(ctx, c.prCacheKey(owner, repo, prNumber)); err != nil {
+		c.logger.WarnContext(ctx, "failed to delete cache entry after update", "owner", owner, "repo", repo, "pr", prNumber, "error", err)
+	}
+}
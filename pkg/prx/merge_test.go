@@ -0,0 +1,137 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestMergeBlocker(t *testing.T) {
+	frozen := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	notMergeable := false
+	tests := []struct {
+		name        string
+		pr          PullRequest
+		want        string
+		wantPending string
+	}{
+		{"clean", PullRequest{State: "open", MergeableState: "clean"}, "", ""},
+		{"merged", PullRequest{State: "closed", Merged: true}, "already merged", "already merged"},
+		{"closed", PullRequest{State: "closed"}, "pull request is closed", "pull request is closed"},
+		{"draft", PullRequest{State: "open", Draft: true, MergeableState: "clean"}, "pull request is a draft", "pull request is a draft"},
+		{"conflicts", PullRequest{State: "open", MergeableState: "dirty"}, "merge conflicts with the base branch", "merge conflicts with the base branch"},
+		{
+			"frozen",
+			PullRequest{State: "open", MergeableState: "clean", FrozenUntil: &frozen, Mergeable: &notMergeable, MergeableStateDescription: "PR is blocked by deployment freeze"},
+			"PR is blocked by deployment freeze", "PR is blocked by deployment freeze",
+		},
+		{
+			"blocked",
+			PullRequest{State: "open", MergeableState: "blocked", MergeableStateDescription: "PR requires approval"},
+			"PR requires approval", "",
+		},
+		{"behind", PullRequest{State: "open", MergeableState: "behind"}, "merge state is behind", ""},
+		{"unknown", PullRequest{State: "open", MergeableState: "unknown"}, "GitHub is still computing mergeability", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pr.MergeBlocker(); got != tt.want {
+				t.Errorf("MergeBlocker() = %q, want %q", got, tt.want)
+			}
+			if got := mergeBlocker(&tt.pr, true); got != tt.wantPending {
+				t.Errorf("mergeBlocker(pending) = %q, want %q", got, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestClient_Merge(t *testing.T) {
+	mergeState := "CLEAN"
+	var mutations []string
+	var inputs []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			if strings.Contains(r.URL.Path, "/check-runs") {
+				w.Write([]byte(`{"check_runs": []}`))
+				return
+			}
+			w.Write([]byte(`[]`))
+			return
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, m := range []string{"mergePullRequest", "enablePullRequestAutoMerge", "disablePullRequestAutoMerge"} {
+			if strings.Contains(req.Query, m+"(") {
+				mutations = append(mutations, m)
+				input, _ := req.Variables["input"].(map[string]any)
+				inputs = append(inputs, input)
+				w.Write([]byte(`{"data": {}}`))
+				return
+			}
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+			"id": "PR_node", "number": 7, "title": "Add parser", "state": "OPEN",
+			"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-02T00:00:00Z",
+			"mergeable": "MERGEABLE", "mergeStateStatus": "` + mergeState + `", "authorAssociation": "OWNER",
+			"author": {"login": "alice", "__typename": "User"},
+			"headRef": {"name": "feature", "target": {"oid": "abc123"}},
+			"reviews": {"pageInfo": {"hasNextPage": false}, "nodes": []},
+			"comments": {"pageInfo": {"hasNextPage": false}, "nodes": []},
+			"timelineItems": {"pageInfo": {"hasNextPage": false}, "nodes": []}
+		}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	if err := client.Merge(ctx, "owner", "repo", 7, MergeMethodSquash); err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+	if len(inputs) != 1 || inputs[0]["pullRequestId"] != "PR_node" || inputs[0]["mergeMethod"] != "SQUASH" ||
+		inputs[0]["expectedHeadOid"] != "abc123" {
+		t.Errorf("merge input = %v", inputs)
+	}
+
+	// A clean pull request is merged instead of queued for auto-merge
+	merged, err := client.EnableAutoMerge(ctx, "owner", "repo", 7, MergeMethodMerge)
+	if err != nil || !merged || mutations[len(mutations)-1] != "mergePullRequest" {
+		t.Errorf("EnableAutoMerge() = %v, %v; mutations %v", merged, err, mutations)
+	}
+
+	mergeState = "BLOCKED"
+	if err := client.Merge(ctx, "owner", "repo", 7, MergeMethodSquash); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("Merge() error = %v, want ErrNotMergeable", err)
+	}
+	merged, err = client.EnableAutoMerge(ctx, "owner", "repo", 7, MergeMethodRebase)
+	if err != nil || merged || mutations[len(mutations)-1] != "enablePullRequestAutoMerge" {
+		t.Errorf("EnableAutoMerge() = %v, %v; mutations %v", merged, err, mutations)
+	}
+
+	mergeState = "DIRTY"
+	if _, err := client.EnableAutoMerge(ctx, "owner", "repo", 7, MergeMethodMerge); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("EnableAutoMerge() error = %v, want ErrNotMergeable", err)
+	}
+	if err := client.Merge(ctx, "owner", "repo", 7, "FAST_FORWARD"); err == nil {
+		t.Error("expected an invalid merge method to be rejected")
+	}
+
+	if err := client.DisableAutoMerge(ctx, "owner", "repo", 7); err != nil {
+		t.Fatalf("DisableAutoMerge() error: %v", err)
+	}
+	if mutations[len(mutations)-1] != "disablePullRequestAutoMerge" {
+		t.Errorf("mutations = %v", mutations)
+	}
+}
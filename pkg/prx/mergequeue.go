@@ -0,0 +1,99 @@
+package prx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergeQueueState is where a pull request stands with its base branch's merge queue. GitHub reports
+// pull requests waiting for a queue as blocked, so without it they look stuck.
+type MergeQueueState struct {
+	EnqueuedAt    *time.Time               `json:"enqueued_at,omitempty"`
+	Configuration *MergeQueueConfiguration `json:"configuration,omitempty"` // Nil if the base branch has no merge queue
+	// State of the queue entry: "queued", "awaiting_checks", "mergeable", "unmergeable", or "locked"
+	State         string `json:"state,omitempty"`
+	RemovedReason string `json:"removed_reason,omitempty"` // Why the pull request last left the queue, if it did
+	Position      int    `json:"position,omitempty"`       // 1 is next to merge; 0 if unknown or not queued
+	InQueue       bool   `json:"in_queue"`
+}
+
+// MergeQueueConfiguration is how a merge queue groups and merges pull requests.
+type MergeQueueConfiguration struct {
+	MergeMethod     string `json:"merge_method,omitempty"`     // "merge", "squash", or "rebase"
+	MergingStrategy string `json:"merging_strategy,omitempty"` // "allgreen" or "headgreen"
+	// Limits on the pull requests built and merged together
+	MinimumEntriesToMerge int `json:"minimum_entries_to_merge,omitempty"`
+	MaximumEntriesToMerge int `json:"maximum_entries_to_merge,omitempty"`
+	MaximumEntriesToBuild int `json:"maximum_entries_to_build,omitempty"`
+	// Minutes to wait for required checks before removing a pull request from the queue
+	CheckResponseTimeout int `json:"check_response_timeout,omitempty"`
+}
+
+// graphQLMergeQueueState builds the merge queue state GraphQL reports, or nil if the pull request
+// has no queue entry and its base branch no queue.
+func graphQLMergeQueueState(data *graphQLPullRequestComplete) *MergeQueueState {
+	if data.MergeQueueEntry == nil && (data.MergeQueue == nil || data.MergeQueue.Configuration == nil) {
+		return nil
+	}
+	state := &MergeQueueState{}
+	if entry := data.MergeQueueEntry; entry != nil {
+		state.InQueue = true
+		state.Position = entry.Position
+		state.State = strings.ToLower(entry.State)
+		state.EnqueuedAt = entry.EnqueuedAt
+	}
+	if data.MergeQueue != nil {
+		if config := data.MergeQueue.Configuration; config != nil {
+			state.Configuration = &MergeQueueConfiguration{
+				MergeMethod:           strings.ToLower(config.MergeMethod),
+				MergingStrategy:       strings.ToLower(config.MergingStrategy),
+				MinimumEntriesToMerge: config.MinimumEntriesToMerge,
+				MaximumEntriesToMerge: config.MaximumEntriesToMerge,
+				MaximumEntriesToBuild: config.MaximumEntriesToBuild,
+				CheckResponseTimeout:  config.CheckResponseTimeout,
+			}
+		}
+	}
+	return state
+}
+
+// setMergeQueueState brings the pull request's merge queue state up to date with the latest queue
+// event among events, which are in chronological order, such as one applied from a webhook.
+// Pull requests leave the queue when merged or closed.
+func setMergeQueueState(pr *PullRequest, events []Event) {
+	var last *Event
+	for i := range events {
+		if k := events[i].Kind; k == EventKindAddedToMergeQueue || k == EventKindRemovedFromMergeQueue {
+			last = &events[i]
+		}
+	}
+	if last == nil {
+		return
+	}
+	if pr.MergeQueue == nil {
+		pr.MergeQueue = &MergeQueueState{}
+	}
+	q := pr.MergeQueue
+	if last.Kind == EventKindRemovedFromMergeQueue || pr.Merged || pr.State != "open" {
+		q.InQueue, q.Position, q.State, q.EnqueuedAt = false, 0, "", nil
+		if last.Kind == EventKindRemovedFromMergeQueue {
+			q.RemovedReason = last.Body
+		}
+		return
+	}
+	q.InQueue, q.RemovedReason = true, ""
+	if q.EnqueuedAt == nil {
+		// Added since GitHub last reported the queue entry, so its position is unknown
+		at := last.Timestamp
+		q.EnqueuedAt, q.State = &at, "queued"
+	}
+}
+
+// queuedReason describes where a queued pull request is in its merge queue.
+func (q *MergeQueueState) queuedReason() string {
+	if q.Position > 0 {
+		return fmt.Sprintf("PR is at position %d in the merge queue", q.Position)
+	}
+	return "PR is in the merge queue"
+}
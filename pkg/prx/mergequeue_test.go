@@ -0,0 +1,57 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestMergeQueueState(t *testing.T) {
+	client := &Client{logger: slog.Default()}
+	ctx := context.Background()
+	var data graphQLPullRequestComplete
+	if err := json.Unmarshal([]byte(`{
+		"state": "OPEN",
+		"mergeQueueEntry": {"position": 2, "state": "AWAITING_CHECKS", "enqueuedAt": "2025-01-01T10:00:00Z"},
+		"mergeQueue": {"configuration": {"mergeMethod": "SQUASH", "mergingStrategy": "ALLGREEN", "maximumEntriesToMerge": 5}}
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	pr := client.convertGraphQLToPullRequest(ctx, &data, "o", "r")
+	q := pr.MergeQueue
+	if q == nil || !q.InQueue || q.Position != 2 || q.State != "awaiting_checks" || q.Configuration == nil ||
+		q.Configuration.MergeMethod != "squash" || q.Configuration.MaximumEntriesToMerge != 5 {
+		t.Fatalf("MergeQueue = %+v, want queued at position 2 in a squashing queue", q)
+	}
+
+	removed := client.parseGraphQLTimelineEvent(ctx, map[string]any{
+		"__typename": "RemovedFromMergeQueueEvent",
+		"createdAt":  "2025-01-01T11:00:00Z",
+		"actor":      map[string]any{"login": "github-merge-queue"},
+		"reason":     "MERGE_CONFLICT",
+	}, "o", "r")
+	if removed == nil || removed.Body != "MERGE_CONFLICT" {
+		t.Fatalf("parseGraphQLTimelineEvent() = %+v, want the removal reason in the body", removed)
+	}
+	added := Event{Kind: EventKindAddedToMergeQueue, Timestamp: removed.Timestamp.Add(time.Hour)}
+
+	setMergeQueueState(&pr, []Event{*removed})
+	if q.InQueue || q.Position != 0 || q.RemovedReason != "MERGE_CONFLICT" || q.Configuration == nil {
+		t.Errorf("after removal, MergeQueue = %+v, want out of the queue with its reason", q)
+	}
+	setMergeQueueState(&pr, []Event{*removed, added})
+	if !q.InQueue || q.RemovedReason != "" || q.EnqueuedAt == nil || !q.EnqueuedAt.Equal(added.Timestamp) {
+		t.Errorf("after adding again, MergeQueue = %+v, want queued since %v", q, added.Timestamp)
+	}
+	pr.State, pr.Merged = "closed", true
+	setMergeQueueState(&pr, []Event{added})
+	if q.InQueue {
+		t.Error("merged pull request is still in the merge queue")
+	}
+
+	if state := graphQLMergeQueueState(&graphQLPullRequestComplete{}); state != nil {
+		t.Errorf("without a queue, MergeQueue = %+v, want nil", state)
+	}
+}
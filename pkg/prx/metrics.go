@@ -0,0 +1,65 @@
+package prx
+
+import (
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// APIs reported to a Recorder.
+const (
+	MetricsAPIREST    = "rest"
+	MetricsAPIGraphQL = "graphql"
+)
+
+// Caches reported to a Recorder.
+const (
+	MetricsCachePullRequest = "pull_request"
+	MetricsCacheCheckRuns   = "check_runs"
+)
+
+// Recorder receives operational metrics from a Client, e.g. to export them to a monitoring system.
+// Implementations must be safe for concurrent use; see the metrics package for a Prometheus one.
+type Recorder interface {
+	// APICall records a GitHub API request. Status is the HTTP status code, or 0 if no response was received.
+	APICall(api string, status int, elapsed time.Duration)
+	// GraphQLCost records the rate limit points consumed by a GraphQL query.
+	GraphQLCost(points int)
+	// CacheLookup records a lookup in one of the client's caches.
+	CacheLookup(cache string, hit bool)
+	// Retry records a request retried after a response with the given status code.
+	Retry(status int)
+	// Fetch records a pull request fetch, whether from cache or from GitHub, and its error.
+	Fetch(elapsed time.Duration, err error)
+}
+
+// WithMetrics reports API calls, GraphQL cost, cache hits and misses, retries, and fetch latency to r.
+func WithMetrics(r Recorder) Option {
+	return func(c *Client) {
+		c.metrics = r
+	}
+}
+
+// configureMetrics connects the GitHub client's hooks to the metrics recorder.
+func (c *Client) configureMetrics() {
+	if c.metrics == nil {
+		return
+	}
+	c.github.OnResponse = func(resource string, status int, elapsed time.Duration) {
+		api := MetricsAPIREST
+		if resource == github.ResourceGraphQL {
+			api = MetricsAPIGraphQL
+		}
+		c.metrics.APICall(api, status, elapsed)
+	}
+	if t, ok := c.github.HTTPClient.Transport.(*github.Transport); ok {
+		t.OnRetry = c.metrics.Retry
+	}
+}
+
+// recordCacheLookup reports a cache lookup to the metrics recorder, if any.
+func (c *Client) recordCacheLookup(cache string, hit bool) {
+	if c.metrics != nil {
+		c.metrics.CacheLookup(cache, hit)
+	}
+}
@@ -0,0 +1,239 @@
+// Package metrics provides a Prometheus implementation of prx.Recorder.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// DefaultBuckets are the latency histogram buckets in seconds, from fast cache hits to slow paginated fetches.
+var DefaultBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var _ prx.Recorder = (*Prometheus)(nil)
+
+// Prometheus records prx metrics and serves them in the Prometheus text exposition format.
+// It has no dependency on the Prometheus client library; mount it as the /metrics handler:
+//
+//	m := metrics.NewPrometheus("prx")
+//	client := prx.NewClient(token, prx.WithMetrics(m))
+//	http.Handle("/metrics", m)
+type Prometheus struct {
+	apiCalls     map[[2]string]uint64 // api, status
+	apiLatency   map[string]*histogram
+	cache        map[[2]string]uint64 // cache, result
+	retries      map[string]uint64    // status
+	fetches      map[string]uint64    // result
+	fetchLatency *histogram
+	namespace    string
+	buckets      []float64
+	graphQLCost  uint64
+	mu           sync.Mutex
+}
+
+// NewPrometheus creates a recorder whose metric names start with namespace, e.g. "prx".
+func NewPrometheus(namespace string) *Prometheus {
+	return &Prometheus{
+		namespace:    namespace,
+		buckets:      DefaultBuckets,
+		apiCalls:     make(map[[2]string]uint64),
+		apiLatency:   make(map[string]*histogram),
+		cache:        make(map[[2]string]uint64),
+		retries:      make(map[string]uint64),
+		fetches:      make(map[string]uint64),
+		fetchLatency: newHistogram(DefaultBuckets),
+	}
+}
+
+// APICall implements prx.Recorder.
+func (p *Prometheus) APICall(api string, status int, elapsed time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.apiCalls[[2]string{api, strconv.Itoa(status)}]++
+	h, ok := p.apiLatency[api]
+	if !ok {
+		h = newHistogram(p.buckets)
+		p.apiLatency[api] = h
+	}
+	h.observe(elapsed.Seconds())
+}
+
+// GraphQLCost implements prx.Recorder.
+func (p *Prometheus) GraphQLCost(points int) {
+	if points <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.graphQLCost += uint64(points)
+}
+
+// CacheLookup implements prx.Recorder.
+func (p *Prometheus) CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache[[2]string{cache, result}]++
+}
+
+// Retry implements prx.Recorder.
+func (p *Prometheus) Retry(status int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[strconv.Itoa(status)]++
+}
+
+// Fetch implements prx.Recorder.
+func (p *Prometheus) Fetch(elapsed time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetches[result]++
+	p.fetchLatency.observe(elapsed.Seconds())
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := p.WriteTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.header(cw, "api_calls_total", "counter", "GitHub API requests by API and HTTP status (0 if no response).")
+	for _, k := range sortedKeys(p.apiCalls) {
+		p.sample(cw, "api_calls_total", labels("api", k[0], "status", k[1]), float64(p.apiCalls[k]))
+	}
+	p.header(cw, "api_call_duration_seconds", "histogram", "GitHub API request latency by API.")
+	for _, api := range sortedKeys(p.apiLatency) {
+		p.histogram(cw, "api_call_duration_seconds", labels("api", api), p.apiLatency[api])
+	}
+	p.header(cw, "graphql_cost_points_total", "counter", "GitHub GraphQL rate limit points consumed.")
+	p.sample(cw, "graphql_cost_points_total", "", float64(p.graphQLCost))
+	p.header(cw, "cache_lookups_total", "counter", "Cache lookups by cache and result.")
+	for _, k := range sortedKeys(p.cache) {
+		p.sample(cw, "cache_lookups_total", labels("cache", k[0], "result", k[1]), float64(p.cache[k]))
+	}
+	p.header(cw, "retries_total", "counter", "GitHub API requests retried, by the HTTP status that caused the retry.")
+	for _, status := range sortedKeys(p.retries) {
+		p.sample(cw, "retries_total", labels("status", status), float64(p.retries[status]))
+	}
+	p.header(cw, "fetches_total", "counter", "Pull request fetches by result.")
+	for _, result := range sortedKeys(p.fetches) {
+		p.sample(cw, "fetches_total", labels("result", result), float64(p.fetches[result]))
+	}
+	p.header(cw, "fetch_duration_seconds", "histogram", "Pull request fetch latency, including cache hits.")
+	p.histogram(cw, "fetch_duration_seconds", "", p.fetchLatency)
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (p *Prometheus) header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s %s\n", p.namespace, name, help, p.namespace, name, kind)
+}
+
+func (p *Prometheus) sample(w io.Writer, name, lbls string, v float64) {
+	fmt.Fprintf(w, "%s_%s%s %s\n", p.namespace, name, lbls, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (p *Prometheus) histogram(w io.Writer, name, lbls string, h *histogram) {
+	var cumulative uint64
+	for i, le := range h.buckets {
+		cumulative += h.counts[i]
+		p.sample(w, name+"_bucket", withLabel(lbls, "le", strconv.FormatFloat(le, 'g', -1, 64)), float64(cumulative))
+	}
+	p.sample(w, name+"_bucket", withLabel(lbls, "le", "+Inf"), float64(h.count))
+	p.sample(w, name+"_sum", lbls, h.sum)
+	p.sample(w, name+"_count", lbls, float64(h.count))
+}
+
+// histogram counts observations per bucket; counts are per bucket, not cumulative.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.sum += v
+	h.count++
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		h.counts[i]++
+	}
+}
+
+// labels formats name/value pairs as a Prometheus label set.
+func labels(kv ...string) string {
+	var s string
+	for i := 0; i+1 < len(kv); i += 2 {
+		s = withLabel(s, kv[i], kv[i+1])
+	}
+	return s
+}
+
+// withLabel adds a label to a formatted label set.
+func withLabel(lbls, name, value string) string {
+	l := name + `="` + labelEscaper.Replace(value) + `"`
+	if lbls == "" {
+		return "{" + l + "}"
+	}
+	return lbls[:len(lbls)-1] + "," + l + "}"
+}
+
+// labelEscaper escapes label values as required by the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sortedKeys returns the keys of m in a stable order.
+func sortedKeys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b K) int { return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)) })
+	return keys
+}
+
+// countingWriter counts bytes written and keeps the first error, so writes can be chained.
+type countingWriter struct {
+	w   *bufio.Writer
+	err error
+	n   int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
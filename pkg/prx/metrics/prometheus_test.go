@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus("prx")
+	p.APICall("rest", 200, 30*time.Millisecond)
+	p.APICall("rest", 200, 300*time.Millisecond)
+	p.APICall("graphql", 502, time.Second)
+	p.GraphQLCost(3)
+	p.CacheLookup("pull_request", true)
+	p.CacheLookup("pull_request", false)
+	p.Retry(502)
+	p.Fetch(2*time.Second, nil)
+	p.Fetch(time.Second, errors.New("boom"))
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	out := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE prx_api_calls_total counter\n",
+		`prx_api_calls_total{api="graphql",status="502"} 1` + "\n",
+		`prx_api_calls_total{api="rest",status="200"} 2` + "\n",
+		`prx_api_call_duration_seconds_bucket{api="rest",le="0.05"} 1` + "\n",
+		`prx_api_call_duration_seconds_bucket{api="rest",le="0.5"} 2` + "\n",
+		`prx_api_call_duration_seconds_bucket{api="rest",le="+Inf"} 2` + "\n",
+		`prx_api_call_duration_seconds_count{api="rest"} 2` + "\n",
+		"prx_graphql_cost_points_total 3\n",
+		`prx_cache_lookups_total{cache="pull_request",result="hit"} 1` + "\n",
+		`prx_cache_lookups_total{cache="pull_request",result="miss"} 1` + "\n",
+		`prx_retries_total{status="502"} 1` + "\n",
+		`prx_fetches_total{result="error"} 1` + "\n",
+		"prx_fetch_duration_seconds_sum 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(out)
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	if got := withLabel(labels("a", "x"), "b", `q"\`+"\n"); got != `{a="x",b="q\"\\\n"}` {
+		t.Errorf("withLabel() = %s", got)
+	}
+}
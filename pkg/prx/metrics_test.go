@@ -0,0 +1,92 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+// countingRecorder is a Recorder that counts what it receives.
+type countingRecorder struct {
+	apiCalls    map[string]int
+	cache       map[string]int
+	graphQLCost int
+	fetches     int
+	mu          sync.Mutex
+}
+
+func (r *countingRecorder) APICall(api string, _ int, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiCalls[api]++
+}
+
+func (r *countingRecorder) GraphQLCost(points int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.graphQLCost += points
+}
+
+func (r *countingRecorder) CacheLookup(cache string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hit {
+		r.cache[cache+":hit"]++
+	} else {
+		r.cache[cache+":miss"]++
+	}
+}
+
+func (*countingRecorder) Retry(int) {}
+
+func (r *countingRecorder) Fetch(time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetches++
+}
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"headRef": {"name": "feature", "target": {"oid": "abc123"}}}},
+				"rateLimit": {"cost": 2, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	rec := &countingRecorder{apiCalls: make(map[string]int), cache: make(map[string]int)}
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithMetrics(rec))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	client.configureMetrics()
+
+	ctx := context.Background()
+	refTime := time.Now().Add(-time.Hour)
+	for range 2 {
+		if _, err := client.PullRequestWithReferenceTime(ctx, "owner", "repo", 1, refTime); err != nil {
+			t.Fatalf("PullRequestWithReferenceTime() error: %v", err)
+		}
+	}
+
+	if rec.apiCalls[MetricsAPIGraphQL] != 1 || rec.apiCalls[MetricsAPIREST] == 0 {
+		t.Errorf("apiCalls = %v, want 1 GraphQL call and some REST calls", rec.apiCalls)
+	}
+	if rec.graphQLCost != 2 {
+		t.Errorf("graphQLCost = %d, want 2", rec.graphQLCost)
+	}
+	if rec.cache[MetricsCachePullRequest+":miss"] != 1 || rec.cache[MetricsCachePullRequest+":hit"] != 1 {
+		t.Errorf("cache = %v, want one pull request miss and hit", rec.cache)
+	}
+	if rec.fetches != 2 {
+		t.Errorf("fetches = %d, want 2", rec.fetches)
+	}
+}
@@ -0,0 +1,208 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Events come from the GraphQL query, from REST check runs, and from webhook deliveries, whose payloads
+// have the shape of the REST API. Each path converts what its API reports into the API-neutral inputs
+// below and builds its events here, so that an event looks the same whichever path produced it:
+// webhook events then merge with fetched ones instead of duplicating them.
+
+// timelineEventKinds maps the GraphQL types of timeline items to event kinds.
+var timelineEventKinds = map[string]EventKind{
+	"AssignedEvent":                     EventKindAssigned,
+	"UnassignedEvent":                   EventKindUnassigned,
+	"LabeledEvent":                      EventKindLabeled,
+	"UnlabeledEvent":                    EventKindUnlabeled,
+	"MilestonedEvent":                   EventKindMilestoned,
+	"DemilestonedEvent":                 EventKindDemilestoned,
+	"ReviewRequestedEvent":              EventKindReviewRequested,
+	"ReviewRequestRemovedEvent":         EventKindReviewRequestRemoved,
+	"MentionedEvent":                    EventKindMentioned,
+	"ReadyForReviewEvent":               EventKindReadyForReview,
+	"ConvertToDraftEvent":               EventKindConvertToDraft,
+	"ClosedEvent":                       EventKindClosed,
+	"ReopenedEvent":                     EventKindReopened,
+	"MergedEvent":                       EventKindMerged,
+	"AutoMergeEnabledEvent":             EventKindAutoMergeEnabled,
+	"AutoMergeDisabledEvent":            EventKindAutoMergeDisabled,
+	"ReviewDismissedEvent":              EventKindReviewDismissed,
+	"BaseRefChangedEvent":               EventKindBaseRefChanged,
+	"BaseRefForcePushedEvent":           EventKindBaseRefForcePushed,
+	"HeadRefForcePushedEvent":           EventKindHeadRefForcePushed,
+	"HeadRefDeletedEvent":               EventKindHeadRefDeleted,
+	"HeadRefRestoredEvent":              EventKindHeadRefRestored,
+	"RenamedTitleEvent":                 EventKindRenamedTitle,
+	"LockedEvent":                       EventKindLocked,
+	"UnlockedEvent":                     EventKindUnlocked,
+	"AddedToMergeQueueEvent":            EventKindAddedToMergeQueue,
+	"RemovedFromMergeQueueEvent":        EventKindRemovedFromMergeQueue,
+	"AutomaticBaseChangeSucceededEvent": EventKindAutomaticBaseChangeSucceeded,
+	"AutomaticBaseChangeFailedEvent":    EventKindAutomaticBaseChangeFailed,
+	"ConnectedEvent":                    EventKindConnected,
+	"DisconnectedEvent":                 EventKindDisconnected,
+	"CrossReferencedEvent":              EventKindCrossReferenced,
+	"ReferencedEvent":                   EventKindReferenced,
+	"SubscribedEvent":                   EventKindSubscribed,
+	"UnsubscribedEvent":                 EventKindUnsubscribed,
+	"DeployedEvent":                     EventKindDeployed,
+	"DeploymentEnvironmentChangedEvent": EventKindDeploymentEnvironmentChanged,
+	"PinnedEvent":                       EventKindPinned,
+	"UnpinnedEvent":                     EventKindUnpinned,
+	"TransferredEvent":                  EventKindTransferred,
+	"UserBlockedEvent":                  EventKindUserBlocked,
+}
+
+// pullRequestActionKinds maps the actions of pull_request webhook deliveries to event kinds. Closing
+// isn't here, as its kind depends on whether the pull request was merged.
+var pullRequestActionKinds = map[string]EventKind{
+	"opened":                 EventKindPROpened,
+	"reopened":               EventKindReopened,
+	"ready_for_review":       EventKindReadyForReview,
+	"converted_to_draft":     EventKindConvertToDraft,
+	"labeled":                EventKindLabeled,
+	"unlabeled":              EventKindUnlabeled,
+	"assigned":               EventKindAssigned,
+	"unassigned":             EventKindUnassigned,
+	"review_requested":       EventKindReviewRequested,
+	"review_request_removed": EventKindReviewRequestRemoved,
+	"synchronize":            EventKindCommit,
+	"enqueued":               EventKindAddedToMergeQueue,
+	"dequeued":               EventKindRemovedFromMergeQueue,
+}
+
+// outcome normalizes a review state, check run status or conclusion, or commit status, which GraphQL
+// reports in upper case and REST in lower case.
+func outcome(state string) string {
+	return strings.ToLower(state)
+}
+
+// post is a comment or review.
+type post struct {
+	createdAt   time.Time
+	author      graphQLActor
+	body        string
+	association string // Of the author with the repository, e.g. "MEMBER"
+	app         string // Slug of the GitHub App that posted it, if known
+}
+
+// postEvent builds the event for a comment or review. Callers add what only their API reports,
+// such as reactions and review outcomes.
+func (c *Client) postEvent(ctx context.Context, kind EventKind, p *post, owner, repo string) Event {
+	e := Event{
+		Kind:        kind,
+		Timestamp:   p.createdAt,
+		Actor:       p.author.Login,
+		ActorType:   c.classifyActor(ctx, p.author),
+		Body:        c.truncate(p.body),
+		Question:    c.containsQuestion(p.body),
+		Annotations: c.classifyBody(p.body),
+		Suggestion:  kind == EventKindReviewComment && containsSuggestion(p.body),
+		Mentions:    extractMentions(p.body),
+		Bot:         c.classifyBot(ctx, p.author),
+		WriteAccess: c.writeAccessFromAssociation(ctx, owner, repo, p.author.Login, p.association),
+	}
+	if isGhost(p.author) {
+		attributeGhost(&e, p.app)
+	}
+	return e
+}
+
+// target sets the user or team an event is about, such as an assignee or requested reviewer.
+// Teams have no login, and are given by name.
+func (c *Client) target(ctx context.Context, e *Event, user graphQLActor, team string) {
+	if user.Login == "" {
+		e.Target = team
+		return
+	}
+	e.Target = user.Login
+	e.TargetIsBot = c.classifyBot(ctx, user)
+}
+
+// checkRun is a check run.
+type checkRun struct {
+	startedAt   time.Time // Zero if it hasn't started
+	completedAt time.Time // Zero if it hasn't completed
+	name        string
+	headSHA     string
+	status      string
+	conclusion  string
+	title       string
+	summary     string
+	detailsURL  string // For GitHub Actions, the page of the job in its workflow run
+	id          int64
+}
+
+// checkRunEvent builds the event for a check run's start, or for its completion if completed.
+func checkRunEvent(run *checkRun, completed bool) Event {
+	e := Event{
+		Kind:        EventKindCheckRun,
+		Timestamp:   run.startedAt,
+		Actor:       "github",
+		Bot:         true,
+		Body:        run.name,
+		Target:      run.headSHA,
+		Outcome:     outcome(run.status),
+		Description: checkDescription(run.title, run.summary),
+		CheckRunID:  run.id,
+	}
+	e.WorkflowRunID, e.WorkflowJobID = workflowRunIDs(run.detailsURL)
+	if completed {
+		e.Timestamp, e.Outcome = run.completedAt, outcome(run.conclusion)
+	}
+	return e
+}
+
+// latestCheckRunEvent builds the event for where a check run is now: its completion, or else its
+// start. It reports false for runs that haven't started.
+func latestCheckRunEvent(run *checkRun) (Event, bool) {
+	switch {
+	case !run.completedAt.IsZero():
+		return checkRunEvent(run, true), true
+	case !run.startedAt.IsZero():
+		return checkRunEvent(run, false), true
+	default:
+		return Event{}, false
+	}
+}
+
+// checkDescription describes a check run by its output's title and summary.
+func checkDescription(title, summary string) string {
+	switch {
+	case title != "" && summary != "":
+		return fmt.Sprintf("%s: %s", title, summary)
+	case title != "":
+		return title
+	default:
+		return summary
+	}
+}
+
+// commitStatus is a commit status, as set by the statuses API.
+type commitStatus struct {
+	createdAt   time.Time
+	creator     graphQLActor
+	sha         string
+	context     string
+	state       string
+	description string
+}
+
+// statusEvent builds the event for a commit status.
+func (c *Client) statusEvent(ctx context.Context, status *commitStatus) Event {
+	return Event{
+		Kind:        EventKindStatusCheck,
+		Timestamp:   status.createdAt,
+		Actor:       status.creator.Login,
+		ActorType:   c.classifyActor(ctx, status.creator),
+		Bot:         c.classifyBot(ctx, status.creator),
+		Target:      status.sha,
+		Body:        status.context,
+		Outcome:     outcome(status.state),
+		Description: status.description,
+	}
+}
@@ -0,0 +1,225 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+// parityCase is the same activity as GraphQL and a webhook delivery report it.
+type parityCase struct {
+	name      string
+	graphQL   map[string]any // Fields of graphQLPullRequestComplete
+	eventType string         // Of the webhook delivery
+	webhook   map[string]any
+}
+
+// randomParityCase generates activity from r, in each API's shape.
+func randomParityCase(r *rand.Rand) parityCase {
+	pick := func(options ...string) string { return options[r.IntN(len(options))] }
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(r.IntN(1e6)) * time.Second).Format(time.RFC3339)
+	var body []string
+	for range r.IntN(4) {
+		body = append(body, pick("LGTM", "Why not a map?", "cc @carol", "Thanks @dave!", "```suggestion\nx := 1\n```", "See `@notamention`"))
+	}
+	text := strings.Join(body, "\n\n")
+	// Authors may have been deleted; senders of deliveries and actors of timeline items can't have been
+	login := pick("alice", "bob-ci", "dependabot[bot]", "renovate", "ghost", "")
+	sender := pick("alice", "bob-ci", "dependabot[bot]", "renovate")
+	typ := pick("User", "Bot")
+	association := pick("OWNER", "MEMBER", "COLLABORATOR", "CONTRIBUTOR", "NONE", "FIRST_TIME_CONTRIBUTOR")
+	graphQLUser := func(login string) any {
+		if login == "" {
+			return nil
+		}
+		return map[string]any{"login": login, "__typename": typ}
+	}
+	webhookUser := func(login string) any {
+		if login == "" {
+			return nil
+		}
+		return map[string]any{"login": login, "type": typ}
+	}
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	pullRequest := map[string]any{"updated_at": at, "head": map[string]any{"sha": sha}}
+	timeline := func(item map[string]any) map[string]any {
+		item["createdAt"], item["actor"] = at, graphQLUser(sender)
+		return map[string]any{"timelineItems": map[string]any{"nodes": []any{item}}}
+	}
+
+	switch kind := r.IntN(9); kind {
+	case 0, 1:
+		comment := map[string]any{"createdAt": at, "author": graphQLUser(login), "body": text, "authorAssociation": association}
+		hook := map[string]any{"action": "created", "comment": map[string]any{
+			"created_at": at, "user": webhookUser(login), "body": text, "author_association": association,
+		}}
+		if kind == 0 {
+			return parityCase{"comment", map[string]any{"comments": map[string]any{"nodes": []any{comment}}}, "issue_comment", hook}
+		}
+		threads := map[string]any{"nodes": []any{map[string]any{"path": "x.go", "comments": map[string]any{"nodes": []any{comment}}}}}
+		return parityCase{"review comment", map[string]any{"reviewThreads": threads}, "pull_request_review_comment", hook}
+	case 2:
+		state := pick("APPROVED", "CHANGES_REQUESTED", "COMMENTED")
+		review := map[string]any{
+			"createdAt": at, "submittedAt": at, "author": graphQLUser(login), "body": text, "state": state, "authorAssociation": association,
+			"commit": map[string]any{"oid": sha},
+		}
+		hook := map[string]any{"action": "submitted", "review": map[string]any{
+			"submitted_at": at, "user": webhookUser(login), "body": text, "state": strings.ToLower(state), "author_association": association,
+			"commit_id": sha,
+		}}
+		return parityCase{"review", map[string]any{"reviews": map[string]any{"nodes": []any{review}}}, "pull_request_review", hook}
+	case 3:
+		action := pick("labeled", "unlabeled")
+		label := pick("bug", "needs review")
+		item := map[string]any{"__typename": map[string]string{"labeled": "LabeledEvent", "unlabeled": "UnlabeledEvent"}[action], "label": map[string]any{"name": label}}
+		hook := map[string]any{"action": action, "sender": webhookUser(sender), "pull_request": pullRequest, "label": map[string]any{"name": label}}
+		return parityCase{action, timeline(item), "pull_request", hook}
+	case 4:
+		action := pick("assigned", "unassigned")
+		assignee := pick("alice", "dependabot[bot]")
+		item := map[string]any{
+			"__typename": map[string]string{"assigned": "AssignedEvent", "unassigned": "UnassignedEvent"}[action],
+			"assignee":   map[string]any{"login": assignee},
+		}
+		hook := map[string]any{"action": action, "sender": webhookUser(sender), "pull_request": pullRequest, "assignee": map[string]any{"login": assignee}}
+		return parityCase{action, timeline(item), "pull_request", hook}
+	case 5, 6:
+		action := pick("review_requested", "review_request_removed")
+		typename := map[string]string{"review_requested": "ReviewRequestedEvent", "review_request_removed": "ReviewRequestRemovedEvent"}[action]
+		hook := map[string]any{"action": action, "sender": webhookUser(sender), "pull_request": pullRequest}
+		var reviewer map[string]any
+		if kind == 5 {
+			name := pick("carol", "copilot[bot]")
+			reviewer, hook["requested_reviewer"] = map[string]any{"login": name}, map[string]any{"login": name}
+		} else {
+			team := pick("core", "release")
+			reviewer, hook["requested_team"] = map[string]any{"name": team}, map[string]any{"name": team}
+		}
+		return parityCase{action, timeline(map[string]any{"__typename": typename, "requestedReviewer": reviewer}), "pull_request", hook}
+	case 7:
+		name, title, summary := pick("build", "lint"), pick("", "2 errors"), pick("", "See the log")
+		conclusion := pick("SUCCESS", "FAILURE", "CANCELLED")
+		detailsURL := pick("", "https://github.com/o/r/actions/runs/7/job/8", "https://ci.example.com/build/9")
+		node := map[string]any{
+			"__typename": "CheckRun", "databaseId": r.IntN(1e6), "name": name, "status": "COMPLETED", "conclusion": conclusion,
+			"startedAt": at, "completedAt": at, "title": title, "summary": summary, "detailsUrl": detailsURL,
+		}
+		hook := map[string]any{"action": "completed", "sender": webhookUser(sender), "check_run": map[string]any{
+			"id": node["databaseId"], "name": name, "head_sha": sha, "status": "completed", "conclusion": strings.ToLower(conclusion),
+			"started_at": at, "completed_at": at, "output": map[string]any{"title": title, "summary": summary}, "details_url": detailsURL,
+		}}
+		return parityCase{"check run", checkRollup(sha, node), "check_run", hook}
+	default:
+		statusContext, state, description := pick("ci/jenkins", "deploy"), pick("SUCCESS", "FAILURE", "PENDING"), pick("", "Build #12")
+		node := map[string]any{"__typename": "StatusContext", "createdAt": at, "creator": graphQLUser(sender), "context": statusContext, "state": state, "description": description}
+		hook := map[string]any{
+			"sender": webhookUser(sender), "updated_at": at, "sha": sha, "context": statusContext, "state": strings.ToLower(state), "description": description,
+		}
+		return parityCase{"status", checkRollup(sha, node), "status", hook}
+	}
+}
+
+// checkRollup returns GraphQL data whose head commit has a check.
+func checkRollup(sha string, node map[string]any) map[string]any {
+	return map[string]any{"headRef": map[string]any{"target": map[string]any{
+		"oid": sha, "statusCheckRollup": map[string]any{"contexts": map[string]any{"nodes": []any{node}}},
+	}}}
+}
+
+// TestEventParity checks that GraphQL and webhook deliveries describe random activity with identical events.
+func TestEventParity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`[{"login": "alice", "permissions": {"push": true}}]`)) // Collaborators, for MEMBER authors
+	}))
+	defer server.Close()
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 500 {
+		tc := randomParityCase(r)
+		b, err := json.Marshal(tc.graphQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data graphQLPullRequestComplete
+		if err := json.Unmarshal(b, &data); err != nil {
+			t.Fatalf("case %d (%s): %v", i, tc.name, err)
+		}
+		if b, err = json.Marshal(tc.webhook); err != nil {
+			t.Fatal(err)
+		}
+		var hook webhookPayload
+		if err := json.Unmarshal(b, &hook); err != nil {
+			t.Fatalf("case %d (%s): %v", i, tc.name, err)
+		}
+
+		fromGraphQL := client.convertGraphQLConnectionEvents(ctx, &data, "o", "r")
+		fromWebhook := client.webhookEvents(ctx, tc.eventType, &hook, prRef{owner: "o", repo: "r", number: 1})
+		if len(fromGraphQL) == 0 || len(fromWebhook) != 1 {
+			t.Fatalf("case %d (%s): %d events from GraphQL and %d from the webhook, want some and one",
+				i, tc.name, len(fromGraphQL), len(fromWebhook))
+		}
+		// GraphQL also reports when check runs started; a completed delivery only has the latest state
+		got, want := sharedFields(fromWebhook[0]), sharedFields(fromGraphQL[len(fromGraphQL)-1])
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("case %d (%s):\nwebhook %+v\nGraphQL %+v\nfrom %s\nand %s", i, tc.name, got, want, jsonString(tc.graphQL), jsonString(tc.webhook))
+		}
+	}
+}
+
+// sharedFields clears the fields of e that only one API reports.
+func sharedFields(e Event) Event {
+	e.Raw, e.Reactions, e.Outdated, e.ThreadResolved, e.PathDeleted = nil, nil, false, false, false
+	return e
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// TestKindTables checks that every kind is produced by a table or known to be produced elsewhere.
+func TestKindTables(t *testing.T) {
+	produced := make(map[EventKind]bool)
+	for _, k := range timelineEventKinds {
+		produced[k] = true
+	}
+	for _, k := range pullRequestActionKinds {
+		produced[k] = true
+	}
+	// Built by the converters directly, rather than looked up
+	for _, k := range []EventKind{EventKindReview, EventKindComment, EventKindReviewComment, EventKindPRClosed, EventKindPRMerged,
+		EventKindCheckRun, EventKindStatusCheck} {
+		produced[k] = true
+	}
+	for k := range eventKinds {
+		if !produced[k] {
+			t.Errorf("no converter produces %q", k)
+		}
+	}
+	// Every webhook action has a GraphQL timeline counterpart, except for those GraphQL reports outside the timeline
+	timeline := slices.Collect(maps.Values(timelineEventKinds))
+	for action, k := range pullRequestActionKinds {
+		if k != EventKindPROpened && k != EventKindCommit && !slices.Contains(timeline, k) {
+			t.Errorf("webhook action %q has kind %q, which no timeline item has", action, k)
+		}
+	}
+}
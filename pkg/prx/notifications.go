@@ -0,0 +1,128 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notification reasons reported by GitHub for pull request threads.
+const (
+	NotificationReasonReviewRequested = "review_requested"
+	NotificationReasonMention         = "mention"
+	NotificationReasonTeamMention     = "team_mention"
+	NotificationReasonAuthor          = "author"
+	NotificationReasonComment         = "comment"
+	NotificationReasonAssign          = "assign"
+	NotificationReasonStateChange     = "state_change"
+	NotificationReasonCIActivity      = "ci_activity"
+	NotificationReasonSubscribed      = "subscribed"
+)
+
+// NotificationFilter selects the notifications returned by Notifications.
+// The zero value returns unread notifications for all pull requests.
+type NotificationFilter struct {
+	Since         time.Time // Only threads updated after this time
+	Before        time.Time // Only threads updated before this time
+	Reasons       []string  // Only these reasons, e.g. NotificationReasonReviewRequested
+	Repos         []string  // Only these "owner/repo" repositories
+	All           bool      // Include read threads
+	Participating bool      // Only threads the user participates in or is mentioned in
+}
+
+// PullRequestNotification is a notification thread about a pull request.
+type PullRequestNotification struct {
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastReadAt *time.Time `json:"last_read_at,omitempty"` // Nil if never read
+	ThreadID   string     `json:"thread_id"`
+	Owner      string     `json:"owner"`
+	Repo       string     `json:"repo"`
+	Title      string     `json:"title"`
+	Reason     string     `json:"reason"`
+	Number     int        `json:"number"`
+	Unread     bool       `json:"unread"`
+}
+
+// Notifications fetches the authenticated user's notification threads about pull requests,
+// most recently updated first, so unread activity can be combined with PullRequest data.
+// GitHub only serves notifications to classic personal access tokens and OAuth tokens,
+// not to GitHub App installations or fine-grained tokens.
+func (c *Client) Notifications(ctx context.Context, filter NotificationFilter) ([]PullRequestNotification, error) {
+	params := url.Values{}
+	if filter.All {
+		params.Set("all", "true")
+	}
+	if filter.Participating {
+		params.Set("participating", "true")
+	}
+	if !filter.Since.IsZero() {
+		params.Set("since", filter.Since.UTC().Format(time.RFC3339))
+	}
+	if !filter.Before.IsZero() {
+		params.Set("before", filter.Before.UTC().Format(time.RFC3339))
+	}
+
+	threads, err := c.github.Notifications(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("fetching notifications: %w", err)
+	}
+
+	var notifications []PullRequestNotification
+	for _, t := range threads {
+		if t.Subject.Type != "PullRequest" {
+			continue
+		}
+		if len(filter.Reasons) > 0 && !slices.Contains(filter.Reasons, t.Reason) {
+			continue
+		}
+		if len(filter.Repos) > 0 && !slices.ContainsFunc(filter.Repos, func(r string) bool {
+			return strings.EqualFold(r, t.Repository.FullName)
+		}) {
+			continue
+		}
+		owner, repo, number, ok := pullRequestAPIURL(t.Subject.URL)
+		if !ok {
+			c.logger.DebugContext(ctx, "skipping notification with unrecognized subject URL",
+				"thread", t.ID, "url", t.Subject.URL)
+			continue
+		}
+		notifications = append(notifications, PullRequestNotification{
+			UpdatedAt:  t.UpdatedAt,
+			LastReadAt: t.LastReadAt,
+			ThreadID:   t.ID,
+			Owner:      owner,
+			Repo:       repo,
+			Title:      t.Subject.Title,
+			Reason:     t.Reason,
+			Number:     number,
+			Unread:     t.Unread,
+		})
+	}
+	return notifications, nil
+}
+
+// pullRequestAPIURL parses a pull request API URL such as https://api.github.com/repos/o/r/pulls/1.
+// On GitHub Enterprise Server, the path has an /api/v3 prefix.
+func pullRequestAPIURL(raw string) (owner, repo string, number int, ok bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", 0, false
+	}
+	_, rest, found := strings.Cut(u.Path, "/repos/")
+	if !found {
+		return "", "", 0, false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 4 || parts[2] != "pulls" {
+		return "", "", 0, false
+	}
+	number, err = strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], number, true
+}
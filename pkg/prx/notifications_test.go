@@ -0,0 +1,97 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_Notifications(t *testing.T) {
+	var query string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/notifications" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "1" {
+			query = r.URL.RawQuery
+			w.Header().Set("Link", fmt.Sprintf(`<%s/notifications?page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`[
+				{"id": "1", "reason": "review_requested", "unread": true, "updated_at": "2025-01-02T10:00:00Z",
+				 "subject": {"title": "Add parser", "type": "PullRequest", "url": "https://api.github.com/repos/owner/repo/pulls/7"},
+				 "repository": {"full_name": "owner/repo"}},
+				{"id": "2", "reason": "mention", "unread": true, "updated_at": "2025-01-02T09:00:00Z",
+				 "subject": {"title": "Crash on start", "type": "Issue", "url": "https://api.github.com/repos/owner/repo/issues/8"},
+				 "repository": {"full_name": "owner/repo"}}
+			]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id": "3", "reason": "mention", "unread": false, "updated_at": "2025-01-01T09:00:00Z",
+			 "last_read_at": "2025-01-01T10:00:00Z",
+			 "subject": {"title": "Fix tests", "type": "PullRequest", "url": "https://ghe.example.com/api/v3/repos/Owner/other/pulls/9"},
+			 "repository": {"full_name": "Owner/other"}}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, err := client.Notifications(ctx, NotificationFilter{All: true, Since: since})
+	if err != nil {
+		t.Fatalf("Notifications() error: %v", err)
+	}
+	if query != "all=true&page=1&per_page=50&since=2025-01-01T00%3A00%3A00Z" {
+		t.Errorf("unexpected query %q", query)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 pull request notifications, got %+v", got)
+	}
+	if got[0].Owner != "owner" || got[0].Repo != "repo" || got[0].Number != 7 ||
+		got[0].Reason != NotificationReasonReviewRequested || !got[0].Unread || got[0].LastReadAt != nil {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	if got[1].Owner != "Owner" || got[1].Repo != "other" || got[1].Number != 9 || got[1].Unread || got[1].LastReadAt == nil {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+
+	got, err = client.Notifications(ctx, NotificationFilter{Reasons: []string{NotificationReasonMention}, Repos: []string{"owner/other"}})
+	if err != nil {
+		t.Fatalf("Notifications() error: %v", err)
+	}
+	if len(got) != 1 || got[0].ThreadID != "3" {
+		t.Errorf("expected only the mention in owner/other, got %+v", got)
+	}
+}
+
+func TestPullRequestAPIURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		owner  string
+		repo   string
+		number int
+		ok     bool
+	}{
+		{"https://api.github.com/repos/owner/repo/pulls/12", "owner", "repo", 12, true},
+		{"https://ghe.example.com/api/v3/repos/owner/repo/pulls/3", "owner", "repo", 3, true},
+		{"https://api.github.com/repos/owner/repo/issues/12", "", "", 0, false},
+		{"https://api.github.com/repos/owner/repo/pulls/12/files", "", "", 0, false},
+		{"https://api.github.com/repos/owner/repo/pulls/abc", "", "", 0, false},
+		{"", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		owner, repo, number, ok := pullRequestAPIURL(tt.url)
+		if owner != tt.owner || repo != tt.repo || number != tt.number || ok != tt.ok {
+			t.Errorf("pullRequestAPIURL(%q) = %q, %q, %d, %v", tt.url, owner, repo, number, ok)
+		}
+	}
+}
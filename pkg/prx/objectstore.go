@@ -0,0 +1,215 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido"
+)
+
+// Object metadata keys written by ObjectCacheStore, as Unix nanoseconds; an expiry of 0 never expires.
+const (
+	ObjectMetadataExpiry  = "prx-expiry"
+	ObjectMetadataUpdated = "prx-updated"
+)
+
+// ObjectStorage is the subset of an object store, such as an S3 or GCS bucket, used by ObjectCacheStore.
+// The bucket package implements it for S3 and GCS without a cloud SDK, or implement it with the SDK
+// of your choice. Metadata is stored with the object, e.g. as S3 user-defined metadata or GCS custom metadata.
+type ObjectStorage interface {
+	// Get returns an object and its metadata, with found false if it doesn't exist.
+	Get(ctx context.Context, key string) (data []byte, metadata map[string]string, found bool, err error)
+	// Put creates or replaces an object.
+	Put(ctx context.Context, key string, data []byte, metadata map[string]string) error
+	// Delete removes an object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys of all objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ObjectCacheStore persists pull requests and repository-level data (collaborators and rulesets)
+// as JSON objects in a bucket, so ephemeral runners such as GitHub Actions jobs can share a cache
+// without a database. Each entry records its expiry in its metadata, and expired entries are
+// ignored on read; a bucket lifecycle rule removes them more cheaply than Cleanup, which reads
+// every object.
+type ObjectCacheStore struct {
+	storage ObjectStorage
+	prefix  string
+}
+
+// NewObjectCacheStore returns a store keeping its objects under prefix, e.g. "prx-cache/".
+// Use it with WithObjectCache.
+func NewObjectCacheStore(storage ObjectStorage, prefix string) *ObjectCacheStore {
+	return &ObjectCacheStore{storage: storage, prefix: prefix}
+}
+
+// PullRequests returns the store for pull request data, for use with WithCacheStore.
+func (s *ObjectCacheStore) PullRequests() PRStore {
+	return &objectTable[PullRequestData]{store: s, kind: cacheKindPullRequests}
+}
+
+// WithObjectCache persists pull requests, collaborators, and rulesets in store.
+// The in-memory caches in front of it still prevent duplicate fetches within a process.
+func WithObjectCache(store *ObjectCacheStore) Option {
+	return func(c *Client) {
+		WithCacheStore(store.PullRequests())(c)
+		c.collaboratorsStore = newObjectTiered[map[string]string](c.logger, store, cacheKindCollaborators, collaboratorsCacheTTL)
+		c.rulesetsStore = newObjectTiered[rulesetRequirements](c.logger, store, cacheKindRulesets, rulesetsCacheTTL)
+	}
+}
+
+// newObjectTiered creates a tiered cache over one namespace of store, or nil on failure.
+func newObjectTiered[V any](log *slog.Logger, store *ObjectCacheStore, kind string, ttl time.Duration) *fido.TieredCache[string, V] {
+	cache, err := fido.NewTiered[string, V](&objectTable[V]{store: store, kind: kind}, fido.TTL(ttl))
+	if err != nil {
+		log.Warn("failed to create cache from object store, keeping it in memory", "kind", kind, "error", err)
+		return nil
+	}
+	return cache
+}
+
+// objectTable implements fido.Store over one namespace of an ObjectCacheStore, with JSON values.
+type objectTable[V any] struct {
+	store *ObjectCacheStore
+	kind  string
+}
+
+// objectKey returns the object key for a cache key.
+func (t *objectTable[V]) objectKey(key string) string {
+	return t.namespace() + key + ".json"
+}
+
+// namespace returns the key prefix shared by the table's objects.
+func (t *objectTable[V]) namespace() string {
+	return t.store.prefix + t.kind + "/"
+}
+
+func (t *objectTable[V]) ValidateKey(key string) error {
+	if key == "" {
+		return errors.New("empty cache key")
+	}
+	if path.Clean("/"+key) != "/"+key {
+		return fmt.Errorf("invalid cache key %q", key)
+	}
+	return nil
+}
+
+func (t *objectTable[V]) Get(ctx context.Context, key string) (V, time.Time, bool, error) {
+	var zero V
+	data, metadata, found, err := t.store.storage.Get(ctx, t.objectKey(key))
+	if err != nil {
+		return zero, time.Time{}, false, fmt.Errorf("loading %s: %w", t.kind, err)
+	}
+	if !found {
+		return zero, time.Time{}, false, nil
+	}
+	exp := objectTime(metadata, ObjectMetadataExpiry)
+	if !exp.IsZero() && !exp.After(time.Now()) {
+		return zero, time.Time{}, false, nil
+	}
+	var v V
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, time.Time{}, false, fmt.Errorf("decoding %s: %w", t.kind, err)
+	}
+	return v, exp, true, nil
+}
+
+func (t *objectTable[V]) Set(ctx context.Context, key string, value V, expiry time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", t.kind, err)
+	}
+	var exp int64
+	if !expiry.IsZero() {
+		exp = expiry.UnixNano()
+	}
+	metadata := map[string]string{
+		ObjectMetadataExpiry:  strconv.FormatInt(exp, 10),
+		ObjectMetadataUpdated: strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+	if err := t.store.storage.Put(ctx, t.objectKey(key), data, metadata); err != nil {
+		return fmt.Errorf("storing %s: %w", t.kind, err)
+	}
+	return nil
+}
+
+func (t *objectTable[V]) Delete(ctx context.Context, key string) error {
+	if err := t.store.storage.Delete(ctx, t.objectKey(key)); err != nil {
+		return fmt.Errorf("deleting %s: %w", t.kind, err)
+	}
+	return nil
+}
+
+// Cleanup removes expired entries and entries not written within maxAge. It reads every object
+// in the namespace, so prefer a bucket lifecycle rule for large caches.
+func (t *objectTable[V]) Cleanup(ctx context.Context, maxAge time.Duration) (int, error) {
+	keys, err := t.store.storage.List(ctx, t.namespace())
+	if err != nil {
+		return 0, fmt.Errorf("listing %s: %w", t.kind, err)
+	}
+	now := time.Now()
+	n := 0
+	for _, key := range keys {
+		_, metadata, found, err := t.store.storage.Get(ctx, key)
+		if err != nil {
+			return n, fmt.Errorf("loading %s: %w", t.kind, err)
+		}
+		if !found {
+			continue
+		}
+		exp := objectTime(metadata, ObjectMetadataExpiry)
+		if (exp.IsZero() || exp.After(now)) && !objectTime(metadata, ObjectMetadataUpdated).Before(now.Add(-maxAge)) {
+			continue
+		}
+		if err := t.store.storage.Delete(ctx, key); err != nil {
+			return n, fmt.Errorf("deleting %s: %w", t.kind, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (t *objectTable[V]) Location(key string) string {
+	return t.objectKey(key)
+}
+
+func (t *objectTable[V]) Flush(ctx context.Context) (int, error) {
+	keys, err := t.store.storage.List(ctx, t.namespace())
+	if err != nil {
+		return 0, fmt.Errorf("listing %s: %w", t.kind, err)
+	}
+	for i, key := range keys {
+		if err := t.store.storage.Delete(ctx, key); err != nil {
+			return i, fmt.Errorf("flushing %s: %w", t.kind, err)
+		}
+	}
+	return len(keys), nil
+}
+
+func (t *objectTable[V]) Len(ctx context.Context) (int, error) {
+	keys, err := t.store.storage.List(ctx, t.namespace())
+	if err != nil {
+		return 0, fmt.Errorf("counting %s: %w", t.kind, err)
+	}
+	return len(keys), nil
+}
+
+// Close is a no-op; the ObjectStorage is owned by the caller.
+func (*objectTable[V]) Close() error {
+	return nil
+}
+
+// objectTime parses a Unix nanosecond timestamp from metadata, returning the zero time if it's absent or 0.
+func objectTime(metadata map[string]string, key string) time.Time {
+	ns, err := strconv.ParseInt(metadata[key], 10, 64)
+	if err != nil || ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
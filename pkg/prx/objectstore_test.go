@@ -0,0 +1,99 @@
+package prx
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryObjectStorage is an in-memory ObjectStorage.
+type memoryObjectStorage struct {
+	data     map[string][]byte
+	metadata map[string]map[string]string
+	mu       sync.Mutex
+}
+
+func newMemoryObjectStorage() *memoryObjectStorage {
+	return &memoryObjectStorage{data: make(map[string][]byte), metadata: make(map[string]map[string]string)}
+}
+
+func (m *memoryObjectStorage) Get(_ context.Context, key string) ([]byte, map[string]string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[key]
+	return data, m.metadata[key], ok, nil
+}
+
+func (m *memoryObjectStorage) Put(_ context.Context, key string, data []byte, metadata map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = data
+	m.metadata[key] = maps.Clone(metadata)
+	return nil
+}
+
+func (m *memoryObjectStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	delete(m.metadata, key)
+	return nil
+}
+
+func (m *memoryObjectStorage) List(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func TestObjectCacheStore(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryObjectStorage()
+	prs := NewObjectCacheStore(storage, "cache/").PullRequests()
+
+	if err := prs.Set(ctx, "fresh", PullRequestData{PullRequest: PullRequest{Number: 1}}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := prs.Set(ctx, "expired", PullRequestData{PullRequest: PullRequest{Number: 2}}, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if _, ok := storage.data["cache/pr/fresh.json"]; !ok {
+		t.Errorf("expected object cache/pr/fresh.json, got %v", storage.data)
+	}
+
+	got, exp, found, err := prs.Get(ctx, "fresh")
+	if err != nil || !found || got.PullRequest.Number != 1 || exp.IsZero() {
+		t.Errorf("Get(fresh) = %v, %v, %v, %v", got.PullRequest.Number, exp, found, err)
+	}
+	if _, _, found, err := prs.Get(ctx, "expired"); found || err != nil {
+		t.Errorf("Get(expired) = %v, %v; want not found", found, err)
+	}
+	if _, _, found, err := prs.Get(ctx, "missing"); found || err != nil {
+		t.Errorf("Get(missing) = %v, %v; want not found", found, err)
+	}
+	if err := prs.ValidateKey("../escape"); err == nil {
+		t.Error("expected a key escaping the namespace to be rejected")
+	}
+
+	if n, err := prs.Cleanup(ctx, time.Hour); err != nil || n != 1 {
+		t.Errorf("Cleanup() = %d, %v; want 1 expired entry removed", n, err)
+	}
+	if n, err := prs.Len(ctx); err != nil || n != 1 {
+		t.Errorf("Len() = %d, %v; want 1", n, err)
+	}
+	if n, err := prs.Flush(ctx); err != nil || n != 1 {
+		t.Errorf("Flush() = %d, %v; want 1", n, err)
+	}
+	if len(storage.data) != 0 {
+		t.Errorf("expected an empty bucket after Flush, got %v", storage.data)
+	}
+}
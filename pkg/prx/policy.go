@@ -0,0 +1,81 @@
+package prx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido"
+)
+
+// policyVerdictsCacheTTL is how long policy verdicts are kept; a commit's contents never change.
+const policyVerdictsCacheTTL = 90 * 24 * time.Hour
+
+// PolicyVerdict is the result of evaluating an approval or check policy against a pull request's head commit.
+type PolicyVerdict struct {
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	Policy      string    `json:"policy"`
+	HeadSHA     string    `json:"head_sha"`
+	Reason      string    `json:"reason,omitempty"`
+	Passed      bool      `json:"passed"`
+}
+
+// PolicyStore is the interface for policy verdict storage backends.
+// This is an alias for fido.Store with the appropriate type parameters.
+type PolicyStore = fido.Store[string, PolicyVerdict]
+
+// WithPolicyStore sets the store that persists policy verdicts across runs.
+// By default they are persisted next to the default PR cache, or kept in memory when WithCacheStore is used.
+func WithPolicyStore(store PolicyStore) Option {
+	return func(c *Client) {
+		cache, err := fido.NewTiered(store, fido.TTL(policyVerdictsCacheTTL))
+		if err != nil {
+			c.logger.Warn("failed to create policy cache from store, using default", "error", err)
+			return
+		}
+		c.policyCache = cache
+	}
+}
+
+// RecordPolicyVerdict stores the verdict of a policy evaluated against a commit of owner/repo,
+// replacing any earlier verdict of that policy for the commit. EvaluatedAt defaults to now.
+func (c *Client) RecordPolicyVerdict(ctx context.Context, owner, repo string, verdict PolicyVerdict) error {
+	if verdict.Policy == "" || verdict.HeadSHA == "" {
+		return errors.New("policy verdict requires a policy and head SHA")
+	}
+	if c.policyCache == nil {
+		return errors.New("policy verdict cache unavailable")
+	}
+	if verdict.EvaluatedAt.IsZero() {
+		verdict.EvaluatedAt = time.Now()
+	}
+	if err := c.policyCache.Set(ctx, policyCacheKey(owner, repo, verdict.HeadSHA, verdict.Policy), verdict); err != nil {
+		return fmt.Errorf("storing policy verdict: %w", err)
+	}
+	return nil
+}
+
+// PolicyVerdict returns the verdict recorded for policy at commit headSHA of owner/repo, if any.
+// Verdicts follow the commit rather than the pull request, so a force-push back to an
+// already-evaluated commit, or another pull request with the same head, finds them.
+func (c *Client) PolicyVerdict(ctx context.Context, owner, repo, headSHA, policy string) (PolicyVerdict, bool, error) {
+	if c.policyCache == nil {
+		return PolicyVerdict{}, false, nil
+	}
+	verdict, found, err := c.policyCache.Get(ctx, policyCacheKey(owner, repo, headSHA, policy))
+	if err != nil {
+		return PolicyVerdict{}, false, fmt.Errorf("loading policy verdict: %w", err)
+	}
+	return verdict, found, nil
+}
+
+// policyCacheKey generates a cache key for a policy verdict. Repository names are case-insensitive.
+func policyCacheKey(owner, repo, headSHA, policy string) string {
+	key := strings.Join([]string{"policy", strings.ToLower(owner), strings.ToLower(repo), headSHA, policy}, "/")
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,293 @@
+package prx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is a set of merge requirements evaluated against a pull request, typically loaded from a
+// policy file with LoadPolicy. Zero values impose no requirement.
+type Policy struct {
+	MaxUnresolvedThreads       *int     `json:"max_unresolved_threads,omitempty"`
+	Name                       string   `json:"name,omitempty"`            // Recorded with verdicts; defaults to "gate"
+	RequiredChecks             []string `json:"required_checks,omitempty"` // Must have succeeded, or been skipped or neutral
+	MinApprovals               int      `json:"min_approvals,omitempty"`   // From reviewers with write access
+	RequirePassingChecks       bool     `json:"require_passing_checks,omitempty"`
+	RequireNoChangesRequested  bool     `json:"require_no_changes_requested,omitempty"`
+	RequireReady               bool     `json:"require_ready,omitempty"` // Open and not a draft
+	RequireMergeable           bool     `json:"require_mergeable,omitempty"`
+	RequireTicket              bool     `json:"require_ticket,omitempty"`               // Head branch references a ticket
+	RequireCompleteDescription bool     `json:"require_complete_description,omitempty"` // See WithDescriptionTemplates
+}
+
+// PolicyResult is the outcome of evaluating a Policy.
+type PolicyResult struct {
+	Policy string   `json:"policy"`
+	Unmet  []string `json:"unmet,omitempty"` // Explanations of unmet requirements, in policy order
+	Passed bool     `json:"passed"`
+}
+
+// Verdict converts the result into a verdict for RecordPolicyVerdict.
+func (r *PolicyResult) Verdict(headSHA string) PolicyVerdict {
+	return PolicyVerdict{
+		EvaluatedAt: time.Now(),
+		Policy:      r.Policy,
+		HeadSHA:     headSHA,
+		Reason:      strings.Join(r.Unmet, "; "),
+		Passed:      r.Passed,
+	}
+}
+
+// LoadPolicy reads a policy file. See ParsePolicy for the accepted formats.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+	p, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParsePolicy parses a policy in JSON, or in YAML limited to top-level keys with scalar or list values:
+//
+//	min_approvals: 2
+//	required_checks: [build, lint]
+//	max_unresolved_threads: 0
+//	require_passing_checks: true
+//
+// Unknown keys are rejected, so typos don't silently drop requirements.
+func ParsePolicy(data []byte) (*Policy, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		fields, err := parseFlatYAML(string(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+	if p.Name == "" {
+		p.Name = "gate"
+	}
+	return &p, nil
+}
+
+// Evaluate checks the pull request against every requirement of the policy.
+func (p *Policy) Evaluate(data *PullRequestData) PolicyResult {
+	pr := &data.PullRequest
+	result := PolicyResult{Policy: p.Name}
+	unmet := func(format string, args ...any) {
+		result.Unmet = append(result.Unmet, fmt.Sprintf(format, args...))
+	}
+
+	if p.RequireReady {
+		switch {
+		case pr.State != "open":
+			unmet("pull request is %s", pr.State)
+		case pr.Draft:
+			unmet("pull request is a draft")
+		default:
+		}
+	}
+	if p.RequireMergeable {
+		if reason := pr.MergeBlocker(); reason != "" {
+			unmet("not mergeable: %s", reason)
+		}
+	}
+
+	var approvals, changesRequested int
+	if a := pr.ApprovalSummary; a != nil {
+		approvals, changesRequested = a.ApprovalsWithWriteAccess, a.ChangesRequested
+	}
+	if approvals < p.MinApprovals {
+		unmet("%d of %d required approvals from reviewers with write access", approvals, p.MinApprovals)
+	}
+	if p.RequireNoChangesRequested && changesRequested > 0 {
+		unmet("%d outstanding change requests", changesRequested)
+	}
+	if p.MaxUnresolvedThreads != nil && pr.ThreadSummary != nil && pr.ThreadSummary.Unresolved > *p.MaxUnresolvedThreads {
+		unmet("%d unresolved review threads, at most %d allowed", pr.ThreadSummary.Unresolved, *p.MaxUnresolvedThreads)
+	}
+
+	checks := pr.CheckSummary
+	if checks == nil {
+		checks = &CheckSummary{}
+	}
+	for _, name := range p.RequiredChecks {
+		_, ok := checks.Success[name]
+		if _, skipped := checks.Skipped[name]; skipped {
+			ok = true
+		}
+		if _, neutral := checks.Neutral[name]; neutral {
+			ok = true
+		}
+		if !ok {
+			unmet("required check %q %s", name, checkStatus(checks, name))
+		}
+	}
+	if p.RequirePassingChecks {
+		failing := checkNames(checks.Failing, checks.Cancelled)
+		pending := checkNames(checks.Pending)
+		if len(failing) > 0 {
+			unmet("failing checks: %s", strings.Join(failing, ", "))
+		}
+		if len(pending) > 0 {
+			unmet("pending checks: %s", strings.Join(pending, ", "))
+		}
+	}
+
+	if p.RequireTicket && len(pr.TicketRefs) == 0 {
+		unmet("head branch %q references no ticket", pr.HeadRef)
+	}
+	if p.RequireCompleteDescription {
+		switch d := pr.DescriptionCheck; {
+		case d == nil:
+			unmet("description wasn't checked against a template")
+		case len(d.Missing) > 0:
+			unmet("description is missing sections: %s", strings.Join(d.Missing, ", "))
+		case len(d.Empty) > 0:
+			unmet("description sections not filled in: %s", strings.Join(d.Empty, ", "))
+		default:
+		}
+	}
+
+	result.Passed = len(result.Unmet) == 0
+	return result
+}
+
+// checkStatus describes the state of a check that hasn't passed.
+func checkStatus(checks *CheckSummary, name string) string {
+	for _, s := range []struct {
+		checks map[string]string
+		status string
+	}{
+		{checks.Failing, "failed"},
+		{checks.Cancelled, "was cancelled"},
+		{checks.Pending, "is pending"},
+		{checks.Stale, "is stale"},
+	} {
+		if _, ok := s.checks[name]; ok {
+			return s.status
+		}
+	}
+	return "hasn't run"
+}
+
+// checkNames returns the sorted names of the checks in summaries.
+func checkNames(summaries ...map[string]string) []string {
+	var names []string
+	for _, m := range summaries {
+		for name := range m {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseFlatYAML parses YAML consisting of top-level keys with scalar values, inline lists, or block lists.
+func parseFlatYAML(text string) (map[string]any, error) {
+	fields := make(map[string]any)
+	var listKey string
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(stripYAMLComment(line), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			items, _ := fields[listKey].([]any)
+			fields[listKey] = append(items, yamlScalar(strings.TrimSpace(item)))
+			continue
+		}
+		if line != trimmed {
+			return nil, fmt.Errorf("line %d: nested values are not supported", i+1)
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", i+1)
+		}
+		if _, dup := fields[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", i+1, key)
+		}
+		value = strings.TrimSpace(value)
+		listKey = ""
+		switch {
+		case value == "":
+			fields[key] = nil // Null unless block list items follow
+			listKey = key
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			items := []any{}
+			if inner := strings.TrimSpace(value[1 : len(value)-1]); inner != "" {
+				for item := range strings.SplitSeq(inner, ",") {
+					items = append(items, yamlScalar(strings.TrimSpace(item)))
+				}
+			}
+			fields[key] = items
+		case strings.HasPrefix(value, "{"), strings.HasPrefix(value, "|"), strings.HasPrefix(value, ">"):
+			return nil, fmt.Errorf("line %d: only scalar and list values are supported", i+1)
+		default:
+			fields[key] = yamlScalar(value)
+		}
+	}
+	return fields, nil
+}
+
+// stripYAMLComment removes a trailing comment, ignoring # inside quoted strings.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		default:
+		}
+	}
+	return line
+}
+
+// yamlScalar converts a YAML scalar to a bool, integer, or string.
+func yamlScalar(s string) any {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if unquoted, err := strconv.Unquote(s); err == nil {
+				return unquoted
+			}
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null", "~":
+		return nil
+	default:
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package prx
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	yaml := `# Merge requirements
+name: release-gate
+min_approvals: 2
+required_checks: [build, "lint # strict"]
+max_unresolved_threads: 0   # every thread resolved
+require_passing_checks: true
+require_ticket:
+`
+	p, err := ParsePolicy([]byte(yaml))
+	if err != nil {
+		t.Fatalf("ParsePolicy() error: %v", err)
+	}
+	if p.Name != "release-gate" || p.MinApprovals != 2 || !p.RequirePassingChecks || p.RequireTicket ||
+		!slices.Equal(p.RequiredChecks, []string{"build", "lint # strict"}) ||
+		p.MaxUnresolvedThreads == nil || *p.MaxUnresolvedThreads != 0 {
+		t.Errorf("ParsePolicy() = %+v", p)
+	}
+
+	p, err = ParsePolicy([]byte("required_checks:\n  - build\n  - test\n"))
+	if err != nil || !slices.Equal(p.RequiredChecks, []string{"build", "test"}) || p.Name != "gate" {
+		t.Errorf("ParsePolicy(block list) = %+v, %v", p, err)
+	}
+	p, err = ParsePolicy([]byte(`{"min_approvals": 1, "require_ready": true}`))
+	if err != nil || p.MinApprovals != 1 || !p.RequireReady {
+		t.Errorf("ParsePolicy(JSON) = %+v, %v", p, err)
+	}
+
+	for _, invalid := range []string{
+		"min_aprovals: 2",                    // Typo
+		"min_approvals: two",                 // Wrong type
+		"checks:\n  build: true",             // Nested mapping
+		"min_approvals: 1\nmin_approvals: 2", // Duplicate
+		"just text",
+	} {
+		if _, err := ParsePolicy([]byte(invalid)); err == nil {
+			t.Errorf("ParsePolicy(%q) succeeded, want error", invalid)
+		}
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	zero := 0
+	p := &Policy{
+		Name:                      "gate",
+		MinApprovals:              2,
+		RequiredChecks:            []string{"build", "lint", "docs"},
+		RequirePassingChecks:      true,
+		RequireNoChangesRequested: true,
+		MaxUnresolvedThreads:      &zero,
+		RequireReady:              true,
+		RequireTicket:             true,
+	}
+	data := &PullRequestData{PullRequest: PullRequest{
+		State:           "open",
+		HeadRef:         "fix-typo",
+		ApprovalSummary: &ApprovalSummary{ApprovalsWithWriteAccess: 1, ChangesRequested: 1},
+		ThreadSummary:   &ThreadSummary{Total: 3, Unresolved: 2},
+		CheckSummary: &CheckSummary{
+			Success: map[string]string{"build": "ok"},
+			Failing: map[string]string{"lint": "2 errors"},
+			Pending: map[string]string{"e2e": "running"},
+			Skipped: map[string]string{"docs": "no changes"},
+		},
+	}}
+
+	result := p.Evaluate(data)
+	want := []string{
+		"1 of 2 required approvals from reviewers with write access",
+		"1 outstanding change requests",
+		"2 unresolved review threads, at most 0 allowed",
+		`required check "lint" failed`,
+		"failing checks: lint",
+		"pending checks: e2e",
+		`head branch "fix-typo" references no ticket`,
+	}
+	if result.Passed || !slices.Equal(result.Unmet, want) {
+		t.Errorf("Evaluate() unmet =\n%s\nwant\n%s", strings.Join(result.Unmet, "\n"), strings.Join(want, "\n"))
+	}
+	verdict := result.Verdict("abc123")
+	if verdict.Passed || verdict.HeadSHA != "abc123" || !strings.HasPrefix(verdict.Reason, want[0]+"; ") {
+		t.Errorf("Verdict() = %+v", verdict)
+	}
+
+	data.PullRequest.HeadRef = "PROJ-12-fix-typo"
+	data.PullRequest.TicketRefs = []string{"PROJ-12"}
+	data.PullRequest.ApprovalSummary = &ApprovalSummary{ApprovalsWithWriteAccess: 2}
+	data.PullRequest.ThreadSummary = &ThreadSummary{Total: 3, Resolved: 3}
+	data.PullRequest.CheckSummary = &CheckSummary{Success: map[string]string{"build": "ok", "lint": "ok"}, Neutral: map[string]string{"docs": ""}}
+	if result := p.Evaluate(data); !result.Passed {
+		t.Errorf("Evaluate() unmet = %v, want passed", result.Unmet)
+	}
+
+	data.PullRequest.Draft = true
+	if result := p.Evaluate(data); result.Passed || result.Unmet[0] != "pull request is a draft" {
+		t.Errorf("Evaluate() = %+v, want draft to fail", result)
+	}
+}
@@ -0,0 +1,49 @@
+package prx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/localfs"
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PolicyVerdict(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	newClient := func() *Client {
+		store, err := localfs.New[string, PolicyVerdict]("prx-policy", dir)
+		if err != nil {
+			t.Fatalf("creating store: %v", err)
+		}
+		return NewClient("token", WithCacheStore(null.New[string, PullRequestData]()), WithPolicyStore(store))
+	}
+
+	first := newClient()
+	if err := first.RecordPolicyVerdict(ctx, "Owner", "repo", PolicyVerdict{Policy: "two-approvals"}); err == nil {
+		t.Error("expected error for verdict without head SHA")
+	}
+	if err := first.RecordPolicyVerdict(ctx, "Owner", "repo", PolicyVerdict{
+		Policy: "two-approvals", HeadSHA: "abc123", Passed: true, Reason: "approved by alice and bob",
+	}); err != nil {
+		t.Fatalf("RecordPolicyVerdict() error: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	second := newClient()
+	got, found, err := second.PolicyVerdict(ctx, "owner", "repo", "abc123", "two-approvals")
+	if err != nil || !found {
+		t.Fatalf("PolicyVerdict() = %+v, %v, %v; want persisted verdict", got, found, err)
+	}
+	if !got.Passed || got.Reason != "approved by alice and bob" || got.EvaluatedAt.IsZero() {
+		t.Errorf("PolicyVerdict() = %+v, want passed verdict with evaluation time", got)
+	}
+
+	for _, tc := range []struct{ sha, policy string }{{"def456", "two-approvals"}, {"abc123", "ci-green"}} {
+		if _, found, _ := second.PolicyVerdict(ctx, "owner", "repo", tc.sha, tc.policy); found {
+			t.Errorf("PolicyVerdict(%s, %s) found, want no verdict", tc.sha, tc.policy)
+		}
+	}
+}
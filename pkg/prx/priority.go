@@ -0,0 +1,151 @@
+package prx
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scales at which each priority factor reaches half of its weight.
+const (
+	priorityAgeScale  = 7 * 24 * time.Hour // Time since the pull request was opened
+	priorityWaitScale = 24 * time.Hour     // Time waiting on reviewers since the author last acted
+	prioritySizeScale = 200                // Lines changed; smaller pull requests score higher
+)
+
+// PriorityWeights configures a PriorityScorer. Each factor is scaled to between 0 and 1 and multiplied
+// by its weight, so weights set the factors' relative importance; a zero weight ignores a factor.
+type PriorityWeights struct {
+	SensitivePaths []string      // CODEOWNERS-style patterns of security-sensitive files
+	SecurityLabels []string      // Labels marking security-sensitive pull requests
+	SLA            time.Duration // How long reviewers may keep an author waiting; 0 for none
+	Age            float64       // Older pull requests first
+	Size           float64       // Smaller pull requests first
+	AuthorWait     float64       // Pull requests whose authors have waited longest on reviewers first
+	Security       float64       // Security-sensitive pull requests first
+	SLABreach      float64       // Pull requests past the SLA first
+}
+
+// DefaultPriorityWeights returns weights that favor SLA breaches, then security-sensitive changes, then
+// authors kept waiting, with a 2-day review SLA and the "security" label marking sensitive pull requests.
+func DefaultPriorityWeights() PriorityWeights {
+	return PriorityWeights{
+		SecurityLabels: []string{"security"},
+		SLA:            48 * time.Hour,
+		Age:            1,
+		Size:           1,
+		AuthorWait:     2,
+		Security:       3,
+		SLABreach:      5,
+	}
+}
+
+// PriorityScore is a pull request's priority in a review queue, with the weighted contribution of
+// each factor so queues can explain their order.
+type PriorityScore struct {
+	Score       float64 `json:"score"` // Sum of the contributions; higher goes first
+	Age         float64 `json:"age"`
+	Size        float64 `json:"size"`
+	AuthorWait  float64 `json:"author_wait"`
+	Security    float64 `json:"security"`
+	SLABreach   float64 `json:"sla_breach"`
+	SLABreached bool    `json:"sla_breached,omitempty"`
+}
+
+// PriorityScorer scores pull requests with fixed weights, so every queue using the same weights
+// ranks pull requests the same way.
+type PriorityScorer struct {
+	sensitive []*regexp.Regexp
+	weights   PriorityWeights
+}
+
+// NewPriorityScorer returns a scorer using weights. It fails if a sensitive path pattern is invalid.
+func NewPriorityScorer(weights PriorityWeights) (*PriorityScorer, error) {
+	s := &PriorityScorer{weights: weights}
+	for _, pattern := range weights.SensitivePaths {
+		re, err := codeownersPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("sensitive path: %w", err)
+		}
+		s.sensitive = append(s.sensitive, re)
+	}
+	return s, nil
+}
+
+// Score scores a pull request as of now. Closed pull requests score zero. Author wait time counts only
+// while the pull request is waiting on reviewers, from the author's last activity.
+func (s *PriorityScorer) Score(data *PullRequestData, now time.Time) PriorityScore {
+	pr := &data.PullRequest
+	var score PriorityScore
+	if pr.State != "open" {
+		return score
+	}
+	w := &s.weights
+
+	score.Age = w.Age * saturate(now.Sub(pr.CreatedAt).Hours(), priorityAgeScale.Hours())
+	score.Size = w.Size * (1 - saturate(float64(pr.Additions+pr.Deletions), prioritySizeScale))
+	if pr.WaitingOn == WaitingOnReviewers {
+		since := pr.LastAuthorActivity
+		if since.IsZero() {
+			since = pr.CreatedAt
+		}
+		wait := now.Sub(since)
+		score.AuthorWait = w.AuthorWait * saturate(wait.Hours(), priorityWaitScale.Hours())
+		if w.SLA > 0 && wait > w.SLA {
+			score.SLABreached = true
+			score.SLABreach = w.SLABreach
+		}
+	}
+	if s.isSensitive(pr) {
+		score.Security = w.Security
+	}
+	score.Score = score.Age + score.Size + score.AuthorWait + score.Security + score.SLABreach
+	return score
+}
+
+// Rank sorts prs by descending priority as of now, breaking ties by pull request number, and returns
+// their scores in the same order.
+func (s *PriorityScorer) Rank(prs []*PullRequestData, now time.Time) []PriorityScore {
+	type scored struct {
+		data  *PullRequestData
+		score PriorityScore
+	}
+	ranked := make([]scored, len(prs))
+	for i, d := range prs {
+		ranked[i] = scored{data: d, score: s.Score(d, now)}
+	}
+	slices.SortStableFunc(ranked, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score.Score, a.score.Score), cmp.Compare(a.data.PullRequest.Number, b.data.PullRequest.Number))
+	})
+	scores := make([]PriorityScore, len(ranked))
+	for i, r := range ranked {
+		prs[i], scores[i] = r.data, r.score
+	}
+	return scores
+}
+
+// isSensitive reports whether pr has a security label or changes a sensitive path.
+func (s *PriorityScorer) isSensitive(pr *PullRequest) bool {
+	for _, label := range pr.Labels {
+		if slices.ContainsFunc(s.weights.SecurityLabels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return true
+		}
+	}
+	for _, path := range pr.Files {
+		if slices.ContainsFunc(s.sensitive, func(re *regexp.Regexp) bool { return re.MatchString(path) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// saturate maps x ≥ 0 to [0, 1), reaching 0.5 at scale.
+func saturate(x, scale float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return x / (x + scale)
+}
@@ -0,0 +1,71 @@
+package prx
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPriorityScorer(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	weights := DefaultPriorityWeights()
+	weights.SensitivePaths = []string{"/internal/auth/"}
+	s, err := NewPriorityScorer(weights)
+	if err != nil {
+		t.Fatalf("NewPriorityScorer() error: %v", err)
+	}
+
+	pr := func(number int, age time.Duration, lines int, waiting WaitingOn) *PullRequestData {
+		return &PullRequestData{PullRequest: PullRequest{
+			Number:             number,
+			State:              "open",
+			CreatedAt:          now.Add(-age),
+			LastAuthorActivity: now.Add(-age),
+			Additions:          lines,
+			WaitingOn:          waiting,
+		}}
+	}
+	fresh := pr(1, time.Hour, 20, WaitingOnReviewers)
+	breached := pr(2, 3*24*time.Hour, 400, WaitingOnReviewers)
+	authTouching := pr(3, time.Hour, 20, WaitingOnAuthor)
+	authTouching.PullRequest.Files = []string{"internal/auth/token.go"}
+	labeled := pr(4, time.Hour, 20, WaitingOnChecks)
+	labeled.PullRequest.Labels = []string{"Security"}
+	closed := pr(5, 30*24*time.Hour, 1, WaitingOnNobody)
+	closed.PullRequest.State = "closed"
+
+	score := s.Score(breached, now)
+	if !score.SLABreached || score.SLABreach != 5 || score.Security != 0 ||
+		score.AuthorWait != 1.5 || math.Abs(score.Size-1.0/3) > 1e-9 {
+		t.Errorf("Score(breached) = %+v", score)
+	}
+	if score := s.Score(authTouching, now); score.Security != 3 || score.AuthorWait != 0 || score.SLABreached {
+		t.Errorf("Score(sensitive path) = %+v", score)
+	}
+	if score := s.Score(labeled, now); score.Security != 3 {
+		t.Errorf("Score(security label) = %+v", score)
+	}
+	if score := s.Score(closed, now); score != (PriorityScore{}) {
+		t.Errorf("Score(closed) = %+v, want zero", score)
+	}
+
+	queue := []*PullRequestData{fresh, closed, labeled, breached, authTouching}
+	scores := s.Rank(queue, now)
+	var order []int
+	for _, d := range queue {
+		order = append(order, d.PullRequest.Number)
+	}
+	if want := []int{2, 3, 4, 1, 5}; !slices.Equal(order, want) {
+		t.Errorf("Rank() order = %v, want %v", order, want)
+	}
+	for i := 1; i < len(scores); i++ {
+		if scores[i].Score > scores[i-1].Score {
+			t.Errorf("Rank() scores not descending: %+v", scores)
+		}
+	}
+
+	if _, err := NewPriorityScorer(PriorityWeights{SensitivePaths: []string{"/"}}); err == nil {
+		t.Error("NewPriorityScorer() with an empty pattern succeeded, want error")
+	}
+}
@@ -0,0 +1,89 @@
+package prx
+
+import (
+	"time"
+
+	"github.com/codeGROOVE-dev/fido"
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// Profile is a named preset of options suited to a way of using the client.
+type Profile string
+
+// Profiles accepted by WithProfile.
+const (
+	// ProfileInteractive suits a person waiting on the result, e.g. in a CLI or dashboard: requests give
+	// up after a few quick retries, repository settings are refreshed every 15 minutes, and bodies
+	// aren't truncated.
+	ProfileInteractive Profile = "interactive"
+	// ProfileCI suits a CI job on an ephemeral runner: the PR cache isn't persisted, and 100 requests
+	// of rate limit are kept in reserve for the job's other steps, which share the token.
+	ProfileCI Profile = "ci"
+	// ProfileBulk suits backfills and batch analysis of many pull requests: retries wait out rate
+	// limits, commits are fetched 16 at a time, repository settings are cached for 12 hours, and
+	// 500 requests of rate limit are kept in reserve.
+	ProfileBulk Profile = "bulk"
+)
+
+// WithProfile applies a preset of options. Options given after it override the preset's choices.
+// Unknown profiles are ignored with a warning.
+func WithProfile(p Profile) Option {
+	return func(c *Client) {
+		var opts []Option
+		switch p {
+		case ProfileInteractive:
+			opts = []Option{WithRetry(3, 10*time.Second), WithRepositoryCacheTTL(15 * time.Minute), WithFullBodies()}
+		case ProfileCI:
+			opts = []Option{WithRetry(5, time.Minute), WithCacheStore(null.New[string, PullRequestData]()), WithRateLimitBudget(100)}
+		case ProfileBulk:
+			opts = []Option{WithRetry(10, 2*time.Minute), WithConcurrency(16), WithRepositoryCacheTTL(12 * time.Hour), WithRateLimitBudget(500)}
+		default:
+			c.logger.Warn("unknown client profile, ignoring", "profile", p)
+			return
+		}
+		c.profile = p
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
+// WithRetry sets how many times a request failing with a server error or rate limit is attempted,
+// and the longest delay between attempts. The default is 10 attempts with delays of up to 2 minutes.
+func WithRetry(attempts int, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		c.retryAttempts = uint(max(attempts, 1))
+		c.retryMaxDelay = maxDelay
+	}
+}
+
+// WithRepositoryCacheTTL sets how long repository-level data — collaborators, rulesets, settings,
+// CODEOWNERS, and pull request templates — is cached in memory. The default is 3 hours.
+func WithRepositoryCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.repositoryCacheTTL = ttl
+	}
+}
+
+// configureRetry applies WithRetry to the GitHub client's transport.
+func (c *Client) configureRetry() {
+	if t, ok := c.github.HTTPClient.Transport.(*github.Transport); ok && c.retryAttempts > 0 {
+		t.Attempts = c.retryAttempts
+		t.MaxDelay = c.retryMaxDelay
+	}
+}
+
+// configureRepositoryCaches recreates the repository-level caches with the TTL set by WithRepositoryCacheTTL.
+func (c *Client) configureRepositoryCaches() {
+	if c.repositoryCacheTTL <= 0 {
+		return
+	}
+	ttl := fido.TTL(c.repositoryCacheTTL)
+	c.collaboratorsCache = fido.New[string, map[string]string](ttl)
+	c.teamsCache = fido.New[string, map[string]string](ttl)
+	c.rulesetsCache = fido.New[string, rulesetRequirements](ttl)
+	c.repositoryCache = fido.New[string, *Repository](ttl)
+	c.codeownersCache = fido.New[string, *Codeowners](ttl)
+	c.templatesCache = fido.New[string, []PullRequestTemplate](ttl)
+}
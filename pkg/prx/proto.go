@@ -0,0 +1,858 @@
+package prx
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// ToProto encodes the pull request data as a prx.v1.PullRequestData protocol buffer message, as
+// defined in proto/prx/v1/prx.proto, for gRPC services and compact storage. It's typically less than
+// half the size of the JSON encoding. Times are converted to UTC.
+func (d *PullRequestData) ToProto() ([]byte, error) {
+	var e protoEncoder
+	var err error
+	e.message(1, func(m *protoEncoder) { encodePullRequest(m, &d.PullRequest) })
+	for i := range d.Events {
+		e.message(2, func(m *protoEncoder) {
+			if encodeErr := encodeEvent(m, &d.Events[i]); encodeErr != nil && err == nil {
+				err = fmt.Errorf("event %d: %w", i, encodeErr)
+			}
+		})
+	}
+	e.timestamp(3, d.CachedAt)
+	if h := d.ActivityHistogram; h != nil {
+		e.message(4, func(m *protoEncoder) {
+			for _, b := range h.Hourly {
+				m.message(1, func(bm *protoEncoder) { encodeActivityBucket(bm, b) })
+			}
+			for _, b := range h.Daily {
+				m.message(2, func(bm *protoEncoder) { encodeActivityBucket(bm, b) })
+			}
+		})
+	}
+	if t := d.Timing; t != nil {
+		e.message(5, func(m *protoEncoder) {
+			m.optionalInt(1, (*int64)(t.TimeToFirstReview))
+			m.optionalInt(2, (*int64)(t.TimeToFirstApproval))
+			m.int(3, int(t.TimeInDraft))
+			m.int(4, t.ReviewIterations)
+		})
+	}
+	if p := d.Pruned; p != nil {
+		e.message(6, func(m *protoEncoder) {
+			m.timestamp(1, p.Oldest)
+			m.timestamp(2, p.Newest)
+			m.intMap(3, p.Kinds)
+			m.intMap(4, p.Actors)
+			m.int(5, p.Bots)
+			m.int(6, p.Total)
+		})
+	}
+	if r := d.Repository; r != nil {
+		e.message(7, func(m *protoEncoder) {
+			m.string(1, r.DefaultBranch)
+			m.string(2, r.Visibility)
+			m.string(3, r.Language)
+			m.strings(4, r.Topics)
+			m.bool(5, r.Archived)
+		})
+	}
+	for _, w := range d.FetchReport.Warnings {
+		e.message(8, func(m *protoEncoder) {
+			m.string(1, w.Section)
+			m.string(2, w.Target)
+			m.string(3, w.Error)
+			m.int(4, w.StatusCode)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// FromProto replaces the pull request data with a prx.v1.PullRequestData protocol buffer message,
+// as encoded by ToProto. Unknown fields, such as those added by a newer prx, are ignored.
+func (d *PullRequestData) FromProto(b []byte) error {
+	*d = PullRequestData{}
+	err := decodeProto(b, func(field int, v protoValue) error {
+		var err error
+		switch field {
+		case 1:
+			err = decodePullRequest(v.b, &d.PullRequest)
+		case 2:
+			var e Event
+			err = decodeEvent(v.b, &e)
+			d.Events = append(d.Events, e)
+		case 3:
+			d.CachedAt, err = v.timestamp()
+		case 4:
+			d.ActivityHistogram = &ActivityHistogram{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				bucket, err := decodeActivityBucket(v.b)
+				switch field {
+				case 1:
+					d.ActivityHistogram.Hourly = append(d.ActivityHistogram.Hourly, bucket)
+				case 2:
+					d.ActivityHistogram.Daily = append(d.ActivityHistogram.Daily, bucket)
+				default:
+				}
+				return err
+			})
+		case 5:
+			d.Timing = &TimingSummary{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					review := time.Duration(v.int64())
+					d.Timing.TimeToFirstReview = &review
+				case 2:
+					approval := time.Duration(v.int64())
+					d.Timing.TimeToFirstApproval = &approval
+				case 3:
+					d.Timing.TimeInDraft = time.Duration(v.int64())
+				case 4:
+					d.Timing.ReviewIterations = v.int()
+				default:
+				}
+				return nil
+			})
+		case 6:
+			d.Pruned = &PrunedEvents{Kinds: make(map[string]int), Actors: make(map[string]int)}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				var err error
+				switch field {
+				case 1:
+					d.Pruned.Oldest, err = v.timestamp()
+				case 2:
+					d.Pruned.Newest, err = v.timestamp()
+				case 3:
+					err = decodeIntMapEntry(v, d.Pruned.Kinds)
+				case 4:
+					err = decodeIntMapEntry(v, d.Pruned.Actors)
+				case 5:
+					d.Pruned.Bots = v.int()
+				case 6:
+					d.Pruned.Total = v.int()
+				default:
+				}
+				return err
+			})
+		case 7:
+			d.Repository = &Repository{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					d.Repository.DefaultBranch = v.string()
+				case 2:
+					d.Repository.Visibility = v.string()
+				case 3:
+					d.Repository.Language = v.string()
+				case 4:
+					d.Repository.Topics = append(d.Repository.Topics, v.string())
+				case 5:
+					d.Repository.Archived = v.bool()
+				default:
+				}
+				return nil
+			})
+		case 8:
+			var w FetchWarning
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					w.Section = v.string()
+				case 2:
+					w.Target = v.string()
+				case 3:
+					w.Error = v.string()
+				case 4:
+					w.StatusCode = v.int()
+				default:
+				}
+				return nil
+			})
+			d.FetchReport.Warnings = append(d.FetchReport.Warnings, w)
+		default:
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("decoding pull request data: %w", err)
+	}
+	return nil
+}
+
+//nolint:maintidx // One line per field
+func encodePullRequest(e *protoEncoder, pr *PullRequest) {
+	e.int(1, pr.Number)
+	e.string(2, pr.Title)
+	e.string(3, pr.Body)
+	e.string(4, pr.Author)
+	e.string(5, pr.State)
+	e.timestamp(6, pr.CreatedAt)
+	e.timestamp(7, pr.UpdatedAt)
+	e.optionalTimestamp(8, pr.ClosedAt)
+	e.optionalTimestamp(9, pr.MergedAt)
+	e.string(10, pr.MergedBy)
+	e.bool(11, pr.Merged)
+	e.bool(12, pr.Draft)
+	e.bool(13, pr.AuthorBot)
+	e.sint(14, int64(pr.AuthorWriteAccess))
+	e.optionalBool(15, pr.Mergeable)
+	e.string(16, pr.MergeableState)
+	e.string(17, pr.MergeableStateDescription)
+	e.string(18, pr.TestState)
+	e.string(19, pr.HeadSHA)
+	e.string(20, pr.HeadRef)
+	e.string(21, pr.NodeID)
+	e.string(22, string(pr.WaitingOn))
+	e.string(23, pr.WaitingOnReason)
+	e.int(24, pr.Additions)
+	e.int(25, pr.Deletions)
+	e.int(26, pr.ChangedFiles)
+	e.strings(27, pr.Assignees)
+	e.strings(28, pr.Labels)
+	e.strings(29, pr.Commits)
+	e.strings(30, pr.Files)
+	reviewers := make(map[string]string, len(pr.Reviewers))
+	for login, state := range pr.Reviewers {
+		reviewers[login] = string(state)
+	}
+	e.stringMap(31, reviewers)
+	for _, login := range slices.Sorted(maps.Keys(pr.ParticipantAccess)) {
+		e.message(32, func(entry *protoEncoder) {
+			entry.string(1, login)
+			entry.sint(2, int64(pr.ParticipantAccess[login]))
+		})
+	}
+	if a := pr.ApprovalSummary; a != nil {
+		e.message(33, func(m *protoEncoder) {
+			m.int(1, a.ApprovalsWithWriteAccess)
+			m.int(2, a.ApprovalsWithUnknownAccess)
+			m.int(3, a.ApprovalsWithoutWriteAccess)
+			m.int(4, a.ChangesRequested)
+			m.int(5, a.StaleApprovals)
+			m.stringMap(6, a.ApprovedAtSHA)
+		})
+	}
+	if s := pr.CheckSummary; s != nil {
+		e.message(34, func(m *protoEncoder) { encodeCheckSummary(m, s) })
+	}
+	if t := pr.ThreadSummary; t != nil {
+		e.message(35, func(m *protoEncoder) {
+			m.int(1, t.Total)
+			m.int(2, t.Resolved)
+			m.int(3, t.Unresolved)
+			m.int(4, t.Outdated)
+			m.int(5, t.PathDeleted)
+		})
+	}
+	for _, sha := range slices.Sorted(maps.Keys(pr.ChecksByCommit)) {
+		e.message(36, func(entry *protoEncoder) {
+			entry.string(1, sha)
+			entry.message(2, func(m *protoEncoder) { encodeCheckSummary(m, pr.ChecksByCommit[sha]) })
+		})
+	}
+	e.timestamp(37, pr.LastHumanActivity)
+	e.timestamp(38, pr.LastAuthorActivity)
+	e.timestamp(39, pr.LastReviewerActivity)
+	e.optionalTimestamp(40, pr.FrozenUntil)
+	if f := pr.FeatureFlags; f != nil {
+		e.message(41, func(m *protoEncoder) {
+			m.strings(1, f.ConfigFiles)
+			m.strings(2, f.Flags)
+		})
+	}
+	e.optionalBool(42, pr.BranchNameValid)
+	if c := pr.DescriptionCheck; c != nil {
+		e.message(43, func(m *protoEncoder) {
+			m.string(1, c.Template)
+			m.strings(2, c.Missing)
+			m.strings(3, c.Empty)
+		})
+	}
+	e.strings(44, pr.TicketRefs)
+	for _, t := range pr.Tickets {
+		e.message(45, func(m *protoEncoder) {
+			m.string(1, t.Key)
+			m.string(2, t.Title)
+			m.string(3, t.Status)
+			m.string(4, t.URL)
+		})
+	}
+	for _, ref := range pr.OverlappingPRs {
+		e.message(46, func(m *protoEncoder) {
+			m.string(1, ref.Owner)
+			m.string(2, ref.Repo)
+			m.strings(3, ref.Files)
+			m.int(4, ref.Number)
+		})
+	}
+	e.intMap(47, pr.CommitEmailDomains)
+	for _, sha := range slices.Sorted(maps.Keys(pr.CommitFiles)) {
+		e.message(48, func(entry *protoEncoder) {
+			entry.string(1, sha)
+			entry.message(2, func(m *protoEncoder) { m.strings(1, pr.CommitFiles[sha]) })
+		})
+	}
+	e.bytes(49, pr.Raw)
+	e.bool(50, pr.RepoArchived)
+	e.bool(51, pr.ConversationLocked)
+	e.int(52, pr.ForcePushCount)
+	e.bool(53, pr.ForcePushedAfterApproval)
+	e.int(54, pr.RequiredApprovals)
+	e.bool(55, pr.ApprovalsSatisfied)
+	if q := pr.MergeQueue; q != nil {
+		e.message(56, func(m *protoEncoder) { encodeMergeQueueState(m, q) })
+	}
+	for _, n := range pr.ClosesIssues {
+		e.int(57, n) // Issue numbers are positive, so none is left out as zero
+	}
+	for _, path := range slices.Sorted(maps.Keys(pr.ChangedLineAuthors)) {
+		e.message(58, func(entry *protoEncoder) {
+			entry.string(1, path)
+			entry.message(2, func(m *protoEncoder) { m.intMap(1, pr.ChangedLineAuthors[path]) })
+		})
+	}
+	e.strings(59, pr.ClosesIssueRefs)
+}
+
+//nolint:maintidx,gocyclo // One case per field
+func decodePullRequest(b []byte, pr *PullRequest) error {
+	pr.Assignees = make([]string, 0)
+	return decodeProto(b, func(field int, v protoValue) error {
+		var err error
+		switch field {
+		case 1:
+			pr.Number = v.int()
+		case 2:
+			pr.Title = v.string()
+		case 3:
+			pr.Body = v.string()
+		case 4:
+			pr.Author = v.string()
+		case 5:
+			pr.State = v.string()
+		case 6:
+			pr.CreatedAt, err = v.timestamp()
+		case 7:
+			pr.UpdatedAt, err = v.timestamp()
+		case 8:
+			pr.ClosedAt, err = optionalTimestamp(v)
+		case 9:
+			pr.MergedAt, err = optionalTimestamp(v)
+		case 10:
+			pr.MergedBy = v.string()
+		case 11:
+			pr.Merged = v.bool()
+		case 12:
+			pr.Draft = v.bool()
+		case 13:
+			pr.AuthorBot = v.bool()
+		case 14:
+			pr.AuthorWriteAccess = v.sint()
+		case 15:
+			mergeable := v.bool()
+			pr.Mergeable = &mergeable
+		case 16:
+			pr.MergeableState = v.string()
+		case 17:
+			pr.MergeableStateDescription = v.string()
+		case 18:
+			pr.TestState = v.string()
+		case 19:
+			pr.HeadSHA = v.string()
+		case 20:
+			pr.HeadRef = v.string()
+		case 21:
+			pr.NodeID = v.string()
+		case 22:
+			pr.WaitingOn = WaitingOn(v.string())
+		case 23:
+			pr.WaitingOnReason = v.string()
+		case 24:
+			pr.Additions = v.int()
+		case 25:
+			pr.Deletions = v.int()
+		case 26:
+			pr.ChangedFiles = v.int()
+		case 27:
+			pr.Assignees = append(pr.Assignees, v.string())
+		case 28:
+			pr.Labels = append(pr.Labels, v.string())
+		case 29:
+			pr.Commits = append(pr.Commits, v.string())
+		case 30:
+			pr.Files = append(pr.Files, v.string())
+		case 31:
+			var login string
+			var state protoValue
+			if login, state, err = v.mapEntry(); err == nil {
+				if pr.Reviewers == nil {
+					pr.Reviewers = make(map[string]ReviewState)
+				}
+				pr.Reviewers[login] = ReviewState(state.string())
+			}
+		case 32:
+			var login string
+			var access protoValue
+			if login, access, err = v.mapEntry(); err == nil {
+				if pr.ParticipantAccess == nil {
+					pr.ParticipantAccess = make(map[string]int)
+				}
+				pr.ParticipantAccess[login] = access.sint()
+			}
+		case 33:
+			pr.ApprovalSummary = &ApprovalSummary{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					pr.ApprovalSummary.ApprovalsWithWriteAccess = v.int()
+				case 2:
+					pr.ApprovalSummary.ApprovalsWithUnknownAccess = v.int()
+				case 3:
+					pr.ApprovalSummary.ApprovalsWithoutWriteAccess = v.int()
+				case 4:
+					pr.ApprovalSummary.ChangesRequested = v.int()
+				case 5:
+					pr.ApprovalSummary.StaleApprovals = v.int()
+				case 6:
+					login, sha, err := v.mapEntry()
+					if err != nil {
+						return err
+					}
+					if pr.ApprovalSummary.ApprovedAtSHA == nil {
+						pr.ApprovalSummary.ApprovedAtSHA = make(map[string]string)
+					}
+					pr.ApprovalSummary.ApprovedAtSHA[login] = sha.string()
+				default:
+				}
+				return nil
+			})
+		case 34:
+			pr.CheckSummary, err = decodeCheckSummary(v.b)
+		case 35:
+			pr.ThreadSummary = &ThreadSummary{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					pr.ThreadSummary.Total = v.int()
+				case 2:
+					pr.ThreadSummary.Resolved = v.int()
+				case 3:
+					pr.ThreadSummary.Unresolved = v.int()
+				case 4:
+					pr.ThreadSummary.Outdated = v.int()
+				case 5:
+					pr.ThreadSummary.PathDeleted = v.int()
+				default:
+				}
+				return nil
+			})
+		case 36:
+			var sha string
+			var summary protoValue
+			if sha, summary, err = v.mapEntry(); err == nil {
+				if pr.ChecksByCommit == nil {
+					pr.ChecksByCommit = make(map[string]*CheckSummary)
+				}
+				pr.ChecksByCommit[sha], err = decodeCheckSummary(summary.b)
+			}
+		case 37:
+			pr.LastHumanActivity, err = v.timestamp()
+		case 38:
+			pr.LastAuthorActivity, err = v.timestamp()
+		case 39:
+			pr.LastReviewerActivity, err = v.timestamp()
+		case 40:
+			pr.FrozenUntil, err = optionalTimestamp(v)
+		case 41:
+			pr.FeatureFlags = &FeatureFlagChanges{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					pr.FeatureFlags.ConfigFiles = append(pr.FeatureFlags.ConfigFiles, v.string())
+				case 2:
+					pr.FeatureFlags.Flags = append(pr.FeatureFlags.Flags, v.string())
+				default:
+				}
+				return nil
+			})
+		case 42:
+			valid := v.bool()
+			pr.BranchNameValid = &valid
+		case 43:
+			pr.DescriptionCheck = &DescriptionCheck{}
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					pr.DescriptionCheck.Template = v.string()
+				case 2:
+					pr.DescriptionCheck.Missing = append(pr.DescriptionCheck.Missing, v.string())
+				case 3:
+					pr.DescriptionCheck.Empty = append(pr.DescriptionCheck.Empty, v.string())
+				default:
+				}
+				return nil
+			})
+		case 44:
+			pr.TicketRefs = append(pr.TicketRefs, v.string())
+		case 45:
+			var t Ticket
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					t.Key = v.string()
+				case 2:
+					t.Title = v.string()
+				case 3:
+					t.Status = v.string()
+				case 4:
+					t.URL = v.string()
+				default:
+				}
+				return nil
+			})
+			pr.Tickets = append(pr.Tickets, t)
+		case 46:
+			var ref PRRef
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					ref.Owner = v.string()
+				case 2:
+					ref.Repo = v.string()
+				case 3:
+					ref.Files = append(ref.Files, v.string())
+				case 4:
+					ref.Number = v.int()
+				default:
+				}
+				return nil
+			})
+			pr.OverlappingPRs = append(pr.OverlappingPRs, ref)
+		case 47:
+			if pr.CommitEmailDomains == nil {
+				pr.CommitEmailDomains = make(map[string]int)
+			}
+			err = decodeIntMapEntry(v, pr.CommitEmailDomains)
+		case 48:
+			var sha string
+			var list protoValue
+			if sha, list, err = v.mapEntry(); err == nil {
+				var files []string
+				err = decodeProto(list.b, func(field int, v protoValue) error {
+					if field == 1 {
+						files = append(files, v.string())
+					}
+					return nil
+				})
+				if pr.CommitFiles == nil {
+					pr.CommitFiles = make(map[string][]string)
+				}
+				pr.CommitFiles[sha] = files
+			}
+		case 49:
+			pr.Raw = v.bytes()
+		case 50:
+			pr.RepoArchived = v.bool()
+		case 51:
+			pr.ConversationLocked = v.bool()
+		case 52:
+			pr.ForcePushCount = v.int()
+		case 53:
+			pr.ForcePushedAfterApproval = v.bool()
+		case 54:
+			pr.RequiredApprovals = v.int()
+		case 55:
+			pr.ApprovalsSatisfied = v.bool()
+		case 56:
+			pr.MergeQueue, err = decodeMergeQueueState(v.b)
+		case 57:
+			pr.ClosesIssues = append(pr.ClosesIssues, v.int())
+		case 58:
+			var path string
+			var counts protoValue
+			if path, counts, err = v.mapEntry(); err == nil {
+				authors := make(map[string]int)
+				err = decodeProto(counts.b, func(field int, v protoValue) error {
+					if field == 1 {
+						return decodeIntMapEntry(v, authors)
+					}
+					return nil
+				})
+				if pr.ChangedLineAuthors == nil {
+					pr.ChangedLineAuthors = make(map[string]map[string]int)
+				}
+				pr.ChangedLineAuthors[path] = authors
+			}
+		case 59:
+			pr.ClosesIssueRefs = append(pr.ClosesIssueRefs, v.string())
+		default:
+		}
+		return err
+	})
+}
+
+func encodeCheckSummary(e *protoEncoder, s *CheckSummary) {
+	if s == nil {
+		return
+	}
+	e.stringMap(1, s.Success)
+	e.stringMap(2, s.Failing)
+	e.stringMap(3, s.Pending)
+	e.stringMap(4, s.Cancelled)
+	e.stringMap(5, s.Skipped)
+	e.stringMap(6, s.Stale)
+	e.stringMap(7, s.Neutral)
+}
+
+// decodeCheckSummary decodes a CheckSummary, whose maps are never nil, as calculated by prx.
+func decodeCheckSummary(b []byte) (*CheckSummary, error) {
+	s := &CheckSummary{
+		Success:   make(map[string]string),
+		Failing:   make(map[string]string),
+		Pending:   make(map[string]string),
+		Cancelled: make(map[string]string),
+		Skipped:   make(map[string]string),
+		Stale:     make(map[string]string),
+		Neutral:   make(map[string]string),
+	}
+	byField := []map[string]string{nil, s.Success, s.Failing, s.Pending, s.Cancelled, s.Skipped, s.Stale, s.Neutral}
+	err := decodeProto(b, func(field int, v protoValue) error {
+		if field < 1 || field >= len(byField) {
+			return nil
+		}
+		name, description, err := v.mapEntry()
+		byField[field][name] = description.string()
+		return err
+	})
+	return s, err
+}
+
+func encodeMergeQueueState(e *protoEncoder, q *MergeQueueState) {
+	e.optionalTimestamp(1, q.EnqueuedAt)
+	if c := q.Configuration; c != nil {
+		e.message(2, func(m *protoEncoder) {
+			m.string(1, c.MergeMethod)
+			m.string(2, c.MergingStrategy)
+			m.int(3, c.MinimumEntriesToMerge)
+			m.int(4, c.MaximumEntriesToMerge)
+			m.int(5, c.MaximumEntriesToBuild)
+			m.int(6, c.CheckResponseTimeout)
+		})
+	}
+	e.string(3, q.State)
+	e.string(4, q.RemovedReason)
+	e.int(5, q.Position)
+	e.bool(6, q.InQueue)
+}
+
+func decodeMergeQueueState(b []byte) (*MergeQueueState, error) {
+	q := &MergeQueueState{}
+	err := decodeProto(b, func(field int, v protoValue) error {
+		var err error
+		switch field {
+		case 1:
+			q.EnqueuedAt, err = optionalTimestamp(v)
+		case 2:
+			c := &MergeQueueConfiguration{}
+			q.Configuration = c
+			err = decodeProto(v.b, func(field int, v protoValue) error {
+				switch field {
+				case 1:
+					c.MergeMethod = v.string()
+				case 2:
+					c.MergingStrategy = v.string()
+				case 3:
+					c.MinimumEntriesToMerge = v.int()
+				case 4:
+					c.MaximumEntriesToMerge = v.int()
+				case 5:
+					c.MaximumEntriesToBuild = v.int()
+				case 6:
+					c.CheckResponseTimeout = v.int()
+				default:
+				}
+				return nil
+			})
+		case 3:
+			q.State = v.string()
+		case 4:
+			q.RemovedReason = v.string()
+		case 5:
+			q.Position = v.int()
+		case 6:
+			q.InQueue = v.bool()
+		default:
+		}
+		return err
+	})
+	return q, err
+}
+
+func encodeEvent(e *protoEncoder, ev *Event) error {
+	e.timestamp(1, ev.Timestamp)
+	e.string(2, string(ev.Kind))
+	e.string(3, ev.Actor)
+	e.string(4, string(ev.ActorType))
+	e.string(5, ev.Target)
+	e.string(6, ev.Outcome)
+	e.string(7, ev.Body)
+	e.string(8, ev.Description)
+	e.strings(9, ev.Mentions)
+	e.uint(10, uint64(ev.CheckRunID)) //nolint:gosec // IDs are positive
+	e.uint(11, uint64(ev.Seq))        //nolint:gosec // Sequence numbers are positive
+	e.sint(12, int64(ev.WriteAccess))
+	e.bool(13, ev.Bot)
+	e.bool(14, ev.TargetIsBot)
+	e.bool(15, ev.Question)
+	e.bool(16, ev.Suggestion)
+	e.bool(17, ev.Required)
+	e.bool(18, ev.Outdated)
+	e.intMap(19, ev.Reactions)
+	e.bool(20, ev.ThreadResolved)
+	e.stringMap(21, ev.Tags)
+	if len(ev.Annotations) > 0 {
+		annotations, err := json.Marshal(ev.Annotations)
+		if err != nil {
+			return fmt.Errorf("encoding annotations: %w", err)
+		}
+		e.bytes(22, annotations)
+	}
+	e.bytes(23, ev.Raw)
+	e.bool(24, ev.PathDeleted)
+	e.string(25, ev.BeforeSHA)
+	e.string(26, ev.AfterSHA)
+	e.bool(27, ev.Orphaned)
+	e.string(28, ev.CommitSHA)
+	e.uint(29, uint64(ev.WorkflowRunID)) //nolint:gosec // IDs are positive
+	e.uint(30, uint64(ev.WorkflowJobID)) //nolint:gosec // IDs are positive
+	return nil
+}
+
+func decodeEvent(b []byte, ev *Event) error {
+	return decodeProto(b, func(field int, v protoValue) error {
+		var err error
+		switch field {
+		case 1:
+			ev.Timestamp, err = v.timestamp()
+		case 2:
+			ev.Kind = EventKind(v.string())
+		case 3:
+			ev.Actor = v.string()
+		case 4:
+			ev.ActorType = ActorType(v.string())
+		case 5:
+			ev.Target = v.string()
+		case 6:
+			ev.Outcome = v.string()
+		case 7:
+			ev.Body = v.string()
+		case 8:
+			ev.Description = v.string()
+		case 9:
+			ev.Mentions = append(ev.Mentions, v.string())
+		case 10:
+			ev.CheckRunID = v.int64()
+		case 11:
+			ev.Seq = v.int64()
+		case 12:
+			ev.WriteAccess = v.sint()
+		case 13:
+			ev.Bot = v.bool()
+		case 14:
+			ev.TargetIsBot = v.bool()
+		case 15:
+			ev.Question = v.bool()
+		case 16:
+			ev.Suggestion = v.bool()
+		case 17:
+			ev.Required = v.bool()
+		case 18:
+			ev.Outdated = v.bool()
+		case 19:
+			if ev.Reactions == nil {
+				ev.Reactions = make(map[string]int)
+			}
+			err = decodeIntMapEntry(v, ev.Reactions)
+		case 20:
+			ev.ThreadResolved = v.bool()
+		case 21:
+			var key string
+			var value protoValue
+			if key, value, err = v.mapEntry(); err == nil {
+				if ev.Tags == nil {
+					ev.Tags = make(map[string]string)
+				}
+				ev.Tags[key] = value.string()
+			}
+		case 22:
+			err = json.Unmarshal(v.b, &ev.Annotations)
+		case 23:
+			ev.Raw = v.bytes()
+		case 24:
+			ev.PathDeleted = v.bool()
+		case 25:
+			ev.BeforeSHA = v.string()
+		case 26:
+			ev.AfterSHA = v.string()
+		case 27:
+			ev.Orphaned = v.bool()
+		case 28:
+			ev.CommitSHA = v.string()
+		case 29:
+			ev.WorkflowRunID = v.int64()
+		case 30:
+			ev.WorkflowJobID = v.int64()
+		default:
+		}
+		return err
+	})
+}
+
+func encodeActivityBucket(e *protoEncoder, b ActivityBucket) {
+	e.timestamp(1, b.Start)
+	e.int(2, b.Comments)
+	e.int(3, b.Commits)
+	e.int(4, b.Checks)
+}
+
+func decodeActivityBucket(b []byte) (ActivityBucket, error) {
+	var bucket ActivityBucket
+	err := decodeProto(b, func(field int, v protoValue) error {
+		var err error
+		switch field {
+		case 1:
+			bucket.Start, err = v.timestamp()
+		case 2:
+			bucket.Comments = v.int()
+		case 3:
+			bucket.Commits = v.int()
+		case 4:
+			bucket.Checks = v.int()
+		default:
+		}
+		return err
+	})
+	return bucket, err
+}
+
+// decodeIntMapEntry adds a map<string, int64> entry to m.
+func decodeIntMapEntry(v protoValue, m map[string]int) error {
+	key, value, err := v.mapEntry()
+	if err == nil {
+		m[key] = value.int()
+	}
+	return err
+}
+
+// optionalTimestamp decodes a google.protobuf.Timestamp whose presence is meaningful.
+func optionalTimestamp(v protoValue) (*time.Time, error) {
+	t, err := v.timestamp()
+	return &t, err
+}
@@ -0,0 +1,296 @@
+package prx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// protoRoundTrip encodes data with ToProto, decodes it with FromProto, and fails unless the result
+// has the same JSON encoding.
+func protoRoundTrip(t *testing.T, data *PullRequestData) []byte {
+	t.Helper()
+	encoded, err := data.ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() error: %v", err)
+	}
+	var decoded PullRequestData
+	if err := decoded.FromProto(encoded); err != nil {
+		t.Fatalf("FromProto() error: %v", err)
+	}
+	want, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("round trip changed pull request %d:\n got %s\nwant %s", data.PullRequest.Number, got, want)
+	}
+	return encoded
+}
+
+func TestProtoRoundTripSample(t *testing.T) {
+	var protoSize, jsonSize int
+	for _, data := range GenerateSample(SampleOptions{Seed: 3, PullRequests: 50}) {
+		protoSize += len(protoRoundTrip(t, &data))
+		encoded, err := json.Marshal(&data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonSize += len(encoded)
+	}
+	if protoSize >= jsonSize/2 {
+		t.Errorf("protobuf encoding is %d bytes, want well under JSON's %d", protoSize, jsonSize)
+	}
+}
+
+func TestProtoRoundTripAllFields(t *testing.T) {
+	at := time.Date(2025, 3, 10, 12, 30, 15, 123456789, time.UTC)
+	later := at.Add(90 * time.Minute)
+	review, approval := 10*time.Minute, 2*time.Hour
+	yes, no := true, false
+	checks := &CheckSummary{
+		Success: map[string]string{"build": "ok"}, Failing: map[string]string{"lint": "2 errors"},
+		Pending: map[string]string{}, Cancelled: map[string]string{}, Skipped: map[string]string{"docs": ""},
+		Stale: map[string]string{}, Neutral: map[string]string{},
+	}
+	data := &PullRequestData{
+		CachedAt: at,
+		PullRequest: PullRequest{
+			Number: 42, Title: "Add caching", Body: "Adds a cache.\n\n- [x] Tests", Author: "alice", State: "closed",
+			CreatedAt: at, UpdatedAt: later, ClosedAt: &later, MergedAt: &later, FrozenUntil: &at, MergedBy: "bob",
+			Merged: true, Draft: false, AuthorBot: true, AuthorWriteAccess: WriteAccessUnlikely,
+			Mergeable: &no, BranchNameValid: &yes, MergeableState: "clean", MergeableStateDescription: "All good",
+			TestState: "passing", HeadSHA: "abc123", HeadRef: "feature/cache", NodeID: "PR_kw",
+			WaitingOn: WaitingOnMerger, WaitingOnReason: "ready to merge",
+			Additions: 120, Deletions: 7, ChangedFiles: 3,
+			Assignees: []string{"alice", ""}, Labels: []string{"enhancement"}, Commits: []string{"abc000", "abc123"},
+			Files: []string{"cache.go"}, TicketRefs: []string{"PROJ-1"}, ClosesIssues: []int{7, 12},
+			Tickets:        []Ticket{{Key: "PROJ-1", Title: "Cache", Status: "Done", URL: "https://tickets.example.com/PROJ-1"}},
+			OverlappingPRs: []PRRef{{Owner: "org", Repo: "app", Number: 41, Files: []string{"cache.go"}}},
+			Reviewers:      map[string]ReviewState{"bob": ReviewStateApproved, "Core": ReviewStatePending},
+			ParticipantAccess: map[string]int{
+				"alice": WriteAccessUnlikely, "bob": WriteAccessDefinitely, "carol": WriteAccessNA,
+			},
+			ApprovalSummary: &ApprovalSummary{
+				ApprovalsWithWriteAccess: 1, ChangesRequested: 2, StaleApprovals: 1, ApprovedAtSHA: map[string]string{"alice": "abc000"},
+			},
+			CheckSummary:       checks,
+			ThreadSummary:      &ThreadSummary{Total: 3, Resolved: 2, Unresolved: 1, Outdated: 1, PathDeleted: 1},
+			ChecksByCommit:     map[string]*CheckSummary{"abc123": checks},
+			LastHumanActivity:  later,
+			LastAuthorActivity: at,
+			FeatureFlags:       &FeatureFlagChanges{ConfigFiles: []string{"flags.yaml"}, Flags: []string{"new-cache"}},
+			DescriptionCheck:   &DescriptionCheck{Template: ".github/pull_request_template.md", Missing: []string{"Testing"}},
+			CommitEmailDomains: map[string]int{"example.com": 2},
+			CommitFiles:        map[string][]string{"abc000": {"cache.go", "cache_test.go"}, "abc123": nil},
+			Raw:                json.RawMessage(`{"id":1}`),
+			RepoArchived:       true,
+			ConversationLocked: true,
+			ForcePushCount:     2,
+			RequiredApprovals:  2,
+
+			ForcePushedAfterApproval: true,
+			ApprovalsSatisfied:       true,
+			MergeQueue: &MergeQueueState{
+				EnqueuedAt: &at, State: "awaiting_checks", Position: 2, InQueue: true,
+				Configuration: &MergeQueueConfiguration{
+					MergeMethod: "squash", MergingStrategy: "allgreen", MinimumEntriesToMerge: 1, MaximumEntriesToMerge: 5,
+					MaximumEntriesToBuild: 5, CheckResponseTimeout: 60,
+				},
+			},
+		},
+		Events: []Event{
+			{
+				Timestamp: at, Kind: EventKindReview, Actor: "bob", ActorType: ActorTypeHuman, Target: "alice",
+				Outcome: "approved", Body: "LGTM?", Description: "d", Mentions: []string{"alice", "org/core"},
+				CheckRunID: 9_000_000_001, Seq: 7, WriteAccess: WriteAccessDefinitely, Bot: true, TargetIsBot: true,
+				Question: true, Suggestion: true, Required: true, Outdated: true, ThreadResolved: true, PathDeleted: true,
+				BeforeSHA: "abc000", AfterSHA: "abc123", Orphaned: true, CommitSHA: "abc123", WorkflowRunID: 9_000_000_002,
+				WorkflowJobID: 9_000_000_003,
+				Reactions:     map[string]int{"+1": 2}, Tags: map[string]string{"team": "payments"},
+				Annotations: map[string]any{"tone": "positive", "score": 0.9, "labels": []any{"a"}},
+				Raw:         json.RawMessage(`{"node":true}`),
+			},
+			{Kind: EventKindCommit, WriteAccess: WriteAccessUnlikely},
+		},
+		ActivityHistogram: &ActivityHistogram{
+			Hourly: []ActivityBucket{{Start: at.Truncate(time.Hour), Comments: 1, Commits: 2, Checks: 3}},
+			Daily:  []ActivityBucket{{Start: at.Truncate(24 * time.Hour), Comments: 1}},
+		},
+		Timing:     &TimingSummary{TimeToFirstReview: &review, TimeToFirstApproval: &approval, TimeInDraft: time.Hour, ReviewIterations: 2},
+		Pruned:     &PrunedEvents{Oldest: at, Newest: later, Kinds: map[string]int{"comment": 3}, Actors: map[string]int{"bob": 3}, Bots: 1, Total: 3},
+		Repository: &Repository{DefaultBranch: "main", Visibility: "private", Language: "Go", Topics: []string{"cache"}, Archived: true},
+		FetchReport: FetchReport{Warnings: []FetchWarning{
+			{Section: FetchSectionRulesets, Error: "forbidden", StatusCode: 403},
+			{Section: FetchSectionCheckRuns, Target: "abc123", Error: "timeout"},
+		}},
+	}
+	protoRoundTrip(t, data)
+
+	// Zero timing durations and pointers stay distinguishable from unset ones
+	zero := time.Duration(0)
+	protoRoundTrip(t, &PullRequestData{
+		Timing:      &TimingSummary{TimeToFirstReview: &zero},
+		PullRequest: PullRequest{Mergeable: &no, Assignees: []string{}},
+	})
+}
+
+func TestProtoUnknownAndInvalid(t *testing.T) {
+	data := &PullRequestData{PullRequest: PullRequest{Number: 7, Title: "Fix"}}
+	encoded, err := data.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fields from a newer schema: a varint, a fixed64, a fixed32, and a length-delimited field
+	var extra protoEncoder
+	extra.uint(99, 5)
+	extra.tag(100, protoFixed64)
+	extra = append(extra, make([]byte, 8)...)
+	extra.tag(101, protoFixed32)
+	extra = append(extra, make([]byte, 4)...)
+	extra.string(102, "future")
+	var decoded PullRequestData
+	if err := decoded.FromProto(append(encoded, extra...)); err != nil {
+		t.Fatalf("FromProto() with unknown fields error: %v", err)
+	}
+	if decoded.PullRequest.Number != 7 || decoded.PullRequest.Title != "Fix" {
+		t.Errorf("FromProto() = %+v", decoded.PullRequest)
+	}
+
+	if err := decoded.FromProto(encoded[:len(encoded)-1]); !errors.Is(err, errProtoTruncated) {
+		t.Errorf("FromProto() of a truncated message error = %v, want errProtoTruncated", err)
+	}
+
+	bad := &PullRequestData{Events: []Event{{Annotations: map[string]any{"f": func() {}}}}}
+	if _, err := bad.ToProto(); err == nil {
+		t.Error("ToProto() with unencodable annotations succeeded")
+	}
+}
+
+// TestProtoMapsEveryField sets each field of PullRequestData, PullRequest, and Event in turn, along
+// with everything nested in it, and fails if the protobuf encoding loses it or prx.proto doesn't
+// declare it, so that a field added to one of those structs can't be left out of the encoding.
+func TestProtoMapsEveryField(t *testing.T) {
+	schema, err := os.ReadFile("../../proto/prx/v1/prx.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JSON names whose protobuf field is named differently
+	renamed := map[string]string{"fetch_report": "fetch_warnings", "annotations": "annotations_json"}
+	check := func(message string, field func(*PullRequestData) reflect.Value) {
+		declared := protoMessageFields(t, string(schema), message)
+		typ := field(&PullRequestData{Events: make([]Event, 1)}).Type()
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if protoName, ok := renamed[name]; ok {
+				name = protoName
+			}
+			if !declared[name] {
+				t.Errorf("%s.%s: prx.proto's %s message has no %s field", message, f.Name, message, name)
+			}
+
+			data := PullRequestData{Events: make([]Event, 1)}
+			fillProtoTestValue(field(&data).Field(i))
+			encoded, err := data.ToProto()
+			if err != nil {
+				t.Errorf("%s.%s: ToProto() error: %v", message, f.Name, err)
+				continue
+			}
+			var decoded PullRequestData
+			if err := decoded.FromProto(encoded); err != nil {
+				t.Errorf("%s.%s: FromProto() error: %v", message, f.Name, err)
+				continue
+			}
+			if len(decoded.Events) == 0 {
+				decoded.Events = make([]Event, 1)
+			}
+			if want, got := field(&data).Field(i).Interface(), field(&decoded).Field(i).Interface(); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s.%s doesn't survive the protobuf encoding; map it in proto.go:\n got %#v\nwant %#v", message, f.Name, got, want)
+			}
+		}
+	}
+	check("PullRequestData", func(d *PullRequestData) reflect.Value { return reflect.ValueOf(d).Elem() })
+	check("PullRequest", func(d *PullRequestData) reflect.Value { return reflect.ValueOf(&d.PullRequest).Elem() })
+	check("Event", func(d *PullRequestData) reflect.Value { return reflect.ValueOf(&d.Events[0]).Elem() })
+}
+
+// protoMessageFields returns the names of the fields declared by a message in a .proto file.
+func protoMessageFields(t *testing.T, schema, message string) map[string]bool {
+	t.Helper()
+	_, body, ok := strings.Cut(schema, "\nmessage "+message+" {\n")
+	if !ok {
+		t.Fatalf("prx.proto has no %s message", message)
+	}
+	body, _, _ = strings.Cut(body, "\n}")
+	fields := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`(?m)^\s*(?:repeated\s+|optional\s+)?[\w.<>, ]+\s+(\w+)\s*=\s*\d+`).FindAllStringSubmatch(body, -1) {
+		fields[m[1]] = true
+	}
+	return fields
+}
+
+// fillProtoTestValue sets v, and every exported field, element, and pointer target within it, to a
+// value other than its zero value.
+func fillProtoTestValue(v reflect.Value) {
+	switch v.Type() {
+	case reflect.TypeFor[time.Time]():
+		v.Set(reflect.ValueOf(time.Date(2025, 3, 10, 12, 30, 15, 0, time.UTC)))
+		return
+	case reflect.TypeFor[json.RawMessage]():
+		v.Set(reflect.ValueOf(json.RawMessage(`{"id":1}`)))
+		return
+	default:
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(2)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(2)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(0.5)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("x"))
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fillProtoTestValue(p.Elem())
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fillProtoTestValue(s.Index(0))
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fillProtoTestValue(key)
+		fillProtoTestValue(elem)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				fillProtoTestValue(v.Field(i))
+			}
+		}
+	default:
+		panic("fillProtoTestValue: unsupported kind " + v.Kind().String())
+	}
+}
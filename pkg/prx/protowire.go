@@ -0,0 +1,254 @@
+package prx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Protocol buffer wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+// protoEncoder appends fields in protocol buffer wire format. Like proto3, it omits zero scalars
+// unless their presence is tracked.
+type protoEncoder []byte
+
+func (e *protoEncoder) tag(field, wire int) {
+	*e = binary.AppendUvarint(*e, uint64(field)<<3|uint64(wire))
+}
+
+// uint encodes a uint64, int64, or int32 field.
+func (e *protoEncoder) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, protoVarint)
+	*e = binary.AppendUvarint(*e, v)
+}
+
+func (e *protoEncoder) int(field, v int) {
+	e.uint(field, uint64(int64(v))) //nolint:gosec // Negative values wrap, as in protobuf
+}
+
+// sint encodes a zigzag-encoded sint32 or sint64 field.
+func (e *protoEncoder) sint(field int, v int64) {
+	e.uint(field, uint64(v<<1)^uint64(v>>63)) //nolint:gosec // Zigzag encoding
+}
+
+func (e *protoEncoder) bool(field int, v bool) {
+	if v {
+		e.uint(field, 1)
+	}
+}
+
+// optionalBool encodes a proto3 optional bool, present if v isn't nil.
+func (e *protoEncoder) optionalBool(field int, v *bool) {
+	if v == nil {
+		return
+	}
+	e.tag(field, protoVarint)
+	if *v {
+		*e = append(*e, 1)
+	} else {
+		*e = append(*e, 0)
+	}
+}
+
+// optionalInt encodes a proto3 optional int64, present if v isn't nil.
+func (e *protoEncoder) optionalInt(field int, v *int64) {
+	if v == nil {
+		return
+	}
+	e.tag(field, protoVarint)
+	*e = binary.AppendUvarint(*e, uint64(*v)) //nolint:gosec // Negative values wrap, as in protobuf
+}
+
+func (e *protoEncoder) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	e.tag(field, protoBytes)
+	*e = binary.AppendUvarint(*e, uint64(len(b)))
+	*e = append(*e, b...)
+}
+
+func (e *protoEncoder) string(field int, s string) {
+	e.bytes(field, []byte(s))
+}
+
+// strings encodes a repeated string field, keeping empty strings.
+func (e *protoEncoder) strings(field int, ss []string) {
+	for _, s := range ss {
+		e.tag(field, protoBytes)
+		*e = binary.AppendUvarint(*e, uint64(len(s)))
+		*e = append(*e, s...)
+	}
+}
+
+// message encodes an embedded message, which is present even if empty.
+func (e *protoEncoder) message(field int, encode func(*protoEncoder)) {
+	var m protoEncoder
+	encode(&m)
+	e.tag(field, protoBytes)
+	*e = binary.AppendUvarint(*e, uint64(len(m)))
+	*e = append(*e, m...)
+}
+
+// timestamp encodes t as a google.protobuf.Timestamp, omitting the zero time.
+func (e *protoEncoder) timestamp(field int, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	e.message(field, func(m *protoEncoder) {
+		m.int(1, int(t.Unix()))
+		m.int(2, t.Nanosecond())
+	})
+}
+
+// optionalTimestamp encodes t as a google.protobuf.Timestamp, present if t isn't nil.
+func (e *protoEncoder) optionalTimestamp(field int, t *time.Time) {
+	if t == nil {
+		return
+	}
+	e.message(field, func(m *protoEncoder) {
+		m.int(1, int(t.Unix()))
+		m.int(2, t.Nanosecond())
+	})
+}
+
+// stringMap encodes a map<string, string> field, in key order so output is deterministic.
+func (e *protoEncoder) stringMap(field int, m map[string]string) {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		e.message(field, func(entry *protoEncoder) {
+			entry.string(1, k)
+			entry.string(2, m[k])
+		})
+	}
+}
+
+// intMap encodes a map<string, int64> field, in key order.
+func (e *protoEncoder) intMap(field int, m map[string]int) {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		e.message(field, func(entry *protoEncoder) {
+			entry.string(1, k)
+			entry.int(2, m[k])
+		})
+	}
+}
+
+// protoValue is a decoded field value: a varint, or the contents of a length-delimited field.
+type protoValue struct {
+	b []byte
+	n uint64
+}
+
+func (v protoValue) int() int {
+	return int(int64(v.n)) //nolint:gosec // Negative values wrap, as in protobuf
+}
+
+func (v protoValue) int64() int64 {
+	return int64(v.n) //nolint:gosec // Negative values wrap, as in protobuf
+}
+
+func (v protoValue) sint() int {
+	return int(int64(v.n>>1) ^ -int64(v.n&1)) //nolint:gosec // Zigzag decoding
+}
+
+func (v protoValue) bool() bool {
+	return v.n != 0
+}
+
+func (v protoValue) string() string {
+	return string(v.b)
+}
+
+func (v protoValue) bytes() []byte {
+	return slices.Clone(v.b)
+}
+
+// timestamp decodes a google.protobuf.Timestamp as a UTC time.
+func (v protoValue) timestamp() (time.Time, error) {
+	var sec, nsec int64
+	err := decodeProto(v.b, func(field int, f protoValue) error {
+		switch field {
+		case 1:
+			sec = f.int64()
+		case 2:
+			nsec = f.int64()
+		default:
+		}
+		return nil
+	})
+	return time.Unix(sec, nsec).UTC(), err
+}
+
+// mapEntry decodes a map entry's key and value.
+func (v protoValue) mapEntry() (string, protoValue, error) {
+	var key string
+	var value protoValue
+	err := decodeProto(v.b, func(field int, f protoValue) error {
+		switch field {
+		case 1:
+			key = f.string()
+		case 2:
+			value = f
+		default:
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+// decodeProto calls fn with each field of a message in wire format, skipping fixed-width fields,
+// which prx messages don't use, so that messages from newer schemas still decode. Fields with an
+// unexpected wire type decode as zero values.
+func decodeProto(b []byte, fn func(field int, v protoValue) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		b = b[n:]
+		field := int(key >> 3) //nolint:gosec // Field numbers fit in 29 bits
+		var v protoValue
+		switch wire := key & 7; wire {
+		case protoVarint:
+			if v.n, n = binary.Uvarint(b); n <= 0 {
+				return errProtoTruncated
+			}
+			b = b[n:]
+		case protoBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return errProtoTruncated
+			}
+			v.b, b = b[n:n+int(size)], b[n+int(size):] //nolint:gosec // Bounded by len(b) above
+		case protoFixed64, protoFixed32:
+			size := 8
+			if wire == protoFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return errProtoTruncated
+			}
+			b = b[size:]
+			continue
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d for field %d", wire, field)
+		}
+		if err := fn(field, v); err != nil {
+			return fmt.Errorf("field %d: %w", field, err)
+		}
+	}
+	return nil
+}
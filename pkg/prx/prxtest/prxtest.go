@@ -0,0 +1,215 @@
+// Package prxtest provides a fake prx client for unit-testing code that uses prx, without an HTTP
+// server. Write code against prx.Fetcher, and in tests pass a FakeClient loaded with pull requests:
+//
+//	fake := prxtest.NewFakeClient()
+//	fake.AddPullRequest("owner", "repo", &prx.PullRequestData{PullRequest: prx.PullRequest{Number: 1, State: "open"}})
+//	err := fake.AddPullRequestJSON("owner", "repo", mustRead("testdata/pr.json")) // As output by the prx command
+//	dashboard := NewDashboard(fake)
+package prxtest
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+// ErrNotFound is returned for pull requests and diffs that the fake hasn't been given.
+var ErrNotFound = errors.New("not found")
+
+var _ prx.Fetcher = (*FakeClient)(nil)
+
+// key identifies a pull request.
+type key struct {
+	owner  string
+	repo   string
+	number int
+}
+
+func (k key) String() string {
+	return fmt.Sprintf("%s/%s#%d", k.owner, k.repo, k.number)
+}
+
+// FakeClient implements prx.Fetcher from pull requests held in memory. It is safe for concurrent use.
+type FakeClient struct {
+	prs     map[key]*prx.PullRequestData
+	errs    map[key]error
+	diffs   map[key]string
+	fetches map[key]int
+	mu      sync.Mutex
+}
+
+// NewFakeClient returns a FakeClient with no pull requests.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		prs:     make(map[key]*prx.PullRequestData),
+		errs:    make(map[key]error),
+		diffs:   make(map[key]string),
+		fetches: make(map[key]int),
+	}
+}
+
+// AddPullRequest adds a pull request in owner/repo, replacing any with the same number.
+func (f *FakeClient) AddPullRequest(owner, repo string, data *prx.PullRequestData) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prs[key{owner, repo, data.PullRequest.Number}] = data
+}
+
+// AddPullRequestJSON adds a pull request in owner/repo from JSON, such as the output of the prx command.
+func (f *FakeClient) AddPullRequestJSON(owner, repo string, b []byte) error {
+	var data prx.PullRequestData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return fmt.Errorf("parsing pull request: %w", err)
+	}
+	f.AddPullRequest(owner, repo, &data)
+	return nil
+}
+
+// SetError makes fetches of a pull request fail with err, or succeed again if err is nil.
+func (f *FakeClient) SetError(owner, repo string, number int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, key{owner, repo, number})
+		return
+	}
+	f.errs[key{owner, repo, number}] = err
+}
+
+// SetDiff sets the unified diff returned by Diff for a pull request.
+func (f *FakeClient) SetDiff(owner, repo string, number int, diff string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.diffs[key{owner, repo, number}] = diff
+}
+
+// Fetches returns how many times a pull request has been fetched, by any method.
+func (f *FakeClient) Fetches(owner, repo string, number int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetches[key{owner, repo, number}]
+}
+
+// fetch returns a copy of a pull request, counting the fetch.
+func (f *FakeClient) fetch(ctx context.Context, k key) (*prx.PullRequestData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches[k]++
+	if err := f.errs[k]; err != nil {
+		return nil, err
+	}
+	data, ok := f.prs[k]
+	if !ok {
+		return nil, fmt.Errorf("pull request %s: %w", k, ErrNotFound)
+	}
+	// Callers may change what they get without changing what later fetches return
+	c := *data
+	c.Events = slices.Clone(data.Events)
+	return &c, nil
+}
+
+// PullRequest returns the pull request, or ErrNotFound if it hasn't been added.
+func (f *FakeClient) PullRequest(ctx context.Context, owner, repo string, prNumber int) (*prx.PullRequestData, error) {
+	return f.fetch(ctx, key{owner, repo, prNumber})
+}
+
+// PullRequestWithReferenceTime returns the pull request, like PullRequest; the reference time is ignored.
+func (f *FakeClient) PullRequestWithReferenceTime(
+	ctx context.Context, owner, repo string, pr int, _ time.Time,
+) (*prx.PullRequestData, error) {
+	return f.fetch(ctx, key{owner, repo, pr})
+}
+
+// PullRequests yields the requested pull requests one at a time, in order of priority and then in
+// the order given. Requests whose deadline has passed fail with context.DeadlineExceeded.
+func (f *FakeClient) PullRequests(ctx context.Context, requests []prx.BatchRequest, _ time.Time, _ int) iter.Seq[prx.BatchResult] {
+	return func(yield func(prx.BatchResult) bool) {
+		order := make([]int, len(requests))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(requests[b].Priority, requests[a].Priority) })
+		for _, i := range order {
+			r := requests[i]
+			result := prx.BatchResult{Index: i}
+			if !r.Deadline.IsZero() && !time.Now().Before(r.Deadline) {
+				result.Err = fmt.Errorf("pull request %s: %w", key{r.Owner, r.Repo, r.Number}, context.DeadlineExceeded)
+			} else {
+				result.Data, result.Err = f.fetch(ctx, key{r.Owner, r.Repo, r.Number})
+			}
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// PullRequestNumbers returns the numbers of the pull requests added for owner/repo with state
+// "open", "closed" (including merged), or "all", most recently updated first.
+func (f *FakeClient) PullRequestNumbers(ctx context.Context, owner, repo, state string) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var prs []*prx.PullRequest
+	for k, data := range f.prs {
+		open := data.PullRequest.State == "open"
+		if k.owner == owner && k.repo == repo && (state == "all" || state == "open" && open || state == "closed" && !open) {
+			prs = append(prs, &data.PullRequest)
+		}
+	}
+	slices.SortFunc(prs, func(a, b *prx.PullRequest) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(b.Number, a.Number))
+	})
+	numbers := make([]int, len(prs))
+	for i, pr := range prs {
+		numbers[i] = pr.Number
+	}
+	return numbers, nil
+}
+
+// Events yields the pull request's events, or its error.
+func (f *FakeClient) Events(ctx context.Context, owner, repo string, prNumber int) iter.Seq2[prx.Event, error] {
+	return func(yield func(prx.Event, error) bool) {
+		data, err := f.fetch(ctx, key{owner, repo, prNumber})
+		if err != nil {
+			yield(prx.Event{}, err)
+			return
+		}
+		for _, e := range data.Events {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Diff returns the diff set with SetDiff, or ErrNotFound.
+func (f *FakeClient) Diff(ctx context.Context, owner, repo string, pr int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k := key{owner, repo, pr}
+	if err := f.errs[k]; err != nil {
+		return "", err
+	}
+	diff, ok := f.diffs[k]
+	if !ok {
+		return "", fmt.Errorf("diff of %s: %w", k, ErrNotFound)
+	}
+	return diff, nil
+}
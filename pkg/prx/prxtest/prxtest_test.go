@@ -0,0 +1,97 @@
+package prxtest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx"
+)
+
+func TestFakeClient(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fake := NewFakeClient()
+	fake.AddPullRequest("o", "r", &prx.PullRequestData{
+		PullRequest: prx.PullRequest{Number: 1, State: "open", UpdatedAt: now.Add(-time.Hour)},
+		Events:      []prx.Event{{Kind: prx.EventKindComment, Actor: "alice"}, {Kind: prx.EventKindReview, Actor: "bob"}},
+	})
+	if err := fake.AddPullRequestJSON("o", "r", []byte(`{"pull_request": {"number": 2, "state": "merged", "updated_at": "2025-01-01T00:00:00Z"}}`)); err != nil {
+		t.Fatalf("AddPullRequestJSON: %v", err)
+	}
+	fake.AddPullRequest("o", "r", &prx.PullRequestData{PullRequest: prx.PullRequest{Number: 3, State: "open", UpdatedAt: now}})
+
+	data, err := fake.PullRequest(ctx, "o", "r", 1)
+	if err != nil || data.PullRequest.Number != 1 || len(data.Events) != 2 {
+		t.Fatalf("PullRequest = %+v, %v; want #1 with two events", data, err)
+	}
+	data.Events[0].Actor = "mallory"
+	if again, _ := fake.PullRequestWithReferenceTime(ctx, "o", "r", 1, now); again.Events[0].Actor != "alice" {
+		t.Error("changing a fetched pull request changed the fake's")
+	}
+	if got := fake.Fetches("o", "r", 1); got != 2 {
+		t.Errorf("Fetches = %d, want 2", got)
+	}
+	if _, err := fake.PullRequest(ctx, "o", "r", 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PullRequest of a missing pull request: error = %v, want ErrNotFound", err)
+	}
+
+	errForbidden := errors.New("forbidden")
+	fake.SetError("o", "r", 3, errForbidden)
+	if _, err := fake.PullRequest(ctx, "o", "r", 3); !errors.Is(err, errForbidden) {
+		t.Errorf("PullRequest after SetError: error = %v, want %v", err, errForbidden)
+	}
+	var actors []string
+	for e, err := range fake.Events(ctx, "o", "r", 1) {
+		if err != nil {
+			t.Fatalf("Events: %v", err)
+		}
+		actors = append(actors, e.Actor)
+	}
+	if want := []string{"alice", "bob"}; !slices.Equal(actors, want) {
+		t.Errorf("Events actors = %v, want %v", actors, want)
+	}
+	fake.SetError("o", "r", 3, nil)
+
+	for state, want := range map[string][]int{"open": {3, 1}, "closed": {2}, "all": {3, 1, 2}} {
+		if got, err := fake.PullRequestNumbers(ctx, "o", "r", state); err != nil || !slices.Equal(got, want) {
+			t.Errorf("PullRequestNumbers(%q) = %v, %v; want %v", state, got, err, want)
+		}
+	}
+
+	requests := []prx.BatchRequest{
+		{Owner: "o", Repo: "r", Number: 1},
+		{Owner: "o", Repo: "r", Number: 2, Priority: 1},
+		{Owner: "o", Repo: "r", Number: 3, Deadline: now.Add(-time.Second)},
+	}
+	var order []int
+	for r := range fake.PullRequests(ctx, requests, now, 2) {
+		order = append(order, r.Index)
+		if r.Index == 2 {
+			if !errors.Is(r.Err, context.DeadlineExceeded) {
+				t.Errorf("request past its deadline: error = %v, want deadline exceeded", r.Err)
+			}
+		} else if r.Err != nil || r.Data.PullRequest.Number != requests[r.Index].Number {
+			t.Errorf("result %d = %+v, want its pull request", r.Index, r)
+		}
+	}
+	if want := []int{1, 0, 2}; !slices.Equal(order, want) {
+		t.Errorf("PullRequests yielded %v, want %v", order, want)
+	}
+
+	fake.SetDiff("o", "r", 1, "diff --git a/x b/x\n")
+	if diff, err := fake.Diff(ctx, "o", "r", 1); err != nil || diff != "diff --git a/x b/x\n" {
+		t.Errorf("Diff = %q, %v", diff, err)
+	}
+	if _, err := fake.Diff(ctx, "o", "r", 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Diff without SetDiff: error = %v, want ErrNotFound", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := fake.PullRequest(canceled, "o", "r", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("PullRequest with a canceled context: error = %v, want context.Canceled", err)
+	}
+}
@@ -1,6 +1,7 @@
 package prx
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -32,18 +33,48 @@ type PullRequest struct {
 	// 16-byte fields (time.Time)
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Latest activity by people, ignoring bots and CI, for detecting stale pull requests; zero if none
+	LastHumanActivity    time.Time `json:"last_human_activity,omitzero"`
+	LastAuthorActivity   time.Time `json:"last_author_activity,omitzero"`   // By the pull request's author
+	LastReviewerActivity time.Time `json:"last_reviewer_activity,omitzero"` // Reviews and comments by anyone else
 	// 8-byte pointer fields
-	ClosedAt        *time.Time       `json:"closed_at,omitempty"`
-	MergedAt        *time.Time       `json:"merged_at,omitempty"`
-	ApprovalSummary *ApprovalSummary `json:"approval_summary,omitempty"`
-	CheckSummary    *CheckSummary    `json:"check_summary,omitempty"`
-	Mergeable       *bool            `json:"mergeable"`
+	ClosedAt        *time.Time          `json:"closed_at,omitempty"`
+	MergedAt        *time.Time          `json:"merged_at,omitempty"`
+	FrozenUntil     *time.Time          `json:"frozen_until,omitempty"` // End of the active deployment freeze; see WithFreezeWindows
+	ApprovalSummary *ApprovalSummary    `json:"approval_summary,omitempty"`
+	CheckSummary    *CheckSummary       `json:"check_summary,omitempty"`
+	ThreadSummary   *ThreadSummary      `json:"thread_summary,omitempty"`
+	FeatureFlags    *FeatureFlagChanges `json:"feature_flags,omitempty"` // Set only with WithFeatureFlagDetection
+	Mergeable       *bool               `json:"mergeable"`
+	BranchNameValid *bool               `json:"branch_name_valid,omitempty"` // Set only with WithBranchPatterns
+	// Description compared to the repository's pull request templates; set only with WithDescriptionTemplates
+	DescriptionCheck *DescriptionCheck `json:"description_check,omitempty"`
+	// Nil unless the base branch has a merge queue or the pull request has been in one
+	MergeQueue *MergeQueueState `json:"merge_queue,omitempty"`
 	// 24-byte slice/map fields
 	Assignees         []string               `json:"assignees"`
 	Labels            []string               `json:"labels,omitempty"`
-	Commits           []string               `json:"commits,omitempty"` // List of commit SHAs in chronological order (oldest to newest)
+	Commits           []string               `json:"commits,omitempty"`         // List of commit SHAs in chronological order (oldest to newest)
+	Files             []string               `json:"files,omitempty"`           // Paths of files changed by the pull request
+	TicketRefs        []string               `json:"ticket_refs,omitempty"`     // Issue tracker keys found in the head branch name
+	ClosesIssues      []int                  `json:"closes_issues,omitempty"`   // Issues in the repository that merging will close
+	Tickets           []Ticket               `json:"tickets,omitempty"`         // TicketRefs resolved with WithTicketResolver
+	OverlappingPRs    []PRRef                `json:"overlapping_prs,omitempty"` // Other open PRs changing the same files; set by FlagOverlappingPRs
 	Reviewers         map[string]ReviewState `json:"reviewers,omitempty"`
 	ParticipantAccess map[string]int         `json:"participant_access,omitempty"` // Map of username to WriteAccess level
+	// Issues in other repositories that merging will close, as owner/repo#number
+	ClosesIssueRefs []string `json:"closes_issue_refs,omitempty"`
+	// Check results of each commit with any, keyed by SHA, showing how CI evolved across pushes
+	ChecksByCommit map[string]*CheckSummary `json:"checks_by_commit,omitempty"`
+	// Map of commit author email domain to commit count; only populated with WithCommitEmailDomains
+	CommitEmailDomains map[string]int `json:"commit_email_domains,omitempty"`
+	// Map of commit SHA to the paths of files it changed; only populated with WithCommitFiles
+	CommitFiles map[string][]string `json:"commit_files,omitempty"`
+	// Map of changed file path to who last changed the base lines it modifies or removes, counted by line,
+	// by login; only populated with WithChangedLineBlame
+	ChangedLineAuthors map[string]map[string]int `json:"changed_line_authors,omitempty"`
+	// Original GitHub JSON node for the pull request; only populated with WithRawPayloads
+	Raw json.RawMessage `json:"raw,omitempty"`
 	// 16-byte string fields
 	MergeableState            string `json:"mergeable_state"`
 	MergeableStateDescription string `json:"mergeable_state_description,omitempty"`
@@ -54,16 +85,33 @@ type PullRequest struct {
 	State                     string `json:"state"`
 	TestState                 string `json:"test_state,omitempty"`
 	HeadSHA                   string `json:"head_sha,omitempty"`
+	HeadRef                   string `json:"head_ref,omitempty"` // Head branch name
+	NodeID                    string `json:"node_id,omitempty"`  // GraphQL node ID, used by mutations such as Merge
+	// Who has to act next for the pull request to move forward, and why
+	WaitingOn       WaitingOn `json:"waiting_on,omitempty"`
+	WaitingOnReason string    `json:"waiting_on_reason,omitempty"` // e.g. "status checks are failing: lint"
 	// 8-byte int fields
 	Number            int `json:"number"`
 	ChangedFiles      int `json:"changed_files"`
 	Deletions         int `json:"deletions"`
 	Additions         int `json:"additions"`
 	AuthorWriteAccess int `json:"author_write_access,omitempty"`
+	ForcePushCount    int `json:"force_push_count,omitempty"` // Force pushes to the head branch
+	// Approvals the base branch's protection rule or rulesets require before merging, whichever is more;
+	// 0 if they require none
+	RequiredApprovals int `json:"required_approvals,omitempty"`
 	// 1-byte bool fields
 	AuthorBot bool `json:"author_bot"`
 	Merged    bool `json:"merged"`
 	Draft     bool `json:"draft"`
+	// Archived repositories are read-only; write operations such as SyncReviewComments are refused
+	RepoArchived bool `json:"repo_archived,omitempty"`
+	// Locked conversations only accept comments from collaborators; SyncReviewComments is refused
+	ConversationLocked bool `json:"conversation_locked,omitempty"`
+	// The head branch was force-pushed after an approval, so what was approved may no longer be what's there
+	ForcePushedAfterApproval bool `json:"force_pushed_after_approval,omitempty"`
+	// Whether there are RequiredApprovals approvals from users with write access
+	ApprovalsSatisfied bool `json:"approvals_satisfied"`
 }
 
 // CheckSummary aggregates all status checks and check runs.
@@ -90,21 +138,50 @@ type ApprovalSummary struct {
 
 	// Outstanding change requests from any reviewer
 	ChangesRequested int `json:"changes_requested"`
+
+	// Approvals counted above that are of an earlier commit than the head commit. GitHub dismisses these only if the
+	// branch protection rule asks it to.
+	StaleApprovals int `json:"stale_approvals,omitempty"`
+
+	// Commit each approving reviewer approved, by login, where GitHub reports it
+	ApprovedAtSHA map[string]string `json:"approved_at_sha,omitempty"`
+}
+
+// ThreadSummary counts review threads by state, showing whether review feedback has been addressed.
+type ThreadSummary struct {
+	Total      int `json:"total"`
+	Resolved   int `json:"resolved"`
+	Unresolved int `json:"unresolved"`
+	Outdated   int `json:"outdated"` // Threads on code that has since changed, resolved or not
+	// PathDeleted counts threads on files no longer in the pull request, resolved or not. With
+	// WithDeletedFileThreadsExcluded, they are counted only here.
+	PathDeleted int `json:"path_deleted,omitempty"`
 }
 
 // PullRequestData contains a pull request and all its associated events.
 type PullRequestData struct {
-	CachedAt    time.Time   `json:"cached_at,omitzero"` // When this data was cached
-	Events      []Event     `json:"events"`
-	PullRequest PullRequest `json:"pull_request"`
+	CachedAt          time.Time          `json:"cached_at,omitzero"` // When this data was cached
+	ActivityHistogram *ActivityHistogram `json:"activity_histogram,omitempty"`
+	Timing            *TimingSummary     `json:"timing,omitempty"`
+	Pruned            *PrunedEvents      `json:"pruned,omitempty"` // Aggregates of events removed by PruneEvents
+	Repository        *Repository        `json:"repository,omitempty"`
+	FetchReport       FetchReport        `json:"fetch_report,omitzero"` // Parts that couldn't be fetched
+	Events            []Event            `json:"events"`
+	PullRequest       PullRequest        `json:"pull_request"`
 }
 
 // finalizePullRequest applies final calculations and consistency fixes.
 func finalizePullRequest(pullRequest *PullRequest, events []Event, requiredChecks []string, testStateFromAPI string) {
 	pullRequest.TestState = testStateFromAPI
+	markOrphanedChecks(events, pullRequest)
+	setForcePushes(pullRequest, events)
+	setMergeQueueState(pullRequest, events)
 	pullRequest.CheckSummary = calculateCheckSummary(events, requiredChecks)
-	pullRequest.ApprovalSummary = calculateApprovalSummary(events)
+	pullRequest.ChecksByCommit = calculateChecksByCommit(events, pullRequest.HeadSHA)
+	pullRequest.ApprovalSummary = calculateApprovalSummary(events, pullRequest.HeadSHA)
+	setRequiredApprovals(pullRequest, pullRequest.RequiredApprovals)
 	pullRequest.ParticipantAccess = calculateParticipantAccess(events, pullRequest)
+	setLastActivity(pullRequest, events)
 
 	fixTestState(pullRequest)
 
@@ -115,6 +192,13 @@ func finalizePullRequest(pullRequest *PullRequest, events []Event, requiredCheck
 	}
 
 	setMergeableDescription(pullRequest)
+	setWaitingOn(pullRequest)
+}
+
+// setRequiredApprovals sets how many approvals the pull request requires, and whether it has them.
+func setRequiredApprovals(pullRequest *PullRequest, required int) {
+	pullRequest.RequiredApprovals = required
+	pullRequest.ApprovalsSatisfied = pullRequest.ApprovalSummary.ApprovalsWithWriteAccess >= required
 }
 
 // fixTestState ensures test_state is consistent with check_summary.
@@ -158,6 +242,8 @@ func setBlockedDescription(pullRequest *PullRequest) {
 	hasPendingChecks := len(pullRequest.CheckSummary.Pending) > 0
 
 	switch {
+	case pullRequest.MergeQueue != nil && pullRequest.MergeQueue.InQueue:
+		pullRequest.MergeableStateDescription = pullRequest.MergeQueue.queuedReason()
 	case !hasApprovals && !hasFailingChecks:
 		if hasPendingChecks {
 			pullRequest.MergeableStateDescription = "PR requires approval and has pending status checks"
@@ -172,6 +258,8 @@ func setBlockedDescription(pullRequest *PullRequest) {
 		}
 	case hasPendingChecks:
 		pullRequest.MergeableStateDescription = "PR is blocked by pending status checks"
+	case pullRequest.MergeQueue != nil && pullRequest.MergeQueue.Configuration != nil && pullRequest.ApprovalsSatisfied:
+		pullRequest.MergeableStateDescription = "PR is ready to be added to the merge queue"
 	default:
 		pullRequest.MergeableStateDescription = "PR is blocked by required status checks, reviews, or branch protection rules"
 	}
@@ -0,0 +1,95 @@
+package prx
+
+import "regexp"
+
+// QuestionPatterns configures question detection in comment and review bodies, which sets
+// Event.Question. Patterns add to the built-in English phrases and "?"; a body matching any of them
+// is a question.
+type QuestionPatterns struct {
+	// Phrases are matched case-insensitively, allowing any whitespace between words and requiring word
+	// boundaries at ASCII letters and digits, like the built-in phrases.
+	Phrases []string
+	// Regexes match anywhere in the body.
+	Regexes []*regexp.Regexp
+	// Disable turns question detection off, leaving Event.Question false.
+	Disable bool
+}
+
+// SpanishQuestionPatterns returns patterns for Spanish questions, including the "¿" opening mark.
+func SpanishQuestionPatterns() QuestionPatterns {
+	return QuestionPatterns{
+		Phrases: []string{
+			"cómo puedo", "cómo podemos", "cómo se",
+			"puedes", "podrías", "podríamos", "puede alguien",
+			"deberíamos", "debería",
+			"qué opinas", "qué opinan", "qué te parece", "qué les parece",
+			"alguna idea", "alguna sugerencia", "algún comentario",
+			"alguien sabe", "es posible", "hay alguna forma", "hay alguna manera",
+			"por qué", "cuándo", "dónde",
+		},
+		Regexes: []*regexp.Regexp{regexp.MustCompile(`¿`)},
+	}
+}
+
+// GermanQuestionPatterns returns patterns for German questions.
+func GermanQuestionPatterns() QuestionPatterns {
+	return QuestionPatterns{
+		Phrases: []string{
+			"kannst du", "können wir", "können sie", "könntest du", "könnten wir", "könnten sie",
+			"sollten wir", "sollen wir", "wie kann", "wie können", "wie sollen",
+			"was meinst du", "was denkst du", "was haltet ihr", "was halten sie",
+			"ist es möglich", "gibt es eine möglichkeit", "weiß jemand", "hat jemand",
+			"irgendwelche ideen", "irgendwelche vorschläge",
+			"warum ist", "warum wird", "wieso", "weshalb", "bist du sicher", "sind sie sicher",
+		},
+	}
+}
+
+// JapaneseQuestionPatterns returns patterns for Japanese questions, including the full-width "？"
+// and polite sentence-final "か".
+func JapaneseQuestionPatterns() QuestionPatterns {
+	return QuestionPatterns{
+		Phrases: []string{
+			"？", "いかが", "どう思", "どうすれば", "どうしたら", "なぜ", "教えてください", "でしょうか",
+		},
+		Regexes: []*regexp.Regexp{
+			// "ますか" but not "ますから" ("because")
+			regexp.MustCompile(`(?:ます|です|ません)か(?:[。！!」\s]|$)`),
+		},
+	}
+}
+
+// WithQuestionPatterns adds question patterns, such as SpanishQuestionPatterns, to the built-in
+// English ones; it may be given more than once. Any patterns with Disable set turn detection off.
+func WithQuestionPatterns(patterns ...QuestionPatterns) Option {
+	return func(c *Client) {
+		for _, p := range patterns {
+			if p.Disable {
+				c.questionsDisabled = true
+			}
+			for _, phrase := range p.Phrases {
+				if re := questionPhraseRegexp(phrase); re != nil {
+					c.questionPatterns = append(c.questionPatterns, re)
+				}
+			}
+			c.questionPatterns = append(c.questionPatterns, p.Regexes...)
+		}
+	}
+}
+
+// containsQuestion reports whether text is a question, using the built-in patterns and any
+// configured with WithQuestionPatterns.
+func (c *Client) containsQuestion(text string) bool {
+	if c.questionsDisabled {
+		return false
+	}
+	if containsQuestion(text) {
+		return true
+	}
+	for _, re := range c.questionPatterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
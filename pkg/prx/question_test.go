@@ -2,6 +2,8 @@ package prx
 
 import (
 	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
 )
 
 //nolint:maintidx // Comprehensive test coverage requires many test cases
@@ -375,6 +377,50 @@ func TestContainsQuestion(t *testing.T) {
 	}
 }
 
+func TestClient_ContainsQuestionPatterns(t *testing.T) {
+	store := WithCacheStore(null.New[string, PullRequestData]())
+	english := NewClient("test-token", store)
+	packs := NewClient("test-token", store, WithQuestionPatterns(
+		SpanishQuestionPatterns(),
+		GermanQuestionPatterns(),
+		JapaneseQuestionPatterns(),
+	), WithQuestionPatterns(QuestionPatterns{Phrases: []string{"ptal"}}))
+	disabled := NewClient("test-token", store, WithQuestionPatterns(QuestionPatterns{Disable: true}))
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"¿Podemos usar otra biblioteca aquí", true},
+		{"Podrías revisar el manejo de errores", true},
+		{"Qué opinas del nuevo nombre", true},
+		{"Se corrigió el error de compilación", false},
+		{"Könntest du die Tests noch einmal laufen lassen", true},
+		{"Wieso brauchen wir diese Abhängigkeit", true},
+		{"Die Tests laufen jetzt durch", false},
+		{"このテストは必要ですか", true},
+		{"この変更でよろしいでしょうか。", true},
+		{"テストを追加しましたから、確認お願いします", false},
+		{"修正しました。", false},
+		{"PTAL when you get a chance", true},
+		{"LGTM, merging", false},
+	}
+	for _, tt := range tests {
+		if english.containsQuestion(tt.text) && !tt.want {
+			t.Errorf("English-only containsQuestion(%q) = true, want false", tt.text)
+		}
+		if got := packs.containsQuestion(tt.text); got != tt.want {
+			t.Errorf("containsQuestion(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+	if english.containsQuestion("Könntest du das prüfen") {
+		t.Error("English-only client detected a German question")
+	}
+	if disabled.containsQuestion("Can you review this?") {
+		t.Error("containsQuestion() with detection disabled = true, want false")
+	}
+}
+
 // Benchmark to ensure performance is acceptable
 func BenchmarkContainsQuestion(b *testing.B) {
 	testCases := []string{
@@ -0,0 +1,168 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// ErrRateLimitBudget is returned when the remaining rate limit is below the configured
+// budget and the context deadline doesn't allow waiting for the limit to reset.
+var ErrRateLimitBudget = errors.New("rate limit budget exhausted")
+
+// RateLimitBucket is the most recently observed state of one rate limit resource.
+type RateLimitBucket struct {
+	Reset     time.Time `json:"reset,omitzero"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+}
+
+// known reports whether the bucket has been observed and hasn't reset since.
+func (b RateLimitBucket) known() bool {
+	return b.Limit > 0 && time.Now().Before(b.Reset)
+}
+
+// RateLimitState is the rate limit state observed from recent API responses.
+type RateLimitState struct {
+	Core            RateLimitBucket `json:"core"`
+	GraphQL         RateLimitBucket `json:"graphql"`
+	LastGraphQLCost int             `json:"last_graphql_cost"` // Points consumed by the most recent GraphQL query
+}
+
+// WithRateLimitBudget keeps at least n requests (REST) or points (GraphQL) of rate limit in reserve.
+// When the remaining budget drops below n, requests wait for the limit to reset, or fail fast
+// with ErrRateLimitBudget if the reset is beyond the context deadline.
+func WithRateLimitBudget(n int) Option {
+	return func(c *Client) {
+		c.rateLimitBudget = n
+	}
+}
+
+// RateLimitState returns the most recently observed rate limit state.
+func (c *Client) RateLimitState() RateLimitState {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit
+}
+
+// recordRateLimit updates the rate limit state from response headers.
+func (c *Client) recordRateLimit(rl github.RateLimit) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	bucket := RateLimitBucket{Reset: rl.Reset, Limit: rl.Limit, Remaining: rl.Remaining}
+	switch rl.Resource {
+	case github.ResourceCore:
+		c.rateLimit.Core = bucket
+	case github.ResourceGraphQL:
+		c.rateLimit.GraphQL = bucket
+	default:
+		// Other resources (search, code scanning, ...) aren't used by prx
+	}
+}
+
+// recordGraphQLCost updates the GraphQL rate limit state from a query's rateLimit field.
+func (c *Client) recordGraphQLCost(cost, remaining, limit int, reset time.Time) {
+	if c.metrics != nil {
+		c.metrics.GraphQLCost(cost)
+	}
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.rateLimit.LastGraphQLCost = cost
+	if limit > 0 {
+		c.rateLimit.GraphQL = RateLimitBucket{Reset: reset, Limit: limit, Remaining: remaining}
+	}
+}
+
+// throttle enforces the rate limit budget before a request against resource.
+func (c *Client) throttle(ctx context.Context, resource string) error {
+	if c.rateLimitBudget <= 0 {
+		return nil
+	}
+
+	state := c.RateLimitState()
+	bucket := state.Core
+	if resource == github.ResourceGraphQL {
+		bucket = state.GraphQL
+	}
+	if !bucket.known() || bucket.Remaining >= c.rateLimitBudget {
+		return nil
+	}
+
+	wait := time.Until(bucket.Reset)
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return fmt.Errorf("%w: %d %s remaining (budget %d), resets at %s",
+			ErrRateLimitBudget, bucket.Remaining, resource, c.rateLimitBudget, bucket.Reset.Format(time.RFC3339))
+	}
+
+	c.logger.WarnContext(ctx, "rate limit budget reached, waiting for reset",
+		"resource", resource, "remaining", bucket.Remaining, "budget", c.rateLimitBudget, "wait", wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SchedulingHints is pacing advice for a caller fetching a batch of pull requests.
+type SchedulingHints struct {
+	ResetAt            time.Time     `json:"reset_at,omitzero"`          // When the constraining rate limit resets
+	SuggestedDelay     time.Duration `json:"suggested_delay"`            // How long to wait before the next fetch
+	RequestsUntilReset int           `json:"requests_until_reset"`       // Fetches the remaining quota allows; -1 if unknown
+	DeadlineAtRisk     bool          `json:"deadline_at_risk,omitempty"` // The quota won't reset in time to finish by the deadline
+}
+
+// SchedulingHints derives pacing advice from the observed rate limits for fetching pending more
+// pull requests, each costing per (see EstimateCost). Quota held back by WithRateLimitBudget is
+// not counted. When the pending fetches exceed the remaining quota, they are spread across the
+// reset window rather than exhausting it at once. With a non-zero deadline, fetches are also
+// spread evenly so the batch completes by the deadline instead of bursting.
+func (c *Client) SchedulingHints(pending int, per CostEstimate, deadline time.Time) SchedulingHints {
+	hints := SchedulingHints{RequestsUntilReset: -1}
+	if pending <= 0 {
+		return hints
+	}
+
+	state := c.RateLimitState()
+	for _, b := range []struct {
+		bucket RateLimitBucket
+		cost   int
+	}{{state.Core, per.RESTCalls}, {state.GraphQL, per.GraphQLPoints}} {
+		if !b.bucket.known() || b.cost <= 0 {
+			continue
+		}
+		n := max(b.bucket.Remaining-c.rateLimitBudget, 0) / b.cost
+		if hints.RequestsUntilReset < 0 || n < hints.RequestsUntilReset {
+			hints.RequestsUntilReset = n
+			hints.ResetAt = b.bucket.Reset
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case hints.RequestsUntilReset == 0:
+		hints.SuggestedDelay = hints.ResetAt.Sub(now)
+	case hints.RequestsUntilReset > 0 && pending > hints.RequestsUntilReset:
+		hints.SuggestedDelay = hints.ResetAt.Sub(now) / time.Duration(hints.RequestsUntilReset)
+	default:
+		// Enough quota for the whole batch
+	}
+
+	if deadline.IsZero() {
+		return hints
+	}
+	if !deadline.After(now) {
+		hints.DeadlineAtRisk = true
+		return hints
+	}
+	hints.SuggestedDelay = max(hints.SuggestedDelay, deadline.Sub(now)/time.Duration(pending))
+	if hints.RequestsUntilReset >= 0 && pending > hints.RequestsUntilReset && !hints.ResetAt.Before(deadline) {
+		hints.DeadlineAtRisk = true
+	}
+	return hints
+}
@@ -0,0 +1,136 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+func TestClient_RateLimitState(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "1234")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+			_, _ = w.Write([]byte(`{"data": {
+				"repository": {"pullRequest": {"number": 1, "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "a"}}},
+				"rateLimit": {"cost": 3, "remaining": 4000, "limit": 5000, "resetAt": "` + reset.Format(time.RFC3339) + `"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	client.configureGitHubClient()
+
+	if _, err := client.PullRequest(context.Background(), "owner", "repo", 1); err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+
+	state := client.RateLimitState()
+	if state.LastGraphQLCost != 3 {
+		t.Errorf("LastGraphQLCost = %d, want 3", state.LastGraphQLCost)
+	}
+	if state.GraphQL.Remaining != 4000 {
+		t.Errorf("GraphQL.Remaining = %d, want 4000", state.GraphQL.Remaining)
+	}
+	if state.Core.Remaining != 1234 || !state.Core.Reset.Equal(reset) {
+		t.Errorf("Core = %+v, want remaining 1234 reset %v", state.Core, reset)
+	}
+}
+
+func TestClient_RateLimitBudget(t *testing.T) {
+	client := NewClient("test-token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithRateLimitBudget(100),
+	)
+
+	// Unknown state never throttles.
+	if err := client.throttle(context.Background(), "core"); err != nil {
+		t.Errorf("throttle() with unknown state: %v", err)
+	}
+
+	client.recordGraphQLCost(1, 500, 5000, time.Now().Add(time.Hour))
+	if err := client.throttle(context.Background(), "graphql"); err != nil {
+		t.Errorf("throttle() within budget: %v", err)
+	}
+
+	// Below budget with a reset beyond the deadline fails fast.
+	client.recordGraphQLCost(1, 50, 5000, time.Now().Add(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.throttle(ctx, "graphql"); !errors.Is(err, ErrRateLimitBudget) {
+		t.Errorf("expected ErrRateLimitBudget, got %v", err)
+	}
+
+	// Below budget with a reset inside the deadline waits for it.
+	client.recordGraphQLCost(1, 50, 5000, time.Now().Add(50*time.Millisecond))
+	start := time.Now()
+	if err := client.throttle(ctx, "graphql"); err != nil {
+		t.Errorf("throttle() waiting for reset: %v", err)
+	}
+	if time.Since(start) < 40*time.Millisecond {
+		t.Error("expected throttle to wait for the reset")
+	}
+}
+
+func TestClient_SchedulingHints(t *testing.T) {
+	near := func(got, want time.Duration) bool {
+		return got > want-time.Second && got <= want
+	}
+	per := CostEstimate{GraphQLPoints: 1, RESTCalls: 3}
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithRateLimitBudget(100))
+
+	// Unknown rate limits impose no delay.
+	if hints := client.SchedulingHints(10, per, time.Time{}); hints.RequestsUntilReset != -1 || hints.SuggestedDelay != 0 {
+		t.Errorf("SchedulingHints() with unknown limits = %+v", hints)
+	}
+
+	reset := time.Now().Add(time.Hour)
+	client.recordRateLimit(github.RateLimit{Resource: github.ResourceCore, Limit: 5000, Remaining: 400, Reset: reset})
+	client.recordGraphQLCost(1, 4000, 5000, reset)
+
+	// Enough quota: (400 - 100 budget) / 3 REST calls per fetch = 100 fetches.
+	hints := client.SchedulingHints(50, per, time.Time{})
+	if hints.RequestsUntilReset != 100 || hints.SuggestedDelay != 0 || !hints.ResetAt.Equal(reset) {
+		t.Errorf("SchedulingHints(50) = %+v, want 100 requests and no delay", hints)
+	}
+
+	// Too little quota: spread the remaining fetches across the reset window.
+	hints = client.SchedulingHints(500, per, time.Time{})
+	if !near(hints.SuggestedDelay, time.Hour/100) {
+		t.Errorf("SchedulingHints(500).SuggestedDelay = %v, want ~%v", hints.SuggestedDelay, time.Hour/100)
+	}
+
+	// Deadline mode spreads fetches evenly until the deadline.
+	hints = client.SchedulingHints(50, per, time.Now().Add(50*time.Minute))
+	if !near(hints.SuggestedDelay, time.Minute) || hints.DeadlineAtRisk {
+		t.Errorf("SchedulingHints(50, deadline) = %+v, want ~1m delay", hints)
+	}
+
+	// The quota won't reset before the deadline.
+	hints = client.SchedulingHints(500, per, time.Now().Add(30*time.Minute))
+	if !hints.DeadlineAtRisk || !near(hints.SuggestedDelay, time.Hour/100) {
+		t.Errorf("SchedulingHints(500, deadline) = %+v, want deadline at risk", hints)
+	}
+
+	// Exhausted quota: wait for the reset.
+	client.recordRateLimit(github.RateLimit{Resource: github.ResourceCore, Limit: 5000, Remaining: 50, Reset: reset})
+	hints = client.SchedulingHints(1, per, time.Time{})
+	if hints.RequestsUntilReset != 0 || !near(hints.SuggestedDelay, time.Hour) {
+		t.Errorf("SchedulingHints() with exhausted quota = %+v, want ~1h delay", hints)
+	}
+}
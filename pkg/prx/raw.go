@@ -0,0 +1,125 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// WithRawPayloads attaches the original GitHub JSON node to each Event and to the PullRequest
+// as Raw, giving access to fields prx does not model. This increases memory use and cache size.
+func WithRawPayloads() Option {
+	return func(c *Client) {
+		c.rawPayloads = true
+	}
+}
+
+// graphQLRawPayloads holds the undecoded JSON nodes of a pull request query response,
+// index-aligned with the corresponding connection nodes in graphQLPullRequestComplete.
+type graphQLRawPayloads struct {
+	PullRequest          json.RawMessage
+	Commits              []json.RawMessage
+	Reviews              []json.RawMessage
+	Comments             []json.RawMessage
+	TimelineItems        []json.RawMessage
+	Checks               []json.RawMessage
+	ReviewThreadComments [][]json.RawMessage
+}
+
+// append adds the connection nodes of a follow-up page. The pull request node itself
+// is kept from the first page.
+func (r *graphQLRawPayloads) append(page *graphQLRawPayloads) {
+	r.Commits = append(r.Commits, page.Commits...)
+	r.Reviews = append(r.Reviews, page.Reviews...)
+	r.Comments = append(r.Comments, page.Comments...)
+	r.TimelineItems = append(r.TimelineItems, page.TimelineItems...)
+}
+
+// graphQLRawConnections mirrors the connections of graphQLPullRequestComplete, keeping nodes undecoded.
+type graphQLRawConnections struct {
+	HeadRef struct {
+		Target struct {
+			StatusCheckRollup *struct {
+				Contexts struct {
+					Nodes []json.RawMessage `json:"nodes"`
+				} `json:"contexts"`
+			} `json:"statusCheckRollup"`
+		} `json:"target"`
+	} `json:"headRef"`
+	Commits struct {
+		Nodes []json.RawMessage `json:"nodes"`
+	} `json:"commits"`
+	Reviews struct {
+		Nodes []json.RawMessage `json:"nodes"`
+	} `json:"reviews"`
+	ReviewThreads struct {
+		Nodes []struct {
+			Comments struct {
+				Nodes []json.RawMessage `json:"nodes"`
+			} `json:"comments"`
+		} `json:"nodes"`
+	} `json:"reviewThreads"`
+	Comments struct {
+		Nodes []json.RawMessage `json:"nodes"`
+	} `json:"comments"`
+	TimelineItems struct {
+		Nodes []json.RawMessage `json:"nodes"`
+	} `json:"timelineItems"`
+}
+
+// queryGraphQL executes a pull request query, additionally capturing the raw JSON nodes
+// of the response when WithRawPayloads is enabled.
+func (c *Client) queryGraphQL(ctx context.Context, query string, variables map[string]any, result *graphQLCompleteResponse) error {
+	if !c.rawPayloads {
+		return c.github.GraphQL(ctx, query, variables, result)
+	}
+
+	var body json.RawMessage
+	if err := c.github.GraphQL(ctx, query, variables, &body); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("decoding GraphQL response: %w", err)
+	}
+
+	var envelope struct {
+		Data struct {
+			Repository struct {
+				PullRequest json.RawMessage `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("decoding raw GraphQL response: %w", err)
+	}
+	prNode := envelope.Data.Repository.PullRequest
+	if len(prNode) == 0 || string(prNode) == "null" {
+		return nil
+	}
+	var conns graphQLRawConnections
+	if err := json.Unmarshal(prNode, &conns); err != nil {
+		return fmt.Errorf("decoding raw GraphQL pull request: %w", err)
+	}
+
+	raw := &result.Data.Repository.PullRequest.raw
+	raw.PullRequest = prNode
+	raw.Commits = conns.Commits.Nodes
+	raw.Reviews = conns.Reviews.Nodes
+	raw.Comments = conns.Comments.Nodes
+	raw.TimelineItems = conns.TimelineItems.Nodes
+	if rollup := conns.HeadRef.Target.StatusCheckRollup; rollup != nil {
+		raw.Checks = rollup.Contexts.Nodes
+	}
+	for _, thread := range conns.ReviewThreads.Nodes {
+		raw.ReviewThreadComments = append(raw.ReviewThreadComments, thread.Comments.Nodes)
+	}
+	return nil
+}
+
+// rawAt returns nodes[i], or nil if raw payloads were not captured.
+func rawAt(nodes []json.RawMessage, i int) json.RawMessage {
+	if i < 0 || i >= len(nodes) {
+		return nil
+	}
+	return nodes[i]
+}
@@ -0,0 +1,87 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequestRawPayloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		rateLimit := `"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}`
+		if _, ok := req.Variables["cursor"].(string); ok {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {` + commentPage(100, 20, false) + `}}, ` + rateLimit + `}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+			"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+			"unmodeledField": "kept", ` + commentPage(0, 100, true) + `}}, ` + rateLimit + `}}`))
+	}))
+	defer server.Close()
+
+	for _, raw := range []bool{false, true} {
+		opts := []Option{WithCacheStore(null.New[string, PullRequestData]())}
+		if raw {
+			opts = append(opts, WithRawPayloads())
+		}
+		client := NewClient("test-token", opts...)
+		client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+		data, err := client.PullRequest(context.Background(), "owner", "repo", 1)
+		if err != nil {
+			t.Fatalf("PullRequest() error: %v", err)
+		}
+
+		if !raw {
+			if data.PullRequest.Raw != nil {
+				t.Errorf("PullRequest.Raw = %s, want nil without WithRawPayloads", data.PullRequest.Raw)
+			}
+			for i := range data.Events {
+				if data.Events[i].Raw != nil {
+					t.Fatalf("Event.Raw = %s, want nil without WithRawPayloads", data.Events[i].Raw)
+				}
+			}
+			continue
+		}
+
+		if !strings.Contains(string(data.PullRequest.Raw), `"unmodeledField": "kept"`) {
+			t.Errorf("PullRequest.Raw = %s, want original pull request node", data.PullRequest.Raw)
+		}
+		comments := 0
+		for i := range data.Events {
+			e := &data.Events[i]
+			if e.Kind != EventKindComment {
+				continue
+			}
+			comments++
+			var node struct {
+				Body string `json:"body"`
+			}
+			if err := json.Unmarshal(e.Raw, &node); err != nil {
+				t.Fatalf("unmarshaling Event.Raw %q: %v", e.Raw, err)
+			}
+			if node.Body != e.Body {
+				t.Errorf("Event.Raw body = %q, want %q", node.Body, e.Body)
+			}
+		}
+		if comments != 120 {
+			t.Errorf("got %d comment events, want 120", comments)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package prx
+
+import (
+	"iter"
+	"slices"
+	"time"
+)
+
+// EventsBetween returns a cursor over the events at or after from and before to, in chronological
+// order, for timelines and replays. A zero from or to leaves that end of the range open.
+func (d *PullRequestData) EventsBetween(from, to time.Time) iter.Seq[Event] {
+	byTime := func(a, b Event) int { return a.Timestamp.Compare(b.Timestamp) }
+	events := d.Events
+	if !slices.IsSortedFunc(events, byTime) {
+		events = slices.SortedStableFunc(slices.Values(events), byTime)
+	}
+	start, _ := slices.BinarySearchFunc(events, from, func(e Event, t time.Time) int { return e.Timestamp.Compare(t) })
+	return func(yield func(Event) bool) {
+		for _, e := range events[start:] {
+			if !to.IsZero() && !e.Timestamp.Before(to) {
+				return
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// StateAt reconstructs the pull request as it was at t by replaying its events: its state, draft
+// status, labels, assignees, reviewers, commits, check and approval summaries, and who it was
+// waiting on. Fields without a history in the events, such as the title, body, and size, keep their
+// current values; mergeability, which GitHub only reports for now, is cleared. It returns nil if t is
+// before the pull request was created.
+func (d *PullRequestData) StateAt(t time.Time) *PullRequest {
+	if t.Before(d.PullRequest.CreatedAt) {
+		return nil
+	}
+	current := &d.PullRequest
+	var events []Event
+	for e := range d.EventsBetween(time.Time{}, t.Add(time.Nanosecond)) {
+		events = append(events, e)
+	}
+
+	pr := *current
+	pr.UpdatedAt = current.CreatedAt
+	pr.State = "open"
+	pr.Merged = false
+	pr.MergedBy = ""
+	pr.ClosedAt = nil
+	pr.MergedAt = nil
+	pr.Mergeable = nil
+	pr.MergeableState = ""
+	pr.Labels = nil
+	pr.Assignees = []string{}
+	pr.Reviewers = nil
+	pr.ConversationLocked = false
+
+	// A pull request opened as a draft has a ready_for_review before any convert_to_draft
+	for i := range d.Events {
+		if d.Events[i].Kind == EventKindReadyForReview || d.Events[i].Kind == EventKindConvertToDraft {
+			pr.Draft = d.Events[i].Kind == EventKindReadyForReview
+			break
+		}
+	}
+
+	var commits []string
+	for i := range events {
+		e := &events[i]
+		pr.UpdatedAt = e.Timestamp
+		switch e.Kind {
+		case EventKindCommit:
+			if e.Body != "" {
+				commits = append(commits, e.Body)
+			}
+		case EventKindPRClosed, EventKindClosed:
+			pr.State = "closed"
+			pr.ClosedAt = &e.Timestamp
+		case EventKindPRMerged, EventKindMerged:
+			pr.State = "closed"
+			pr.Merged = true
+			pr.MergedBy = e.Actor
+			pr.ClosedAt = &e.Timestamp
+			pr.MergedAt = &e.Timestamp
+		case EventKindReopened:
+			pr.State = "open"
+			pr.ClosedAt = nil
+		case EventKindConvertToDraft:
+			pr.Draft = true
+		case EventKindReadyForReview:
+			pr.Draft = false
+		case EventKindLocked:
+			pr.ConversationLocked = true
+		case EventKindUnlocked:
+			pr.ConversationLocked = false
+		default:
+		}
+	}
+	// Commit events may have been pruned; keep the current commits rather than none
+	if slices.ContainsFunc(d.Events, func(e Event) bool { return e.Kind == EventKindCommit }) {
+		pr.Commits = commits
+		pr.HeadSHA = ""
+		if len(commits) > 0 {
+			pr.HeadSHA = commits[len(commits)-1]
+		}
+	}
+
+	applyEventToPullRequest(&pr, events)
+	finalizePullRequest(&pr, events, existingRequiredChecks(d), "")
+	return &pr
+}
@@ -0,0 +1,266 @@
+// Package replay records GitHub API traffic to fixture files and replays it, so that tests can run
+// against real pull requests offline. Record a fixture once with a Recorder:
+//
+//	rec := &replay.Recorder{}
+//	client := prx.NewClient(token, prx.WithHTTPClient(&http.Client{Transport: rec}))
+//	if _, err := client.PullRequest(ctx, "codeGROOVE-dev", "slacker", 66); err != nil { ... }
+//	err := rec.Save("testdata/slacker_66.json")
+//
+// and replay it in tests with a Player:
+//
+//	player, err := replay.Load("testdata/slacker_66.json")
+//	client := prx.NewClient("token", prx.WithHTTPClient(&http.Client{Transport: player}))
+//
+// Fixtures are sanitized as they are recorded: request headers other than Accept aren't kept,
+// responses keep only their Content-Type and Link headers, and GitHub tokens in bodies are redacted.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// FixtureVersion is the version of the fixture format written by Recorder.
+const FixtureVersion = 1
+
+// keptHeaders are the response headers recorded. Rate limit headers are left out so that replays
+// don't throttle.
+var keptHeaders = []string{"Content-Type", "Link"}
+
+// Fixture is a recording of API traffic.
+type Fixture struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response to it.
+type Interaction struct {
+	Method string `json:"method"`
+	// URL is the request's path and query, e.g. "/repos/o/r/pulls/1/files?page=2", without the scheme
+	// and host, so that fixtures replay against test servers and mirrors.
+	URL    string `json:"url"`
+	Accept string `json:"accept,omitempty"`
+	// Request is the request body: for GraphQL, its query and variables.
+	Request json.RawMessage `json:"request,omitempty"`
+	Status  int             `json:"status"`
+	Header  http.Header     `json:"header,omitempty"`
+	// Body is a JSON response body. Other bodies, such as diffs, are in Text.
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that sends requests with Base and records the responses.
+type Recorder struct {
+	// Base sends requests; if nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Sanitize, if set, edits each interaction before it is recorded, e.g. to replace private names.
+	Sanitize func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// RoundTrip implements the http.RoundTripper interface, recording the request and its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	in := Interaction{
+		Method:  req.Method,
+		URL:     req.URL.RequestURI(),
+		Accept:  req.Header.Get("Accept"),
+		Request: compactJSON(reqBody),
+		Status:  resp.StatusCode,
+		Header:  make(http.Header),
+	}
+	for _, h := range keptHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			in.Header[h] = v
+		}
+	}
+	body = github.TokenPattern.ReplaceAll(body, []byte("[REDACTED]"))
+	if in.Body = compactJSON(body); in.Body == nil {
+		in.Text = string(body)
+	}
+	if r.Sanitize != nil {
+		r.Sanitize(&in)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, in)
+	return resp, nil
+}
+
+// Save writes the recorded interactions to a fixture file.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	fixture := Fixture{Version: FixtureVersion, Interactions: r.interactions}
+	b, err := json.MarshalIndent(fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// Player is an http.RoundTripper that answers requests from a fixture, without network access.
+// A request is answered by the first unused interaction with the same method, URL, Accept header,
+// and body. GraphQL requests whose query has changed since recording are answered by the first
+// unused one with the same variables. Once all matching interactions are used, the last one is
+// repeated, as for polling. Requests that match nothing fail.
+type Player struct {
+	fixture Fixture
+	mu      sync.Mutex
+	used    []bool
+}
+
+// Load reads a fixture file for replay.
+func Load(path string) (*Player, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	if fixture.Version != FixtureVersion {
+		return nil, fmt.Errorf("fixture %s has version %d, want %d: record it again", path, fixture.Version, FixtureVersion)
+	}
+	// Fixtures are indented for review; requests are matched and bodies replayed compactly, as recorded
+	for i := range fixture.Interactions {
+		in := &fixture.Interactions[i]
+		in.Request, in.Body = compactJSON(in.Request), compactJSON(in.Body)
+	}
+	return &Player{fixture: fixture, used: make([]bool, len(fixture.Interactions))}, nil
+}
+
+// RoundTrip implements the http.RoundTripper interface with the recorded responses.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+	}
+	in := p.match(req, compactJSON(reqBody))
+	if in == nil {
+		return nil, fmt.Errorf("replay: no recorded response for %s %s", req.Method, req.URL.RequestURI())
+	}
+
+	body := []byte(in.Text)
+	if in.Body != nil {
+		body = in.Body
+	}
+	header := in.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// match returns the interaction that answers a request, marking it used, or nil if there is none.
+func (p *Player) match(req *http.Request, body json.RawMessage) *Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sameRequest := func(in *Interaction) bool {
+		return in.Method == req.Method && in.URL == req.URL.RequestURI() && in.Accept == req.Header.Get("Accept")
+	}
+	variables := graphQLVariables(body)
+	matchers := []func(in *Interaction) bool{
+		func(in *Interaction) bool { return sameRequest(in) && bytes.Equal(in.Request, body) },
+		func(in *Interaction) bool {
+			return variables != nil && sameRequest(in) && bytes.Equal(graphQLVariables(in.Request), variables)
+		},
+	}
+	for _, matches := range matchers {
+		last := -1
+		for i := range p.fixture.Interactions {
+			if !matches(&p.fixture.Interactions[i]) {
+				continue
+			}
+			if !p.used[i] {
+				p.used[i] = true
+				return &p.fixture.Interactions[i]
+			}
+			last = i
+		}
+		if last >= 0 {
+			return &p.fixture.Interactions[last]
+		}
+	}
+	return nil
+}
+
+// compactJSON returns b without insignificant whitespace, or nil if it isn't JSON.
+func compactJSON(b []byte) json.RawMessage {
+	var buf bytes.Buffer
+	if len(b) == 0 || json.Compact(&buf, b) != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// graphQLVariables returns the variables of a GraphQL request body, compacted, or nil if it isn't one.
+func graphQLVariables(body json.RawMessage) json.RawMessage {
+	var req struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if body == nil || json.Unmarshal(body, &req) != nil || req.Query == "" {
+		return nil
+	}
+	if v := compactJSON(req.Variables); v != nil {
+		return v
+	}
+	return json.RawMessage("null")
+}
@@ -0,0 +1,122 @@
+package prx
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEventsBetween(t *testing.T) {
+	base := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	data := &PullRequestData{Events: []Event{
+		{Kind: EventKindComment, Timestamp: at(3), Body: "c"},
+		{Kind: EventKindPROpened, Timestamp: at(0), Body: "a"},
+		{Kind: EventKindCommit, Timestamp: at(1), Body: "b"},
+		{Kind: EventKindReview, Timestamp: at(5), Body: "e"},
+		{Kind: EventKindComment, Timestamp: at(3), Body: "d"},
+	}}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{name: "all", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "from is inclusive", from: at(3), want: []string{"c", "d", "e"}},
+		{name: "to is exclusive", to: at(3), want: []string{"a", "b"}},
+		{name: "window", from: at(1), to: at(4), want: []string{"b", "c", "d"}},
+		{name: "empty", from: at(6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for e := range data.EventsBetween(tt.from, tt.to) {
+				got = append(got, e.Body)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("EventsBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Stopping early
+	for e := range data.EventsBetween(time.Time{}, time.Time{}) {
+		if e.Body != "a" {
+			t.Errorf("first event = %q, want a", e.Body)
+		}
+		break
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	base := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	merged := at(10)
+	data := &PullRequestData{
+		PullRequest: PullRequest{
+			Number: 7, Title: "Add retries", Author: "alice", State: "closed", Merged: true, MergedBy: "bob",
+			CreatedAt: at(0), UpdatedAt: merged, ClosedAt: &merged, MergedAt: &merged,
+			Labels: []string{"enhancement"}, Assignees: []string{"alice"}, Commits: []string{"aaa", "bbb"}, HeadSHA: "bbb",
+			Reviewers: map[string]ReviewState{"bob": ReviewStateApproved},
+		},
+		Events: []Event{
+			{Kind: EventKindCommit, Timestamp: at(-1), Actor: "alice", Body: "aaa"},
+			{Kind: EventKindPROpened, Timestamp: at(0), Actor: "alice"},
+			{Kind: EventKindLabeled, Timestamp: at(0), Actor: "alice", Target: "wip"},
+			{Kind: EventKindCheckRun, Timestamp: at(1), Body: "test", Outcome: "failure", Target: "aaa"},
+			{Kind: EventKindReadyForReview, Timestamp: at(2), Actor: "alice"},
+			{Kind: EventKindReviewRequested, Timestamp: at(2), Actor: "alice", Target: "bob"},
+			{Kind: EventKindReview, Timestamp: at(4), Actor: "bob", Outcome: "changes_requested", WriteAccess: WriteAccessDefinitely},
+			{Kind: EventKindCommit, Timestamp: at(5), Actor: "alice", Body: "bbb"},
+			{Kind: EventKindUnlabeled, Timestamp: at(5), Actor: "alice", Target: "wip"},
+			{Kind: EventKindLabeled, Timestamp: at(5), Actor: "alice", Target: "enhancement"},
+			{Kind: EventKindAssigned, Timestamp: at(5), Actor: "alice", Target: "alice"},
+			{Kind: EventKindCheckRun, Timestamp: at(6), Body: "test", Outcome: "success", Target: "bbb"},
+			{Kind: EventKindReview, Timestamp: at(8), Actor: "bob", Outcome: "approved", WriteAccess: WriteAccessDefinitely},
+			{Kind: EventKindPRMerged, Timestamp: merged, Actor: "bob"},
+		},
+	}
+
+	if got := data.StateAt(at(-1)); got != nil {
+		t.Errorf("StateAt() before creation = %+v, want nil", got)
+	}
+
+	opened := data.StateAt(at(1))
+	if !opened.Draft || opened.State != "open" || opened.Merged || opened.MergedAt != nil || opened.ClosedAt != nil {
+		t.Errorf("StateAt(opened) draft=%v state=%q merged=%v", opened.Draft, opened.State, opened.Merged)
+	}
+	if !slices.Equal(opened.Labels, []string{"wip"}) || len(opened.Assignees) != 0 || len(opened.Reviewers) != 0 {
+		t.Errorf("StateAt(opened) labels=%v assignees=%v reviewers=%v", opened.Labels, opened.Assignees, opened.Reviewers)
+	}
+	if opened.HeadSHA != "aaa" || opened.TestState != TestStateFailing || !opened.UpdatedAt.Equal(at(1)) {
+		t.Errorf("StateAt(opened) head=%q test=%q updated=%v", opened.HeadSHA, opened.TestState, opened.UpdatedAt)
+	}
+
+	reviewed := data.StateAt(at(4))
+	if reviewed.Draft || reviewed.Reviewers["bob"] != ReviewStateChangesRequested || reviewed.ApprovalSummary.ChangesRequested != 1 {
+		t.Errorf("StateAt(reviewed) draft=%v reviewers=%v approvals=%+v", reviewed.Draft, reviewed.Reviewers, reviewed.ApprovalSummary)
+	}
+	if reviewed.WaitingOn != WaitingOnAuthor {
+		t.Errorf("StateAt(reviewed).WaitingOn = %q, want %q", reviewed.WaitingOn, WaitingOnAuthor)
+	}
+
+	approved := data.StateAt(at(9))
+	if !slices.Equal(approved.Commits, []string{"aaa", "bbb"}) || approved.TestState != TestStatePassing {
+		t.Errorf("StateAt(approved) commits=%v test=%q", approved.Commits, approved.TestState)
+	}
+	if !slices.Equal(approved.Labels, []string{"enhancement"}) || !slices.Equal(approved.Assignees, []string{"alice"}) {
+		t.Errorf("StateAt(approved) labels=%v assignees=%v", approved.Labels, approved.Assignees)
+	}
+	if approved.State != "open" || approved.Reviewers["bob"] != ReviewStateApproved {
+		t.Errorf("StateAt(approved) state=%q reviewers=%v", approved.State, approved.Reviewers)
+	}
+
+	final := data.StateAt(merged)
+	if final.State != "closed" || !final.Merged || final.MergedBy != "bob" || final.MergedAt == nil || !final.MergedAt.Equal(merged) {
+		t.Errorf("StateAt(merged) state=%q merged=%v by=%q at=%v", final.State, final.Merged, final.MergedBy, final.MergedAt)
+	}
+	if final.Title != "Add retries" || data.PullRequest.State != "closed" || !slices.Equal(data.PullRequest.Labels, []string{"enhancement"}) {
+		t.Error("StateAt() changed the pull request or lost its unchanged fields")
+	}
+}
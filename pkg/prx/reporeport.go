@@ -0,0 +1,146 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RepoReport summarizes how the pull requests in a repository closed since a time were reviewed
+// and merged, as returned by RepoReport.
+type RepoReport struct {
+	Since time.Time `json:"since"`
+	// Fraction of pull requests merged rather than closed, by the ActorType of their author; pull
+	// requests whose author's type is unknown are under ""
+	MergeRateByAuthorType map[ActorType]float64 `json:"merge_rate_by_author_type,omitempty"`
+	// Time from becoming ready for review to the first review, of pull requests that were reviewed
+	ReviewLatency     DurationDistribution `json:"review_latency"`
+	MedianTimeToMerge time.Duration        `json:"median_time_to_merge,omitempty"` // From opening to merging
+	Closed            int                  `json:"closed"`                         // Including merged pull requests
+	Merged            int                  `json:"merged"`
+	Unfetched         int                  `json:"unfetched,omitempty"` // Pull requests left out because they couldn't be fetched
+}
+
+// DurationDistribution summarizes durations by percentile, using the nearest rank.
+type DurationDistribution struct {
+	P50   time.Duration `json:"p50"`
+	P75   time.Duration `json:"p75"`
+	P90   time.Duration `json:"p90"`
+	Max   time.Duration `json:"max"`
+	Count int           `json:"count"`
+}
+
+// RepoReport reports on the pull requests in owner/repo that were closed or merged since since,
+// fetching each of them, c.concurrency at a time. Pull requests that can't be fetched are logged,
+// counted in Unfetched, and otherwise left out.
+func (c *Client) RepoReport(ctx context.Context, owner, repo string, since time.Time) (*RepoReport, error) {
+	numbers, err := c.closedPullRequestNumbers(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]BatchRequest, len(numbers))
+	for i, n := range numbers {
+		requests[i] = BatchRequest{Owner: owner, Repo: repo, Number: n}
+	}
+
+	report := &RepoReport{Since: since}
+	var toMerge, latencies []time.Duration
+	closed := make(map[ActorType]int)
+	merged := make(map[ActorType]int)
+	for r := range c.PullRequests(ctx, requests, time.Now(), c.concurrency) {
+		if r.Err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			c.logger.WarnContext(ctx, "failed to fetch pull request for repository report",
+				"owner", owner, "repo", repo, "number", requests[r.Index].Number, "error", r.Err)
+			report.Unfetched++
+			continue
+		}
+		pr := &r.Data.PullRequest
+		author := authorType(r.Data.Events)
+		report.Closed++
+		closed[author]++
+		if pr.Merged && pr.MergedAt != nil {
+			report.Merged++
+			merged[author]++
+			toMerge = append(toMerge, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+		if t := r.Data.Timing; t != nil && t.TimeToFirstReview != nil {
+			latencies = append(latencies, *t.TimeToFirstReview)
+		}
+	}
+
+	report.MedianTimeToMerge = durationDistribution(toMerge).P50
+	report.ReviewLatency = durationDistribution(latencies)
+	if len(closed) > 0 {
+		report.MergeRateByAuthorType = make(map[ActorType]float64, len(closed))
+		for t, n := range closed {
+			report.MergeRateByAuthorType[t] = float64(merged[t]) / float64(n)
+		}
+	}
+	return report, nil
+}
+
+// closedPullRequestNumbers lists the pull requests in owner/repo closed since since. Pull requests
+// are updated when closed, so listing by last update stops at the first one not updated since.
+func (c *Client) closedPullRequestNumbers(ctx context.Context, owner, repo string, since time.Time) ([]int, error) {
+	params := url.Values{
+		"state":     {"closed"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {"100"},
+	}
+	var numbers []int
+	page := 1
+	for range maxPullRequestListPages {
+		params.Set("page", strconv.Itoa(page))
+		var prs []struct {
+			UpdatedAt time.Time  `json:"updated_at"`
+			ClosedAt  *time.Time `json:"closed_at"`
+			Number    int        `json:"number"`
+		}
+		resp, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s/pulls?%s", owner, repo, params.Encode()), &prs)
+		if err != nil {
+			return nil, fmt.Errorf("listing closed pull requests in %s/%s: %w", owner, repo, err)
+		}
+		for _, pr := range prs {
+			if pr.UpdatedAt.Before(since) {
+				return numbers, nil
+			}
+			if pr.ClosedAt != nil && !pr.ClosedAt.Before(since) {
+				numbers = append(numbers, pr.Number)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return numbers, nil
+}
+
+// authorType returns the ActorType of the pull request's author, from its opening event.
+func authorType(events []Event) ActorType {
+	for i := range events {
+		if events[i].Kind == EventKindPROpened {
+			return events[i].ActorType
+		}
+	}
+	return ""
+}
+
+// durationDistribution summarizes ds, which it sorts.
+func durationDistribution(ds []time.Duration) DurationDistribution {
+	if len(ds) == 0 {
+		return DurationDistribution{}
+	}
+	slices.Sort(ds)
+	rank := func(p int) time.Duration {
+		return ds[(len(ds)*p+99)/100-1]
+	}
+	return DurationDistribution{P50: rank(50), P75: rank(75), P90: rank(90), Max: ds[len(ds)-1], Count: len(ds)}
+}
@@ -0,0 +1,85 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_RepoReport(t *testing.T) {
+	// Opened at 00:00, by a user or a bot; reviewed after two or six hours; merged after a day, or closed
+	pullRequests := map[int]string{
+		4: `"author": {"login": "alice", "__typename": "User"}, "state": "MERGED", "mergedAt": "2025-01-02T00:00:00Z",
+			"mergedBy": {"login": "bob", "__typename": "User"}, "closedAt": "2025-01-02T00:00:00Z",
+			"reviews": {"nodes": [{"state": "APPROVED", "submittedAt": "2025-01-01T02:00:00Z", "author": {"login": "bob"}}]}`,
+		3: `"author": {"login": "carol", "__typename": "User"}, "state": "CLOSED", "closedAt": "2025-01-03T00:00:00Z",
+			"reviews": {"nodes": [{"state": "COMMENTED", "submittedAt": "2025-01-01T06:00:00Z", "author": {"login": "bob"}}]}`,
+		5: `"author": {"login": "renovate", "__typename": "Bot"}, "state": "MERGED", "mergedAt": "2025-01-01T12:00:00Z",
+			"mergedBy": {"login": "bob", "__typename": "User"}, "closedAt": "2025-01-01T12:00:00Z"`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/pulls":
+			if q := r.URL.Query(); q.Get("state") != "closed" || q.Get("sort") != "updated" {
+				t.Errorf("listing pull requests with %v, want closed ones by update", q)
+			}
+			// #2 was updated since, but closed before; #1 and later weren't updated since
+			w.Write([]byte(`[
+				{"number": 4, "updated_at": "2025-01-05T00:00:00Z", "closed_at": "2025-01-02T00:00:00Z"},
+				{"number": 2, "updated_at": "2025-01-04T00:00:00Z", "closed_at": "2024-12-01T00:00:00Z"},
+				{"number": 3, "updated_at": "2025-01-03T00:00:00Z", "closed_at": "2025-01-03T00:00:00Z"},
+				{"number": 5, "updated_at": "2025-01-02T00:00:00Z", "closed_at": "2025-01-01T12:00:00Z"},
+				{"number": 6, "updated_at": "2025-01-02T00:00:00Z", "closed_at": "2025-01-01T12:00:00Z"},
+				{"number": 1, "updated_at": "2024-12-01T00:00:00Z", "closed_at": "2024-12-01T00:00:00Z"}
+			]`))
+		case "/graphql":
+			var req struct {
+				Variables struct {
+					Number int `json:"number"`
+				} `json:"variables"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decoding request: %v", err)
+			}
+			fields, ok := pullRequests[req.Variables.Number]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message": "Not Found"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"data": {"repository": {"pullRequest": {"number": %d, "createdAt": "2025-01-01T00:00:00Z", %s}}}}`,
+				req.Variables.Number, fields)
+		default:
+			w.Write([]byte(`{"total_count": 0, "check_runs": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	report, err := client.RepoReport(context.Background(), "o", "r", since)
+	if err != nil {
+		t.Fatalf("RepoReport() error: %v", err)
+	}
+	if report.Closed != 3 || report.Merged != 2 || report.Unfetched != 1 {
+		t.Errorf("Closed, Merged, Unfetched = %d, %d, %d; want 3, 2, 1 (#6 can't be fetched)", report.Closed, report.Merged, report.Unfetched)
+	}
+	if report.MedianTimeToMerge != 12*time.Hour {
+		t.Errorf("MedianTimeToMerge = %v, want 12h (the shorter of 12h and 24h)", report.MedianTimeToMerge)
+	}
+	if want := (DurationDistribution{P50: 2 * time.Hour, P75: 6 * time.Hour, P90: 6 * time.Hour, Max: 6 * time.Hour, Count: 2}); report.ReviewLatency != want {
+		t.Errorf("ReviewLatency = %+v, want %+v", report.ReviewLatency, want)
+	}
+	if got := report.MergeRateByAuthorType; len(got) != 2 || got[ActorTypeHuman] != 0.5 || got[ActorTypeGitHubApp] != 1 {
+		t.Errorf("MergeRateByAuthorType = %v, want half of humans' and all of apps' merged", got)
+	}
+}
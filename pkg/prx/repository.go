@@ -0,0 +1,47 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// Repository is basic context about the repository of a pull request, fetched once per
+// repository and cached for 3 hours.
+type Repository struct {
+	DefaultBranch string   `json:"default_branch"`
+	Visibility    string   `json:"visibility"`         // "public", "private", or "internal"
+	Language      string   `json:"language,omitempty"` // Primary language
+	Topics        []string `json:"topics,omitempty"`
+	Archived      bool     `json:"archived"`
+}
+
+// fetchRepository fetches repository metadata via the REST API, sharing it across pull requests.
+func (c *Client) fetchRepository(ctx context.Context, owner, repo string) (*Repository, error) {
+	return c.repositoryCache.Fetch(repositoryCacheKey(owner, repo), func() (*Repository, error) {
+		var r github.Repository
+		if _, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s", owner, repo), &r); err != nil {
+			return nil, err
+		}
+		visibility := r.Visibility
+		if visibility == "" {
+			visibility = "public"
+			if r.Private {
+				visibility = "private"
+			}
+		}
+		return &Repository{
+			DefaultBranch: r.DefaultBranch,
+			Visibility:    visibility,
+			Language:      r.Language,
+			Topics:        r.Topics,
+			Archived:      r.Archived,
+		}, nil
+	})
+}
+
+// repositoryCacheKey generates a cache key for repository metadata.
+func repositoryCacheKey(owner, repo string) string {
+	return fmt.Sprintf("%s/%s", owner, repo)
+}
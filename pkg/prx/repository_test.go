@@ -0,0 +1,47 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_PullRequestRepository(t *testing.T) {
+	repoRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"}}},
+				"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`))
+		case "/repos/owner/repo":
+			repoRequests++
+			w.Write([]byte(`{"default_branch": "main", "private": true, "language": "Go", "topics": ["github", "cli"]}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	for _, number := range []int{1, 2} {
+		data, err := client.PullRequest(context.Background(), "owner", "repo", number)
+		if err != nil {
+			t.Fatalf("PullRequest(%d) error: %v", number, err)
+		}
+		r := data.Repository
+		if r == nil || r.DefaultBranch != "main" || r.Visibility != "private" || r.Language != "Go" || !slices.Equal(r.Topics, []string{"github", "cli"}) {
+			t.Errorf("PullRequest(%d).Repository = %+v", number, r)
+		}
+	}
+	if repoRequests != 1 {
+		t.Errorf("got %d repository requests, want 1 shared across pull requests", repoRequests)
+	}
+}
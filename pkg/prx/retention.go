@@ -0,0 +1,68 @@
+package prx
+
+import (
+	"slices"
+	"time"
+)
+
+// PrunedEvents aggregates the events removed by PruneEvents, so metrics survive their removal.
+// Summaries computed at fetch time, such as Timing and the PullRequest summaries, are kept as is.
+type PrunedEvents struct {
+	Oldest time.Time      `json:"oldest"` // Timestamp of the oldest pruned event
+	Newest time.Time      `json:"newest"` // Timestamp of the newest pruned event
+	Kinds  map[string]int `json:"kinds"`  // Number of pruned events by kind
+	Actors map[string]int `json:"actors"` // Number of pruned events by actor
+	Bots   int            `json:"bots"`   // Number of pruned events by bots
+	Total  int            `json:"total"`
+}
+
+// PruneEvents removes events older than cutoff, adding them to the Pruned aggregates.
+// It can be applied repeatedly as the retention window moves. Returns the number of events removed.
+func (d *PullRequestData) PruneEvents(cutoff time.Time) int {
+	removed := 0
+	d.Events = slices.DeleteFunc(d.Events, func(e Event) bool {
+		if !e.Timestamp.Before(cutoff) {
+			return false
+		}
+		if d.Pruned == nil {
+			d.Pruned = &PrunedEvents{Kinds: make(map[string]int), Actors: make(map[string]int)}
+		}
+		p := d.Pruned
+		if p.Oldest.IsZero() || e.Timestamp.Before(p.Oldest) {
+			p.Oldest = e.Timestamp
+		}
+		if e.Timestamp.After(p.Newest) {
+			p.Newest = e.Timestamp
+		}
+		p.Kinds[string(e.Kind)]++
+		if e.Actor != "" {
+			p.Actors[e.Actor]++
+		}
+		if e.Bot {
+			p.Bots++
+		}
+		p.Total++
+		removed++
+		return true
+	})
+	return removed
+}
+
+// RedactEvents clears the free text of events older than cutoff: bodies, descriptions, mentions,
+// and raw payloads. Kinds, actors, timestamps, and outcomes are kept, so the events still count
+// towards metrics. Returns the number of events redacted.
+func (d *PullRequestData) RedactEvents(cutoff time.Time) int {
+	redacted := 0
+	for i := range d.Events {
+		e := &d.Events[i]
+		if !e.Timestamp.Before(cutoff) {
+			continue
+		}
+		if e.Body == "" && e.Description == "" && e.Mentions == nil && e.Raw == nil {
+			continue
+		}
+		e.Body, e.Description, e.Mentions, e.Raw = "", "", nil, nil
+		redacted++
+	}
+	return redacted
+}
@@ -0,0 +1,54 @@
+package prx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPullRequestData_PruneEvents(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &PullRequestData{Events: []Event{
+		{Kind: EventKindCommit, Timestamp: t0, Actor: "alice"},
+		{Kind: EventKindComment, Timestamp: t0.Add(time.Hour), Actor: "bob", Body: "old"},
+		{Kind: EventKindCheckRun, Timestamp: t0.Add(2 * time.Hour), Actor: "ci", Bot: true},
+		{Kind: EventKindComment, Timestamp: t0.Add(48 * time.Hour), Actor: "bob", Body: "recent"},
+	}}
+
+	if n := data.PruneEvents(t0.Add(90 * time.Minute)); n != 2 {
+		t.Errorf("PruneEvents() = %d, want 2", n)
+	}
+	if n := data.PruneEvents(t0.Add(24 * time.Hour)); n != 1 {
+		t.Errorf("second PruneEvents() = %d, want 1", n)
+	}
+	if len(data.Events) != 1 || data.Events[0].Body != "recent" {
+		t.Fatalf("Events = %+v, want only the recent comment", data.Events)
+	}
+	p := data.Pruned
+	if p.Total != 3 || p.Bots != 1 || p.Kinds[string(EventKindComment)] != 1 || p.Actors["alice"] != 1 {
+		t.Errorf("Pruned = %+v", p)
+	}
+	if !p.Oldest.Equal(t0) || !p.Newest.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("Pruned range = %v to %v", p.Oldest, p.Newest)
+	}
+}
+
+func TestPullRequestData_RedactEvents(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &PullRequestData{Events: []Event{
+		{Kind: EventKindComment, Timestamp: t0, Actor: "bob", Body: "ping @alice", Mentions: []string{"alice"}, Raw: json.RawMessage(`{}`)},
+		{Kind: EventKindCommit, Timestamp: t0, Actor: "alice"},
+		{Kind: EventKindComment, Timestamp: t0.Add(48 * time.Hour), Actor: "bob", Body: "recent"},
+	}}
+
+	if n := data.RedactEvents(t0.Add(time.Hour)); n != 1 {
+		t.Errorf("RedactEvents() = %d, want 1", n)
+	}
+	old := data.Events[0]
+	if old.Body != "" || old.Mentions != nil || old.Raw != nil || old.Actor != "bob" {
+		t.Errorf("redacted event = %+v", old)
+	}
+	if data.Events[2].Body != "recent" {
+		t.Error("expected recent event to keep its body")
+	}
+}
@@ -0,0 +1,217 @@
+package prx
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxSuggestionPaths limits how many changed files' histories SuggestReviewers fetches.
+	maxSuggestionPaths = 20
+	// suggestionHistory is how far back SuggestReviewers looks for commits to changed files.
+	suggestionHistory = 180 * 24 * time.Hour
+	// codeownerWeight is what owning a changed file counts for, in commits to changed files.
+	codeownerWeight = 3
+)
+
+// ReviewerSuggestion is a candidate reviewer for a pull request and the evidence behind it.
+type ReviewerSuggestion struct {
+	Login          string  `json:"login"`
+	Score          float64 `json:"score"`           // (3 × OwnedFiles + Commits) / (1 + PendingReviews)
+	OwnedFiles     int     `json:"owned_files"`     // Changed files the candidate is an individual CODEOWNER of
+	Commits        int     `json:"commits"`         // Recent commits by the candidate to changed files
+	PendingReviews int     `json:"pending_reviews"` // Other open pull requests awaiting the candidate's review
+}
+
+// SuggestReviewers ranks candidate reviewers for a pull request, best first. Candidates own changed
+// files in CODEOWNERS or recently committed to them; up to 20 changed files' histories over the last
+// 180 days are considered. Scores are discounted by the candidate's pending review requests on other
+// open pull requests in the repository, to spread review load. The author, bots, teams, and users
+// who are already reviewers are excluded. Missing CODEOWNERS, history, or load data is logged and
+// leaves out that signal.
+func (c *Client) SuggestReviewers(ctx context.Context, owner, repo string, prNumber int) ([]ReviewerSuggestion, error) {
+	data, err := c.PullRequest(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	pr := &data.PullRequest
+
+	// Logins are case-insensitive, and CODEOWNERS and commit history may differ in case from GitHub
+	reviewers := make(map[string]bool, len(pr.Reviewers))
+	for login := range pr.Reviewers {
+		reviewers[strings.ToLower(login)] = true
+	}
+	candidates := make(map[string]*ReviewerSuggestion)
+	candidate := func(login string) *ReviewerSuggestion {
+		key := strings.ToLower(login)
+		if login == "" || key == strings.ToLower(pr.Author) || reviewers[key] || c.classifyBot(ctx, graphQLActor{Login: login}) {
+			return nil
+		}
+		s, ok := candidates[key]
+		if !ok {
+			s = &ReviewerSuggestion{Login: login}
+			candidates[key] = s
+		}
+		return s
+	}
+
+	codeowners, err := c.Codeowners(ctx, owner, repo)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to fetch CODEOWNERS for reviewer suggestions", "owner", owner, "repo", repo, "error", err)
+	}
+	if codeowners != nil {
+		for _, path := range pr.Files {
+			rule := codeowners.Match(path)
+			if rule == nil {
+				continue
+			}
+			for _, o := range rule.Owners {
+				// Teams and email addresses can't be ranked against individuals
+				if login, ok := strings.CutPrefix(o, "@"); ok && !strings.Contains(login, "/") {
+					if s := candidate(login); s != nil {
+						s.OwnedFiles++
+					}
+				}
+			}
+		}
+	}
+
+	for _, authors := range c.pathCommitAuthors(ctx, owner, repo, pr.Files) {
+		for _, login := range authors {
+			if s := candidate(login); s != nil {
+				s.Commits++
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	pending, err := c.pendingReviewRequests(ctx, owner, repo, prNumber)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to fetch open pull requests for reviewer load", "owner", owner, "repo", repo, "error", err)
+	}
+
+	suggestions := make([]ReviewerSuggestion, 0, len(candidates))
+	for key, s := range candidates {
+		s.PendingReviews = pending[key]
+		s.Score = float64(codeownerWeight*s.OwnedFiles+s.Commits) / float64(1+s.PendingReviews)
+		suggestions = append(suggestions, *s)
+	}
+	slices.SortFunc(suggestions, func(a, b ReviewerSuggestion) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Login, b.Login))
+	})
+	return suggestions, nil
+}
+
+// pathCommitAuthors fetches the authors of recent commits to each of the first maxSuggestionPaths
+// paths, at most c.concurrency at a time. Paths whose history can't be fetched are skipped.
+func (c *Client) pathCommitAuthors(ctx context.Context, owner, repo string, paths []string) [][]string {
+	paths = paths[:min(len(paths), maxSuggestionPaths)]
+	since := time.Now().Add(-suggestionHistory).UTC().Format(time.RFC3339)
+	results := make([][]string, len(paths))
+	sem := make(chan struct{}, max(c.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var commits []struct {
+				Author *struct {
+					Login string `json:"login"`
+				} `json:"author"` // Nil when the commit email isn't linked to an account
+			}
+			params := url.Values{"path": {path}, "since": {since}, "per_page": {"30"}}
+			if _, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s/commits?%s", owner, repo, params.Encode()), &commits); err != nil {
+				c.logger.WarnContext(ctx, "failed to fetch file history for reviewer suggestions", "path", path, "error", err)
+				return
+			}
+			for _, commit := range commits {
+				if commit.Author != nil {
+					results[i] = append(results[i], commit.Author.Login)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// pendingReviewRequests counts, by lower-cased login, the open pull requests other than prNumber
+// with a pending review request for each user.
+func (c *Client) pendingReviewRequests(ctx context.Context, owner, repo string, prNumber int) (map[string]int, error) {
+	pending := make(map[string]int)
+	page := 1
+	for range maxPullRequestListPages {
+		var prs []struct {
+			RequestedReviewers []struct {
+				Login string `json:"login"`
+			} `json:"requested_reviewers"`
+			Number int `json:"number"`
+		}
+		resp, err := c.github.Get(ctx, fmt.Sprintf("/repos/%s/%s/pulls?state=open&per_page=100&page=%d", owner, repo, page), &prs)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prs {
+			if p.Number == prNumber {
+				continue
+			}
+			for _, r := range p.RequestedReviewers {
+				pending[strings.ToLower(r.Login)]++
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return pending, nil
+}
+
+// ReviewerLoad counts, for each of logins, the open pull requests in the organization that are
+// waiting on their review: those with a pending review request for them personally, not only for
+// a team they're on. Assignment automation can use it to spread reviews. It makes one search
+// request per login, at most c.concurrency at a time; GitHub allows 30 searches a minute.
+func (c *Client) ReviewerLoad(ctx context.Context, org string, logins []string) (map[string]int, error) {
+	counts := make([]int, len(logins))
+	errs := make([]error, len(logins))
+	sem := make(chan struct{}, max(c.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, login := range logins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var result struct {
+				TotalCount int `json:"total_count"`
+			}
+			q := fmt.Sprintf("is:pr is:open archived:false org:%s user-review-requested:%s", org, login)
+			params := url.Values{"q": {q}, "per_page": {"1"}}
+			if _, err := c.github.Get(ctx, "/search/issues?"+params.Encode(), &result); err != nil {
+				errs[i] = fmt.Errorf("searching review requests for %s: %w", login, err)
+				return
+			}
+			counts[i] = result.TotalCount
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	load := make(map[string]int, len(logins))
+	for i, login := range logins {
+		load[login] = counts[i]
+	}
+	return load, nil
+}
@@ -0,0 +1,119 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_SuggestReviewers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql":
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"id": "PR_node", "number": 7, "title": "Fix parser", "state": "OPEN",
+				"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-02T00:00:00Z",
+				"author": {"login": "alice", "__typename": "User"},
+				"headRef": {"name": "fix", "target": {"oid": "abc123"}},
+				"files": {"pageInfo": {"hasNextPage": false}, "nodes": [{"path": "parser/lex.go"}, {"path": "docs/parser.md"}]},
+				"reviewRequests": {"nodes": [{"requestedReviewer": {"login": "Erin", "__typename": "User"}}]},
+				"reviews": {"pageInfo": {"hasNextPage": false}, "nodes": []},
+				"comments": {"pageInfo": {"hasNextPage": false}, "nodes": []},
+				"timelineItems": {"pageInfo": {"hasNextPage": false}, "nodes": []}
+			}}}}`))
+		case r.URL.Path == "/repos/owner/repo/contents/.github/CODEOWNERS":
+			codeowners := "*.md @org/docs @dana\nparser/ @bob @org/parsers\n"
+			w.Write([]byte(`{"encoding": "base64", "content": "` + base64.StdEncoding.EncodeToString([]byte(codeowners)) + `"}`))
+		case strings.Contains(r.URL.Path, "/contents/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		case r.URL.Path == "/repos/owner/repo/commits":
+			switch r.URL.Query().Get("path") {
+			case "parser/lex.go":
+				w.Write([]byte(`[{"author": {"login": "carol"}}, {"author": {"login": "carol"}}, {"author": {"login": "alice"}},
+					{"author": {"login": "renovate[bot]"}}, {"author": null}, {"author": {"login": "erin"}}]`))
+			default:
+				w.Write([]byte(`[{"author": {"login": "dana"}}]`))
+			}
+		case r.URL.Path == "/repos/owner/repo/pulls" && r.URL.Query().Get("page") == "2":
+			w.Write([]byte(`[{"number": 9, "requested_reviewers": [{"login": "bob"}]}]`))
+		case r.URL.Path == "/repos/owner/repo/pulls":
+			w.Header().Set("Link", `<`+r.URL.Path+`?state=open&per_page=100&page=2>; rel="next"`)
+			w.Write([]byte(`[{"number": 7, "requested_reviewers": [{"login": "bob"}]},
+				{"number": 8, "requested_reviewers": [{"login": "bob"}, {"login": "Carol"}]}]`))
+		case strings.Contains(r.URL.Path, "/check-runs"):
+			w.Write([]byte(`{"check_runs": []}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	got, err := client.SuggestReviewers(context.Background(), "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("SuggestReviewers() error: %v", err)
+	}
+	// dana owns docs/parser.md and committed to it: (3+1)/1; carol committed twice to parser/lex.go but has a
+	// pending review: 2/2; bob owns parser/lex.go but has two pending reviews elsewhere: 3/3.
+	// alice is the author, erin is already requested (as Erin), and bots and teams are skipped.
+	want := []ReviewerSuggestion{
+		{Login: "dana", Score: 4, OwnedFiles: 1, Commits: 1},
+		{Login: "bob", Score: 1, OwnedFiles: 1, PendingReviews: 2},
+		{Login: "carol", Score: 1, Commits: 2, PendingReviews: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("SuggestReviewers() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("suggestion %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestClient_ReviewerLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch q := r.URL.Query().Get("q"); {
+		case !strings.Contains(q, "is:pr is:open") || !strings.Contains(q, "org:acme"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "unexpected query ` + q + `"}`))
+		case strings.HasSuffix(q, "user-review-requested:bob"):
+			w.Write([]byte(`{"total_count": 4, "items": [{"number": 1}]}`))
+		case strings.HasSuffix(q, "user-review-requested:ghost-user"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "Validation Failed"}`))
+		default:
+			w.Write([]byte(`{"total_count": 0, "items": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	load, err := client.ReviewerLoad(context.Background(), "acme", []string{"bob", "carol"})
+	if err != nil {
+		t.Fatalf("ReviewerLoad() error: %v", err)
+	}
+	if len(load) != 2 || load["bob"] != 4 || load["carol"] != 0 {
+		t.Errorf("ReviewerLoad() = %v, want bob: 4 and carol: 0", load)
+	}
+
+	if _, err := client.ReviewerLoad(context.Background(), "acme", []string{"bob", "ghost-user"}); err == nil ||
+		!strings.Contains(err.Error(), "ghost-user") {
+		t.Errorf("ReviewerLoad() with a failing search: error = %v, want one naming ghost-user", err)
+	}
+}
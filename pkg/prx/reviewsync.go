@@ -0,0 +1,249 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Finding is an unresolved issue found in a pull request, reported as an inline review comment
+// by SyncReviewComments.
+type Finding struct {
+	ID   string // Stable identifier matching the finding across syncs, e.g. "policy/rule:path"
+	Path string // File path relative to the repository root
+	Body string // Markdown comment body
+	Line int    // Line in the pull request's version of the file
+}
+
+// ReviewSyncResult counts the changes made by SyncReviewComments.
+type ReviewSyncResult struct {
+	Posted    int `json:"posted"`    // New review threads
+	Reopened  int `json:"reopened"`  // Resolved threads whose finding reappeared
+	Resolved  int `json:"resolved"`  // Threads whose finding cleared
+	Unchanged int `json:"unchanged"` // Open threads whose finding is still present
+}
+
+// Errors returned by writes for pull requests that can't be changed. ErrConversationLocked is only
+// returned for comment writes, such as SyncReviewComments.
+var (
+	ErrRepoArchived       = errors.New("repository is archived")
+	ErrConversationLocked = errors.New("pull request conversation is locked")
+)
+
+// findingMarkerPattern matches the hidden marker identifying a thread posted for a finding.
+var findingMarkerPattern = regexp.MustCompile(`<!-- prx-finding:(\S+) -->`)
+
+const reviewThreadsQuery = `
+query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+	repository(owner: $owner, name: $repo) {
+		pullRequest(number: $number) {
+			id
+			locked
+			baseRepository {
+				isArchived
+			}
+			reviewThreads(first: 100, after: $cursor) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				nodes {
+					id
+					isResolved
+					comments(first: 1) {
+						nodes {
+							body
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+const addReviewMutation = `
+mutation($input: AddPullRequestReviewInput!) {
+	addPullRequestReview(input: $input) {
+		pullRequestReview {
+			id
+		}
+	}
+}`
+
+const resolveReviewThreadMutation = `
+mutation($threadId: ID!) {
+	resolveReviewThread(input: {threadId: $threadId}) {
+		thread {
+			id
+		}
+	}
+}`
+
+const unresolveReviewThreadMutation = `
+mutation($threadId: ID!) {
+	unresolveReviewThread(input: {threadId: $threadId}) {
+		thread {
+			id
+		}
+	}
+}`
+
+// findingThread is a review thread previously posted for a finding.
+type findingThread struct {
+	id       string
+	resolved bool
+}
+
+// SyncReviewComments makes the pull request's review threads mirror findings: new findings are posted
+// as inline comments in a single review, threads of cleared findings are resolved, and resolved threads
+// of findings that reappear are reopened. Threads are matched to findings by a hidden marker in their
+// first comment, so threads posted by people are never touched. Archived repositories and locked
+// conversations are detected up front and reported as ErrRepoArchived or ErrConversationLocked.
+func (c *Client) SyncReviewComments(ctx context.Context, owner, repo string, prNumber int, findings []Finding) (*ReviewSyncResult, error) {
+	prID, threads, err := c.findingThreads(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReviewSyncResult{}
+	current := make(map[string]bool, len(findings))
+	var newThreads []map[string]any
+	for _, f := range findings {
+		if f.ID == "" || strings.ContainsAny(f.ID, " \t\n") {
+			return nil, fmt.Errorf("finding ID %q must be non-empty without whitespace", f.ID)
+		}
+		if current[f.ID] {
+			continue
+		}
+		current[f.ID] = true
+
+		t, ok := threads[f.ID]
+		switch {
+		case !ok:
+			newThreads = append(newThreads, map[string]any{
+				"path": f.Path,
+				"line": f.Line,
+				"side": "RIGHT",
+				"body": f.Body + "\n\n<!-- prx-finding:" + f.ID + " -->",
+			})
+		case t.resolved:
+			if err := c.mutateGraphQL(ctx, unresolveReviewThreadMutation, map[string]any{"threadId": t.id}); err != nil {
+				return result, fmt.Errorf("reopening thread for finding %s: %w", f.ID, err)
+			}
+			result.Reopened++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if len(newThreads) > 0 {
+		input := map[string]any{"pullRequestId": prID, "event": "COMMENT", "threads": newThreads}
+		if err := c.mutateGraphQL(ctx, addReviewMutation, map[string]any{"input": input}); err != nil {
+			return result, fmt.Errorf("posting findings: %w", err)
+		}
+		result.Posted = len(newThreads)
+	}
+
+	for id, t := range threads {
+		if current[id] || t.resolved {
+			continue
+		}
+		if err := c.mutateGraphQL(ctx, resolveReviewThreadMutation, map[string]any{"threadId": t.id}); err != nil {
+			return result, fmt.Errorf("resolving thread for finding %s: %w", id, err)
+		}
+		result.Resolved++
+	}
+
+	c.logger.InfoContext(ctx, "synced findings to review comments",
+		"owner", owner, "repo", repo, "pr", prNumber,
+		"posted", result.Posted, "reopened", result.Reopened, "resolved", result.Resolved, "unchanged", result.Unchanged)
+	return result, nil
+}
+
+// findingThreads returns the pull request's node ID and its review threads posted for findings, keyed by finding ID.
+func (c *Client) findingThreads(ctx context.Context, owner, repo string, prNumber int) (string, map[string]findingThread, error) {
+	threads := make(map[string]findingThread)
+	var prID string
+	var cursor any
+	for range maxGraphQLPages + 1 {
+		var resp struct {
+			Data struct {
+				Repository struct {
+					PullRequest *struct {
+						ID             string `json:"id"`
+						Locked         bool   `json:"locked"`
+						BaseRepository struct {
+							IsArchived bool `json:"isArchived"`
+						} `json:"baseRepository"`
+						ReviewThreads struct {
+							PageInfo graphQLPageInfo `json:"pageInfo"`
+							Nodes    []struct {
+								ID       string `json:"id"`
+								Comments struct {
+									Nodes []struct {
+										Body string `json:"body"`
+									} `json:"nodes"`
+								} `json:"comments"`
+								IsResolved bool `json:"isResolved"`
+							} `json:"nodes"`
+						} `json:"reviewThreads"`
+					} `json:"pullRequest"`
+				} `json:"repository"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		variables := map[string]any{"owner": owner, "repo": repo, "number": prNumber, "cursor": cursor}
+		if err := c.github.GraphQL(ctx, reviewThreadsQuery, variables, &resp); err != nil {
+			return "", nil, fmt.Errorf("fetching review threads for PR %s/%s#%d: %w", owner, repo, prNumber, err)
+		}
+		if len(resp.Errors) > 0 {
+			return "", nil, fmt.Errorf("fetching review threads for PR %s/%s#%d: %s", owner, repo, prNumber, resp.Errors[0].Message)
+		}
+		pr := resp.Data.Repository.PullRequest
+		if pr == nil {
+			return "", nil, fmt.Errorf("pull request %s/%s#%d not found", owner, repo, prNumber)
+		}
+		switch {
+		case pr.BaseRepository.IsArchived:
+			return "", nil, fmt.Errorf("syncing review comments on PR %s/%s#%d: %w", owner, repo, prNumber, ErrRepoArchived)
+		case pr.Locked:
+			return "", nil, fmt.Errorf("syncing review comments on PR %s/%s#%d: %w", owner, repo, prNumber, ErrConversationLocked)
+		default:
+		}
+		prID = pr.ID
+		for _, t := range pr.ReviewThreads.Nodes {
+			if len(t.Comments.Nodes) == 0 {
+				continue
+			}
+			if m := findingMarkerPattern.FindStringSubmatch(t.Comments.Nodes[0].Body); m != nil {
+				threads[m[1]] = findingThread{id: t.ID, resolved: t.IsResolved}
+			}
+		}
+		if !pr.ReviewThreads.PageInfo.HasNextPage {
+			return prID, threads, nil
+		}
+		cursor = pr.ReviewThreads.PageInfo.EndCursor
+	}
+	// Syncing from a partial view could post duplicates of threads on unseen pages
+	return "", nil, fmt.Errorf("too many review threads on PR %s/%s#%d", owner, repo, prNumber)
+}
+
+// mutateGraphQL runs a GraphQL mutation, returning the first GraphQL error.
+func (c *Client) mutateGraphQL(ctx context.Context, mutation string, variables map[string]any) error {
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := c.github.GraphQL(ctx, mutation, variables, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return errors.New(resp.Errors[0].Message)
+	}
+	return nil
+}
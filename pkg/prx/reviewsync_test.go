@@ -0,0 +1,116 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_SyncReviewComments(t *testing.T) {
+	var posted []map[string]any
+	var resolved, unresolved []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.Contains(req.Query, "addPullRequestReview"):
+			input := req.Variables["input"].(map[string]any)
+			for _, th := range input["threads"].([]any) {
+				posted = append(posted, th.(map[string]any))
+			}
+			w.Write([]byte(`{"data": {"addPullRequestReview": {"pullRequestReview": {"id": "R1"}}}}`))
+		case strings.Contains(req.Query, "unresolveReviewThread"):
+			unresolved = append(unresolved, req.Variables["threadId"].(string))
+			w.Write([]byte(`{"data": {}}`))
+		case strings.Contains(req.Query, "resolveReviewThread"):
+			resolved = append(resolved, req.Variables["threadId"].(string))
+			w.Write([]byte(`{"data": {}}`))
+		default:
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"id": "PR1", "reviewThreads": {
+				"pageInfo": {"hasNextPage": false},
+				"nodes": [
+					{"id": "T1", "isResolved": false, "comments": {"nodes": [{"body": "Stale\n\n<!-- prx-finding:cleared -->"}]}},
+					{"id": "T2", "isResolved": true, "comments": {"nodes": [{"body": "Back\n\n<!-- prx-finding:reappeared -->"}]}},
+					{"id": "T3", "isResolved": false, "comments": {"nodes": [{"body": "Open\n\n<!-- prx-finding:still-open -->"}]}},
+					{"id": "T4", "isResolved": false, "comments": {"nodes": [{"body": "A human comment"}]}}
+				]}}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	result, err := client.SyncReviewComments(context.Background(), "owner", "repo", 1, []Finding{
+		{ID: "reappeared", Path: "a.go", Line: 1, Body: "Back"},
+		{ID: "still-open", Path: "b.go", Line: 2, Body: "Open"},
+		{ID: "new", Path: "c.go", Line: 3, Body: "Missing test"},
+	})
+	if err != nil {
+		t.Fatalf("SyncReviewComments() error: %v", err)
+	}
+	want := ReviewSyncResult{Posted: 1, Reopened: 1, Resolved: 1, Unchanged: 1}
+	if *result != want {
+		t.Errorf("SyncReviewComments() = %+v, want %+v", *result, want)
+	}
+	if len(posted) != 1 || posted[0]["path"] != "c.go" || !strings.Contains(posted[0]["body"].(string), "<!-- prx-finding:new -->") {
+		t.Errorf("posted threads = %v, want one marked thread on c.go", posted)
+	}
+	if len(resolved) != 1 || resolved[0] != "T1" {
+		t.Errorf("resolved threads = %v, want [T1]", resolved)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "T2" {
+		t.Errorf("reopened threads = %v, want [T2]", unresolved)
+	}
+
+	if _, err := client.SyncReviewComments(context.Background(), "owner", "repo", 1, []Finding{{ID: "has space"}}); err == nil {
+		t.Error("expected error for finding ID with whitespace")
+	}
+}
+
+func TestClient_SyncReviewComments_ReadOnly(t *testing.T) {
+	tests := []struct {
+		want error
+		name string
+		pr   string
+	}{
+		{name: "archived", pr: `"baseRepository": {"isArchived": true}`, want: ErrRepoArchived},
+		{name: "locked", pr: `"locked": true`, want: ErrConversationLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutations := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(body), "mutation") {
+					mutations++
+				}
+				w.Write([]byte(`{"data": {"repository": {"pullRequest": {"id": "PR1", ` + tt.pr + `,
+					"reviewThreads": {"pageInfo": {"hasNextPage": false}, "nodes": []}}}}}`))
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+			client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+			_, err := client.SyncReviewComments(context.Background(), "owner", "repo", 1, []Finding{{ID: "new", Path: "a.go", Line: 1}})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SyncReviewComments() error = %v, want %v", err, tt.want)
+			}
+			if mutations != 0 {
+				t.Errorf("got %d mutations, want none", mutations)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package prx
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SampleOptions configures GenerateSample. Zero values use the defaults noted.
+type SampleOptions struct {
+	End              time.Time     // Creation times and events don't go past End; defaults to 2025-01-01 UTC
+	Owner            string        // For overlapping pull request references; defaults to "example"
+	Repo             string        // Defaults to "sample"
+	Checks           []string      // Required checks run on every push; defaults to build, lint, and test
+	Window           time.Duration // Pull requests are created in the Window before End; defaults to 90 days
+	Seed             uint64        // The same seed and options always produce the same data
+	PullRequests     int           // Defaults to 100
+	Contributors     int           // Size of the author and reviewer pool; defaults to 12
+	MeanCommits      float64       // Pushes per pull request not authored by a bot; defaults to 3
+	MeanComments     float64       // Conversation and review comments per pull request; defaults to 4
+	MeanReviews      float64       // Reviews per pull request besides the approval that merges it; defaults to 1.5
+	MergeRate        float64       // Share of pull requests merged; defaults to 0.7
+	CloseRate        float64       // Share closed without merging; defaults to 0.1
+	BotRate          float64       // Share authored by bots; defaults to 0.15
+	CheckFailureRate float64       // Chance of each check run failing; defaults to 0.1
+}
+
+// sampleFiles are the paths sample pull requests change.
+var sampleFiles = []string{
+	"README.md", "go.mod", "go.sum", "cmd/server/main.go", "internal/api/handler.go", "internal/api/handler_test.go",
+	"internal/api/routes.go", "internal/auth/token.go", "internal/auth/token_test.go", "internal/db/query.go",
+	"internal/db/migrate.go", "pkg/client/client.go", "pkg/client/client_test.go", "docs/setup.md", ".github/workflows/ci.yml",
+}
+
+// sampleWords make up sample titles and comments.
+var sampleWords = []string{
+	"add", "fix", "remove", "update", "refactor", "handle", "retry", "cache", "timeout", "config", "logging",
+	"handler", "token", "query", "migration", "docs", "tests", "error", "flaky", "startup", "metrics",
+}
+
+// GenerateSample produces synthetic but realistically shaped pull requests, with events and all derived
+// summaries, for benchmarks and dashboard demos without calling the GitHub API. Logins, SHAs, and text
+// are made up, and open pull requests changing the same files are flagged as with FlagOverlappingPRs.
+// Counts follow geometric distributions around the configured means, so a few pull requests are much
+// busier than the rest, and a few contributors author most of them.
+func GenerateSample(opts SampleOptions) []PullRequestData {
+	opts = opts.withDefaults()
+	g := &sampleGenerator{opts: opts, rng: rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x5eed))}
+	repository := &Repository{DefaultBranch: "main", Visibility: "private", Language: "Go"}
+	prs := make([]PullRequestData, opts.PullRequests)
+	refs := make([]*PullRequestData, len(prs))
+	for i := range prs {
+		prs[i] = g.pullRequest(i + 1)
+		prs[i].Repository = repository
+		refs[i] = &prs[i]
+	}
+	FlagOverlappingPRs(opts.Owner, opts.Repo, refs)
+	return prs
+}
+
+func (o SampleOptions) withDefaults() SampleOptions {
+	if o.End.IsZero() {
+		o.End = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if o.Owner == "" {
+		o.Owner = "example"
+	}
+	if o.Repo == "" {
+		o.Repo = "sample"
+	}
+	if o.Checks == nil {
+		o.Checks = []string{"build", "lint", "test"}
+	}
+	defaults := []struct {
+		value *float64
+		def   float64
+	}{
+		{&o.MeanCommits, 3}, {&o.MeanComments, 4}, {&o.MeanReviews, 1.5},
+		{&o.MergeRate, 0.7}, {&o.CloseRate, 0.1}, {&o.BotRate, 0.15}, {&o.CheckFailureRate, 0.1},
+	}
+	for _, d := range defaults {
+		if *d.value == 0 {
+			*d.value = d.def
+		}
+	}
+	if o.Window <= 0 {
+		o.Window = 90 * 24 * time.Hour
+	}
+	if o.PullRequests <= 0 {
+		o.PullRequests = 100
+	}
+	if o.Contributors <= 0 {
+		o.Contributors = 12
+	}
+	return o
+}
+
+type sampleGenerator struct {
+	rng  *rand.Rand
+	opts SampleOptions
+}
+
+// pullRequest generates one pull request. Its events are generated in order from its creation,
+// and any after End are dropped, leaving recent pull requests open.
+func (g *sampleGenerator) pullRequest(number int) PullRequestData {
+	opts := g.opts
+	created := opts.End.Add(-time.Duration(g.rng.Int64N(int64(opts.Window)))).Truncate(time.Second)
+	author, authorBot := g.contributor(), g.chance(opts.BotRate)
+	if authorBot {
+		author = []string{"dependabot[bot]", "renovate[bot]"}[g.rng.IntN(2)]
+	}
+
+	pr := PullRequest{
+		Number:            number,
+		Author:            author,
+		AuthorBot:         authorBot,
+		AuthorWriteAccess: g.writeAccess(author, authorBot),
+		CreatedAt:         created,
+		Title:             g.sentence(3 + g.rng.IntN(5)),
+		Body:              g.sentence(10 + g.rng.IntN(30)),
+		HeadRef:           fmt.Sprintf("%s/%s-%d", strings.TrimSuffix(author, "[bot]"), sampleWords[g.rng.IntN(len(sampleWords))], number),
+		State:             "open",
+		NodeID:            fmt.Sprintf("PR_sample%d", number),
+		Assignees:         []string{},
+		Reviewers:         make(map[string]ReviewState),
+	}
+	// Bots bump dependencies in a single commit
+	pushes := 1
+	if authorBot {
+		pr.Files = []string{"go.mod", "go.sum"}
+	} else {
+		pushes += g.count(opts.MeanCommits - 1)
+		for _, i := range g.rng.Perm(len(sampleFiles))[:1+min(g.count(2), len(sampleFiles)-1)] {
+			pr.Files = append(pr.Files, sampleFiles[i])
+		}
+		slices.Sort(pr.Files)
+	}
+	pr.ChangedFiles = len(pr.Files)
+	pr.Additions = 1 + g.count(float64(40*pr.ChangedFiles))
+	pr.Deletions = g.count(float64(15 * pr.ChangedFiles))
+
+	events := []Event{{
+		Kind: EventKindPROpened, Timestamp: created, Actor: author, Body: pr.Body, Bot: authorBot, WriteAccess: pr.AuthorWriteAccess,
+	}}
+	if !authorBot {
+		pr.Draft = g.chance(0.1)
+	}
+
+	// Reviewers are requested within hours and respond within a day, while the author pushes fixes
+	var reviewers []string
+	if !authorBot || g.chance(0.5) {
+		for range 1 + g.rng.IntN(2) {
+			if r := g.contributor(); r != author && !slices.Contains(reviewers, r) {
+				reviewers = append(reviewers, r)
+				events = append(events, Event{Kind: EventKindReviewRequested, Timestamp: g.after(created, time.Hour), Actor: author, Target: r})
+				pr.Reviewers[r] = ReviewStatePending
+			}
+		}
+	}
+
+	// The first commit is pushed as the pull request is opened
+	at := created
+	for i := range pushes {
+		if i > 0 {
+			at = g.after(at, 3*time.Hour)
+		}
+		sha := g.sha()
+		events = append(events, Event{Kind: EventKindCommit, Timestamp: at, Actor: author, Bot: authorBot, Body: sha, Description: g.sentence(4)})
+		for _, check := range opts.Checks {
+			outcome := "success"
+			if g.chance(opts.CheckFailureRate) {
+				outcome = "failure"
+			}
+			events = append(events, Event{
+				Kind: EventKindCheckRun, Timestamp: g.after(at, 10*time.Minute), Body: check, Outcome: outcome, Bot: true, Required: true,
+				Description: outcome, CheckRunID: g.rng.Int64N(1 << 40),
+			})
+		}
+	}
+
+	participants := append([]string{author}, reviewers...)
+	for range g.count(opts.MeanComments) {
+		actor := participants[g.rng.IntN(len(participants))]
+		e := Event{
+			Kind: EventKindComment, Timestamp: g.after(created, 24*time.Hour), Actor: actor, Body: g.sentence(5 + g.rng.IntN(20)),
+			Bot: actor == author && authorBot, WriteAccess: g.writeAccess(actor, actor == author && authorBot),
+		}
+		if actor != author && g.chance(0.6) {
+			e.Kind = EventKindReviewComment
+			e.ThreadResolved = g.chance(0.6)
+		}
+		e.Question = g.chance(0.2)
+		if e.Question {
+			e.Body += "?"
+		}
+		events = append(events, e)
+	}
+	if len(reviewers) > 0 {
+		for range g.count(opts.MeanReviews) {
+			r := reviewers[g.rng.IntN(len(reviewers))]
+			outcome := []string{"commented", "changes_requested", "approved"}[g.rng.IntN(3)]
+			events = append(events, Event{Kind: EventKindReview, Timestamp: g.after(created, 24*time.Hour), Actor: r, Outcome: outcome, WriteAccess: g.writeAccess(r, false)})
+		}
+	}
+
+	last := created
+	for i := range events {
+		if events[i].Timestamp.After(last) {
+			last = events[i].Timestamp
+		}
+	}
+	switch outcome := g.rng.Float64(); {
+	case pr.Draft:
+		// Drafts stay open
+	case outcome < opts.MergeRate:
+		merger := author
+		if len(reviewers) > 0 {
+			merger = reviewers[0]
+			events = append(events, Event{Kind: EventKindReview, Timestamp: g.after(last, 4*time.Hour), Actor: merger, Outcome: "approved", WriteAccess: g.writeAccess(merger, false)})
+			last = events[len(events)-1].Timestamp
+		}
+		if merged := g.after(last, 2*time.Hour); !merged.After(opts.End) {
+			pr.State, pr.Merged, pr.MergedBy, pr.MergedAt, pr.ClosedAt = "closed", true, merger, &merged, &merged
+			events = append(events, Event{Kind: EventKindPRMerged, Timestamp: merged, Actor: merger})
+		}
+	case outcome < opts.MergeRate+opts.CloseRate:
+		if closed := g.after(last, 3*24*time.Hour); !closed.After(opts.End) {
+			pr.State, pr.ClosedAt = "closed", &closed
+			events = append(events, Event{Kind: EventKindPRClosed, Timestamp: closed})
+		}
+	default:
+	}
+
+	events = slices.DeleteFunc(events, func(e Event) bool { return e.Timestamp.After(opts.End) })
+	slices.SortStableFunc(events, func(a, b Event) int { return a.Timestamp.Compare(b.Timestamp) })
+	assignSequence(events, nil)
+	threads := &ThreadSummary{}
+	for i := range events {
+		e := &events[i]
+		pr.UpdatedAt = e.Timestamp
+		switch {
+		case e.Actor == "":
+		case e.Bot:
+			e.ActorType = ActorTypeGitHubApp
+		default:
+			e.ActorType = ActorTypeHuman
+		}
+		switch e.Kind {
+		case EventKindCommit:
+			pr.Commits = append(pr.Commits, e.Body)
+			pr.HeadSHA = e.Body
+		case EventKindReview:
+			pr.Reviewers[e.Actor] = ReviewState(e.Outcome)
+		case EventKindReviewComment:
+			threads.Total++
+			if e.ThreadResolved {
+				threads.Resolved++
+			} else {
+				threads.Unresolved++
+			}
+		default:
+		}
+	}
+	pr.ThreadSummary = threads
+	if len(pr.Reviewers) == 0 {
+		pr.Reviewers = nil
+	}
+	pr.MergeableState = g.mergeableState(&pr, events)
+
+	finalizePullRequest(&pr, events, opts.Checks, "")
+	return PullRequestData{
+		PullRequest:       pr,
+		Events:            events,
+		ActivityHistogram: calculateActivityHistogram(events),
+		Timing:            calculateTimingSummary(&pr, events, opts.End),
+	}
+}
+
+// mergeableState approximates GitHub's merge state for an open pull request.
+func (g *sampleGenerator) mergeableState(pr *PullRequest, events []Event) string {
+	if pr.State != "open" {
+		return ""
+	}
+	checks := calculateCheckSummary(events, g.opts.Checks)
+	switch {
+	case pr.Draft:
+		return "draft"
+	case g.chance(0.05):
+		return "dirty"
+	case len(checks.Failing) > 0:
+		return "unstable"
+	case calculateApprovalSummary(events, pr.HeadSHA).ApprovalsWithWriteAccess == 0:
+		return "blocked"
+	default:
+		return "clean"
+	}
+}
+
+// contributor picks a login, favoring the first few so that a core group does most of the work.
+func (g *sampleGenerator) contributor() string {
+	return fmt.Sprintf("user-%02d", int(float64(g.opts.Contributors)*math.Pow(g.rng.Float64(), 2)))
+}
+
+// writeAccess grants write access to the first half of the contributor pool.
+func (g *sampleGenerator) writeAccess(login string, bot bool) int {
+	index, ok := strings.CutPrefix(login, "user-")
+	n, err := strconv.Atoi(index)
+	if bot || !ok || err != nil {
+		return WriteAccessUnlikely
+	}
+	if n < (g.opts.Contributors+1)/2 {
+		return WriteAccessDefinitely
+	}
+	return WriteAccessUnlikely
+}
+
+// count draws from a geometric distribution with the given mean.
+func (g *sampleGenerator) count(mean float64) int {
+	if mean <= 0 {
+		return 0
+	}
+	return int(g.rng.ExpFloat64() / math.Log1p(1/mean))
+}
+
+// after returns a time following t by an exponentially distributed delay with the given mean.
+func (g *sampleGenerator) after(t time.Time, mean time.Duration) time.Time {
+	return t.Add(time.Duration(g.rng.ExpFloat64() * float64(mean))).Truncate(time.Second)
+}
+
+func (g *sampleGenerator) chance(p float64) bool {
+	return g.rng.Float64() < p
+}
+
+func (g *sampleGenerator) sha() string {
+	return fmt.Sprintf("%016x%016x%08x", g.rng.Uint64(), g.rng.Uint64(), g.rng.Uint32())
+}
+
+func (g *sampleGenerator) sentence(words int) string {
+	b := make([]byte, 0, words*8)
+	for i := range words {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, sampleWords[g.rng.IntN(len(sampleWords))]...)
+	}
+	return string(b)
+}
@@ -0,0 +1,81 @@
+package prx
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateSample(t *testing.T) {
+	opts := SampleOptions{Seed: 42, PullRequests: 200}
+	prs := GenerateSample(opts)
+	if len(prs) != 200 {
+		t.Fatalf("GenerateSample() returned %d pull requests, want 200", len(prs))
+	}
+
+	first, err := json.Marshal(prs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := json.Marshal(GenerateSample(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(again) {
+		t.Error("GenerateSample() with the same seed produced different data")
+	}
+	opts.Seed = 43
+	other, err := json.Marshal(GenerateSample(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) == string(other) {
+		t.Error("GenerateSample() with a different seed produced the same data")
+	}
+
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	states := make(map[string]int)
+	var bots, events int
+	for i := range prs {
+		pr := &prs[i].PullRequest
+		if pr.Number != i+1 || pr.HeadSHA == "" || pr.HeadSHA != pr.Commits[len(pr.Commits)-1] || pr.CheckSummary == nil || pr.ApprovalSummary == nil {
+			t.Fatalf("pull request %d = %+v", i+1, pr)
+		}
+		if !slices.IsSortedFunc(prs[i].Events, func(a, b Event) int { return a.Timestamp.Compare(b.Timestamp) }) {
+			t.Errorf("pull request %d events aren't in order", pr.Number)
+		}
+		if last := prs[i].Events[len(prs[i].Events)-1]; last.Timestamp.After(end) || last.Seq != int64(len(prs[i].Events)) {
+			t.Errorf("pull request %d last event = %+v", pr.Number, last)
+		}
+		switch {
+		case pr.Merged:
+			states["merged"]++
+			if pr.MergedAt == nil || pr.MergedBy == "" || pr.WaitingOn != WaitingOnNobody {
+				t.Errorf("merged pull request %d = %+v", pr.Number, pr)
+			}
+		case pr.State == "closed":
+			states["closed"]++
+		default:
+			states["open"]++
+			if pr.MergeableState == "" || pr.WaitingOn == WaitingOnNobody {
+				t.Errorf("open pull request %d has merge state %q, waiting on %q", pr.Number, pr.MergeableState, pr.WaitingOn)
+			}
+		}
+		if pr.AuthorBot {
+			bots++
+		}
+		events += len(prs[i].Events)
+	}
+
+	// Loose bounds on the default distributions
+	if states["merged"] < 100 || states["closed"] < 5 || states["open"] < 10 {
+		t.Errorf("states = %v", states)
+	}
+	if bots < 10 || bots > 60 {
+		t.Errorf("%d bot-authored pull requests, want about 30", bots)
+	}
+	if mean := float64(events) / float64(len(prs)); mean < 10 || mean > 40 {
+		t.Errorf("%.1f events per pull request on average, want about 20", mean)
+	}
+}
@@ -0,0 +1,116 @@
+package prx
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// redactedSecret replaces each secret removed by WithSecretRedaction.
+const redactedSecret = "[REDACTED]"
+
+// builtinSecretPatterns match common credential formats. Where only part of a match is secret,
+// such as the value of "password=...", it is in a group named "secret".
+var builtinSecretPatterns = []*regexp.Regexp{
+	github.TokenPattern,
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),                                      // AWS access key IDs
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),                                         // GitLab
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                                    // Slack
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                                            // Google API keys
+	regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}`),                                      // Stripe
+	regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`),                                            // npm
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), // JWTs
+	regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/-]{20,}=*)`),
+	// Assignments such as "password: hunter22" or "NPM_TOKEN=...", including as JSON keys
+	regexp.MustCompile(`(?i)(?:password|passwd|secret|token|api[_-]?key|access[_-]?key)[a-z0-9_]*["']?` +
+		`\s*[:=]\s*["']?(?P<secret>[^\s"'\\]{8,})`),
+}
+
+// WithSecretRedaction replaces secret-looking text, such as API tokens, private keys, and password
+// assignments, with "[REDACTED]" in fetched pull request bodies, event bodies and descriptions, check
+// summaries, raw payloads, and check run output, before they are cached or returned. CI failure
+// summaries occasionally leak credentials, which would otherwise spread to downstream stores.
+//
+// Patterns add to the built-in ones. If a pattern has a group named "secret", only that group is
+// redacted, keeping context such as "password=".
+func WithSecretRedaction(patterns ...*regexp.Regexp) Option {
+	return func(c *Client) {
+		if c.secretPatterns == nil {
+			c.secretPatterns = append(c.secretPatterns, builtinSecretPatterns...)
+		}
+		c.secretPatterns = append(c.secretPatterns, patterns...)
+	}
+}
+
+// redactSecrets applies WithSecretRedaction to the text of data.
+func (c *Client) redactSecrets(data *PullRequestData) {
+	if c.secretPatterns == nil {
+		return
+	}
+	pr := &data.PullRequest
+	pr.Body = c.redactText(pr.Body)
+	pr.Raw = c.redactBytes(pr.Raw)
+	c.redactCheckSummary(pr.CheckSummary)
+	for _, summary := range pr.ChecksByCommit {
+		c.redactCheckSummary(summary)
+	}
+	for i := range data.Events {
+		e := &data.Events[i]
+		e.Body = c.redactText(e.Body)
+		e.Description = c.redactText(e.Description)
+		e.Raw = c.redactBytes(e.Raw)
+	}
+}
+
+func (c *Client) redactCheckSummary(summary *CheckSummary) {
+	if summary == nil {
+		return
+	}
+	for _, m := range []map[string]string{
+		summary.Success, summary.Failing, summary.Pending, summary.Cancelled,
+		summary.Skipped, summary.Stale, summary.Neutral,
+	} {
+		for name, description := range m {
+			m[name] = c.redactText(description)
+		}
+	}
+}
+
+// redactBytes redacts raw JSON. Redacted values stay valid inside JSON strings.
+func (c *Client) redactBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	s := string(b)
+	if redacted := c.redactText(s); redacted != s {
+		return []byte(redacted)
+	}
+	return b
+}
+
+// redactText replaces the secrets in s, returning s itself if there are none.
+func (c *Client) redactText(s string) string {
+	for _, re := range c.secretPatterns {
+		matches := re.FindAllStringSubmatchIndex(s, -1)
+		if matches == nil {
+			continue
+		}
+		group := re.SubexpIndex("secret")
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(redactedSecret)
+			last = end
+		}
+		b.WriteString(s[last:])
+		s = b.String()
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+package prx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido"
+)
+
+// SQLiteDriver is the database/sql driver name used by NewSQLiteCacheStore. prx doesn't link a
+// SQLite driver itself; import one registering this name, such as modernc.org/sqlite.
+const SQLiteDriver = "sqlite"
+
+// Persistent cache namespaces, one per cached data type.
+const (
+	cacheKindPullRequests  = "pr"
+	cacheKindCollaborators = "collaborators"
+	cacheKindRulesets      = "rulesets"
+)
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS prx_cache (
+	kind    TEXT    NOT NULL,
+	key     TEXT    NOT NULL,
+	value   BLOB    NOT NULL,
+	expiry  INTEGER NOT NULL,
+	updated INTEGER NOT NULL,
+	PRIMARY KEY (kind, key)
+);
+CREATE INDEX IF NOT EXISTS prx_cache_updated ON prx_cache (kind, updated);`
+
+// SQLiteCacheStore persists pull requests and repository-level data (collaborators and rulesets)
+// in a single SQLite database, so they survive process restarts. Entries expire with the TTL of
+// the cache using them, and each data type is capped at a maximum number of entries.
+type SQLiteCacheStore struct {
+	db         *sql.DB
+	path       string
+	maxEntries int
+}
+
+// SQLiteOption configures a SQLiteCacheStore.
+type SQLiteOption func(*SQLiteCacheStore)
+
+// WithSQLiteMaxEntries caps the number of entries kept per data type; the least recently written
+// entries are evicted first. Zero disables the cap.
+func WithSQLiteMaxEntries(n int) SQLiteOption {
+	return func(s *SQLiteCacheStore) {
+		s.maxEntries = max(n, 0)
+	}
+}
+
+// NewSQLiteCacheStore opens or creates the SQLite cache database at path, using the driver
+// registered as SQLiteDriver. Use it with WithSQLiteCache, and close it after the client.
+func NewSQLiteCacheStore(path string, opts ...SQLiteOption) (*SQLiteCacheStore, error) {
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return nil, errors.New("cache database must be absolute path")
+	}
+	db, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		return nil, fmt.Errorf("opening cache database: %w", err)
+	}
+	// SQLite allows a single writer; serializing avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, errors.Join(fmt.Errorf("creating cache schema: %w", err), db.Close())
+	}
+
+	s := &SQLiteCacheStore{db: db, path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// PullRequests returns the store for pull request data, for use with WithCacheStore.
+func (s *SQLiteCacheStore) PullRequests() PRStore {
+	return &sqliteTable[PullRequestData]{store: s, kind: cacheKindPullRequests}
+}
+
+// Close closes the database. Stores returned by the SQLiteCacheStore don't close it themselves.
+func (s *SQLiteCacheStore) Close() error {
+	return s.db.Close()
+}
+
+// WithSQLiteCache persists pull requests, collaborators, and rulesets in store.
+// The in-memory caches in front of it still prevent duplicate fetches within a process.
+func WithSQLiteCache(store *SQLiteCacheStore) Option {
+	return func(c *Client) {
+		WithCacheStore(store.PullRequests())(c)
+		c.collaboratorsStore = newSQLiteTiered[map[string]string](c.logger, store, cacheKindCollaborators, collaboratorsCacheTTL)
+		c.rulesetsStore = newSQLiteTiered[rulesetRequirements](c.logger, store, cacheKindRulesets, rulesetsCacheTTL)
+	}
+}
+
+// newSQLiteTiered creates a tiered cache over one namespace of store, or nil on failure.
+func newSQLiteTiered[V any](log *slog.Logger, store *SQLiteCacheStore, kind string, ttl time.Duration) *fido.TieredCache[string, V] {
+	cache, err := fido.NewTiered[string, V](&sqliteTable[V]{store: store, kind: kind}, fido.TTL(ttl))
+	if err != nil {
+		log.Warn("failed to create cache from SQLite store, keeping it in memory", "kind", kind, "error", err)
+		return nil
+	}
+	return cache
+}
+
+// readThrough wraps fetch with a lookup in the persistent store behind an in-memory cache, writing
+// fetched values back to it. Without a store, fetch is returned unchanged.
+func readThrough[V any](ctx context.Context, c *Client, store *fido.TieredCache[string, V], key string, fetch func() (V, error)) func() (V, error) {
+	if store == nil {
+		return fetch
+	}
+	return func() (V, error) {
+		if v, found, err := store.Get(ctx, key); err != nil {
+			c.logger.WarnContext(ctx, "persistent cache get error", "error", err)
+		} else if found {
+			return v, nil
+		}
+		v, err := fetch()
+		if err != nil {
+			return v, err
+		}
+		if err := store.Set(ctx, key, v); err != nil {
+			c.logger.WarnContext(ctx, "persistent cache set error", "error", err)
+		}
+		return v, nil
+	}
+}
+
+// sqliteTable implements fido.Store over one namespace of a SQLiteCacheStore, with JSON values.
+type sqliteTable[V any] struct {
+	store *SQLiteCacheStore
+	kind  string
+}
+
+func (t *sqliteTable[V]) ValidateKey(key string) error {
+	if key == "" {
+		return errors.New("empty cache key")
+	}
+	return nil
+}
+
+func (t *sqliteTable[V]) Get(ctx context.Context, key string) (V, time.Time, bool, error) {
+	var zero V
+	var data []byte
+	var expiry int64
+	err := t.store.db.QueryRowContext(ctx,
+		`SELECT value, expiry FROM prx_cache WHERE kind = ? AND key = ? AND (expiry = 0 OR expiry > ?)`,
+		t.kind, key, time.Now().UnixNano()).Scan(&data, &expiry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero, time.Time{}, false, nil
+	}
+	if err != nil {
+		return zero, time.Time{}, false, fmt.Errorf("loading %s: %w", t.kind, err)
+	}
+	var v V
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, time.Time{}, false, fmt.Errorf("decoding %s: %w", t.kind, err)
+	}
+	var exp time.Time
+	if expiry != 0 {
+		exp = time.Unix(0, expiry)
+	}
+	return v, exp, true, nil
+}
+
+func (t *sqliteTable[V]) Set(ctx context.Context, key string, value V, expiry time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", t.kind, err)
+	}
+	var exp int64
+	if !expiry.IsZero() {
+		exp = expiry.UnixNano()
+	}
+	if _, err := t.store.db.ExecContext(ctx,
+		`INSERT INTO prx_cache (kind, key, value, expiry, updated) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (kind, key) DO UPDATE SET value = excluded.value, expiry = excluded.expiry, updated = excluded.updated`,
+		t.kind, key, data, exp, time.Now().UnixNano()); err != nil {
+		return fmt.Errorf("storing %s: %w", t.kind, err)
+	}
+	if t.store.maxEntries == 0 {
+		return nil
+	}
+	if _, err := t.store.db.ExecContext(ctx,
+		`DELETE FROM prx_cache WHERE kind = ? AND key IN (
+			SELECT key FROM prx_cache WHERE kind = ? ORDER BY updated DESC LIMIT -1 OFFSET ?)`,
+		t.kind, t.kind, t.store.maxEntries); err != nil {
+		return fmt.Errorf("evicting %s: %w", t.kind, err)
+	}
+	return nil
+}
+
+func (t *sqliteTable[V]) Delete(ctx context.Context, key string) error {
+	if _, err := t.store.db.ExecContext(ctx, `DELETE FROM prx_cache WHERE kind = ? AND key = ?`, t.kind, key); err != nil {
+		return fmt.Errorf("deleting %s: %w", t.kind, err)
+	}
+	return nil
+}
+
+// Cleanup removes expired entries and entries not written within maxAge.
+func (t *sqliteTable[V]) Cleanup(ctx context.Context, maxAge time.Duration) (int, error) {
+	now := time.Now()
+	res, err := t.store.db.ExecContext(ctx,
+		`DELETE FROM prx_cache WHERE kind = ? AND ((expiry != 0 AND expiry <= ?) OR updated < ?)`,
+		t.kind, now.UnixNano(), now.Add(-maxAge).UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("cleaning up %s: %w", t.kind, err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (t *sqliteTable[V]) Location(key string) string {
+	return fmt.Sprintf("%s#%s/%s", t.store.path, t.kind, key)
+}
+
+func (t *sqliteTable[V]) Flush(ctx context.Context) (int, error) {
+	res, err := t.store.db.ExecContext(ctx, `DELETE FROM prx_cache WHERE kind = ?`, t.kind)
+	if err != nil {
+		return 0, fmt.Errorf("flushing %s: %w", t.kind, err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (t *sqliteTable[V]) Len(ctx context.Context) (int, error) {
+	var n int
+	if err := t.store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM prx_cache WHERE kind = ?`, t.kind).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting %s: %w", t.kind, err)
+	}
+	return n, nil
+}
+
+// Close is a no-op; the database is shared by all namespaces and closed by SQLiteCacheStore.Close.
+func (*sqliteTable[V]) Close() error {
+	return nil
+}
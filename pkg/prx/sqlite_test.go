@@ -0,0 +1,233 @@
+package prx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The tests run the store's SQL against fakeSQLite, an in-memory stand-in registered as SQLiteDriver
+// unless the test binary links a real driver. It understands exactly the statements the store issues,
+// failing on any other, so a changed statement needs the fake changed too.
+func init() {
+	if !slices.Contains(sql.Drivers(), SQLiteDriver) {
+		sql.Register(SQLiteDriver, &fakeSQLite{dbs: make(map[string]*fakeSQLiteDB)})
+	}
+}
+
+type fakeSQLite struct {
+	dbs map[string]*fakeSQLiteDB // By path, so reopened databases keep their rows
+	mu  sync.Mutex
+}
+
+type fakeSQLiteRow struct {
+	value   []byte
+	expiry  int64
+	updated int64
+}
+
+type fakeSQLiteDB struct {
+	rows map[[2]string]fakeSQLiteRow // By kind and key
+	mu   sync.Mutex
+}
+
+func (d *fakeSQLite) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &fakeSQLiteDB{rows: make(map[[2]string]fakeSQLiteRow)}
+		d.dbs[name] = db
+	}
+	return &fakeSQLiteConn{db: db}, nil
+}
+
+type fakeSQLiteConn struct {
+	db *fakeSQLiteDB
+}
+
+func (c *fakeSQLiteConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLiteStmt{db: c.db, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (*fakeSQLiteConn) Close() error { return nil }
+
+func (*fakeSQLiteConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+type fakeSQLiteStmt struct {
+	db    *fakeSQLiteDB
+	query string
+}
+
+func (*fakeSQLiteStmt) Close() error { return nil }
+
+func (*fakeSQLiteStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLiteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	rows := s.db.rows
+	deleted := 0
+	remove := func(keep func(k [2]string, r fakeSQLiteRow) bool) {
+		for k, r := range rows {
+			if !keep(k, r) {
+				delete(rows, k)
+				deleted++
+			}
+		}
+	}
+	switch q := s.query; {
+	case strings.HasPrefix(q, "CREATE TABLE IF NOT EXISTS prx_cache"):
+	case strings.HasPrefix(q, "INSERT INTO prx_cache (kind, key, value, expiry, updated) VALUES (?, ?, ?, ?, ?) ON CONFLICT (kind, key) DO UPDATE"):
+		rows[[2]string{args[0].(string), args[1].(string)}] = fakeSQLiteRow{value: args[2].([]byte), expiry: args[3].(int64), updated: args[4].(int64)}
+		deleted = 1
+	case q == "DELETE FROM prx_cache WHERE kind = ? AND key IN ( SELECT key FROM prx_cache WHERE kind = ? ORDER BY updated DESC LIMIT -1 OFFSET ?)":
+		var updated []int64
+		for k, r := range rows {
+			if k[0] == args[1] {
+				updated = append(updated, r.updated)
+			}
+		}
+		slices.Sort(updated)
+		slices.Reverse(updated)
+		if n := int(args[2].(int64)); n < len(updated) {
+			cutoff := updated[n-1]
+			remove(func(k [2]string, r fakeSQLiteRow) bool { return k[0] != args[0] || r.updated >= cutoff })
+		}
+	case q == "DELETE FROM prx_cache WHERE kind = ? AND key = ?":
+		remove(func(k [2]string, _ fakeSQLiteRow) bool { return k != [2]string{args[0].(string), args[1].(string)} })
+	case q == "DELETE FROM prx_cache WHERE kind = ? AND ((expiry != 0 AND expiry <= ?) OR updated < ?)":
+		remove(func(k [2]string, r fakeSQLiteRow) bool {
+			return k[0] != args[0] || !(r.expiry != 0 && r.expiry <= args[1].(int64) || r.updated < args[2].(int64))
+		})
+	case q == "DELETE FROM prx_cache WHERE kind = ?":
+		remove(func(k [2]string, _ fakeSQLiteRow) bool { return k[0] != args[0] })
+	default:
+		return nil, fmt.Errorf("fake SQLite can't execute %q", q)
+	}
+	return driver.RowsAffected(deleted), nil
+}
+
+func (s *fakeSQLiteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	switch s.query {
+	case "SELECT value, expiry FROM prx_cache WHERE kind = ? AND key = ? AND (expiry = 0 OR expiry > ?)":
+		r, ok := s.db.rows[[2]string{args[0].(string), args[1].(string)}]
+		if !ok || r.expiry != 0 && r.expiry <= args[2].(int64) {
+			return &fakeSQLiteRows{columns: []string{"value", "expiry"}}, nil
+		}
+		return &fakeSQLiteRows{columns: []string{"value", "expiry"}, values: [][]driver.Value{{r.value, r.expiry}}}, nil
+	case "SELECT COUNT(*) FROM prx_cache WHERE kind = ?":
+		n := 0
+		for k := range s.db.rows {
+			if k[0] == args[0] {
+				n++
+			}
+		}
+		return &fakeSQLiteRows{columns: []string{"count"}, values: [][]driver.Value{{int64(n)}}}, nil
+	default:
+		return nil, fmt.Errorf("fake SQLite can't query %q", s.query)
+	}
+}
+
+type fakeSQLiteRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeSQLiteRows) Columns() []string { return r.columns }
+
+func (*fakeSQLiteRows) Close() error { return nil }
+
+func (r *fakeSQLiteRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestNewSQLiteCacheStore_Errors(t *testing.T) {
+	if _, err := NewSQLiteCacheStore("relative/cache.db"); err == nil {
+		t.Error("expected error for relative path")
+	}
+}
+
+func TestSQLiteCacheStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.db")
+	store, err := NewSQLiteCacheStore(path, WithSQLiteMaxEntries(2))
+	if err != nil {
+		t.Fatalf("NewSQLiteCacheStore() error: %v", err)
+	}
+	prs := store.PullRequests()
+	for i, key := range []string{"a", "b", "c"} {
+		if err := prs.Set(ctx, key, PullRequestData{PullRequest: PullRequest{Number: i}}, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Set(%s) error: %v", key, err)
+		}
+	}
+	if n, err := prs.Len(ctx); err != nil || n != 2 {
+		t.Errorf("Len() = %d, %v; want 2 after eviction", n, err)
+	}
+	if err := prs.Set(ctx, "expired", PullRequestData{}, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Set(expired) error: %v", err)
+	}
+	if _, _, found, _ := prs.Get(ctx, "expired"); found {
+		t.Error("expected expired entry to be missing")
+	}
+	if n, err := prs.Cleanup(ctx, time.Hour); err != nil || n != 1 {
+		t.Errorf("Cleanup() = %d, %v; want the expired entry removed", n, err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	reopened, err := NewSQLiteCacheStore(path)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	defer reopened.Close()
+	prs = reopened.PullRequests()
+	got, _, found, err := prs.Get(ctx, "c")
+	if err != nil || !found || got.PullRequest.Number != 2 {
+		t.Errorf("Get(c) = %+v, %v, %v; want persisted PR 2", got.PullRequest, found, err)
+	}
+	if _, _, found, _ := prs.Get(ctx, "a"); found {
+		t.Error("expected oldest entry to be evicted")
+	}
+	if err := prs.Set(ctx, "d", PullRequestData{}, time.Time{}); err != nil {
+		t.Fatalf("Set(d) error: %v", err)
+	}
+	if err := prs.Delete(ctx, "d"); err != nil {
+		t.Fatalf("Delete(d) error: %v", err)
+	}
+	if _, _, found, _ := prs.Get(ctx, "d"); found {
+		t.Error("expected deleted entry to be missing")
+	}
+
+	// Namespaces are separate
+	client := NewClient("test-token", WithSQLiteCache(reopened))
+	if err := client.rulesetsStore.Set(ctx, "o/r", rulesetRequirements{Checks: []string{"build"}}); err != nil {
+		t.Fatalf("rulesets Set() error: %v", err)
+	}
+	if n, err := prs.Flush(ctx); err != nil || n != 1 {
+		t.Errorf("Flush() = %d, %v; want the one remaining pull request", n, err)
+	}
+	rulesets, found, err := client.rulesetsStore.Get(ctx, "o/r")
+	if err != nil || !found || !slices.Equal(rulesets.Checks, []string{"build"}) {
+		t.Errorf("rulesets Get() = %v, %v, %v; want them kept by the pull request flush", rulesets, found, err)
+	}
+}
@@ -0,0 +1,121 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Commit status states accepted by CreateStatus.
+const (
+	StatusStatePending = "pending"
+	StatusStateSuccess = "success"
+	StatusStateFailure = "failure"
+	StatusStateError   = "error"
+)
+
+// maxStatusDescriptionLength is the longest commit status description GitHub accepts.
+const maxStatusDescriptionLength = 140
+
+// CreateStatus reports a commit status on sha under the given context name, e.g. "prx/policy".
+// Descriptions longer than GitHub's 140 character limit are truncated; targetURL may be empty.
+// Archived repositories are refused with ErrRepoArchived.
+func (c *Client) CreateStatus(
+	ctx context.Context, owner, repo, sha, state, statusContext, description, targetURL string,
+) error {
+	if !slices.Contains([]string{StatusStatePending, StatusStateSuccess, StatusStateFailure, StatusStateError}, state) {
+		return fmt.Errorf("invalid commit status state %q", state)
+	}
+	if r := []rune(description); len(r) > maxStatusDescriptionLength {
+		description = string(r[:maxStatusDescriptionLength-1]) + "…"
+	}
+	if err := c.checkRepoWritable(ctx, owner, repo); err != nil {
+		return fmt.Errorf("creating status %q on %s/%s@%s: %w", statusContext, owner, repo, truncateSHA(sha), err)
+	}
+
+	body := map[string]string{"state": state, "context": statusContext, "description": description}
+	if targetURL != "" {
+		body["target_url"] = targetURL
+	}
+	path := fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, repo, sha)
+	if err := c.github.Post(ctx, path, body, nil); err != nil {
+		return fmt.Errorf("creating status %q on %s/%s@%s: %w", statusContext, owner, repo, truncateSHA(sha), err)
+	}
+	return nil
+}
+
+// CheckRunOptions describes a check run created with CreateCheckRun.
+// Conclusion is required when Status is "completed", and omitted otherwise.
+type CheckRunOptions struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Name        string
+	HeadSHA     string
+	Status      string // "queued", "in_progress", or "completed"; defaults to "queued"
+	Conclusion  string // e.g. "success", "failure", "neutral", or "action_required"
+	DetailsURL  string
+	ExternalID  string
+	Title       string // Output title, shown with Summary and Text on the check's page
+	Summary     string
+	Text        string
+}
+
+// CreateCheckRun creates a check run and returns its ID. GitHub only allows GitHub Apps to create
+// check runs, so the client must authenticate as one (see WithAppAuth); use CreateStatus otherwise.
+// Archived repositories are refused with ErrRepoArchived.
+func (c *Client) CreateCheckRun(ctx context.Context, owner, repo string, opts CheckRunOptions) (int64, error) {
+	if opts.Name == "" || opts.HeadSHA == "" {
+		return 0, errors.New("creating check run: name and head SHA are required")
+	}
+	if err := c.checkRepoWritable(ctx, owner, repo); err != nil {
+		return 0, fmt.Errorf("creating check run %q on %s/%s@%s: %w", opts.Name, owner, repo, truncateSHA(opts.HeadSHA), err)
+	}
+
+	body := map[string]any{"name": opts.Name, "head_sha": opts.HeadSHA}
+	for key, value := range map[string]string{
+		"status":      opts.Status,
+		"conclusion":  opts.Conclusion,
+		"details_url": opts.DetailsURL,
+		"external_id": opts.ExternalID,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	if !opts.StartedAt.IsZero() {
+		body["started_at"] = opts.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if !opts.CompletedAt.IsZero() {
+		body["completed_at"] = opts.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	if opts.Title != "" || opts.Summary != "" {
+		output := map[string]string{"title": opts.Title, "summary": opts.Summary}
+		if opts.Text != "" {
+			output["text"] = opts.Text
+		}
+		body["output"] = output
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/check-runs", owner, repo)
+	if err := c.github.Post(ctx, path, body, &created); err != nil {
+		return 0, fmt.Errorf("creating check run %q on %s/%s@%s: %w", opts.Name, owner, repo, truncateSHA(opts.HeadSHA), err)
+	}
+	return created.ID, nil
+}
+
+// checkRepoWritable returns ErrRepoArchived if the repository is archived, and so read-only.
+func (c *Client) checkRepoWritable(ctx context.Context, owner, repo string) error {
+	repository, err := c.fetchRepository(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if repository.Archived {
+		return ErrRepoArchived
+	}
+	return nil
+}
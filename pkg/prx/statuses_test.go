@@ -0,0 +1,93 @@
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestClient_CreateStatusAndCheckRun(t *testing.T) {
+	bodies := make(map[string]map[string]any)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/") {
+			archived := r.URL.Path == "/repos/owner/archived"
+			if err := json.NewEncoder(w).Encode(map[string]any{"default_branch": "main", "archived": archived}); err != nil {
+				t.Errorf("writing response: %v", err)
+			}
+			return
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		bodies[r.URL.Path] = body
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write([]byte(`{"id": 42}`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	err := client.CreateStatus(ctx, "owner", "repo", "abc123", StatusStateFailure, "prx/policy", strings.Repeat("x", 200), "")
+	if err != nil {
+		t.Fatalf("CreateStatus() error: %v", err)
+	}
+	status := bodies["/repos/owner/repo/statuses/abc123"]
+	if status["state"] != "failure" || status["context"] != "prx/policy" {
+		t.Errorf("status request = %v", status)
+	}
+	if d, _ := status["description"].(string); len([]rune(d)) != maxStatusDescriptionLength {
+		t.Errorf("expected description truncated to %d characters, got %d", maxStatusDescriptionLength, len([]rune(d)))
+	}
+	if _, ok := status["target_url"]; ok {
+		t.Error("expected empty target URL to be omitted")
+	}
+	if err := client.CreateStatus(ctx, "owner", "repo", "abc123", "passed", "prx/policy", "", ""); err == nil {
+		t.Error("expected invalid state to be rejected")
+	}
+
+	id, err := client.CreateCheckRun(ctx, "owner", "repo", CheckRunOptions{
+		Name:        "prx analysis",
+		HeadSHA:     "abc123",
+		Status:      "completed",
+		Conclusion:  "neutral",
+		CompletedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Title:       "2 findings",
+		Summary:     "See annotations",
+	})
+	if err != nil || id != 42 {
+		t.Fatalf("CreateCheckRun() = %d, %v; want 42", id, err)
+	}
+	run := bodies["/repos/owner/repo/check-runs"]
+	if run["head_sha"] != "abc123" || run["conclusion"] != "neutral" || run["completed_at"] != "2025-01-01T12:00:00Z" {
+		t.Errorf("check run request = %v", run)
+	}
+	if output, _ := run["output"].(map[string]any); output["title"] != "2 findings" {
+		t.Errorf("check run output = %v", run["output"])
+	}
+
+	err = client.CreateStatus(ctx, "owner", "archived", "abc123", StatusStateSuccess, "prx/policy", "", "")
+	if !errors.Is(err, ErrRepoArchived) {
+		t.Errorf("CreateStatus() on an archived repository error = %v, want ErrRepoArchived", err)
+	}
+	if _, err := client.CreateCheckRun(ctx, "owner", "archived", CheckRunOptions{Name: "prx", HeadSHA: "abc123"}); !errors.Is(err, ErrRepoArchived) {
+		t.Errorf("CreateCheckRun() on an archived repository error = %v, want ErrRepoArchived", err)
+	}
+	if len(bodies) != 2 {
+		t.Errorf("requests = %v; want nothing posted to the archived repository", bodies)
+	}
+}
@@ -0,0 +1,202 @@
+package prx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// templatesCacheTTL is how long a repository's pull request templates are reused.
+const templatesCacheTTL = 3 * time.Hour
+
+// pullRequestTemplateDirs are the directories GitHub reads pull request templates from; "" is the root.
+var pullRequestTemplateDirs = []string{".github", "", "docs"}
+
+var (
+	// htmlCommentPattern matches HTML comments, which templates use for instructions.
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// markdownHeadingPattern matches ATX headings and lines that are entirely bold, both used as template sections.
+	markdownHeadingPattern = regexp.MustCompile(`^(?:#{1,6}\s+(.+?)\s*#*|\*\*([^*]+)\*\*:?)\s*$`)
+)
+
+// PullRequestTemplate is a pull request template from a repository.
+type PullRequestTemplate struct {
+	Path     string   `json:"path"`
+	Body     string   `json:"body"`
+	Sections []string `json:"sections,omitempty"` // Headings, in order
+}
+
+// DescriptionCheck compares a pull request description with the repository's templates.
+type DescriptionCheck struct {
+	Template string   `json:"template"`          // Path of the template the description follows most closely
+	Missing  []string `json:"missing,omitempty"` // Template sections absent from the description
+	Empty    []string `json:"empty,omitempty"`   // Sections present but not filled in beyond the template's text
+}
+
+// Complete reports whether every section of the template was filled in.
+func (d *DescriptionCheck) Complete() bool {
+	return len(d.Missing) == 0 && len(d.Empty) == 0
+}
+
+// WithDescriptionTemplates populates PullRequest.DescriptionCheck by comparing the full pull request
+// description with the repository's pull request templates. Templates are fetched once per repository
+// and cached, which costs a few REST requests per repository.
+func WithDescriptionTemplates() Option {
+	return func(c *Client) {
+		c.descriptionTemplates = true
+	}
+}
+
+// PullRequestTemplates fetches the repository's pull request templates from its default branch:
+// pull_request_template.md in the root, .github, or docs directory, and the files of a
+// PULL_REQUEST_TEMPLATE directory in any of them. Results are cached per repository.
+func (c *Client) PullRequestTemplates(ctx context.Context, owner, repo string) ([]PullRequestTemplate, error) {
+	return c.templatesCache.Fetch(repositoryCacheKey(owner, repo), func() ([]PullRequestTemplate, error) {
+		var templates []PullRequestTemplate
+		for _, dir := range pullRequestTemplateDirs {
+			entries, err := c.templateDirectory(ctx, owner, repo, dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				name := strings.ToLower(e.Name)
+				switch {
+				case e.Type == "file" && (name == "pull_request_template.md" || name == "pull_request_template"):
+					t, err := c.fetchTemplate(ctx, owner, repo, e.Path)
+					if err != nil {
+						return nil, err
+					}
+					templates = append(templates, t)
+				case e.Type == "dir" && name == "pull_request_template":
+					files, err := c.templateDirectory(ctx, owner, repo, e.Path)
+					if err != nil {
+						return nil, err
+					}
+					for _, f := range files {
+						if f.Type != "file" || !strings.HasSuffix(strings.ToLower(f.Name), ".md") {
+							continue
+						}
+						t, err := c.fetchTemplate(ctx, owner, repo, f.Path)
+						if err != nil {
+							return nil, err
+						}
+						templates = append(templates, t)
+					}
+				default:
+					// Not a template
+				}
+			}
+		}
+		return templates, nil
+	})
+}
+
+// templateDirectory lists dir, treating a missing directory as empty.
+func (c *Client) templateDirectory(ctx context.Context, owner, repo, dir string) ([]*github.ContentEntry, error) {
+	entries, err := c.github.Directory(ctx, owner, repo, dir)
+	var apiErr *github.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing %q: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// fetchTemplate fetches and parses the template at path.
+func (c *Client) fetchTemplate(ctx context.Context, owner, repo, path string) (PullRequestTemplate, error) {
+	data, err := c.github.FileContents(ctx, owner, repo, path)
+	if err != nil {
+		return PullRequestTemplate{}, fmt.Errorf("fetching %s: %w", path, err)
+	}
+	t := PullRequestTemplate{Path: path, Body: string(data)}
+	for _, s := range markdownSections(t.Body) {
+		t.Sections = append(t.Sections, s.heading)
+	}
+	return t, nil
+}
+
+// CheckDescription compares a pull request description with templates, using the template whose
+// sections the description contains the most of. It returns nil when there are no templates with sections.
+func CheckDescription(body string, templates []PullRequestTemplate) *DescriptionCheck {
+	described := make(map[string]string)
+	for _, s := range markdownSections(body) {
+		described[normalizeHeading(s.heading)] = s.content
+	}
+
+	var best *DescriptionCheck
+	bestPresent := -1
+	for i := range templates {
+		sections := markdownSections(templates[i].Body)
+		if len(sections) == 0 {
+			continue
+		}
+		check := &DescriptionCheck{Template: templates[i].Path}
+		present := 0
+		for _, s := range sections {
+			content, ok := described[normalizeHeading(s.heading)]
+			switch {
+			case !ok:
+				check.Missing = append(check.Missing, s.heading)
+			case content == "" || content == s.content:
+				present++
+				check.Empty = append(check.Empty, s.heading)
+			default:
+				present++
+			}
+		}
+		if present > bestPresent {
+			best, bestPresent = check, present
+		}
+	}
+	return best
+}
+
+// markdownSection is a heading and the text up to the next heading, without HTML comments
+// and with whitespace normalized.
+type markdownSection struct {
+	heading string
+	content string
+}
+
+// markdownSections splits markdown into sections by heading. Text before the first heading is ignored.
+func markdownSections(markdown string) []markdownSection {
+	var sections []markdownSection
+	var content []string
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].content = strings.Join(strings.Fields(strings.Join(content, "\n")), " ")
+		}
+		content = nil
+	}
+	inFence := false
+	for line := range strings.SplitSeq(htmlCommentPattern.ReplaceAllString(markdown, ""), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(trimmed); m != nil && !inFence {
+			flush()
+			sections = append(sections, markdownSection{heading: strings.TrimSpace(m[1] + m[2])})
+			continue
+		}
+		content = append(content, line)
+	}
+	flush()
+	return sections
+}
+
+// normalizeHeading returns a heading in a form that tolerates differences in case, spacing, and punctuation.
+func normalizeHeading(heading string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(heading), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
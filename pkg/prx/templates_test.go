@@ -0,0 +1,134 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+const testPullRequestTemplate = `<!-- Describe your change below -->
+## Summary
+
+## Testing
+Describe how you tested this.
+
+## Checklist
+- [ ] Tests added
+`
+
+func TestCheckDescription(t *testing.T) {
+	templates := []PullRequestTemplate{
+		{Path: ".github/pull_request_template.md", Body: testPullRequestTemplate},
+		{Path: ".github/PULL_REQUEST_TEMPLATE/release.md", Body: "## Release notes\n\n## Rollback plan\n"},
+		{Path: "docs/pull_request_template.md", Body: "Please describe your change."},
+	}
+	tests := []struct {
+		name        string
+		body        string
+		wantMissing []string
+		wantEmpty   []string
+	}{
+		{
+			name:      "filled in",
+			body:      "## Summary\nFixes the parser.\n\n## testing:\nRan the unit tests.\n\n**Checklist**\n- [x] Tests added\n",
+			wantEmpty: nil,
+		},
+		{
+			name:        "template text left in place",
+			body:        "<!-- Describe your change below -->\n## Summary\n<!-- TODO -->\n## Testing\nDescribe how you tested this.\n",
+			wantMissing: []string{"Checklist"},
+			wantEmpty:   []string{"Summary", "Testing"},
+		},
+		{
+			name:        "headings inside code blocks are ignored",
+			body:        "## Summary\nAdds docs.\n```\n## Testing\n```\n",
+			wantMissing: []string{"Testing", "Checklist"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CheckDescription(tt.body, templates)
+			if check == nil {
+				t.Fatal("CheckDescription() = nil")
+			}
+			if check.Template != ".github/pull_request_template.md" {
+				t.Errorf("Template = %q, want the default template", check.Template)
+			}
+			if !slices.Equal(check.Missing, tt.wantMissing) || !slices.Equal(check.Empty, tt.wantEmpty) {
+				t.Errorf("Missing = %v, Empty = %v; want %v, %v", check.Missing, check.Empty, tt.wantMissing, tt.wantEmpty)
+			}
+			if check.Complete() != (len(tt.wantMissing) == 0 && len(tt.wantEmpty) == 0) {
+				t.Errorf("Complete() = %v", check.Complete())
+			}
+		})
+	}
+
+	check := CheckDescription("## Release notes\nNew flag.\n", templates)
+	if check == nil || check.Template != ".github/PULL_REQUEST_TEMPLATE/release.md" ||
+		!slices.Equal(check.Missing, []string{"Rollback plan"}) {
+		t.Errorf("CheckDescription() = %+v, want the release template with Rollback plan missing", check)
+	}
+	if check := CheckDescription("## Summary\n", templates[2:]); check != nil {
+		t.Errorf("CheckDescription() = %+v, want nil for templates without sections", check)
+	}
+}
+
+func TestClient_PullRequestTemplates(t *testing.T) {
+	requests := 0
+	content := func(body string) []byte {
+		return []byte(`{"encoding": "base64", "content": "` + base64.StdEncoding.EncodeToString([]byte(body)) + `"}`)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/.github":
+			w.Write([]byte(`[
+				{"name": "CODEOWNERS", "path": ".github/CODEOWNERS", "type": "file"},
+				{"name": "PULL_REQUEST_TEMPLATE.md", "path": ".github/PULL_REQUEST_TEMPLATE.md", "type": "file"},
+				{"name": "PULL_REQUEST_TEMPLATE", "path": ".github/PULL_REQUEST_TEMPLATE", "type": "dir"}
+			]`))
+		case "/repos/owner/repo/contents/.github/PULL_REQUEST_TEMPLATE":
+			w.Write([]byte(`[
+				{"name": "release.md", "path": ".github/PULL_REQUEST_TEMPLATE/release.md", "type": "file"},
+				{"name": "notes.txt", "path": ".github/PULL_REQUEST_TEMPLATE/notes.txt", "type": "file"}
+			]`))
+		case "/repos/owner/repo/contents/.github/PULL_REQUEST_TEMPLATE.md":
+			w.Write(content(testPullRequestTemplate))
+		case "/repos/owner/repo/contents/.github/PULL_REQUEST_TEMPLATE/release.md":
+			w.Write(content("## Release notes\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+
+	for range 2 {
+		templates, err := client.PullRequestTemplates(context.Background(), "owner", "repo")
+		if err != nil {
+			t.Fatalf("PullRequestTemplates() error: %v", err)
+		}
+		if len(templates) != 2 {
+			t.Fatalf("expected 2 templates, got %+v", templates)
+		}
+		if !slices.Equal(templates[0].Sections, []string{"Summary", "Testing", "Checklist"}) {
+			t.Errorf("Sections = %v", templates[0].Sections)
+		}
+		if templates[1].Path != ".github/PULL_REQUEST_TEMPLATE/release.md" {
+			t.Errorf("templates[1].Path = %q", templates[1].Path)
+		}
+	}
+	// Three directory listings, one subdirectory listing, and two templates, fetched once
+	if requests != 6 {
+		t.Errorf("expected 6 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,213 @@
+package prx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// ticketsCacheTTL is how long resolved tickets are reused across pull requests.
+	ticketsCacheTTL = time.Hour
+	// maxTicketResponseSize limits issue tracker response sizes.
+	maxTicketResponseSize = 1 << 20
+	// linearAPI is the Linear GraphQL endpoint.
+	linearAPI = "https://api.linear.app/graphql"
+)
+
+// Ticket is an issue tracker ticket referenced by a pull request.
+type Ticket struct {
+	Key    string `json:"key"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// TicketResolver looks up issue tracker tickets by key, such as "PROJ-123".
+// ResolveTicket returns nil and no error when no ticket exists for the key.
+type TicketResolver interface {
+	ResolveTicket(ctx context.Context, key string) (*Ticket, error)
+}
+
+// WithTicketResolver resolves the pull request's TicketRefs with r, attaching the
+// results to PullRequest.Tickets. Resolution failures are logged and skipped. Pull requests are
+// cached apart by resolver type, and for JiraResolver and LinearResolver by site.
+func WithTicketResolver(r TicketResolver) Option {
+	return func(c *Client) {
+		c.ticketResolver = r
+	}
+}
+
+// ticketResolverName identifies a resolver for cache keys.
+func ticketResolverName(r TicketResolver) string {
+	switch r := r.(type) {
+	case *JiraResolver:
+		return "jira " + r.BaseURL
+	case *LinearResolver:
+		return "linear " + r.APIURL
+	default:
+		return fmt.Sprintf("%T", r)
+	}
+}
+
+// resolveTickets resolves ticket keys, caching results across pull requests.
+// Keys that fail to resolve are added to report.
+func (c *Client) resolveTickets(ctx context.Context, keys []string, report *FetchReport) []Ticket {
+	var tickets []Ticket
+	for _, key := range keys {
+		ticket, err := c.ticketsCache.Fetch(key, func() (*Ticket, error) {
+			return c.ticketResolver.ResolveTicket(ctx, key)
+		})
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to resolve ticket", "key", key, "error", err)
+			report.add(FetchSectionTickets, key, err)
+			continue
+		}
+		if ticket != nil {
+			tickets = append(tickets, *ticket)
+		}
+	}
+	return tickets
+}
+
+// JiraResolver resolves tickets against the Jira REST API.
+type JiraResolver struct {
+	HTTPClient *http.Client
+	BaseURL    string // e.g. "https://example.atlassian.net"
+	Email      string
+	APIToken   string
+}
+
+// NewJiraResolver creates a resolver for the Jira site at baseURL, authenticating
+// with the given account email and API token.
+func NewJiraResolver(baseURL, email, apiToken string) *JiraResolver {
+	return &JiraResolver{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Email:      email,
+		APIToken:   apiToken,
+	}
+}
+
+// ResolveTicket fetches the summary and status of a Jira issue.
+func (r *JiraResolver) ResolveTicket(ctx context.Context, key string) (*Ticket, error) {
+	apiURL := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", r.BaseURL, url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(r.Email, r.APIToken)
+	req.Header.Set("Accept", "application/json")
+
+	var issue struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+			Summary string `json:"summary"`
+		} `json:"fields"`
+		Key string `json:"key"`
+	}
+	found, err := doTicketRequest(r.HTTPClient, req, &issue)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &Ticket{
+		Key:    issue.Key,
+		Title:  issue.Fields.Summary,
+		Status: issue.Fields.Status.Name,
+		URL:    fmt.Sprintf("%s/browse/%s", r.BaseURL, issue.Key),
+	}, nil
+}
+
+// LinearResolver resolves tickets against the Linear GraphQL API.
+type LinearResolver struct {
+	HTTPClient *http.Client
+	APIURL     string
+	APIKey     string
+}
+
+// NewLinearResolver creates a resolver authenticating with a Linear API key.
+func NewLinearResolver(apiKey string) *LinearResolver {
+	return &LinearResolver{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		APIURL:     linearAPI,
+		APIKey:     apiKey,
+	}
+}
+
+// ResolveTicket fetches the title and workflow state of a Linear issue by identifier.
+func (r *LinearResolver) ResolveTicket(ctx context.Context, key string) (*Ticket, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     `query($id: String!) { issue(id: $id) { identifier title url state { name } } }`,
+		"variables": map[string]any{"id": key},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", r.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Data struct {
+			Issue *struct {
+				State struct {
+					Name string `json:"name"`
+				} `json:"state"`
+				Identifier string `json:"identifier"`
+				Title      string `json:"title"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"data"`
+	}
+	// Linear reports unknown identifiers as a GraphQL error with a null issue
+	if _, err := doTicketRequest(r.HTTPClient, req, &result); err != nil {
+		return nil, err
+	}
+	issue := result.Data.Issue
+	if issue == nil {
+		return nil, nil //nolint:nilnil // no ticket exists for key
+	}
+	return &Ticket{
+		Key:    issue.Identifier,
+		Title:  issue.Title,
+		Status: issue.State.Name,
+		URL:    issue.URL,
+	}, nil
+}
+
+// doTicketRequest performs an issue tracker API request, decoding the JSON response into out.
+// It reports false without error when the tracker answers 404.
+func doTicketRequest(httpClient *http.Client, req *http.Request, out any) (bool, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // best-effort close
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%s %s: %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTicketResponseSize)).Decode(out); err != nil {
+		return false, fmt.Errorf("decoding ticket response: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestJiraResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"key": "PROJ-1", "fields": {"summary": "Add login", "status": {"name": "In Progress"}}}`))
+	}))
+	defer server.Close()
+
+	r := NewJiraResolver(server.URL+"/", "me@example.com", "secret")
+	ticket, err := r.ResolveTicket(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("ResolveTicket() error: %v", err)
+	}
+	want := Ticket{Key: "PROJ-1", Title: "Add login", Status: "In Progress", URL: server.URL + "/browse/PROJ-1"}
+	if ticket == nil || *ticket != want {
+		t.Errorf("ResolveTicket() = %+v, want %+v", ticket, want)
+	}
+
+	ticket, err = r.ResolveTicket(context.Background(), "NOPE-1")
+	if err != nil || ticket != nil {
+		t.Errorf("ResolveTicket() for unknown key = %+v, %v; want nil, nil", ticket, err)
+	}
+}
+
+func TestLinearResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if req.Variables["id"] != "ENG-42" {
+			w.Write([]byte(`{"data": {"issue": null}, "errors": [{"message": "Entity not found"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"issue": {"identifier": "ENG-42", "title": "Fix cache", "url": "https://linear.app/x/issue/ENG-42", "state": {"name": "Done"}}}}`))
+	}))
+	defer server.Close()
+
+	r := NewLinearResolver("lin_api_key")
+	r.APIURL = server.URL
+	ticket, err := r.ResolveTicket(context.Background(), "ENG-42")
+	if err != nil {
+		t.Fatalf("ResolveTicket() error: %v", err)
+	}
+	want := Ticket{Key: "ENG-42", Title: "Fix cache", Status: "Done", URL: "https://linear.app/x/issue/ENG-42"}
+	if ticket == nil || *ticket != want {
+		t.Errorf("ResolveTicket() = %+v, want %+v", ticket, want)
+	}
+
+	ticket, err = r.ResolveTicket(context.Background(), "ENG-1")
+	if err != nil || ticket != nil {
+		t.Errorf("ResolveTicket() for unknown key = %+v, %v; want nil, nil", ticket, err)
+	}
+}
+
+type fakeTicketResolver map[string]*Ticket
+
+func (f fakeTicketResolver) ResolveTicket(_ context.Context, key string) (*Ticket, error) {
+	if key == "FAIL-1" {
+		return nil, errors.New("tracker unavailable")
+	}
+	return f[key], nil
+}
+
+func TestClient_ResolveTickets(t *testing.T) {
+	client := NewClient("test-token",
+		WithCacheStore(null.New[string, PullRequestData]()),
+		WithTicketResolver(fakeTicketResolver{"PROJ-1": {Key: "PROJ-1", Status: "Done"}}),
+	)
+
+	var report FetchReport
+	got := client.resolveTickets(context.Background(), []string{"PROJ-1", "FAIL-1", "MISSING-1"}, &report)
+	if len(got) != 1 || got[0].Key != "PROJ-1" || got[0].Status != "Done" {
+		t.Errorf("resolveTickets() = %+v, want only PROJ-1", got)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Section != FetchSectionTickets || report.Warnings[0].Target != "FAIL-1" {
+		t.Errorf("report = %+v, want a warning for FAIL-1 only", report)
+	}
+}
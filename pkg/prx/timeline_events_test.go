@@ -100,7 +100,7 @@ func TestParseGraphQLTimelineEventAutoMerge(t *testing.T) {
 	tests := []struct {
 		name     string
 		item     map[string]any
-		expected string
+		expected EventKind
 	}{
 		{
 			name: "AutoMergeEnabledEvent",
@@ -150,7 +150,7 @@ func TestParseGraphQLTimelineEventNewTypes(t *testing.T) {
 
 	tests := []struct {
 		typename string
-		expected string
+		expected EventKind
 	}{
 		{"ReviewDismissedEvent", "review_dismissed"},
 		{"BaseRefChangedEvent", "base_ref_changed"},
@@ -0,0 +1,96 @@
+package prx
+
+import (
+	"context"
+
+	"github.com/codeGROOVE-dev/prx/pkg/prx/github"
+)
+
+// tracerName identifies prx spans, following the OpenTelemetry convention of the instrumenting package path.
+const tracerName = "github.com/codeGROOVE-dev/prx"
+
+// SpanAttribute annotates a span, e.g. with the repository or HTTP status of an operation.
+type SpanAttribute struct {
+	Value any
+	Key   string
+}
+
+// Span is a traced operation. Its methods mirror OpenTelemetry's trace.Span.
+type Span interface {
+	SetAttributes(attrs ...SpanAttribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans. Its method mirrors OpenTelemetry's trace.Tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracerProvider provides Tracers. prx doesn't link OpenTelemetry itself; an adapter
+// around an OpenTelemetry trace.TracerProvider takes a few lines, see the README.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// WithTracerProvider traces pull request fetches, GraphQL queries, REST requests and loops,
+// and cache lookups with spans from tp, so the latency of a fetch can be attributed to
+// concrete GitHub endpoints.
+func WithTracerProvider(tp TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// startSpan starts a span named name, or a no-op span without a tracer.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := c.tracer.Start(ctx, name)
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	return ctx, span
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// repoAttributes returns the span attributes identifying a pull request.
+func repoAttributes(owner, repo string, prNumber int) []SpanAttribute {
+	return []SpanAttribute{{Key: "github.owner", Value: owner}, {Key: "github.repo", Value: repo}, {Key: "github.pr", Value: prNumber}}
+}
+
+// configureTracing traces the GitHub client's requests.
+func (c *Client) configureTracing() {
+	if c.tracer == nil {
+		return
+	}
+	c.github.TraceRequest = func(ctx context.Context, resource, method, url string) (context.Context, func(int, error)) {
+		name := "github.rest"
+		if resource == github.ResourceGraphQL {
+			name = "github.graphql"
+		}
+		ctx, span := c.startSpan(ctx, name,
+			SpanAttribute{Key: "http.request.method", Value: method}, SpanAttribute{Key: "url.full", Value: url})
+		return ctx, func(status int, err error) {
+			if status != 0 {
+				span.SetAttributes(SpanAttribute{Key: "http.response.status_code", Value: status})
+			}
+			endSpan(span, err)
+		}
+	}
+}
+
+// noopSpan is the Span used when tracing is disabled.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...SpanAttribute) {}
+func (noopSpan) RecordError(error)              {}
+func (noopSpan) End()                           {}
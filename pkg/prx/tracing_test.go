@@ -0,0 +1,88 @@
+//nolint:errcheck // Test handlers don't need to check w.Write errors
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+// recordingTracer is a TracerProvider and Tracer that records ended spans.
+type recordingTracer struct {
+	ended []*recordingSpan
+	mu    sync.Mutex
+}
+
+func (t *recordingTracer) Tracer(string) Tracer { return t }
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, &recordingSpan{tracer: t, name: name, attrs: make(map[string]any)}
+}
+
+type recordingSpan struct {
+	err    error
+	tracer *recordingTracer
+	attrs  map[string]any
+	name   string
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...SpanAttribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) { s.err = err }
+
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.ended = append(s.tracer.ended, s)
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {
+				"number": 1, "state": "OPEN", "createdAt": "2025-01-01T00:00:00Z", "author": {"login": "author"},
+				"headRef": {"name": "feature", "target": {"oid": "abc123"}}}},
+				"rateLimit": {"cost": 1, "remaining": 4000, "limit": 5000, "resetAt": "2025-01-01T01:00:00Z"}}}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()), WithTracerProvider(tracer))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	client.configureTracing()
+
+	if _, err := client.PullRequest(context.Background(), "owner", "repo", 1); err != nil {
+		t.Fatalf("PullRequest() error: %v", err)
+	}
+
+	var names []string
+	for _, s := range tracer.ended {
+		names = append(names, s.name)
+	}
+	for _, want := range []string{"prx.PullRequest", "prx.cache.get", "prx.graphql", "github.graphql", "github.rest", "prx.checkRuns"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("spans = %v, missing %s", names, want)
+		}
+	}
+	last := tracer.ended[len(tracer.ended)-1]
+	if last.name != "prx.PullRequest" || last.attrs["github.repo"] != "repo" {
+		t.Errorf("last span = %s %v, want prx.PullRequest for repo", last.name, last.attrs)
+	}
+	for _, s := range tracer.ended {
+		if s.name == "github.graphql" && s.attrs["http.response.status_code"] != http.StatusOK {
+			t.Errorf("github.graphql status = %v, want 200", s.attrs["http.response.status_code"])
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 const (
@@ -104,20 +105,36 @@ var (
 func initQuestionRegexes() {
 	questionRegexCache = make(map[string]*regexp.Regexp, len(questionPatterns))
 	for _, p := range questionPatterns {
-		// Split pattern into words and add word boundaries
-		w := strings.Fields(p)
-		if len(w) == 0 {
-			continue // Skip empty patterns (defensive programming)
+		if re := questionPhraseRegexp(p); re != nil {
+			questionRegexCache[p] = re
 		}
-		for i, word := range w {
-			w[i] = regexp.QuoteMeta(word)
-		}
-		// Add word boundaries: \b at start of first word, \b at end of last word
+	}
+}
+
+// questionPhraseRegexp compiles a question phrase into a case-insensitive regex that allows any
+// whitespace between words. Word boundaries are added at ASCII letters and digits only, as \b doesn't
+// recognize accented or CJK characters. It returns nil for an empty phrase.
+func questionPhraseRegexp(phrase string) *regexp.Regexp {
+	w := strings.Fields(phrase)
+	if len(w) == 0 {
+		return nil
+	}
+	for i, word := range w {
+		w[i] = regexp.QuoteMeta(word)
+	}
+	first, last := w[0], w[len(w)-1]
+	if isASCIIWordByte(first[0]) {
 		w[0] = "\\b" + w[0]
-		w[len(w)-1] = w[len(w)-1] + "\\b"
-		// Join with flexible whitespace and compile as case-insensitive
-		questionRegexCache[p] = regexp.MustCompile("(?i)" + strings.Join(w, "\\s+"))
 	}
+	if isASCIIWordByte(last[len(last)-1]) {
+		w[len(w)-1] += "\\b"
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(w, "\\s+"))
+}
+
+// isASCIIWordByte reports whether b is matched by \w.
+func isASCIIWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
 }
 
 // containsQuestion determines if text contains a question based on:
@@ -147,6 +164,42 @@ func containsQuestion(text string) bool {
 	return false
 }
 
+// suggestionPattern matches the opening fence of a suggested change block.
+var suggestionPattern = regexp.MustCompile("(?m)^\\s*```+\\s*suggestion\\b")
+
+// containsSuggestion reports whether a review comment body contains a suggested change.
+func containsSuggestion(text string) bool {
+	return suggestionPattern.MatchString(text)
+}
+
+var (
+	// codePattern matches fenced code blocks and inline code spans, where mentions don't notify.
+	codePattern = regexp.MustCompile("(?s)```.*?(?:```|$)|`[^`\n]*`")
+	// mentionPattern matches @user and @org/team mentions. The leading character class excludes
+	// email addresses and other text directly preceding the @.
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@./-])@([a-zA-Z0-9](?:[a-zA-Z0-9-]{0,37}[a-zA-Z0-9])?(?:/[a-zA-Z0-9][\w-]*)?)`)
+)
+
+// extractMentions returns the users and teams @mentioned in text, outside of code,
+// in order of first appearance and without duplicates.
+func extractMentions(text string) []string {
+	if !strings.Contains(text, "@") {
+		return nil
+	}
+	text = codePattern.ReplaceAllString(text, " ")
+
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		key := strings.ToLower(m[1])
+		if !seen[key] {
+			seen[key] = true
+			mentions = append(mentions, m[1])
+		}
+	}
+	return mentions
+}
+
 func isHexString(s string) bool {
 	for i := range s {
 		c := s[i]
@@ -158,9 +211,21 @@ func isHexString(s string) bool {
 	return true
 }
 
-func truncate(s string) string {
-	if len(s) <= maxTruncateLength {
+// truncate shortens s to the client's maximum body length.
+func (c *Client) truncate(s string) string {
+	if c.maxBodyLength <= 0 {
+		return s
+	}
+	return truncateString(s, c.maxBodyLength)
+}
+
+// truncateString shortens s to at most n bytes, without splitting a UTF-8 sequence.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
 		return s
 	}
-	return s[:maxTruncateLength]
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
@@ -3,6 +3,7 @@ package prx
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCalculateCheckSummaryWithMaps(t *testing.T) {
@@ -402,7 +403,7 @@ func TestCalculateApprovalSummaryWriteAccessCategories(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			summary := calculateApprovalSummary(tt.events)
+			summary := calculateApprovalSummary(tt.events, "")
 
 			if summary.ApprovalsWithWriteAccess != tt.expectedWithAccess {
 				t.Errorf("ApprovalsWithWriteAccess: got %d, want %d",
@@ -424,6 +425,30 @@ func TestCalculateApprovalSummaryWriteAccessCategories(t *testing.T) {
 	}
 }
 
+func TestCalculateApprovalSummaryStaleApprovals(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Kind: EventKindReview, Timestamp: base, Actor: "alice", Outcome: "approved", CommitSHA: "old"},
+		{Kind: EventKindReview, Timestamp: base, Actor: "bob", Outcome: "approved"},
+		{Kind: EventKindCommit, Timestamp: base.Add(time.Hour)},
+		{Kind: EventKindReview, Timestamp: base.Add(2 * time.Hour), Actor: "carol", Outcome: "approved", CommitSHA: "head"},
+		{Kind: EventKindReview, Timestamp: base.Add(2 * time.Hour), Actor: "dave", Outcome: "approved"},
+	}
+
+	summary := calculateApprovalSummary(events, "head")
+	if summary.StaleApprovals != 2 {
+		t.Errorf("StaleApprovals = %d, want 2 (alice's of an old commit, and bob's before the push)", summary.StaleApprovals)
+	}
+	if want := map[string]string{"alice": "old", "carol": "head"}; !reflect.DeepEqual(summary.ApprovedAtSHA, want) {
+		t.Errorf("ApprovedAtSHA = %v, want %v", summary.ApprovedAtSHA, want)
+	}
+
+	// Without a head SHA, only the timing is known
+	if got := calculateApprovalSummary(events, "").StaleApprovals; got != 2 {
+		t.Errorf("StaleApprovals without a head SHA = %d, want 2", got)
+	}
+}
+
 func TestCheckSummaryCancelledNotInFailing(t *testing.T) {
 	// Regression test: cancelled checks should only appear in cancelled map, not in failing map
 	// This was a bug where cancelled checks appeared in both maps
@@ -513,3 +538,32 @@ func TestCheckSummaryCancelledNotInFailing(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no mentions", text: "LGTM", want: nil},
+		{name: "single mention", text: "@alice please take a look", want: []string{"alice"}},
+		{name: "multiple mentions", text: "cc @alice, @bob-smith and (@carol)", want: []string{"alice", "bob-smith", "carol"}},
+		{name: "adjacent mentions", text: "@alice,@bob", want: []string{"alice", "bob"}},
+		{name: "team mention", text: "Needs review from @acme/platform-team.", want: []string{"acme/platform-team"}},
+		{name: "trailing punctuation", text: "Thanks @dave. And @eve's point too!", want: []string{"dave", "eve"}},
+		{name: "duplicates ignore case", text: "@Alice and @alice again", want: []string{"Alice"}},
+		{name: "email address", text: "Contact foo@example.com or bar.baz@corp.io", want: nil},
+		{name: "inline code", text: "Use the `@Override` annotation, thanks @frank", want: []string{"frank"}},
+		{name: "fenced code block", text: "See:\n```java\n@Test\nvoid f() {}\n```\n@grace WDYT", want: []string{"grace"}},
+		{name: "unterminated code block", text: "```\n@decorator\n", want: nil},
+		{name: "URL path", text: "https://example.com/@henry", want: nil},
+		{name: "invalid login", text: "@-nope and @", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMentions(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package prx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WaitingOn is who has to act next for a pull request to move forward.
+type WaitingOn string
+
+// WaitingOn values. Closed and merged pull requests wait on nobody.
+const (
+	WaitingOnNobody     WaitingOn = ""
+	WaitingOnAuthor     WaitingOn = "author"      // To resolve conflicts, fix failing checks, address requested changes, or leave draft
+	WaitingOnReviewers  WaitingOn = "reviewers"   // To review or approve
+	WaitingOnChecks     WaitingOn = "checks"      // For pending status checks to complete
+	WaitingOnMerger     WaitingOn = "merger"      // For someone with write access to merge
+	WaitingOnMergeQueue WaitingOn = "merge_queue" // For the merge queue to merge it
+)
+
+// setWaitingOn sets who the pull request is waiting on, and why, from its state, checks, and reviews.
+// It must run after the check and approval summaries are calculated.
+func setWaitingOn(pr *PullRequest) {
+	pr.WaitingOn, pr.WaitingOnReason = waitingOn(pr)
+}
+
+// waitingOn classifies the pull request. Problems the author must fix take precedence over
+// pending checks, which take precedence over missing reviews.
+func waitingOn(pr *PullRequest) (WaitingOn, string) {
+	if pr.Merged || pr.State != "open" {
+		return WaitingOnNobody, ""
+	}
+	if q := pr.MergeQueue; q != nil && q.InQueue {
+		return WaitingOnMergeQueue, q.queuedReason()
+	}
+	if pr.Draft {
+		return WaitingOnAuthor, "PR is a draft"
+	}
+	if pr.MergeableState == "dirty" {
+		return WaitingOnAuthor, "PR has merge conflicts that need to be resolved"
+	}
+
+	checks := pr.CheckSummary
+	if checks == nil {
+		checks = &CheckSummary{}
+	}
+	if failing := checkNames(checks.Failing, checks.Cancelled); len(failing) > 0 {
+		return WaitingOnAuthor, "status checks are failing: " + strings.Join(failing, ", ")
+	}
+
+	var approvals, changesRequested int
+	if a := pr.ApprovalSummary; a != nil {
+		approvals, changesRequested = a.ApprovalsWithWriteAccess, a.ChangesRequested
+	}
+	if changesRequested > 0 {
+		return WaitingOnAuthor, fmt.Sprintf("%d outstanding change requests", changesRequested)
+	}
+	if pending := checkNames(checks.Pending); len(pending) > 0 {
+		return WaitingOnChecks, "status checks are pending: " + strings.Join(pending, ", ")
+	}
+	if pr.MergeableState == "behind" {
+		return WaitingOnAuthor, "PR branch is behind the base branch"
+	}
+
+	if approvals == 0 {
+		for _, state := range pr.Reviewers {
+			if state != ReviewStatePending {
+				return WaitingOnReviewers, "PR has reviews but no approval from a reviewer with write access"
+			}
+		}
+		if len(pr.Reviewers) == 0 {
+			return WaitingOnReviewers, "PR has no review or requested reviewers yet"
+		}
+		return WaitingOnReviewers, "PR has no review yet"
+	}
+	queued := pr.MergeQueue != nil && pr.MergeQueue.Configuration != nil
+	if pr.MergeableState == "blocked" && !(queued && pr.ApprovalsSatisfied) {
+		// Approved with passing checks, so branch protection wants more or code owner reviews
+		return WaitingOnReviewers, "PR needs further approvals required by branch protection"
+	}
+	if queued {
+		// Merge queues report pull requests that are ready for them as blocked
+		return WaitingOnMerger, "PR is approved and ready to be added to the merge queue"
+	}
+	return WaitingOnMerger, "PR is approved and ready to merge"
+}
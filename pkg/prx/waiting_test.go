@@ -0,0 +1,73 @@
+package prx
+
+import "testing"
+
+func TestWaitingOn(t *testing.T) {
+	approved := &ApprovalSummary{ApprovalsWithWriteAccess: 1}
+	passing := &CheckSummary{Success: map[string]string{"build": "ok"}}
+	queue := &MergeQueueConfiguration{MergeMethod: "squash"}
+	tests := []struct {
+		name       string
+		pr         PullRequest
+		want       WaitingOn
+		wantReason string
+	}{
+		{"merged", PullRequest{State: "closed", Merged: true}, WaitingOnNobody, ""},
+		{"draft", PullRequest{State: "open", Draft: true}, WaitingOnAuthor, "PR is a draft"},
+		{"conflicts", PullRequest{State: "open", MergeableState: "dirty", ApprovalSummary: approved}, WaitingOnAuthor, "PR has merge conflicts that need to be resolved"},
+		{
+			"failing checks",
+			PullRequest{State: "open", CheckSummary: &CheckSummary{Failing: map[string]string{"lint": ""}, Cancelled: map[string]string{"e2e": ""}}},
+			WaitingOnAuthor, "status checks are failing: e2e, lint",
+		},
+		{
+			"changes requested",
+			PullRequest{State: "open", CheckSummary: passing, ApprovalSummary: &ApprovalSummary{ChangesRequested: 2}},
+			WaitingOnAuthor, "2 outstanding change requests",
+		},
+		{
+			"pending checks",
+			PullRequest{State: "open", CheckSummary: &CheckSummary{Pending: map[string]string{"test": ""}}},
+			WaitingOnChecks, "status checks are pending: test",
+		},
+		{"behind", PullRequest{State: "open", MergeableState: "behind", ApprovalSummary: approved}, WaitingOnAuthor, "PR branch is behind the base branch"},
+		{"no reviewers", PullRequest{State: "open", CheckSummary: passing}, WaitingOnReviewers, "PR has no review or requested reviewers yet"},
+		{
+			"review requested",
+			PullRequest{State: "open", Reviewers: map[string]ReviewState{"alice": ReviewStatePending}},
+			WaitingOnReviewers, "PR has no review yet",
+		},
+		{
+			"commented",
+			PullRequest{State: "open", Reviewers: map[string]ReviewState{"alice": ReviewStateCommented}},
+			WaitingOnReviewers, "PR has reviews but no approval from a reviewer with write access",
+		},
+		{
+			"more approvals required",
+			PullRequest{State: "open", MergeableState: "blocked", CheckSummary: passing, ApprovalSummary: approved},
+			WaitingOnReviewers, "PR needs further approvals required by branch protection",
+		},
+		{"ready", PullRequest{State: "open", MergeableState: "clean", CheckSummary: passing, ApprovalSummary: approved}, WaitingOnMerger, "PR is approved and ready to merge"},
+		{
+			"ready for the merge queue",
+			PullRequest{
+				State: "open", MergeableState: "blocked", CheckSummary: passing, ApprovalSummary: approved, ApprovalsSatisfied: true,
+				MergeQueue: &MergeQueueState{Configuration: queue},
+			},
+			WaitingOnMerger, "PR is approved and ready to be added to the merge queue",
+		},
+		{
+			"in the merge queue",
+			PullRequest{State: "open", MergeableState: "blocked", MergeQueue: &MergeQueueState{Configuration: queue, InQueue: true, Position: 3}},
+			WaitingOnMergeQueue, "PR is at position 3 in the merge queue",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setWaitingOn(&tt.pr)
+			if tt.pr.WaitingOn != tt.want || tt.pr.WaitingOnReason != tt.wantReason {
+				t.Errorf("WaitingOn = %q, %q; want %q, %q", tt.pr.WaitingOn, tt.pr.WaitingOnReason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
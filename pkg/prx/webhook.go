@@ -0,0 +1,535 @@
+package prx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidSignature is returned when a webhook delivery fails signature verification.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// webhookUser is the user object embedded in webhook payloads.
+type webhookUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+func (u webhookUser) actor() graphQLActor {
+	return graphQLActor{Login: u.Login, Type: u.Type}
+}
+
+// webhookApp is the GitHub App that performed an action, as embedded in webhook payloads.
+type webhookApp struct {
+	Slug string `json:"slug"`
+}
+
+// slug returns the app's slug, or "" if no app performed the action.
+func (a *webhookApp) slug() string {
+	if a == nil {
+		return ""
+	}
+	return a.Slug
+}
+
+// webhookPayload holds the subset of webhook payload fields used for incremental updates.
+//
+//nolint:govet // fieldalignment: Struct mirrors webhook JSON layout
+type webhookPayload struct {
+	Action     string      `json:"action"`
+	Sender     webhookUser `json:"sender"`
+	Number     int         `json:"number"`
+	Repository struct {
+		Name     string      `json:"name"`
+		Owner    webhookUser `json:"owner"`
+		Archived bool        `json:"archived"`
+	} `json:"repository"`
+
+	PullRequest *struct {
+		CreatedAt      time.Time    `json:"created_at"`
+		UpdatedAt      time.Time    `json:"updated_at"`
+		ClosedAt       *time.Time   `json:"closed_at"`
+		MergedAt       *time.Time   `json:"merged_at"`
+		MergedBy       *webhookUser `json:"merged_by"`
+		Mergeable      *bool        `json:"mergeable"`
+		Title          string       `json:"title"`
+		Body           string       `json:"body"`
+		State          string       `json:"state"`
+		MergeableState string       `json:"mergeable_state"`
+		Head           struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Number       int  `json:"number"`
+		Additions    int  `json:"additions"`
+		Deletions    int  `json:"deletions"`
+		ChangedFiles int  `json:"changed_files"`
+		Draft        bool `json:"draft"`
+		Merged       bool `json:"merged"`
+		Locked       bool `json:"locked"`
+	} `json:"pull_request"`
+
+	Issue *struct {
+		PullRequest *struct{} `json:"pull_request"`
+		Number      int       `json:"number"`
+	} `json:"issue"`
+
+	Comment *struct {
+		CreatedAt             time.Time   `json:"created_at"`
+		User                  webhookUser `json:"user"`
+		Body                  string      `json:"body"`
+		AuthorAssociation     string      `json:"author_association"`
+		PerformedViaGitHubApp *webhookApp `json:"performed_via_github_app"`
+	} `json:"comment"`
+
+	Review *struct {
+		SubmittedAt           time.Time   `json:"submitted_at"`
+		User                  webhookUser `json:"user"`
+		Body                  string      `json:"body"`
+		State                 string      `json:"state"`
+		CommitID              string      `json:"commit_id"`
+		AuthorAssociation     string      `json:"author_association"`
+		PerformedViaGitHubApp *webhookApp `json:"performed_via_github_app"`
+	} `json:"review"`
+
+	Label *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Assignee          *webhookUser `json:"assignee"`
+	RequestedReviewer *webhookUser `json:"requested_reviewer"`
+	RequestedTeam     *struct {
+		Name string `json:"name"`
+	} `json:"requested_team"`
+
+	CheckRun *struct {
+		StartedAt   time.Time `json:"started_at"`
+		ID          int64     `json:"id"`
+		CompletedAt time.Time `json:"completed_at"`
+		Name        string    `json:"name"`
+		HeadSHA     string    `json:"head_sha"`
+		Status      string    `json:"status"`
+		Conclusion  string    `json:"conclusion"`
+		DetailsURL  string    `json:"details_url"`
+		Output      struct {
+			Title   string `json:"title"`
+			Summary string `json:"summary"`
+		} `json:"output"`
+		PullRequests []struct {
+			Number int `json:"number"`
+		} `json:"pull_requests"`
+	} `json:"check_run"`
+
+	// Why a pull request was removed from a merge queue, for dequeued actions
+	Reason string `json:"reason"`
+
+	// Fields of status events
+	UpdatedAt   time.Time `json:"updated_at"`
+	SHA         string    `json:"sha"`
+	Context     string    `json:"context"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+}
+
+// prRef identifies a pull request.
+type prRef struct {
+	owner  string
+	repo   string
+	number int
+}
+
+// WebhookProcessor applies GitHub webhook deliveries to cached PullRequestData,
+// avoiding a full refetch. Supported event types are pull_request, pull_request_review,
+// pull_request_review_comment, issue_comment, check_run, and status.
+type WebhookProcessor struct {
+	client *Client
+	shas   map[string]prRef // commit SHA to pull request, used to route status events
+	secret []byte
+	mu     sync.Mutex
+}
+
+// NewWebhookProcessor creates a processor that updates client's PR cache.
+// If secret is non-empty, every delivery must carry a valid X-Hub-Signature-256 signature.
+func NewWebhookProcessor(client *Client, secret []byte) *WebhookProcessor {
+	return &WebhookProcessor{
+		client: client,
+		secret: secret,
+		shas:   make(map[string]prRef),
+	}
+}
+
+// VerifyWebhookSignature checks a payload against the X-Hub-Signature-256 header value.
+func VerifyWebhookSignature(secret, payload []byte, signature string) error {
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Process verifies a webhook delivery and applies it to the cached pull request it concerns.
+// eventType is the X-GitHub-Event header and signature the X-Hub-Signature-256 header.
+// It returns the updated data, or nil if the delivery doesn't concern a cached pull request
+// (the next fetch will load it in full).
+func (p *WebhookProcessor) Process(ctx context.Context, eventType, signature string, payload []byte) (*PullRequestData, error) {
+	if len(p.secret) > 0 {
+		if err := VerifyWebhookSignature(p.secret, payload, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	var hook webhookPayload
+	if err := json.Unmarshal(payload, &hook); err != nil {
+		return nil, fmt.Errorf("decoding %s webhook: %w", eventType, err)
+	}
+
+	c := p.client
+	ref, ok := p.route(eventType, &hook)
+	if !ok || c.prCache == nil {
+		c.logger.DebugContext(ctx, "webhook does not concern a known pull request", "event", eventType, "action", hook.Action)
+		return nil, nil //nolint:nilnil // nil data signals there is nothing to update
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := c.prCacheKey(ref.owner, ref.repo, ref.number)
+	data, found, err := c.prCache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("reading cached pull request: %w", err)
+	}
+	if !found {
+		c.logger.DebugContext(ctx, "webhook for uncached pull request ignored",
+			"owner", ref.owner, "repo", ref.repo, "pr", ref.number, "event", eventType)
+		return nil, nil //nolint:nilnil // nil data signals there is nothing to update
+	}
+
+	c.applyWebhook(ctx, &data, eventType, &hook, ref)
+	data.CachedAt = time.Now()
+	if err := c.prCache.Set(ctx, key, data); err != nil {
+		return nil, fmt.Errorf("updating cached pull request: %w", err)
+	}
+
+	p.trackLocked(ref, &data)
+
+	c.logger.InfoContext(ctx, "applied webhook to cached pull request",
+		"owner", ref.owner, "repo", ref.repo, "pr", ref.number, "event", eventType, "action", hook.Action)
+	return &data, nil
+}
+
+// Track registers a pull request's commits so that status events for them can be routed.
+// Pull requests are tracked automatically once a webhook for them has been processed.
+func (p *WebhookProcessor) Track(owner, repo string, data *PullRequestData) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.trackLocked(prRef{owner: owner, repo: repo, number: data.PullRequest.Number}, data)
+}
+
+func (p *WebhookProcessor) trackLocked(ref prRef, data *PullRequestData) {
+	for _, sha := range data.PullRequest.Commits {
+		p.shas[sha] = ref
+	}
+	if data.PullRequest.HeadSHA != "" {
+		p.shas[data.PullRequest.HeadSHA] = ref
+	}
+}
+
+// route determines which pull request a webhook payload concerns.
+func (p *WebhookProcessor) route(eventType string, hook *webhookPayload) (prRef, bool) {
+	ref := prRef{owner: hook.Repository.Owner.Login, repo: hook.Repository.Name}
+
+	switch eventType {
+	case "pull_request", "pull_request_review", "pull_request_review_comment":
+		if hook.PullRequest != nil {
+			ref.number = hook.PullRequest.Number
+		}
+	case "issue_comment":
+		if hook.Issue != nil && hook.Issue.PullRequest != nil {
+			ref.number = hook.Issue.Number
+		}
+	case "check_run":
+		if hook.CheckRun != nil && len(hook.CheckRun.PullRequests) > 0 {
+			ref.number = hook.CheckRun.PullRequests[0].Number
+		}
+	case "status":
+		p.mu.Lock()
+		known, ok := p.shas[hook.SHA]
+		p.mu.Unlock()
+		if ok {
+			ref = known
+		}
+	default:
+	}
+
+	return ref, ref.number != 0 && ref.owner != "" && ref.repo != ""
+}
+
+// applyWebhook updates data with the pull request fields and events carried by a webhook.
+func (c *Client) applyWebhook(ctx context.Context, data *PullRequestData, eventType string, hook *webhookPayload, ref prRef) {
+	pr := &data.PullRequest
+	pr.RepoArchived = hook.Repository.Archived
+	if hook.PullRequest != nil && eventType == "pull_request" {
+		src := hook.PullRequest
+		pr.Title = src.Title
+		pr.Body = c.truncate(src.Body)
+		pr.State = strings.ToLower(src.State)
+		pr.UpdatedAt = src.UpdatedAt
+		pr.ClosedAt = src.ClosedAt
+		pr.MergedAt = src.MergedAt
+		pr.Merged = src.Merged
+		pr.Draft = src.Draft
+		pr.ConversationLocked = src.Locked
+		pr.Additions = src.Additions
+		pr.Deletions = src.Deletions
+		pr.ChangedFiles = src.ChangedFiles
+		if src.Mergeable != nil {
+			pr.Mergeable = src.Mergeable
+		}
+		if src.MergeableState != "" {
+			pr.MergeableState = src.MergeableState
+		}
+		if src.MergedBy != nil {
+			pr.MergedBy = src.MergedBy.Login
+		}
+		if src.Head.SHA != "" && src.Head.SHA != pr.HeadSHA {
+			pr.HeadSHA = src.Head.SHA
+			if !slices.Contains(pr.Commits, src.Head.SHA) {
+				pr.Commits = append(pr.Commits, src.Head.SHA)
+			}
+		}
+	}
+
+	events := c.webhookEvents(ctx, eventType, hook, ref)
+	c.enrichEvents(ctx, events, nil)
+	applyEventToPullRequest(pr, events)
+
+	data.Events = mergeEvents(data.Events, filterEvents(events))
+	upgradeWriteAccess(data.Events)
+
+	required := existingRequiredChecks(data)
+	finalizePullRequest(pr, data.Events, required, pr.TestState)
+	data.ActivityHistogram = calculateActivityHistogram(data.Events)
+	data.Timing = calculateTimingSummary(pr, data.Events, time.Now())
+	c.redactSecrets(data)
+}
+
+// applyEventToPullRequest keeps derived pull request fields in step with new events.
+func applyEventToPullRequest(pr *PullRequest, events []Event) {
+	for i := range events {
+		e := &events[i]
+		switch e.Kind {
+		case EventKindLabeled:
+			if !slices.Contains(pr.Labels, e.Target) {
+				pr.Labels = append(pr.Labels, e.Target)
+			}
+		case EventKindUnlabeled:
+			pr.Labels = slices.DeleteFunc(pr.Labels, func(l string) bool { return l == e.Target })
+		case EventKindAssigned:
+			if !slices.Contains(pr.Assignees, e.Target) {
+				pr.Assignees = append(pr.Assignees, e.Target)
+			}
+		case EventKindUnassigned:
+			pr.Assignees = slices.DeleteFunc(pr.Assignees, func(a string) bool { return a == e.Target })
+		case EventKindReviewRequested:
+			if pr.Reviewers == nil {
+				pr.Reviewers = make(map[string]ReviewState)
+			}
+			pr.Reviewers[e.Target] = ReviewStatePending
+		case EventKindReviewRequestRemoved:
+			if pr.Reviewers[e.Target] == ReviewStatePending {
+				delete(pr.Reviewers, e.Target)
+			}
+		case EventKindReview:
+			if pr.Reviewers == nil {
+				pr.Reviewers = make(map[string]ReviewState)
+			}
+			switch e.Outcome {
+			case "approved":
+				pr.Reviewers[e.Actor] = ReviewStateApproved
+			case "changes_requested":
+				pr.Reviewers[e.Actor] = ReviewStateChangesRequested
+			case "commented":
+				pr.Reviewers[e.Actor] = ReviewStateCommented
+			default:
+			}
+		default:
+		}
+	}
+}
+
+// webhookEvents converts a webhook payload into timeline events.
+//
+//nolint:gocognit,revive // Must handle each supported webhook event and action
+func (c *Client) webhookEvents(ctx context.Context, eventType string, hook *webhookPayload, ref prRef) []Event {
+	now := time.Now()
+	sender := hook.Sender
+	base := Event{Actor: sender.Login, ActorType: c.classifyActor(ctx, sender.actor()), Bot: c.classifyBot(ctx, sender.actor()), Timestamp: now}
+	if hook.PullRequest != nil && !hook.PullRequest.UpdatedAt.IsZero() {
+		base.Timestamp = hook.PullRequest.UpdatedAt
+	}
+
+	switch eventType {
+	case "pull_request":
+		e := base
+		if hook.Action == "closed" {
+			e.Kind = EventKindPRClosed
+			if hook.PullRequest.Merged {
+				e.Kind = EventKindPRMerged
+			}
+			if hook.PullRequest.ClosedAt != nil {
+				e.Timestamp = *hook.PullRequest.ClosedAt
+			}
+			return []Event{e}
+		}
+		kind, ok := pullRequestActionKinds[hook.Action]
+		if !ok {
+			return nil
+		}
+		e.Kind = kind
+		switch kind {
+		case EventKindPROpened:
+			e.Body = c.truncate(hook.PullRequest.Body)
+			e.Mentions = extractMentions(hook.PullRequest.Body)
+			e.Timestamp = hook.PullRequest.CreatedAt
+		case EventKindLabeled, EventKindUnlabeled:
+			if hook.Label != nil {
+				e.Target = hook.Label.Name
+			}
+		case EventKindAssigned, EventKindUnassigned:
+			if hook.Assignee != nil {
+				c.target(ctx, &e, hook.Assignee.actor(), "")
+			}
+		case EventKindReviewRequested, EventKindReviewRequestRemoved:
+			var team string
+			if hook.RequestedTeam != nil {
+				team = hook.RequestedTeam.Name
+			}
+			var reviewer graphQLActor
+			if hook.RequestedReviewer != nil {
+				reviewer = hook.RequestedReviewer.actor()
+			}
+			c.target(ctx, &e, reviewer, team)
+		case EventKindCommit:
+			e.Body = hook.PullRequest.Head.SHA
+		case EventKindRemovedFromMergeQueue:
+			e.Body = hook.Reason
+		default:
+		}
+		return []Event{e}
+
+	case "issue_comment", "pull_request_review_comment":
+		if hook.Action != "created" || hook.Comment == nil {
+			return nil
+		}
+		kind := EventKindComment
+		if eventType == "pull_request_review_comment" {
+			kind = EventKindReviewComment
+		}
+		cm := hook.Comment
+		return []Event{c.postEvent(ctx, kind, &post{
+			createdAt:   cm.CreatedAt,
+			author:      cm.User.actor(),
+			body:        cm.Body,
+			association: cm.AuthorAssociation,
+			app:         cm.PerformedViaGitHubApp.slug(),
+		}, ref.owner, ref.repo)}
+
+	case "pull_request_review":
+		if hook.Action != "submitted" || hook.Review == nil {
+			return nil
+		}
+		rv := hook.Review
+		e := c.postEvent(ctx, EventKindReview, &post{
+			createdAt:   rv.SubmittedAt,
+			author:      rv.User.actor(),
+			body:        rv.Body,
+			association: rv.AuthorAssociation,
+			app:         rv.PerformedViaGitHubApp.slug(),
+		}, ref.owner, ref.repo)
+		e.Outcome = outcome(rv.State)
+		e.CommitSHA = rv.CommitID
+		return []Event{e}
+
+	case "check_run":
+		if hook.CheckRun == nil {
+			return nil
+		}
+		run := hook.CheckRun
+		e, ok := latestCheckRunEvent(&checkRun{
+			startedAt:   run.StartedAt,
+			completedAt: run.CompletedAt,
+			name:        run.Name,
+			headSHA:     run.HeadSHA,
+			status:      run.Status,
+			conclusion:  run.Conclusion,
+			title:       run.Output.Title,
+			summary:     run.Output.Summary,
+			detailsURL:  run.DetailsURL,
+			id:          run.ID,
+		})
+		if !ok {
+			return nil
+		}
+		return []Event{e}
+
+	case "status":
+		return []Event{c.statusEvent(ctx, &commitStatus{
+			createdAt:   hook.UpdatedAt,
+			creator:     sender.actor(),
+			sha:         hook.SHA,
+			context:     hook.Context,
+			state:       hook.State,
+			description: hook.Description,
+		})}
+
+	default:
+		return nil
+	}
+}
+
+// eventKey identifies an event for deduplication.
+func eventKey(e *Event) string {
+	return strings.Join([]string{string(e.Kind), e.Timestamp.UTC().Format(time.RFC3339Nano), e.Actor, e.Target, e.Body, e.Outcome}, "\x00")
+}
+
+// mergeEvents appends incoming events that aren't already present and keeps the result sorted chronologically.
+// Appended events are numbered after the existing ones.
+func mergeEvents(existing, incoming []Event) []Event {
+	seen := make(map[string]bool, len(existing))
+	var last int64
+	for i := range existing {
+		seen[eventKey(&existing[i])] = true
+		last = max(last, existing[i].Seq)
+	}
+	merged := existing
+	for i := range incoming {
+		k := eventKey(&incoming[i])
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		last++
+		incoming[i].Seq = last
+		merged = append(merged, incoming[i])
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged
+}
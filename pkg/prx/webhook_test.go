@@ -0,0 +1,223 @@
+package prx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func signPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookTestClient(t *testing.T) *Client {
+	t.Helper()
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	data := PullRequestData{
+		CachedAt: base,
+		PullRequest: PullRequest{
+			Number:    7,
+			Author:    "alice",
+			State:     "open",
+			HeadSHA:   "aaa111",
+			Commits:   []string{"aaa111"},
+			Reviewers: map[string]ReviewState{"bob": ReviewStatePending},
+		},
+		Events: []Event{
+			{Kind: EventKindPROpened, Timestamp: base, Actor: "alice"},
+			{Kind: EventKindCommit, Timestamp: base.Add(time.Minute), Actor: "alice", Body: "aaa111"},
+		},
+	}
+	if err := client.prCache.Set(context.Background(), prCacheKey("owner", "repo", 7), data); err != nil {
+		t.Fatalf("seeding cache: %v", err)
+	}
+	return client
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	secret := []byte("s3cret")
+	payload := []byte(`{"action":"opened"}`)
+
+	if err := VerifyWebhookSignature(secret, payload, signPayload(secret, payload)); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	for _, sig := range []string{"", "sha1=abc", "sha256=zz", signPayload([]byte("other"), payload)} {
+		if err := VerifyWebhookSignature(secret, payload, sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("signature %q: got %v, want ErrInvalidSignature", sig, err)
+		}
+	}
+}
+
+func TestWebhookProcessor_RejectsBadSignature(t *testing.T) {
+	p := NewWebhookProcessor(newWebhookTestClient(t), []byte("s3cret"))
+	_, err := p.Process(context.Background(), "issue_comment", "sha256=00", []byte(`{}`))
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestWebhookProcessor_AppliesEvents(t *testing.T) {
+	client := newWebhookTestClient(t)
+	secret := []byte("s3cret")
+	p := NewWebhookProcessor(client, secret)
+	ctx := context.Background()
+
+	deliveries := []struct {
+		event   string
+		payload string
+	}{
+		{"issue_comment", `{"action":"created","repository":{"name":"repo","owner":{"login":"owner"}},
+			"issue":{"number":7,"pull_request":{}},
+			"comment":{"created_at":"2025-01-01T11:00:00Z","user":{"login":"bob"},"body":"Can you add tests?","author_association":"CONTRIBUTOR"}}`},
+		{"pull_request_review", `{"action":"submitted","repository":{"name":"repo","owner":{"login":"owner"}},
+			"pull_request":{"number":7},
+			"review":{"submitted_at":"2025-01-01T12:00:00Z","user":{"login":"bob"},"state":"APPROVED","author_association":"OWNER"}}`},
+		{"pull_request", `{"action":"labeled","repository":{"name":"repo","owner":{"login":"owner"}},"sender":{"login":"carol"},
+			"label":{"name":"lgtm"},
+			"pull_request":{"number":7,"title":"New title","state":"open","updated_at":"2025-01-01T12:30:00Z","head":{"sha":"aaa111"},"locked":true}}`},
+		{"check_run", `{"action":"completed","repository":{"name":"repo","owner":{"login":"owner"}},
+			"check_run":{"name":"test","head_sha":"aaa111","status":"completed","conclusion":"failure",
+				"started_at":"2025-01-01T11:30:00Z","completed_at":"2025-01-01T11:40:00Z","pull_requests":[{"number":7}]}}`},
+		{"status", `{"repository":{"name":"repo","owner":{"login":"owner"},"archived":true},"sender":{"login":"ci-bot","type":"Bot"},
+			"sha":"aaa111","context":"lint","state":"failure","updated_at":"2025-01-01T11:45:00Z"}`},
+	}
+
+	var data *PullRequestData
+	for _, d := range deliveries {
+		payload := []byte(d.payload)
+		var err error
+		data, err = p.Process(ctx, d.event, signPayload(secret, payload), payload)
+		if err != nil {
+			t.Fatalf("%s: Process() error: %v", d.event, err)
+		}
+		if data == nil {
+			t.Fatalf("%s: expected updated data", d.event)
+		}
+	}
+
+	if data.PullRequest.Title != "New title" {
+		t.Errorf("Title = %q, want %q", data.PullRequest.Title, "New title")
+	}
+	if !data.PullRequest.RepoArchived || !data.PullRequest.ConversationLocked {
+		t.Errorf("RepoArchived = %v, ConversationLocked = %v; want both set", data.PullRequest.RepoArchived, data.PullRequest.ConversationLocked)
+	}
+	if len(data.PullRequest.Labels) != 1 || data.PullRequest.Labels[0] != "lgtm" {
+		t.Errorf("Labels = %v, want [lgtm]", data.PullRequest.Labels)
+	}
+	if data.PullRequest.Reviewers["bob"] != ReviewStateApproved {
+		t.Errorf("bob review state = %q, want approved", data.PullRequest.Reviewers["bob"])
+	}
+	if data.PullRequest.ApprovalSummary.ApprovalsWithWriteAccess != 1 {
+		t.Errorf("expected 1 approval with write access, got %+v", data.PullRequest.ApprovalSummary)
+	}
+	if _, ok := data.PullRequest.CheckSummary.Failing["test"]; !ok {
+		t.Errorf("expected failing check run, got %+v", data.PullRequest.CheckSummary)
+	}
+	if _, ok := data.PullRequest.CheckSummary.Failing["lint"]; !ok {
+		t.Errorf("expected failing status routed by SHA, got %+v", data.PullRequest.CheckSummary)
+	}
+	for i := 1; i < len(data.Events); i++ {
+		if data.Events[i].Timestamp.Before(data.Events[i-1].Timestamp) {
+			t.Fatalf("events not sorted at index %d", i)
+		}
+	}
+
+	// Redelivery must not duplicate events.
+	count := len(data.Events)
+	payload := []byte(deliveries[0].payload)
+	data, err := p.Process(ctx, deliveries[0].event, signPayload(secret, payload), payload)
+	if err != nil {
+		t.Fatalf("redelivery error: %v", err)
+	}
+	if len(data.Events) != count {
+		t.Errorf("redelivery changed event count from %d to %d", count, len(data.Events))
+	}
+
+	cached, found, err := client.prCache.Get(ctx, prCacheKey("owner", "repo", 7))
+	if err != nil || !found {
+		t.Fatalf("expected updated data in cache, found=%v err=%v", found, err)
+	}
+	if cached.PullRequest.Title != "New title" {
+		t.Error("cache was not updated")
+	}
+}
+
+func TestWebhookProcessor_IgnoresUnknownPullRequests(t *testing.T) {
+	p := NewWebhookProcessor(newWebhookTestClient(t), nil)
+	ctx := context.Background()
+
+	for _, tc := range []struct{ event, payload string }{
+		{"issue_comment", `{"action":"created","repository":{"name":"repo","owner":{"login":"owner"}},"issue":{"number":8,"pull_request":{}},"comment":{}}`},
+		{"issue_comment", `{"action":"created","repository":{"name":"repo","owner":{"login":"owner"}},"issue":{"number":7},"comment":{}}`},
+		{"status", `{"repository":{"name":"repo","owner":{"login":"owner"}},"sha":"unknown","context":"ci","state":"success"}`},
+		{"push", `{"repository":{"name":"repo","owner":{"login":"owner"}}}`},
+	} {
+		data, err := p.Process(ctx, tc.event, "", []byte(tc.payload))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.event, err)
+		}
+		if data != nil {
+			t.Errorf("%s: expected nil data for %s", tc.event, tc.payload)
+		}
+	}
+}
+
+func TestMergeEvents(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := []Event{
+		{Kind: EventKindComment, Timestamp: t0, Actor: "a", Body: "hi"},
+		{Kind: EventKindComment, Timestamp: t0.Add(2 * time.Hour), Actor: "b", Body: "later"},
+	}
+	incoming := []Event{
+		{Kind: EventKindComment, Timestamp: t0, Actor: "a", Body: "hi"},
+		{Kind: EventKindReview, Timestamp: t0.Add(time.Hour), Actor: "c", Outcome: "approved"},
+	}
+	merged := mergeEvents(existing, incoming)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(merged))
+	}
+	if merged[1].Kind != EventKindReview {
+		t.Errorf("expected review in the middle, got %s", merged[1].Kind)
+	}
+	if merged[1].Seq != 1 {
+		t.Errorf("expected appended review to be numbered after existing events, got seq %d", merged[1].Seq)
+	}
+}
+
+func TestAssignSequence(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := []Event{
+		{Kind: EventKindCommit, Timestamp: t0, Actor: "a", Body: "sha1"},
+		{Kind: EventKindComment, Timestamp: t0.Add(2 * time.Hour), Actor: "b", Body: "lgtm"},
+		{Kind: EventKindComment, Timestamp: t0.Add(2 * time.Hour), Actor: "b", Body: "lgtm"},
+	}
+	assignSequence(first, nil)
+	for i, want := range []int64{1, 2, 3} {
+		if first[i].Seq != want {
+			t.Errorf("first[%d].Seq = %d, want %d", i, first[i].Seq, want)
+		}
+	}
+
+	// A refresh finds a check run that sorts before the comments
+	refreshed := []Event{
+		{Kind: EventKindCommit, Timestamp: t0, Actor: "a", Body: "sha1"},
+		{Kind: EventKindCheckRun, Timestamp: t0.Add(time.Hour), Actor: "github", Body: "test", Outcome: "success"},
+		{Kind: EventKindComment, Timestamp: t0.Add(2 * time.Hour), Actor: "b", Body: "lgtm"},
+		{Kind: EventKindComment, Timestamp: t0.Add(2 * time.Hour), Actor: "b", Body: "lgtm"},
+	}
+	assignSequence(refreshed, first)
+	for i, want := range []int64{1, 4, 2, 3} {
+		if refreshed[i].Seq != want {
+			t.Errorf("refreshed[%d].Seq = %d, want %d", i, refreshed[i].Seq, want)
+		}
+	}
+}
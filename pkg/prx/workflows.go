@@ -0,0 +1,42 @@
+package prx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// workflowRunPattern matches the workflow run and job in the details URL of a GitHub Actions check
+// run, such as https://github.com/owner/repo/actions/runs/123/job/456.
+var workflowRunPattern = regexp.MustCompile(`/actions/runs/([0-9]+)(?:/job/([0-9]+))?(?:[/?#]|$)`)
+
+// workflowRunIDs returns the workflow run and job a check run's details URL links to. Both are 0 if
+// the check run isn't from GitHub Actions, and the job is 0 if the URL only links to the run.
+func workflowRunIDs(detailsURL string) (run, job int64) {
+	m := workflowRunPattern.FindStringSubmatch(detailsURL)
+	if m == nil {
+		return 0, 0
+	}
+	run, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+	if m[2] != "" {
+		job, err = strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			job = 0
+		}
+	}
+	return run, job
+}
+
+// RerunFailedJobs re-runs the failed and cancelled jobs of a workflow run, as identified by
+// Event.WorkflowRunID, along with the jobs that depend on them.
+func (c *Client) RerunFailedJobs(ctx context.Context, owner, repo string, workflowRunID int64) error {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/rerun-failed-jobs", owner, repo, workflowRunID)
+	if err := c.github.Post(ctx, path, struct{}{}, nil); err != nil {
+		return fmt.Errorf("re-running failed jobs of workflow run %d in %s/%s: %w", workflowRunID, owner, repo, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package prx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeGROOVE-dev/fido/pkg/store/null"
+)
+
+func TestWorkflowRunIDs(t *testing.T) {
+	tests := []struct {
+		detailsURL string
+		wantRun    int64
+		wantJob    int64
+	}{
+		{"https://github.com/owner/repo/actions/runs/123/job/456", 123, 456},
+		{"https://github.com/owner/repo/actions/runs/123/job/456?pr=7", 123, 456},
+		{"https://ghe.example.com/owner/repo/actions/runs/789", 789, 0},
+		{"https://github.com/owner/repo/actions/runs/789/attempts/2", 789, 0},
+		{"https://app.circleci.com/pipelines/github/owner/repo/12/workflows/abc", 0, 0},
+		{"https://github.com/owner/repo/actions/runs/abc", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		if run, job := workflowRunIDs(tt.detailsURL); run != tt.wantRun || job != tt.wantJob {
+			t.Errorf("workflowRunIDs(%q) = %d, %d; want %d, %d", tt.detailsURL, run, job, tt.wantRun, tt.wantJob)
+		}
+	}
+}
+
+func TestClient_RerunFailedJobs(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/repos/owner/repo/actions/runs/123/rerun-failed-jobs" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCacheStore(null.New[string, PullRequestData]()))
+	client.github = newTestGitHubClient(&http.Client{}, "test-token", server.URL)
+	ctx := context.Background()
+
+	if err := client.RerunFailedJobs(ctx, "owner", "repo", 123); err != nil {
+		t.Fatalf("RerunFailedJobs() error: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expected one request, got %v", paths)
+	}
+	if err := client.RerunFailedJobs(ctx, "owner", "repo", 124); err == nil {
+		t.Error("expected error when the rerun is refused")
+	}
+}
@@ -0,0 +1,51 @@
+#!/bin/bash
+# Refreshes pkg/prx/testdata/github_schema.json from GitHub's GraphQL API.
+#
+# The fixture is GitHub's introspection result, trimmed to the types it already has. To check a
+# query that uses new types, pass their names as arguments.
+#
+# Usage: scripts/update-github-schema.sh [TYPE...]   (needs gh, authenticated, and jq)
+set -euo pipefail
+
+FIXTURE="$(dirname "$0")/../pkg/prx/testdata/github_schema.json"
+
+QUERY='
+query {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { name description type { ...TypeRef } defaultValue }
+        type { ...TypeRef }
+        isDeprecated
+        deprecationReason
+      }
+      inputFields { name description type { ...TypeRef } defaultValue }
+      interfaces { ...TypeRef }
+      enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}'
+
+names=$(jq -c '[.data.__schema.types[].name] + $ARGS.positional' "$FIXTURE" --args "$@")
+tmp=$(mktemp)
+trap 'rm -f "$tmp"' EXIT
+
+gh api graphql -f query="$QUERY" |
+    jq --argjson names "$names" '.data.__schema.types |= map(select(.name as $n | $names | index($n)))' >"$tmp"
+mv "$tmp" "$FIXTURE"
+echo "Updated $FIXTURE"